@@ -152,7 +152,7 @@ func TestACVPSigVer(t *testing.T) {
 }
 
 type verifier interface {
-	verifyInternal(sig, mu []byte) bool
+	verifyInternal(sig, mu []byte) error
 }
 
 func testACVPSigVer[PK verifier](t *testing.T, paramSet string, newPK func([]byte) (PK, error), pkSize, sigSize int) {
@@ -225,7 +225,7 @@ func testACVPSigVer[PK verifier](t *testing.T, paramSet string, newPK func([]byt
 
 				// The ACVP tests use the internal verify which takes mu directly
 				// mu is the message for internal verification
-				got := pk.verifyInternal(test.Signature, test.Message)
+				got := pk.verifyInternal(test.Signature, test.Message) == nil
 
 				if got != expected {
 					t.Errorf("tcId=%d: verification result mismatch: got %v, want %v", test.TcID, got, expected)
@@ -316,7 +316,7 @@ func testACVPSigGen44(t *testing.T) {
 				}
 
 				// Sign internally with the provided randomness
-				sig, err := sk.signInternal(rnd[:], test.Message)
+				sig, err := sk.signInternal(nil, rnd[:], test.Message)
 				if err != nil {
 					t.Fatalf("tcId=%d: signInternal failed: %v", test.TcID, err)
 				}
@@ -402,7 +402,7 @@ func testACVPSigGen65(t *testing.T) {
 					copy(rnd[:], test.Rnd)
 				}
 
-				sig, err := sk.signInternal(rnd[:], test.Message)
+				sig, err := sk.signInternal(nil, rnd[:], test.Message)
 				if err != nil {
 					t.Fatalf("tcId=%d: signInternal failed: %v", test.TcID, err)
 				}
@@ -488,7 +488,7 @@ func testACVPSigGen87(t *testing.T) {
 					copy(rnd[:], test.Rnd)
 				}
 
-				sig, err := sk.signInternal(rnd[:], test.Message)
+				sig, err := sk.signInternal(nil, rnd[:], test.Message)
 				if err != nil {
 					t.Fatalf("tcId=%d: signInternal failed: %v", test.TcID, err)
 				}
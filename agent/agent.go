@@ -0,0 +1,89 @@
+// Package agent keeps ML-DSA private keys in a separate process and signs
+// with them over a unix socket, in the manner of ssh-agent, for process
+// isolation of signing keys without a full HSM.
+//
+// Only the message representative µ crosses the socket: the client computes
+// µ from the public key, the message and the context (external µ, FIPS 204
+// Section 6.2), and the server signs it. Messages are never sent to the
+// agent, and the hedging randomness is drawn by the server.
+//
+// Basic usage:
+//
+//	srv, err := agent.NewServer(key)
+//	l, err := net.Listen("unix", path)
+//	go srv.Serve(l)
+//	...
+//	client, err := agent.Dial(path)
+//	signers, err := client.Signers()
+//	sig, err := signers[0].SignWithContext(nil, message, context)
+//
+// Signers implement mldsa.PrivateKey, so they can be used wherever a local
+// key is accepted. HashML-DSA is not supported.
+package agent
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// Message types. Each message is a 4-byte big-endian length followed by
+// the type byte and its payload.
+const (
+	msgFailure     = 1 // reason
+	msgListKeys    = 2 // empty
+	msgKeys        = 3 // repeated: 4-byte length || encoded public key
+	msgSignMu      = 4 // key fingerprint || µ
+	msgSignature   = 5 // signature
+	maxMessageSize = 1 << 20
+)
+
+// ErrUnknownKey is returned when the agent does not hold the requested key.
+var ErrUnknownKey = errors.New("agent: key not held by the agent")
+
+// muSigner is implemented by the private keys of package mldsa.
+type muSigner interface {
+	SignMu(rand io.Reader, mu [mldsa.MuSize]byte) ([]byte, error)
+}
+
+// muVerifier is implemented by the public keys of package mldsa.
+type muVerifier interface {
+	ComputeMu(message, context []byte) ([mldsa.MuSize]byte, error)
+	VerifyMu(sig []byte, mu [mldsa.MuSize]byte) bool
+}
+
+func writeMessage(w io.Writer, typ byte, payload ...[]byte) error {
+	n := 1
+	for _, p := range payload {
+		n += len(p)
+	}
+	if n > maxMessageSize {
+		return fmt.Errorf("agent: %d-byte message exceeds the maximum size", n)
+	}
+	b := binary.BigEndian.AppendUint32(make([]byte, 0, 4+n), uint32(n))
+	b = append(b, typ)
+	for _, p := range payload {
+		b = append(b, p...)
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func readMessage(r io.Reader) (byte, []byte, error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	n := binary.BigEndian.Uint32(hdr[:])
+	if n == 0 || n > maxMessageSize {
+		return 0, nil, fmt.Errorf("agent: invalid message length %d", n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return 0, nil, err
+	}
+	return b[0], b[1:], nil
+}
@@ -0,0 +1,127 @@
+package agent
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestAgent(t *testing.T) {
+	var keys []mldsa.PrivateKey
+	for _, s := range mldsa.Schemes() {
+		key, err := s.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	srv, err := NewServer(keys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer l.Close()
+	go srv.Serve(l)
+
+	client, err := Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	signers, err := client.Signers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signers) != len(keys) {
+		t.Fatalf("agent lists %d keys, want %d", len(signers), len(keys))
+	}
+
+	message, context := []byte("release manifest"), []byte("ctx")
+	for i, signer := range signers {
+		pk := keys[i].Public().(mldsa.PublicKey)
+		if !pk.Equal(signer.Public()) {
+			t.Fatalf("key %d: agent listed another key", i)
+		}
+		sig, err := signer.SignWithContext(nil, message, context)
+		if err != nil {
+			t.Fatalf("%s: SignWithContext: %v", signer.Scheme().Name(), err)
+		}
+		if !pk.Verify(sig, message, context) {
+			t.Errorf("%s: agent signature does not verify", signer.Scheme().Name())
+		}
+		sig, err = signer.Sign(nil, message, &mldsa.SignerOpts{Context: context})
+		if err != nil || !pk.Verify(sig, message, context) {
+			t.Errorf("%s: Sign: %v", signer.Scheme().Name(), err)
+		}
+		if _, err := signer.Sign(nil, message, crypto.SHA256); err == nil {
+			t.Errorf("%s: Sign accepted a pre-hash", signer.Scheme().Name())
+		}
+	}
+
+	other, _ := mldsa.GenerateKey44(rand.Reader)
+	if _, err := client.Signer(other.PublicKey()).SignWithContext(nil, message, nil); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("signing with a key the agent does not hold: %v", err)
+	}
+}
+
+// recorder records what a client writes to the agent.
+type recorder struct {
+	net.Conn
+	sent bytes.Buffer
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.sent.Write(b)
+	return r.Conn.Write(b)
+}
+
+func TestOnlyMuCrossesSocket(t *testing.T) {
+	key, _ := mldsa.GenerateKey65(rand.Reader)
+	srv, err := NewServer(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	go srv.ServeConn(c2)
+
+	rec := &recorder{Conn: c1}
+	message := bytes.Repeat([]byte("secret document "), 64)
+	sig, err := NewClient(rec).Signer(key.PublicKey()).SignWithContext(nil, message, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.PublicKey().Verify(sig, message, nil) {
+		t.Error("signature does not verify")
+	}
+	if bytes.Contains(rec.sent.Bytes(), message[:16]) {
+		t.Error("the message was sent to the agent")
+	}
+	if want := 4 + 1 + 32 + mldsa.MuSize; rec.sent.Len() != want {
+		t.Errorf("client sent %d bytes, want %d", rec.sent.Len(), want)
+	}
+}
+
+func TestInvalidAgentSignature(t *testing.T) {
+	key, _ := mldsa.GenerateKey44(rand.Reader)
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	go func() {
+		// A broken agent answering with a zero signature.
+		readMessage(c2)
+		writeMessage(c2, msgSignature, make([]byte, mldsa.SignatureSize44))
+	}()
+	if _, err := NewClient(c1).Signer(key.PublicKey()).SignWithContext(nil, []byte("m"), nil); err == nil {
+		t.Error("client accepted an invalid signature from the agent")
+	}
+}
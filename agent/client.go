@@ -0,0 +1,183 @@
+package agent
+
+import (
+	"crypto"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// Client talks to an agent. It is safe for concurrent use; requests are
+// serialized on the connection.
+type Client struct {
+	mu   sync.Mutex
+	conn io.ReadWriter
+}
+
+// Dial connects to the agent listening on the unix socket at path.
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// NewClient returns a client using conn, an established connection to an
+// agent.
+func NewClient(conn io.ReadWriter) *Client {
+	return &Client{conn: conn}
+}
+
+// Close closes the connection if it implements io.Closer.
+func (c *Client) Close() error {
+	if cl, ok := c.conn.(io.Closer); ok {
+		return cl.Close()
+	}
+	return nil
+}
+
+// call sends a request and returns the payload of a reply of type want.
+func (c *Client) call(want byte, typ byte, payload ...[]byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := writeMessage(c.conn, typ, payload...); err != nil {
+		return nil, err
+	}
+	rtyp, reply, err := readMessage(c.conn)
+	if err != nil {
+		return nil, err
+	}
+	switch rtyp {
+	case want:
+		return reply, nil
+	case msgFailure:
+		if string(reply) == ErrUnknownKey.Error() {
+			return nil, ErrUnknownKey
+		}
+		return nil, fmt.Errorf("agent: request failed: %s", reply)
+	}
+	return nil, fmt.Errorf("agent: unexpected reply %d", rtyp)
+}
+
+// Keys returns the public keys held by the agent.
+func (c *Client) Keys() ([]mldsa.PublicKey, error) {
+	reply, err := c.call(msgKeys, msgListKeys)
+	if err != nil {
+		return nil, err
+	}
+	var keys []mldsa.PublicKey
+	for len(reply) > 0 {
+		if len(reply) < 4 || uint32(len(reply)-4) < binary.BigEndian.Uint32(reply) {
+			return nil, errors.New("agent: malformed key list")
+		}
+		n := binary.BigEndian.Uint32(reply)
+		pk, err := mldsa.ParsePublicKey(reply[4 : 4+n])
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, pk)
+		reply = reply[4+n:]
+	}
+	return keys, nil
+}
+
+// Signers returns a signer for each key held by the agent.
+func (c *Client) Signers() ([]*Signer, error) {
+	keys, err := c.Keys()
+	if err != nil {
+		return nil, err
+	}
+	signers := make([]*Signer, len(keys))
+	for i, pk := range keys {
+		signers[i] = c.Signer(pk)
+	}
+	return signers, nil
+}
+
+// Signer returns a signer for pk. The agent is not contacted until the
+// first signature, which fails with ErrUnknownKey if it does not hold pk.
+func (c *Client) Signer(pk mldsa.PublicKey) *Signer {
+	return &Signer{client: c, pub: pk}
+}
+
+// SignMu asks the agent to sign the message representative µ with the
+// private key of pk, and checks the returned signature.
+func (c *Client) SignMu(pk mldsa.PublicKey, mu [mldsa.MuSize]byte) ([]byte, error) {
+	v, ok := pk.(muVerifier)
+	if !ok {
+		return nil, fmt.Errorf("agent: %T cannot verify an external µ", pk)
+	}
+	fp := pk.Fingerprint()
+	sig, err := c.call(msgSignature, msgSignMu, fp[:], mu[:])
+	if err != nil {
+		return nil, err
+	}
+	if !v.VerifyMu(sig, mu) {
+		return nil, errors.New("agent: agent returned an invalid signature")
+	}
+	return sig, nil
+}
+
+// Signer is a private key held by an agent. It implements crypto.Signer,
+// crypto.MessageSigner and mldsa.PrivateKey. The rand arguments of its
+// methods are ignored: the agent draws the hedging randomness.
+type Signer struct {
+	client *Client
+	pub    mldsa.PublicKey
+}
+
+// Public returns the public key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Scheme returns the parameter set of the key.
+func (s *Signer) Scheme() mldsa.Scheme {
+	return s.pub.Scheme()
+}
+
+// Seed always returns false: the seed never leaves the agent.
+func (s *Signer) Seed() ([]byte, bool) {
+	return nil, false
+}
+
+// SignWithContext signs message with an optional context string.
+func (s *Signer) SignWithContext(_ io.Reader, message, context []byte) ([]byte, error) {
+	v, ok := s.pub.(muVerifier)
+	if !ok {
+		return nil, fmt.Errorf("agent: %T cannot compute µ", s.pub)
+	}
+	mu, err := v.ComputeMu(message, context)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.SignMu(s.pub, mu)
+}
+
+// Sign signs message with pure ML-DSA. opts.HashFunc() must be zero; if
+// opts is *mldsa.SignerOpts, its Context field is used.
+func (s *Signer) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var context []byte
+	if opts != nil {
+		if opts.HashFunc() != 0 {
+			return nil, errors.New("agent: HashML-DSA is not supported")
+		}
+		if o, ok := opts.(*mldsa.SignerOpts); ok && o != nil {
+			context = o.Context
+		}
+	}
+	return s.SignWithContext(rand, message, context)
+}
+
+// SignMessage is like Sign. It implements crypto.MessageSigner.
+func (s *Signer) SignMessage(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.Sign(rand, message, opts)
+}
+
+var _ mldsa.PrivateKey = (*Signer)(nil)
@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// Server holds private keys and answers agent requests.
+type Server struct {
+	keys map[mldsa.Fingerprint]mldsa.PrivateKey
+	pubs [][]byte
+
+	// Rand is the randomness source for hedged signing. If nil,
+	// crypto/rand.Reader is used.
+	Rand io.Reader
+}
+
+// NewServer returns a server holding keys. Keys must be the key types of
+// package mldsa, which can sign an external µ.
+func NewServer(keys ...mldsa.PrivateKey) (*Server, error) {
+	s := &Server{keys: make(map[mldsa.Fingerprint]mldsa.PrivateKey)}
+	for _, k := range keys {
+		pk, ok := k.Public().(mldsa.PublicKey)
+		if _, signsMu := k.(muSigner); !ok || !signsMu {
+			return nil, fmt.Errorf("agent: %T cannot sign an external µ", k)
+		}
+		if _, dup := s.keys[pk.Fingerprint()]; dup {
+			continue
+		}
+		s.keys[pk.Fingerprint()] = k
+		s.pubs = append(s.pubs, pk.Bytes())
+	}
+	return s, nil
+}
+
+// Serve accepts connections on l, typically a unix socket listener, and
+// serves each in its own goroutine. It returns when Accept fails, for
+// example because l was closed.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			s.ServeConn(conn)
+		}()
+	}
+}
+
+// ServeConn answers requests read from conn until it is closed. It returns
+// nil when the client disconnects cleanly.
+func (s *Server) ServeConn(conn io.ReadWriter) error {
+	for {
+		typ, payload, err := readMessage(conn)
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := s.handle(conn, typ, payload); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(w io.Writer, typ byte, payload []byte) error {
+	switch typ {
+	case msgListKeys:
+		var b []byte
+		for _, pub := range s.pubs {
+			b = binary.BigEndian.AppendUint32(b, uint32(len(pub)))
+			b = append(b, pub...)
+		}
+		return writeMessage(w, msgKeys, b)
+
+	case msgSignMu:
+		var fp mldsa.Fingerprint
+		var mu [mldsa.MuSize]byte
+		if len(payload) != len(fp)+len(mu) {
+			return writeMessage(w, msgFailure, []byte("malformed sign request"))
+		}
+		copy(fp[:], payload)
+		copy(mu[:], payload[len(fp):])
+		key, ok := s.keys[fp]
+		if !ok {
+			return writeMessage(w, msgFailure, []byte(ErrUnknownKey.Error()))
+		}
+		sig, err := key.(muSigner).SignMu(s.Rand, mu)
+		if err != nil {
+			return writeMessage(w, msgFailure, []byte(err.Error()))
+		}
+		return writeMessage(w, msgSignature, sig)
+	}
+	return writeMessage(w, msgFailure, []byte(fmt.Sprintf("unsupported request %d", typ)))
+}
@@ -0,0 +1,8 @@
+//go:build go1.25
+
+package agent
+
+import "crypto"
+
+// Compile-time interface assertion for crypto.MessageSigner (Go 1.25+).
+var _ crypto.MessageSigner = (*Signer)(nil)
@@ -0,0 +1,44 @@
+package mldsa
+
+import "crypto/subtle"
+
+// VerifyBackup reports whether seed regenerates the key pair whose encoded
+// public key is publicKey. The parameter set is inferred from the length of
+// publicKey.
+//
+// The regenerated key is wiped before VerifyBackup returns and is never
+// handed to the caller, so offline backup checks can be performed without
+// materializing a usable signer.
+func VerifyBackup(seed, publicKey []byte) (bool, error) {
+	if len(seed) != SeedSize {
+		return false, &LengthError{Err: ErrInvalidSeedLength, Got: len(seed), Want: SeedSize}
+	}
+
+	var derived []byte
+	switch len(publicKey) {
+	case PublicKeySize44:
+		key, err := NewKey44(seed)
+		if err != nil {
+			return false, err
+		}
+		derived = key.publicKeyBytes()
+		key.wipe()
+	case PublicKeySize65:
+		key, err := NewKey65(seed)
+		if err != nil {
+			return false, err
+		}
+		derived = key.publicKeyBytes()
+		key.wipe()
+	case PublicKeySize87:
+		key, err := NewKey87(seed)
+		if err != nil {
+			return false, err
+		}
+		derived = key.publicKeyBytes()
+		key.wipe()
+	default:
+		return false, &LengthError{Err: ErrInvalidPublicKey, Got: len(publicKey)}
+	}
+	return subtle.ConstantTimeCompare(derived, publicKey) == 1, nil
+}
@@ -0,0 +1,57 @@
+package mldsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestVerifyBackup(t *testing.T) {
+	key44, _ := GenerateKey44(rand.Reader)
+	key65, _ := GenerateKey65(rand.Reader)
+	key87, _ := GenerateKey87(rand.Reader)
+
+	for _, tc := range []struct {
+		name      string
+		seed, pub []byte
+	}{
+		{"ML-DSA-44", key44.Bytes(), key44.PublicKey().Bytes()},
+		{"ML-DSA-65", key65.Bytes(), key65.PublicKey().Bytes()},
+		{"ML-DSA-87", key87.Bytes(), key87.PublicKey().Bytes()},
+	} {
+		ok, err := VerifyBackup(tc.seed, tc.pub)
+		if err != nil || !ok {
+			t.Errorf("%s: VerifyBackup = %v, %v; want true, nil", tc.name, ok, err)
+		}
+
+		wrong := append([]byte(nil), tc.seed...)
+		wrong[0] ^= 1
+		ok, err = VerifyBackup(wrong, tc.pub)
+		if err != nil || ok {
+			t.Errorf("%s: VerifyBackup with wrong seed = %v, %v; want false, nil", tc.name, ok, err)
+		}
+	}
+
+	if _, err := VerifyBackup(key65.Bytes()[:31], key65.PublicKey().Bytes()); err == nil {
+		t.Error("VerifyBackup accepted a short seed")
+	}
+	if _, err := VerifyBackup(key65.Bytes(), key65.PublicKey().Bytes()[:100]); err == nil {
+		t.Error("VerifyBackup accepted a truncated public key")
+	}
+}
+
+func TestVerifyBackupLockedMemoryFailure(t *testing.T) {
+	var keys []PrivateKey
+	for _, p := range ParameterSets() {
+		key, _ := p.Scheme().GenerateKey(rand.Reader)
+		keys = append(keys, key)
+	}
+	failLockedMemory(t)
+	for _, key := range keys {
+		seed, _ := key.Seed()
+		pub := key.Public().(PublicKey).Bytes()
+		if ok, err := VerifyBackup(seed, pub); ok || !errors.Is(err, ErrLockedMemory) {
+			t.Errorf("%s: VerifyBackup = %v, %v; want false, ErrLockedMemory", key.Scheme().Name(), ok, err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package mldsa
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// SigMsg is a signature together with the message and context string it
+// signs, as verified by VerifyMany.
+type SigMsg struct {
+	Signature []byte
+	Message   []byte
+	Context   []byte
+}
+
+// BatchItem is a signature to verify with Key, as part of a batch.
+type BatchItem struct {
+	Key PublicKey
+	SigMsg
+}
+
+// DefaultMinParallel is the smallest batch that a BatchVerifier with a
+// zero MinParallel spreads across goroutines. Below it, the cost of
+// starting goroutines is not repaid.
+const DefaultMinParallel = 4
+
+// BatchVerifier verifies batches of signatures made with any keys, such
+// as bursts of client signatures reaching a gateway. Large batches are
+// sharded across a bounded number of goroutines, including the caller's;
+// small ones are verified on the calling goroutine. Verification stops at
+// the first invalid signature or when the context is canceled.
+//
+// The zero value is ready to use, and a BatchVerifier is safe for
+// concurrent use.
+type BatchVerifier struct {
+	// Workers bounds the goroutines verifying one batch. If zero or
+	// negative, GOMAXPROCS is used.
+	Workers int
+	// MinParallel is the smallest batch verified on more than one
+	// goroutine. If zero, DefaultMinParallel is used.
+	MinParallel int
+}
+
+// Verify reports whether every signature of items is valid. It returns nil
+// if so, a *BatchError for an invalid signature, or the error of ctx if it
+// is done first. With several invalid signatures, which one is reported
+// depends on scheduling.
+func (v *BatchVerifier) Verify(ctx context.Context, items []BatchItem) error {
+	workers := v.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	minParallel := v.MinParallel
+	if minParallel == 0 {
+		minParallel = DefaultMinParallel
+	}
+	workers = min(workers, len(items))
+	if len(items) < minParallel {
+		workers = 1
+	}
+
+	var (
+		next   atomic.Int64 // index of the next item to verify
+		failed atomic.Bool
+		once   sync.Once
+		result error
+	)
+	fail := func(err error) {
+		failed.Store(true)
+		once.Do(func() { result = err })
+	}
+	work := func() {
+		for !failed.Load() {
+			i := int(next.Add(1) - 1)
+			if i >= len(items) {
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				fail(err)
+				return
+			}
+			if err := verifyBatchItem(&items[i]); err != nil {
+				fail(&BatchError{Index: i, Err: err})
+				return
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for w := 1; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work()
+		}()
+	}
+	work()
+	wg.Wait()
+	return result
+}
+
+// verifyBatchItem verifies it, with the reason for failure when the key
+// provides one.
+func verifyBatchItem(it *BatchItem) error {
+	if it.Key == nil {
+		return ErrInvalidPublicKey
+	}
+	if k, ok := it.Key.(interface {
+		VerifyError(sig, message, context []byte) error
+	}); ok {
+		return k.VerifyError(it.Signature, it.Message, it.Context)
+	}
+	if !it.Key.Verify(it.Signature, it.Message, it.Context) {
+		return ErrMismatch
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package mldsa
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestVerifyMany(t *testing.T) {
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		pub := sk.Public().(PublicKey)
+
+		var items []SigMsg
+		for i := 0; i < 4; i++ {
+			msg := []byte(fmt.Sprintf("entry %d", i))
+			ctx := []byte(fmt.Sprintf("log %d", i%2))
+			sig, err := sk.SignWithContext(rand.Reader, msg, ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			items = append(items, SigMsg{Signature: sig, Message: msg, Context: ctx})
+		}
+		forged := bytes.Clone(items[0].Signature)
+		forged[0] ^= 1
+		items = append(items,
+			SigMsg{Signature: forged, Message: items[0].Message, Context: items[0].Context},
+			SigMsg{Signature: items[1].Signature, Message: items[1].Message},
+			SigMsg{Signature: items[2].Signature[1:], Message: items[2].Message, Context: items[2].Context},
+			SigMsg{Signature: items[3].Signature, Message: items[3].Message, Context: make([]byte, 256)},
+		)
+		want := []bool{true, true, true, true, false, false, false, false}
+
+		compact, _ := ParsePublicKeyCompact(pub.Bytes())
+		precomputed, _ := ParsePublicKey(pub.Bytes())
+		precomputed.(interface{ Precompute() }).Precompute()
+		for name, pk := range map[string]PublicKey{"plain": pub, "compact": compact, "precomputed": precomputed} {
+			got := pk.(interface{ VerifyMany([]SigMsg) []bool }).VerifyMany(items)
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("%s %s: VerifyMany = %v, want %v", s.Name(), name, got, want)
+			}
+		}
+	}
+
+	key, _ := GenerateKey44(rand.Reader)
+	if got := key.PublicKey().VerifyMany(nil); len(got) != 0 {
+		t.Errorf("VerifyMany(nil) = %v", got)
+	}
+}
+
+func BenchmarkVerifyMany65(b *testing.B) {
+	key, _ := GenerateKey65(rand.Reader)
+	items := make([]SigMsg, 64)
+	for i := range items {
+		msg := []byte(fmt.Sprintf("entry %d", i))
+		sig, _ := key.Sign(rand.Reader, msg, nil)
+		items[i] = SigMsg{Signature: sig, Message: msg}
+	}
+	pk, _ := NewPublicKey65Compact(key.PublicKey().Bytes())
+	b.Run("Verify", func(b *testing.B) {
+		for b.Loop() {
+			for _, it := range items {
+				pk.Verify(it.Signature, it.Message, it.Context)
+			}
+		}
+	})
+	b.Run("VerifyMany", func(b *testing.B) {
+		for b.Loop() {
+			pk.VerifyMany(items)
+		}
+	})
+}
+
+func TestBatchVerifier(t *testing.T) {
+	var items []BatchItem
+	for i, s := range append(Schemes(), Schemes()...) {
+		sk, _ := s.GenerateKey(rand.Reader)
+		msg := []byte(fmt.Sprintf("request %d", i))
+		sig, _ := sk.SignWithContext(rand.Reader, msg, nil)
+		items = append(items, BatchItem{Key: sk.Public().(PublicKey), SigMsg: SigMsg{Signature: sig, Message: msg}})
+	}
+
+	for _, v := range []*BatchVerifier{{}, {Workers: 1}, {Workers: 3, MinParallel: 2}, {MinParallel: 100}} {
+		if err := v.Verify(context.Background(), items); err != nil {
+			t.Errorf("%+v: valid batch rejected: %v", *v, err)
+		}
+		if err := v.Verify(context.Background(), nil); err != nil {
+			t.Errorf("%+v: empty batch rejected: %v", *v, err)
+		}
+
+		bad := slices.Clone(items)
+		bad[4].Message = []byte("tampered")
+		var be *BatchError
+		if err := v.Verify(context.Background(), bad); !errors.As(err, &be) || be.Index != 4 || !errors.Is(err, ErrMismatch) {
+			t.Errorf("%+v: tampered batch: %v", *v, err)
+		}
+		bad[4].Key = nil
+		if err := v.Verify(context.Background(), bad); !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("%+v: batch with a nil key: %v", *v, err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := v.Verify(ctx, items); err != context.Canceled {
+			t.Errorf("%+v: canceled batch: %v", *v, err)
+		}
+	}
+}
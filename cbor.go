@@ -0,0 +1,447 @@
+package mldsa
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Keys and signatures encode to CBOR as a two-element array holding the
+// COSE algorithm identifier of the parameter set and the encoded bytes:
+//
+//	[alg: int, value: bstr]
+//
+// The encoding is deterministic (RFC 8949, Section 4.2): definite lengths
+// and the shortest form of every argument. Decoding accepts only that form,
+// so every value has exactly one valid encoding. Key pairs encode their
+// seed, standalone private keys their expanded encoding.
+
+// COSE algorithm identifiers of the ML-DSA parameter sets.
+const (
+	coseAlgMLDSA44 = -48
+	coseAlgMLDSA65 = -49
+	coseAlgMLDSA87 = -50
+)
+
+// errInvalidCBOR is returned for input that is not a deterministically
+// encoded ML-DSA CBOR value.
+var errInvalidCBOR = errors.New("mldsa: invalid CBOR encoding")
+
+// CBOR major types used by the encoding.
+const (
+	cborUnsigned = 0
+	cborNegative = 1
+	cborBytes    = 2
+	cborArray    = 4
+)
+
+// coseAlgorithm returns the COSE algorithm identifier for a parameter set
+// name, or 0 if there is none.
+func coseAlgorithm(name string) int64 {
+	switch name {
+	case "ML-DSA-44":
+		return coseAlgMLDSA44
+	case "ML-DSA-65":
+		return coseAlgMLDSA65
+	case "ML-DSA-87":
+		return coseAlgMLDSA87
+	}
+	return 0
+}
+
+// coseAlgorithmName is the inverse of coseAlgorithm.
+func coseAlgorithmName(alg int64) string {
+	switch alg {
+	case coseAlgMLDSA44:
+		return "ML-DSA-44"
+	case coseAlgMLDSA65:
+		return "ML-DSA-65"
+	case coseAlgMLDSA87:
+		return "ML-DSA-87"
+	}
+	return ""
+}
+
+// appendCBORHead appends a CBOR item head using the shortest argument form.
+func appendCBORHead(b []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(b, major|byte(n))
+	case n <= 0xff:
+		return append(b, major|24, byte(n))
+	case n <= 0xffff:
+		return append(b, major|25, byte(n>>8), byte(n))
+	case n <= 0xffffffff:
+		return append(b, major|26, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(b, major|27, byte(n>>56), byte(n>>48), byte(n>>40), byte(n>>32),
+		byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+// readCBORHead parses a CBOR item head, rejecting indefinite lengths and
+// arguments that are not in their shortest form.
+func readCBORHead(b []byte) (major byte, n uint64, rest []byte, err error) {
+	if len(b) == 0 {
+		return 0, 0, nil, errInvalidCBOR
+	}
+	major, info := b[0]>>5, b[0]&0x1f
+	b = b[1:]
+	var size int
+	switch {
+	case info < 24:
+		return major, uint64(info), b, nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, 0, nil, errInvalidCBOR
+	}
+	if len(b) < size {
+		return 0, 0, nil, errInvalidCBOR
+	}
+	for _, c := range b[:size] {
+		n = n<<8 | uint64(c)
+	}
+	// The shortest form would have used a smaller size.
+	if (size == 1 && n < 24) || (size > 1 && n>>(4*size) == 0) {
+		return 0, 0, nil, errInvalidCBOR
+	}
+	return major, n, b[size:], nil
+}
+
+// marshalCBOR encodes b as a CBOR value tagged with the named parameter set.
+func marshalCBOR(name string, b []byte) []byte {
+	alg := coseAlgorithm(name)
+	out := make([]byte, 0, 16+len(b))
+	out = appendCBORHead(out, cborArray, 2)
+	out = appendCBORHead(out, cborNegative, uint64(-1-alg))
+	out = appendCBORHead(out, cborBytes, uint64(len(b)))
+	return append(out, b...)
+}
+
+// unmarshalCBOR decodes a CBOR value produced by marshalCBOR and returns the
+// parameter set name and the encoded bytes.
+func unmarshalCBOR(data []byte) (string, []byte, error) {
+	major, n, rest, err := readCBORHead(data)
+	if err != nil || major != cborArray || n != 2 {
+		return "", nil, errInvalidCBOR
+	}
+	major, n, rest, err = readCBORHead(rest)
+	if err != nil || (major != cborUnsigned && major != cborNegative) {
+		return "", nil, errInvalidCBOR
+	}
+	var alg int64
+	if major == cborNegative {
+		if n > 1<<62 {
+			return "", nil, errInvalidCBOR
+		}
+		alg = -1 - int64(n)
+	} else if n <= 1<<62 {
+		alg = int64(n)
+	}
+	name := coseAlgorithmName(alg)
+	if name == "" {
+		return "", nil, fmt.Errorf("%w: COSE algorithm %d", ErrUnknownParameterSet, alg)
+	}
+	major, n, rest, err = readCBORHead(rest)
+	if err != nil || major != cborBytes || n != uint64(len(rest)) {
+		return "", nil, errInvalidCBOR
+	}
+	return name, rest, nil
+}
+
+// unmarshalCBORFor is like unmarshalCBOR, but fails with a
+// *ParameterSetMismatchError unless the value is tagged with want.
+func unmarshalCBORFor(data []byte, want string) ([]byte, error) {
+	name, b, err := unmarshalCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+	if name != want {
+		return nil, &ParameterSetMismatchError{Got: name, Want: want}
+	}
+	return b, nil
+}
+
+// MarshalCBOR encodes the signature, tagged with the parameter set implied
+// by its length.
+func (s Signature) MarshalCBOR() ([]byte, error) {
+	for _, p := range ParameterSets() {
+		if len(s) == p.SignatureSize() {
+			return marshalCBOR(p.String(), s), nil
+		}
+	}
+	return nil, &LengthError{Err: ErrBadSignatureLength, Got: len(s)}
+}
+
+// UnmarshalCBOR decodes a signature and checks its length against the
+// parameter set it is tagged with.
+func (s *Signature) UnmarshalCBOR(data []byte) error {
+	name, b, err := unmarshalCBOR(data)
+	if err != nil {
+		return err
+	}
+	if want := SchemeByName(name).SignatureSize(); len(b) != want {
+		return &LengthError{Err: ErrBadSignatureLength, Got: len(b), Want: want}
+	}
+	*s = append((*s)[:0], b...)
+	return nil
+}
+
+// MarshalCBOR encodes the public key as deterministic CBOR.
+func (pk *PublicKey44) MarshalCBOR() ([]byte, error) {
+	return marshalCBOR("ML-DSA-44", pk.Bytes()), nil
+}
+
+// UnmarshalCBOR decodes a public key encoded by MarshalCBOR.
+func (pk *PublicKey44) UnmarshalCBOR(data []byte) error {
+	b, err := unmarshalCBORFor(data, "ML-DSA-44")
+	if err != nil {
+		return err
+	}
+	parsed, err := NewPublicKey44(b)
+	if err != nil {
+		return err
+	}
+	pk.set(parsed)
+	return nil
+}
+
+// MarshalCBOR encodes the expanded private key as deterministic CBOR. It
+// returns ErrKeyDestroyed for a destroyed key.
+func (sk *PrivateKey44) MarshalCBOR() ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	b := sk.Bytes()
+	defer clear(b)
+	return marshalCBOR("ML-DSA-44", b), nil
+}
+
+// UnmarshalCBOR decodes a private key encoded by MarshalCBOR. It accepts
+// both a seed and an expanded private key.
+func (sk *PrivateKey44) UnmarshalCBOR(data []byte) error {
+	b, err := unmarshalCBORFor(data, "ML-DSA-44")
+	if err != nil {
+		return err
+	}
+	var parsed *PrivateKey44
+	if len(b) == SeedSize {
+		parsed, err = NewPrivateKey44FromSeed(b)
+	} else {
+		parsed, err = NewPrivateKey44(b)
+	}
+	if err != nil {
+		return err
+	}
+	return sk.set(parsed)
+}
+
+// MarshalCBOR encodes the key pair's seed as deterministic CBOR. It returns
+// ErrKeyDestroyed for a destroyed key.
+func (key *Key44) MarshalCBOR() ([]byte, error) {
+	if key.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return marshalCBOR("ML-DSA-44", key.seed[:]), nil
+}
+
+// UnmarshalCBOR decodes a key pair encoded by MarshalCBOR. Only seeds are
+// accepted.
+func (key *Key44) UnmarshalCBOR(data []byte) error {
+	b, err := unmarshalCBORFor(data, "ML-DSA-44")
+	if err != nil {
+		return err
+	}
+	return key.Regenerate(b)
+}
+
+// MarshalCBOR encodes the public key as deterministic CBOR.
+func (pk *PublicKey65) MarshalCBOR() ([]byte, error) {
+	return marshalCBOR("ML-DSA-65", pk.Bytes()), nil
+}
+
+// UnmarshalCBOR decodes a public key encoded by MarshalCBOR.
+func (pk *PublicKey65) UnmarshalCBOR(data []byte) error {
+	b, err := unmarshalCBORFor(data, "ML-DSA-65")
+	if err != nil {
+		return err
+	}
+	parsed, err := NewPublicKey65(b)
+	if err != nil {
+		return err
+	}
+	pk.set(parsed)
+	return nil
+}
+
+// MarshalCBOR encodes the expanded private key as deterministic CBOR. It
+// returns ErrKeyDestroyed for a destroyed key.
+func (sk *PrivateKey65) MarshalCBOR() ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	b := sk.Bytes()
+	defer clear(b)
+	return marshalCBOR("ML-DSA-65", b), nil
+}
+
+// UnmarshalCBOR decodes a private key encoded by MarshalCBOR. It accepts
+// both a seed and an expanded private key.
+func (sk *PrivateKey65) UnmarshalCBOR(data []byte) error {
+	b, err := unmarshalCBORFor(data, "ML-DSA-65")
+	if err != nil {
+		return err
+	}
+	var parsed *PrivateKey65
+	if len(b) == SeedSize {
+		parsed, err = NewPrivateKey65FromSeed(b)
+	} else {
+		parsed, err = NewPrivateKey65(b)
+	}
+	if err != nil {
+		return err
+	}
+	return sk.set(parsed)
+}
+
+// MarshalCBOR encodes the key pair's seed as deterministic CBOR. It returns
+// ErrKeyDestroyed for a destroyed key.
+func (key *Key65) MarshalCBOR() ([]byte, error) {
+	if key.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return marshalCBOR("ML-DSA-65", key.seed[:]), nil
+}
+
+// UnmarshalCBOR decodes a key pair encoded by MarshalCBOR. Only seeds are
+// accepted.
+func (key *Key65) UnmarshalCBOR(data []byte) error {
+	b, err := unmarshalCBORFor(data, "ML-DSA-65")
+	if err != nil {
+		return err
+	}
+	return key.Regenerate(b)
+}
+
+// MarshalCBOR encodes the public key as deterministic CBOR.
+func (pk *PublicKey87) MarshalCBOR() ([]byte, error) {
+	return marshalCBOR("ML-DSA-87", pk.Bytes()), nil
+}
+
+// UnmarshalCBOR decodes a public key encoded by MarshalCBOR.
+func (pk *PublicKey87) UnmarshalCBOR(data []byte) error {
+	b, err := unmarshalCBORFor(data, "ML-DSA-87")
+	if err != nil {
+		return err
+	}
+	parsed, err := NewPublicKey87(b)
+	if err != nil {
+		return err
+	}
+	pk.set(parsed)
+	return nil
+}
+
+// MarshalCBOR encodes the expanded private key as deterministic CBOR. It
+// returns ErrKeyDestroyed for a destroyed key.
+func (sk *PrivateKey87) MarshalCBOR() ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	b := sk.Bytes()
+	defer clear(b)
+	return marshalCBOR("ML-DSA-87", b), nil
+}
+
+// UnmarshalCBOR decodes a private key encoded by MarshalCBOR. It accepts
+// both a seed and an expanded private key.
+func (sk *PrivateKey87) UnmarshalCBOR(data []byte) error {
+	b, err := unmarshalCBORFor(data, "ML-DSA-87")
+	if err != nil {
+		return err
+	}
+	var parsed *PrivateKey87
+	if len(b) == SeedSize {
+		parsed, err = NewPrivateKey87FromSeed(b)
+	} else {
+		parsed, err = NewPrivateKey87(b)
+	}
+	if err != nil {
+		return err
+	}
+	return sk.set(parsed)
+}
+
+// MarshalCBOR encodes the key pair's seed as deterministic CBOR. It returns
+// ErrKeyDestroyed for a destroyed key.
+func (key *Key87) MarshalCBOR() ([]byte, error) {
+	if key.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return marshalCBOR("ML-DSA-87", key.seed[:]), nil
+}
+
+// UnmarshalCBOR decodes a key pair encoded by MarshalCBOR. Only seeds are
+// accepted.
+func (key *Key87) UnmarshalCBOR(data []byte) error {
+	b, err := unmarshalCBORFor(data, "ML-DSA-87")
+	if err != nil {
+		return err
+	}
+	return key.Regenerate(b)
+}
+
+// MarshalCBOR encodes the wrapped public key as deterministic CBOR.
+func (k AnyPublicKey) MarshalCBOR() ([]byte, error) {
+	if k.PublicKey == nil {
+		return nil, errors.New("mldsa: no public key to encode")
+	}
+	return marshalCBOR(k.Scheme().Name(), k.Bytes()), nil
+}
+
+// UnmarshalCBOR decodes a public key of any parameter set.
+func (k *AnyPublicKey) UnmarshalCBOR(data []byte) error {
+	name, b, err := unmarshalCBOR(data)
+	if err != nil {
+		return err
+	}
+	pk, err := parsePublicKeyFor(name, b)
+	if err != nil {
+		return err
+	}
+	k.PublicKey = pk
+	return nil
+}
+
+// MarshalCBOR encodes the wrapped private key as deterministic CBOR.
+func (k AnyPrivateKey) MarshalCBOR() ([]byte, error) {
+	if k.PrivateKey == nil {
+		return nil, errors.New("mldsa: no private key to encode")
+	}
+	b, err := privateKeyEncoding(k.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(b)
+	return marshalCBOR(k.Scheme().Name(), b), nil
+}
+
+// UnmarshalCBOR decodes a private key or key pair of any parameter set.
+func (k *AnyPrivateKey) UnmarshalCBOR(data []byte) error {
+	name, b, err := unmarshalCBOR(data)
+	if err != nil {
+		return err
+	}
+	sk, err := parsePrivateKeyFor(name, b)
+	if err != nil {
+		return err
+	}
+	k.PrivateKey = sk
+	return nil
+}
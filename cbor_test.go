@@ -0,0 +1,88 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestCBORKeys(t *testing.T) {
+	key, _ := GenerateKey44(rand.Reader)
+
+	data, err := key.PublicKey().MarshalCBOR()
+	if err != nil {
+		t.Fatalf("MarshalCBOR failed: %v", err)
+	}
+	// array(2), nint(-48), bstr(1312)
+	if head := []byte{0x82, 0x38, 0x2f, 0x59, 0x05, 0x20}; !bytes.HasPrefix(data, head) {
+		t.Errorf("unexpected encoding prefix %x", data[:len(head)])
+	}
+	var pk PublicKey44
+	if err := pk.UnmarshalCBOR(data); err != nil || !pk.Equal(key.PublicKey()) {
+		t.Errorf("public key did not roundtrip: %v", err)
+	}
+	var mismatch *ParameterSetMismatchError
+	var pk87 PublicKey87
+	if err := pk87.UnmarshalCBOR(data); !errors.As(err, &mismatch) {
+		t.Errorf("decoding ML-DSA-44 as ML-DSA-87: %v", err)
+	}
+
+	// Non-shortest argument for the algorithm and trailing data are rejected.
+	bad := append([]byte{0x82, 0x39, 0x00, 0x2f}, data[3:]...)
+	if err := pk.UnmarshalCBOR(bad); err == nil {
+		t.Error("UnmarshalCBOR accepted a non-deterministic encoding")
+	}
+	if err := pk.UnmarshalCBOR(append(bytes.Clone(data), 0)); err == nil {
+		t.Error("UnmarshalCBOR accepted trailing data")
+	}
+
+	data, _ = key.MarshalCBOR()
+	var key2 Key44
+	if err := key2.UnmarshalCBOR(data); err != nil || !key2.Equal(key) {
+		t.Errorf("key pair did not roundtrip: %v", err)
+	}
+	data, _ = key.PrivateKey44.MarshalCBOR()
+	var sk PrivateKey44
+	if err := sk.UnmarshalCBOR(data); err != nil || !sk.Equal(key) {
+		t.Errorf("private key did not roundtrip: %v", err)
+	}
+}
+
+func TestCBORAnyKeyAndSignature(t *testing.T) {
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		data, err := AnyPrivateKey{sk}.MarshalCBOR()
+		if err != nil {
+			t.Fatalf("%s: MarshalCBOR failed: %v", s.Name(), err)
+		}
+		var anySK AnyPrivateKey
+		if err := anySK.UnmarshalCBOR(data); err != nil || anySK.Scheme().Name() != s.Name() {
+			t.Fatalf("%s: private key did not roundtrip: %v", s.Name(), err)
+		}
+		p, _ := ParseParameterSet(s.Name())
+		seed, _ := sk.Seed()
+		seedKey, _ := NewSeedKey(p, seed)
+		if got, err := (AnyPrivateKey{seedKey}).MarshalCBOR(); err != nil || !bytes.Equal(got, data) {
+			t.Errorf("%s: seed key encoding differs from the key pair's: %v", s.Name(), err)
+		}
+		data, _ = AnyPublicKey{sk.Public().(PublicKey)}.MarshalCBOR()
+		var anyPK AnyPublicKey
+		if err := anyPK.UnmarshalCBOR(data); err != nil || !anyPK.Equal(sk.Public()) {
+			t.Fatalf("%s: public key did not roundtrip: %v", s.Name(), err)
+		}
+
+		sig, _ := anySK.SignWithContext(rand.Reader, []byte("m"), nil)
+		data, err = Signature(sig).MarshalCBOR()
+		if err != nil {
+			t.Fatalf("%s: Signature.MarshalCBOR failed: %v", s.Name(), err)
+		}
+		var decoded Signature
+		if err := decoded.UnmarshalCBOR(data); err != nil || !anyPK.Verify(decoded, []byte("m"), nil) {
+			t.Errorf("%s: signature did not roundtrip: %v", s.Name(), err)
+		}
+	}
+	if _, err := Signature(make([]byte, 10)).MarshalCBOR(); !errors.Is(err, ErrBadSignatureLength) {
+		t.Errorf("MarshalCBOR with a bad signature length: %v", err)
+	}
+}
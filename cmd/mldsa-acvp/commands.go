@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/asn1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/KarpelesLab/mldsa"
+	"github.com/KarpelesLab/mldsa/conformance"
+)
+
+// The ML-DSA commands, one set per parameter set, e.g. "ML-DSA-65/sigGen":
+//
+//	keyGen  seed                                                          -> pk, sk
+//	sigGen  sk, message, rnd, context, mu [, interface [, hashAlg]]       -> signature
+//	sigVer  pk, message, signature, context, mu [, interface [, hashAlg]] -> {1} or {0}
+//
+// The first five arguments of sigGen and sigVer follow the layout used by
+// the Go and BoringSSL wrappers. An empty rnd selects deterministic signing,
+// and a non-empty mu selects the external µ variant, in which case message
+// and context are ignored. interface is "external" (the default) or
+// "internal", where message is the message representative M' of
+// ML-DSA.Sign_internal and context is ignored. hashAlg is empty or "pure"
+// for ML-DSA and an ACVP hash name, such as "SHA2-256", for HashML-DSA.
+
+// command is a request handler accepting minArgs to maxArgs arguments.
+type command struct {
+	minArgs, maxArgs int
+	handler          func(args [][]byte) ([][]byte, error)
+}
+
+var commands = map[string]command{
+	"getConfig": {0, 0, func([][]byte) ([][]byte, error) { return [][]byte{config}, nil }},
+}
+
+func init() {
+	for _, p := range mldsa.ParameterSets() {
+		commands[p.String()+"/keyGen"] = command{1, 1, func(args [][]byte) ([][]byte, error) { return keyGen(p, args) }}
+		commands[p.String()+"/sigGen"] = command{5, 7, func(args [][]byte) ([][]byte, error) { return sigGen(p, args) }}
+		commands[p.String()+"/sigVer"] = command{5, 7, func(args [][]byte) ([][]byte, error) { return sigVer(p, args) }}
+	}
+}
+
+// hashAlgs maps ACVP hash names to the pre-hash functions of HashML-DSA.
+var hashAlgs = map[string]crypto.Hash{
+	"SHA2-224":     crypto.SHA224,
+	"SHA2-256":     crypto.SHA256,
+	"SHA2-384":     crypto.SHA384,
+	"SHA2-512":     crypto.SHA512,
+	"SHA2-512/224": crypto.SHA512_224,
+	"SHA2-512/256": crypto.SHA512_256,
+	"SHA3-224":     crypto.SHA3_224,
+	"SHA3-256":     crypto.SHA3_256,
+	"SHA3-384":     crypto.SHA3_384,
+	"SHA3-512":     crypto.SHA3_512,
+	"SHAKE-128":    mldsa.SHAKE128,
+	"SHAKE-256":    mldsa.SHAKE256,
+}
+
+// config is the ACVP capabilities registration returned by getConfig.
+var config = func() []byte {
+	var parameterSets, hashNames []string
+	for _, p := range mldsa.ParameterSets() {
+		parameterSets = append(parameterSets, p.String())
+	}
+	for name := range hashAlgs {
+		hashNames = append(hashNames, name)
+	}
+	slices.Sort(hashNames)
+	capabilities := []map[string]any{{
+		"parameterSets": parameterSets,
+		"messageLength": []map[string]int{{"min": 8, "max": 65536, "increment": 8}},
+		"contextLength": []map[string]int{{"min": 0, "max": 2040, "increment": 8}},
+		"hashAlgs":      hashNames,
+	}}
+	b, err := json.Marshal([]map[string]any{
+		{
+			"algorithm":     "ML-DSA",
+			"mode":          "keyGen",
+			"revision":      "FIPS204",
+			"parameterSets": parameterSets,
+		},
+		{
+			"algorithm":           "ML-DSA",
+			"mode":                "sigGen",
+			"revision":            "FIPS204",
+			"signatureInterfaces": []string{"internal", "external"},
+			"preHash":             []string{"pure", "preHash"},
+			"deterministic":       []bool{true, false},
+			"externalMu":          []bool{true, false},
+			"capabilities":        capabilities,
+		},
+		{
+			"algorithm":           "ML-DSA",
+			"mode":                "sigVer",
+			"revision":            "FIPS204",
+			"signatureInterfaces": []string{"internal", "external"},
+			"preHash":             []string{"pure", "preHash"},
+			"externalMu":          []bool{true, false},
+			"capabilities":        capabilities,
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return b
+}()
+
+func keyGen(p mldsa.ParameterSet, args [][]byte) ([][]byte, error) {
+	sk, err := p.Scheme().NewKeyFromSeed(args[0])
+	if err != nil {
+		return nil, err
+	}
+	key := sk.(interface {
+		PrivateKeyBytes() []byte
+		Destroy()
+	})
+	defer key.Destroy()
+	return [][]byte{sk.Public().(mldsa.PublicKey).Bytes(), key.PrivateKeyBytes()}, nil
+}
+
+func sigGen(p mldsa.ParameterSet, args [][]byte) ([][]byte, error) {
+	skBytes, message, rnd, context, mu := args[0], args[1], args[2], args[3], args[4]
+	sk, err := p.Scheme().UnmarshalPrivateKey(skBytes)
+	if err != nil {
+		return nil, err
+	}
+	defer sk.(interface{ Destroy() }).Destroy()
+	switch len(rnd) {
+	case 0:
+		rnd = make([]byte, conformance.RandomnessSize)
+	case conformance.RandomnessSize:
+	default:
+		return nil, fmt.Errorf("rnd of %d bytes", len(rnd))
+	}
+
+	var sig []byte
+	if len(mu) != 0 {
+		sig, err = signMu(sk, rnd, mu)
+	} else {
+		var mPrime []byte
+		mPrime, err = messageRepresentative(message, context, args[5:])
+		if err == nil {
+			sig, err = conformance.SignInternal(sk, rnd, mPrime)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{sig}, nil
+}
+
+func sigVer(p mldsa.ParameterSet, args [][]byte) ([][]byte, error) {
+	pkBytes, message, sig, context, mu := args[0], args[1], args[2], args[3], args[4]
+	pk, err := p.Scheme().UnmarshalPublicKey(pkBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	if len(mu) != 0 {
+		ok, err = verifyMu(pk, sig, mu)
+	} else {
+		var mPrime []byte
+		mPrime, err = messageRepresentative(message, context, args[5:])
+		ok = err == nil && conformance.VerifyInternal(pk, sig, mPrime) == nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return [][]byte{{1}}, nil
+	}
+	return [][]byte{{0}}, nil
+}
+
+// messageRepresentative returns M' for message under the optional interface
+// and hashAlg arguments in opts: message itself for the internal interface,
+// and the pure or HashML-DSA encoding with context for the external one.
+func messageRepresentative(message, context []byte, opts [][]byte) ([]byte, error) {
+	iface, hashAlg := "external", "pure"
+	if len(opts) > 0 && len(opts[0]) > 0 {
+		iface = string(opts[0])
+	}
+	if len(opts) > 1 && len(opts[1]) > 0 {
+		hashAlg = string(opts[1])
+	}
+
+	switch iface {
+	case "internal":
+		if hashAlg != "pure" {
+			return nil, errors.New("the internal interface does not pre-hash")
+		}
+		return message, nil
+	case "external":
+	default:
+		return nil, fmt.Errorf("unknown signature interface %q", iface)
+	}
+	if len(context) > 255 {
+		return nil, mldsa.ErrContextTooLong
+	}
+	if hashAlg == "pure" {
+		mPrime := append([]byte{0, byte(len(context))}, context...)
+		return append(mPrime, message...), nil
+	}
+
+	h, ok := hashAlgs[hashAlg]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash algorithm %q", hashAlg)
+	}
+	oid, _ := mldsa.PreHashOID(h)
+	der, err := asn1.Marshal(oid)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := mldsa.PreHash(h, message)
+	if err != nil {
+		return nil, err
+	}
+	mPrime := append([]byte{1, byte(len(context))}, context...)
+	mPrime = append(mPrime, der...)
+	return append(mPrime, digest...), nil
+}
+
+// signMu signs the external message representative µ with the randomness
+// rnd.
+func signMu(sk mldsa.PrivateKey, rnd, mu []byte) ([]byte, error) {
+	if len(mu) != mldsa.MuSize {
+		return nil, fmt.Errorf("µ of %d bytes", len(mu))
+	}
+	var r io.Reader = bytes.NewReader(rnd)
+	if bytes.Equal(rnd, make([]byte, len(rnd))) {
+		r = mldsa.DeterministicRand
+	}
+	return sk.(interface {
+		SignMu(rand io.Reader, mu [mldsa.MuSize]byte) ([]byte, error)
+	}).SignMu(r, [mldsa.MuSize]byte(mu))
+}
+
+// verifyMu verifies sig on the external message representative µ.
+func verifyMu(pk mldsa.PublicKey, sig, mu []byte) (bool, error) {
+	if len(mu) != mldsa.MuSize {
+		return false, fmt.Errorf("µ of %d bytes", len(mu))
+	}
+	return pk.(interface {
+		VerifyMu(sig []byte, mu [mldsa.MuSize]byte) bool
+	}).VerifyMu(sig, [mldsa.MuSize]byte(mu)), nil
+}
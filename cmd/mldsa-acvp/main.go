@@ -0,0 +1,126 @@
+// Command mldsa-acvp is an ACVP module wrapper for the mldsa package. It
+// speaks the protocol of BoringSSL's acvptool on standard input and output,
+// so that testing laboratories can run live ACVP sessions, or replay stored
+// vector sets, against this implementation:
+//
+//	acvptool -json vectors.json -wrapper mldsa-acvp
+//
+// Each request is a little-endian uint32 argument count, one uint32 length
+// per argument and the concatenated arguments; the first argument names the
+// command. Replies use the same framing without a command name. getConfig
+// returns the ACVP capabilities registration, and the ML-DSA commands are
+// described in commands.go.
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	// maxArgs and maxArgLength bound a request, so that a corrupted stream
+	// cannot make the wrapper allocate without limit.
+	maxArgs      = 9
+	maxArgLength = 1 << 20
+)
+
+func main() {
+	if len(os.Args) > 1 {
+		fmt.Fprintln(os.Stderr, "usage: mldsa-acvp (run by acvptool, speaks on standard input and output)")
+		os.Exit(2)
+	}
+	if err := serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "mldsa-acvp:", err)
+		os.Exit(1)
+	}
+}
+
+// serve answers requests read from r until it reaches the end of its input.
+func serve(r io.Reader, w io.Writer) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	for {
+		args, err := readRequest(br)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := string(args[0])
+		cmd, ok := commands[name]
+		if !ok {
+			return fmt.Errorf("unknown command %q", name)
+		}
+		if n := len(args) - 1; n < cmd.minArgs || n > cmd.maxArgs {
+			return fmt.Errorf("%s: got %d arguments, want %d to %d", name, n, cmd.minArgs, cmd.maxArgs)
+		}
+		reply, err := cmd.handler(args[1:])
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if err := writeReply(bw, reply); err != nil {
+			return err
+		}
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// readRequest reads one request. It returns io.EOF if r ends cleanly
+// before the request.
+func readRequest(r io.Reader) ([][]byte, error) {
+	var n uint32
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return nil, err
+	}
+	if n == 0 || n > maxArgs {
+		return nil, fmt.Errorf("request with %d arguments", n)
+	}
+	lengths := make([]uint32, n)
+	if err := binary.Read(r, binary.LittleEndian, lengths); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	args := make([][]byte, n)
+	for i, l := range lengths {
+		if l > maxArgLength {
+			return nil, fmt.Errorf("argument of %d bytes", l)
+		}
+		args[i] = make([]byte, l)
+		if _, err := io.ReadFull(r, args[i]); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+	}
+	return args, nil
+}
+
+// unexpectedEOF turns io.EOF within a request into io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// writeReply writes the framed reply values to w.
+func writeReply(w io.Writer, values [][]byte) error {
+	header := make([]uint32, 1+len(values))
+	header[0] = uint32(len(values))
+	for i, v := range values {
+		header[1+i] = uint32(len(v))
+	}
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	for _, v := range values {
+		if _, err := w.Write(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+	"github.com/KarpelesLab/mldsa/mldsatest"
+)
+
+// call sends a single request to the wrapper and returns the reply.
+func call(t *testing.T, args ...[]byte) [][]byte {
+	t.Helper()
+	var in, out bytes.Buffer
+	if err := writeReply(&in, args); err != nil {
+		t.Fatal(err)
+	}
+	if err := serve(&in, &out); err != nil {
+		t.Fatalf("%s: %v", args[0], err)
+	}
+	reply, err := readRequest(&out)
+	if err != nil {
+		t.Fatalf("%s: reading reply: %v", args[0], err)
+	}
+	if _, err := readRequest(&out); err != io.EOF {
+		t.Fatalf("%s: more than one reply", args[0])
+	}
+	return reply
+}
+
+func TestGetConfig(t *testing.T) {
+	reply := call(t, []byte("getConfig"))
+	var config []struct {
+		Algorithm string `json:"algorithm"`
+		Mode      string `json:"mode"`
+	}
+	if len(reply) != 1 || json.Unmarshal(reply[0], &config) != nil || len(config) != 3 {
+		t.Fatalf("getConfig returned %q", reply)
+	}
+	for _, c := range config {
+		if _, ok := commands["ML-DSA-65/"+c.Mode]; c.Algorithm != "ML-DSA" || !ok {
+			t.Errorf("unexpected capability %+v", c)
+		}
+	}
+}
+
+func TestKeyGen(t *testing.T) {
+	for _, f := range mldsatest.Fixtures() {
+		reply := call(t, []byte(f.ParameterSet+"/keyGen"), f.Seed)
+		if len(reply) != 2 || !bytes.Equal(reply[0], f.PublicKey) || !bytes.Equal(reply[1], f.PrivateKey) {
+			t.Errorf("%s: keyGen does not match the fixture", f.ParameterSet)
+		}
+	}
+}
+
+func TestSigGenSigVer(t *testing.T) {
+	var none []byte
+	rnd := bytes.Repeat([]byte{7}, 32)
+	digest, _ := mldsa.PreHash(crypto.SHA256, mldsatest.Message)
+	for _, f := range mldsatest.Fixtures() {
+		sigGen, sigVer := []byte(f.ParameterSet+"/sigGen"), []byte(f.ParameterSet+"/sigVer")
+		sk, _ := mldsa.SchemeByName(f.ParameterSet).NewKeyFromSeed(f.Seed)
+		pk := sk.Public().(mldsa.PublicKey)
+		mu, _ := pk.(interface {
+			ComputeMu(message, context []byte) ([mldsa.MuSize]byte, error)
+		}).ComputeMu(f.Message, f.Context)
+		mPrime := append([]byte{0, byte(len(f.Context))}, f.Context...)
+		mPrime = append(mPrime, f.Message...)
+
+		// Each variant as [message, rnd, context, mu, options...], with a
+		// check of the signature against the library.
+		for _, tc := range []struct {
+			name  string
+			args  [][]byte
+			check func(sig []byte) bool
+		}{
+			{"deterministic", [][]byte{f.Message, none, f.Context, none}, func(sig []byte) bool {
+				return bytes.Equal(sig, f.Signature)
+			}},
+			{"internal", [][]byte{mPrime, none, none, none, []byte("internal")}, func(sig []byte) bool {
+				return bytes.Equal(sig, f.Signature)
+			}},
+			{"external mu", [][]byte{none, none, none, mu[:]}, func(sig []byte) bool {
+				return bytes.Equal(sig, f.Signature)
+			}},
+			{"hedged", [][]byte{f.Message, rnd, f.Context, none, []byte("external")}, func(sig []byte) bool {
+				return !bytes.Equal(sig, f.Signature) && pk.Verify(sig, f.Message, f.Context)
+			}},
+			{"hedged external mu", [][]byte{none, rnd, none, mu[:]}, func(sig []byte) bool {
+				return pk.Verify(sig, f.Message, f.Context)
+			}},
+			{"pre-hash", [][]byte{f.Message, none, f.Context, none, none, []byte("SHA2-256")}, func(sig []byte) bool {
+				want, _ := sk.(interface {
+					SignPreHash(rand io.Reader, digest []byte, ph crypto.Hash, context []byte) ([]byte, error)
+				}).SignPreHash(mldsa.DeterministicRand, digest, crypto.SHA256, f.Context)
+				return bytes.Equal(sig, want)
+			}},
+		} {
+			reply := call(t, append([][]byte{sigGen, f.PrivateKey}, tc.args...)...)
+			if len(reply) != 1 || !tc.check(reply[0]) {
+				t.Errorf("%s: sigGen %s: wrong signature", f.ParameterSet, tc.name)
+				continue
+			}
+
+			// sigVer takes the signature in place of rnd.
+			args := append([][]byte{sigVer, f.PublicKey}, tc.args...)
+			args[3] = reply[0]
+			if got := call(t, args...); len(got) != 1 || !bytes.Equal(got[0], []byte{1}) {
+				t.Errorf("%s: sigVer %s rejected the signature", f.ParameterSet, tc.name)
+			}
+			args[3] = bytes.Clone(reply[0])
+			args[3][0] ^= 1
+			if got := call(t, args...); len(got) != 1 || !bytes.Equal(got[0], []byte{0}) {
+				t.Errorf("%s: sigVer %s accepted a corrupted signature", f.ParameterSet, tc.name)
+			}
+		}
+	}
+}
+
+func TestServeErrors(t *testing.T) {
+	f := mldsatest.Fixtures()[0]
+	for _, args := range [][][]byte{
+		{[]byte("ML-DSA-44/frobnicate")},
+		{[]byte("ML-DSA-44/keyGen")},
+		{[]byte("ML-DSA-44/keyGen"), make([]byte, 31)},
+		{[]byte("ML-DSA-44/sigGen"), f.PrivateKey, f.Message, make([]byte, 31), nil, nil},
+		{[]byte("ML-DSA-44/sigGen"), f.PrivateKey, f.Message, nil, nil, nil, []byte("internal"), []byte("SHA2-256")},
+		{[]byte("ML-DSA-44/sigGen"), f.PrivateKey, f.Message, nil, nil, nil, nil, []byte("MD5")},
+		{[]byte("ML-DSA-65/sigGen"), f.PrivateKey, f.Message, nil, nil, nil},
+	} {
+		var in bytes.Buffer
+		writeReply(&in, args)
+		if err := serve(&in, io.Discard); err == nil {
+			t.Errorf("%q: no error", args)
+		}
+	}
+
+	// A request cut short is an error; no request at all is not.
+	var in bytes.Buffer
+	writeReply(&in, [][]byte{[]byte("getConfig")})
+	if err := serve(bytes.NewReader(in.Bytes()[:in.Len()-1]), io.Discard); err != io.ErrUnexpectedEOF {
+		t.Errorf("truncated request: got %v, want io.ErrUnexpectedEOF", err)
+	}
+	if err := serve(bytes.NewReader(nil), io.Discard); err != nil {
+		t.Errorf("empty input: %v", err)
+	}
+}
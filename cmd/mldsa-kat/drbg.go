@@ -0,0 +1,61 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+)
+
+// drbg is the AES-256 CTR_DRBG without derivation function of the NIST PQC
+// submission harness (rng.c), which the reference implementations use to
+// derive their known-answer tests from a 48-byte seed. It exists only to
+// reproduce those vectors and is not a source of secure randomness.
+type drbg struct {
+	key [32]byte
+	v   [16]byte
+}
+
+// newDRBG returns the DRBG instantiated with the 48-byte seed, as
+// randombytes_init does without a personalization string.
+func newDRBG(seed []byte) *drbg {
+	d := &drbg{}
+	d.update(seed)
+	return d
+}
+
+// block encrypts the incremented counter V into out.
+func (d *drbg) block(b cipher.Block, out []byte) {
+	for j := len(d.v) - 1; j >= 0; j-- {
+		d.v[j]++
+		if d.v[j] != 0 {
+			break
+		}
+	}
+	b.Encrypt(out, d.v[:])
+}
+
+// update is AES256_CTR_DRBG_Update, with provided nil or 48 bytes long.
+func (d *drbg) update(provided []byte) {
+	b, _ := aes.NewCipher(d.key[:])
+	var temp [48]byte
+	for i := 0; i < 3; i++ {
+		d.block(b, temp[16*i:])
+	}
+	for i := range provided {
+		temp[i] ^= provided[i]
+	}
+	copy(d.key[:], temp[:32])
+	copy(d.v[:], temp[32:])
+}
+
+// Read fills p as a single call to randombytes does. The output depends on
+// how it is split into calls, so callers must read each value at once.
+func (d *drbg) Read(p []byte) (int, error) {
+	b, _ := aes.NewCipher(d.key[:])
+	var buf [16]byte
+	for i := 0; i < len(p); i += 16 {
+		d.block(b, buf[:])
+		copy(p[i:], buf[:])
+	}
+	d.update(nil)
+	return len(p), nil
+}
@@ -0,0 +1,288 @@
+// Command mldsa-kat generates and checks known-answer test files in the
+// response format of the NIST PQC submission harness (PQCgenKAT_sign.c), so
+// that this implementation can be cross-checked against the reference C
+// implementations and fixtures regenerated when an encoding is questioned:
+//
+//	mldsa-kat [-alg ML-DSA-65] [-count 100] [-deterministic] [-out PQCsignKAT.rsp]
+//	mldsa-kat [-alg set] [-deterministic] -check PQCsignKAT.rsp
+//
+// Generation follows the harness. The AES-256 CTR_DRBG of rng.c, seeded
+// with the bytes 0 to 47, draws for each count i a 48-byte seed and a
+// message of 33(i+1) bytes. The DRBG is then re-seeded with seed: the key
+// pair is derived from the first 32 bytes it produces, and the message is
+// signed with an empty context and the next 32 bytes as rnd, or an all-zero
+// rnd with -deterministic. sm is the signature followed by the message, as
+// returned by crypto_sign.
+//
+// -check regenerates every entry of a response file from its seed and
+// message and reports those whose pk, sk or sm differ. The parameter set is
+// read from the "# ML-DSA-65" header unless -alg is given.
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// vector is one entry of a response file.
+type vector struct {
+	count                 int
+	seed, msg, pk, sk, sm []byte
+}
+
+func main() {
+	alg := flag.String("alg", "", "parameter set: ML-DSA-44, ML-DSA-65 or ML-DSA-87 (default ML-DSA-65, or the header of the -check file)")
+	count := flag.Int("count", 100, "number of vectors to generate")
+	deterministic := flag.Bool("deterministic", false, "sign with an all-zero rnd")
+	out := flag.String("out", "", "write the response file to this file instead of standard output")
+	check := flag.String("check", "", "check the response `file` instead of generating one")
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var err error
+	if *check != "" {
+		err = checkFile(*check, *alg, *deterministic, os.Stdout)
+	} else {
+		err = generateFile(*out, *alg, *count, *deterministic)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "mldsa-kat:", err)
+		os.Exit(1)
+	}
+}
+
+// generateFile writes count vectors to path, or to standard output.
+func generateFile(path, alg string, count int, deterministic bool) error {
+	if alg == "" {
+		alg = "ML-DSA-65"
+	}
+	p, err := mldsa.ParseParameterSet(alg)
+	if err != nil {
+		return err
+	}
+	w := io.Writer(os.Stdout)
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	if err := generate(bw, p, count, deterministic); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// generate writes a response file of count vectors for p to w.
+func generate(w io.Writer, p mldsa.ParameterSet, count int, deterministic bool) error {
+	var entropy [48]byte
+	for i := range entropy {
+		entropy[i] = byte(i)
+	}
+	d := newDRBG(entropy[:])
+
+	fmt.Fprintf(w, "# %s\n\n", p)
+	for i := 0; i < count; i++ {
+		seed := make([]byte, 48)
+		d.Read(seed)
+		msg := make([]byte, 33*(i+1))
+		d.Read(msg)
+		v, err := newVector(p, i, seed, msg, deterministic)
+		if err != nil {
+			return fmt.Errorf("count = %d: %w", i, err)
+		}
+		if err := writeVector(w, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newVector derives the key pair and signature of an entry from its seed
+// and message.
+func newVector(p mldsa.ParameterSet, count int, seed, msg []byte, deterministic bool) (*vector, error) {
+	d := newDRBG(seed)
+	xi := make([]byte, mldsa.SeedSize)
+	d.Read(xi)
+	sk, err := p.Scheme().NewKeyFromSeed(xi)
+	if err != nil {
+		return nil, err
+	}
+	key := sk.(interface {
+		PrivateKeyBytes() []byte
+		Destroy()
+	})
+	defer key.Destroy()
+
+	rand := io.Reader(d)
+	if deterministic {
+		rand = mldsa.DeterministicRand
+	}
+	sig, err := sk.SignWithContext(rand, msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &vector{
+		count: count,
+		seed:  seed,
+		msg:   msg,
+		pk:    sk.Public().(mldsa.PublicKey).Bytes(),
+		sk:    key.PrivateKeyBytes(),
+		sm:    append(sig, msg...),
+	}, nil
+}
+
+// writeVector writes v in the format of PQCgenKAT_sign.c.
+func writeVector(w io.Writer, v *vector) error {
+	_, err := fmt.Fprintf(w, "count = %d\nseed = %X\nmlen = %d\nmsg = %X\npk = %X\nsk = %X\nsmlen = %d\nsm = %X\n\n",
+		v.count, v.seed, len(v.msg), v.msg, v.pk, v.sk, len(v.sm), v.sm)
+	return err
+}
+
+// checkFile regenerates the vectors of the response file at path and
+// reports any difference to w.
+func checkFile(path, alg string, deterministic bool, w io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	name, vectors, err := parseResponses(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if alg == "" {
+		alg = name
+	}
+	p, err := mldsa.ParseParameterSet(alg)
+	if err != nil {
+		return fmt.Errorf("%s: %w (use -alg)", path, err)
+	}
+
+	failed := 0
+	for _, want := range vectors {
+		got, err := newVector(p, want.count, want.seed, want.msg, deterministic)
+		if err != nil {
+			return fmt.Errorf("count = %d: %w", want.count, err)
+		}
+		if diff := compare(p, got, want); diff != "" {
+			fmt.Fprintf(w, "count = %d: %s\n", want.count, diff)
+			failed++
+		}
+	}
+	fmt.Fprintf(w, "%s: %d of %d vectors match\n", p, len(vectors)-failed, len(vectors))
+	if failed > 0 {
+		return fmt.Errorf("%d vectors differ", failed)
+	}
+	return nil
+}
+
+// compare describes how the regenerated vector got differs from want, or
+// returns "" if they match.
+func compare(p mldsa.ParameterSet, got, want *vector) string {
+	var diffs []string
+	if string(got.pk) != string(want.pk) {
+		diffs = append(diffs, "pk differs")
+	}
+	if string(got.sk) != string(want.sk) {
+		diffs = append(diffs, "sk differs")
+	}
+	if string(got.sm) != string(want.sm) {
+		// A signature made with other randomness can still be valid.
+		valid := "invalid"
+		if pk, err := p.Scheme().UnmarshalPublicKey(want.pk); err == nil &&
+			len(want.sm) == p.SignatureSize()+len(want.msg) &&
+			string(want.sm[p.SignatureSize():]) == string(want.msg) &&
+			pk.Verify(want.sm[:p.SignatureSize()], want.msg, nil) {
+			valid = "valid"
+		}
+		diffs = append(diffs, "sm differs (the expected signature is "+valid+")")
+	}
+	return strings.Join(diffs, ", ")
+}
+
+// parseResponses parses a response file, returning the parameter set name
+// of its header and its entries.
+func parseResponses(r io.Reader) (string, []*vector, error) {
+	var name string
+	var vectors []*vector
+	var cur *vector
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<24)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(text, "#"); ok {
+			if name == "" {
+				name = strings.TrimSpace(rest)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return "", nil, fmt.Errorf("line %d: missing '='", line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if key == "count" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			cur = &vector{count: n}
+			vectors = append(vectors, cur)
+			continue
+		}
+		if cur == nil {
+			return "", nil, fmt.Errorf("line %d: %s before the first count", line, key)
+		}
+		var field *[]byte
+		switch key {
+		case "seed":
+			field = &cur.seed
+		case "msg":
+			field = &cur.msg
+		case "pk":
+			field = &cur.pk
+		case "sk":
+			field = &cur.sk
+		case "sm":
+			field = &cur.sm
+		default:
+			continue // mlen and smlen are implied by the data
+		}
+		b, err := hex.DecodeString(value)
+		if err != nil {
+			return "", nil, fmt.Errorf("line %d: %s: %w", line, key, err)
+		}
+		*field = b
+	}
+	if err := sc.Err(); err != nil {
+		return "", nil, err
+	}
+	for _, v := range vectors {
+		if len(v.seed) != 48 {
+			return "", nil, fmt.Errorf("count = %d: seed of %d bytes, want 48", v.count, len(v.seed))
+		}
+	}
+	if len(vectors) == 0 {
+		return "", nil, errors.New("no vectors")
+	}
+	return name, vectors, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestDRBG(t *testing.T) {
+	// The first seed and message of every NIST PQC KAT file.
+	var entropy [48]byte
+	for i := range entropy {
+		entropy[i] = byte(i)
+	}
+	d := newDRBG(entropy[:])
+	seed, msg := make([]byte, 48), make([]byte, 33)
+	d.Read(seed)
+	d.Read(msg)
+	if got, want := hex.EncodeToString(seed), "061550234d158c5ec95595fe04ef7a25767f2e24cc2bc479d09d86dc9abcfde7056a8c266f9ef97ed08541dbd2e1ffa1"; got != want {
+		t.Errorf("seed = %s, want %s", got, want)
+	}
+	if got, want := hex.EncodeToString(msg), "d81c4d8d734fcbfbeade3d3f8a039faa2a2c9957e835ad55b22e75bf57bb556ac8"; got != want {
+		t.Errorf("msg = %s, want %s", got, want)
+	}
+}
+
+func TestGenerateAndCheck(t *testing.T) {
+	dir := t.TempDir()
+	for _, p := range mldsa.ParameterSets() {
+		var buf bytes.Buffer
+		if err := generate(&buf, p, 3, false); err != nil {
+			t.Fatal(err)
+		}
+		name, vectors, err := parseResponses(bytes.NewReader(buf.Bytes()))
+		if err != nil || name != p.String() || len(vectors) != 3 {
+			t.Fatalf("%s: parseResponses = %q, %d vectors, %v", p, name, len(vectors), err)
+		}
+		for _, v := range vectors {
+			sig := v.sm[:p.SignatureSize()]
+			pk, _ := p.Scheme().UnmarshalPublicKey(v.pk)
+			if len(v.msg) != 33*(v.count+1) || !pk.Verify(sig, v.msg, nil) {
+				t.Errorf("%s: count = %d: invalid vector", p, v.count)
+			}
+		}
+
+		path := filepath.Join(dir, p.String()+".rsp")
+		os.WriteFile(path, buf.Bytes(), 0o644)
+		var report strings.Builder
+		if err := checkFile(path, "", false, &report); err != nil {
+			t.Errorf("%s: checkFile: %v\n%s", p, err, report.String())
+		}
+
+		// Deterministic signatures differ but are still valid.
+		report.Reset()
+		if err := checkFile(path, "", true, &report); err == nil ||
+			!strings.Contains(report.String(), "sm differs (the expected signature is valid)") {
+			t.Errorf("%s: deterministic check: %v\n%s", p, err, report.String())
+		}
+
+		// A corrupted public key is reported.
+		corrupted := bytes.Replace(buf.Bytes(), []byte("pk = "+strings.ToUpper(hex.EncodeToString(vectors[1].pk[:4]))), []byte("pk = 00000000"), 1)
+		os.WriteFile(path, corrupted, 0o644)
+		report.Reset()
+		if err := checkFile(path, "", false, &report); err == nil || !strings.Contains(report.String(), "count = 1: pk differs") {
+			t.Errorf("%s: corrupted pk not reported: %v\n%s", p, err, report.String())
+		}
+	}
+}
+
+func TestDeterministicVector(t *testing.T) {
+	seed := bytes.Repeat([]byte{1}, 48)
+	msg := []byte("message")
+	v, err := newVector(mldsa.MLDSA87, 0, seed, msg, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, _ := mldsa.ParsePrivateKey(v.sk, 0)
+	want, _ := sk.SignWithContext(mldsa.DeterministicRand, msg, nil)
+	if !bytes.Equal(v.sm, append(want, msg...)) {
+		t.Error("deterministic sm does not match SignWithContext")
+	}
+	if again, _ := newVector(mldsa.MLDSA87, 0, seed, msg, true); !bytes.Equal(again.sm, v.sm) || !bytes.Equal(again.pk, v.pk) {
+		t.Error("vectors are not reproducible")
+	}
+}
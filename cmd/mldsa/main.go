@@ -0,0 +1,676 @@
+// Command mldsa generates ML-DSA keys and signs and verifies files, so that
+// scripts and CI pipelines can use the library without writing Go.
+//
+// Usage:
+//
+//	mldsa keygen [-alg ML-DSA-65] [-format pem|raw] [-out key] [-pubout key.pub]
+//	mldsa pubkey [-alg set] [-format pem|raw] [-out key.pub] -key key
+//	mldsa sign [-alg set] [-context ctx | -context-hex hex] [-format pem|raw] [-out sig] -key key [file]
+//	mldsa verify [-alg set] [-context ctx | -context-hex hex] -key key.pub -sig sig [file]
+//	mldsa inspect [file]
+//	mldsa fixtures [-out fixtures.json]
+//
+// Keys and signatures are written as PEM blocks of the types used by
+// mldsa.PEMBundle, such as "ML-DSA-65 PRIVATE KEY", or as raw bytes. When
+// reading, the format is detected: besides those blocks, PKCS #8 "PRIVATE
+// KEY" and PKIX "PUBLIC KEY" blocks are accepted, and raw keys and
+// signatures are told apart by length. A key pair is stored as its 32-byte
+// seed, whose parameter set must be given with -alg when it is read raw.
+// verify accepts a private key in place of the public key.
+//
+// The message is streamed from file, or from standard input if file is
+// omitted or "-", and is never held in memory. Output goes to standard
+// output unless -out is given. verify exits with status 1 if the signature
+// is invalid, as for any other error, and 2 on a usage error.
+//
+// fixtures writes the public test vectors of package mldsatest as JSON, so
+// that a committed copy can be regenerated and compared.
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/KarpelesLab/mldsa"
+	"github.com/KarpelesLab/mldsa/mldsatest"
+)
+
+// errInvalidSignature is returned by verify for a signature that does not
+// verify.
+var errInvalidSignature = errors.New("signature verification failed")
+
+// errUsage is returned for a bad command line, after the usage was printed.
+var errUsage = errors.New("usage error")
+
+const usage = `usage: mldsa <command> [flags] [args]
+
+commands:
+  keygen   generate a key pair
+  pubkey   extract the public key of a private key
+  sign     sign a file or standard input
+  verify   verify a signature
+  inspect  describe a key or signature file
+  fixtures write the mldsatest test vectors as JSON
+
+Run "mldsa <command> -h" for the flags of a command.
+`
+
+// env holds the standard streams of a command, which tests replace.
+type env struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+}
+
+var commands = map[string]func(*env, []string) error{
+	"keygen":   keygen,
+	"pubkey":   pubkey,
+	"sign":     sign,
+	"verify":   verify,
+	"inspect":  inspect,
+	"fixtures": fixtures,
+}
+
+func main() {
+	os.Exit(run(&env{os.Stdin, os.Stdout, os.Stderr}, os.Args[1:]))
+}
+
+// run executes the command line args and returns the exit status.
+func run(e *env, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(e.stderr, usage)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			fmt.Fprint(e.stdout, usage)
+			return 0
+		}
+		fmt.Fprintf(e.stderr, "mldsa: unknown command %q\n%s", args[0], usage)
+		return 2
+	}
+	switch err := cmd(e, args[1:]); {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintf(e.stderr, "mldsa %s: %v\n", args[0], err)
+		return 1
+	}
+}
+
+// newFlagSet returns a flag set for the named command that reports its
+// errors to e.stderr.
+func newFlagSet(e *env, name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: mldsa %s %s\n", name, synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args into fs and checks that at most maxArgs positional
+// arguments remain.
+func parseFlags(fs *flag.FlagSet, args []string, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() > maxArgs {
+		fmt.Fprintf(fs.Output(), "too many arguments: %q\n", fs.Args()[maxArgs:])
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// commonFlags are the flags shared by several commands.
+type commonFlags struct {
+	alg, format, out, key string
+	context, contextHex   string
+}
+
+func (c *commonFlags) register(fs *flag.FlagSet, defaultAlg string) {
+	fs.StringVar(&c.alg, "alg", defaultAlg, "parameter set: ML-DSA-44, ML-DSA-65 or ML-DSA-87")
+}
+
+func (c *commonFlags) registerOutput(fs *flag.FlagSet, what string) {
+	fs.StringVar(&c.format, "format", "pem", "output format: pem or raw")
+	fs.StringVar(&c.out, "out", "", "write the "+what+" to this file instead of standard output")
+}
+
+func (c *commonFlags) registerContext(fs *flag.FlagSet) {
+	fs.StringVar(&c.context, "context", "", "context string")
+	fs.StringVar(&c.contextHex, "context-hex", "", "context string, hex encoded")
+}
+
+// parameterSet returns the parameter set named by -alg, or zero if it was
+// left empty.
+func (c *commonFlags) parameterSet() (mldsa.ParameterSet, error) {
+	if c.alg == "" {
+		return 0, nil
+	}
+	return mldsa.ParseParameterSet(c.alg)
+}
+
+// contextBytes returns the context string given by -context or
+// -context-hex.
+func (c *commonFlags) contextBytes() ([]byte, error) {
+	switch {
+	case c.context != "" && c.contextHex != "":
+		return nil, errors.New("-context and -context-hex are mutually exclusive")
+	case c.contextHex != "":
+		ctx, err := hex.DecodeString(c.contextHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -context-hex: %w", err)
+		}
+		return ctx, nil
+	}
+	return []byte(c.context), nil
+}
+
+// checkFormat validates -format.
+func (c *commonFlags) checkFormat() error {
+	if c.format != "pem" && c.format != "raw" {
+		return fmt.Errorf("unknown -format %q, want pem or raw", c.format)
+	}
+	return nil
+}
+
+// writeOutput writes data to -out, created with mode perm, or to standard
+// output.
+func (c *commonFlags) writeOutput(e *env, data []byte, perm os.FileMode) error {
+	return writeFile(e, c.out, data, perm)
+}
+
+// writeFile writes data to path, or to standard output if path is empty or
+// "-".
+func writeFile(e *env, path string, data []byte, perm os.FileMode) error {
+	if path == "" || path == "-" {
+		_, err := e.stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+// openInput opens the message file named by args, or standard input.
+func openInput(e *env, args []string) (io.Reader, func(), error) {
+	if len(args) == 0 || args[0] == "-" {
+		return e.stdin, func() {}, nil
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { f.Close() }, nil
+}
+
+// readFile reads path, or standard input if path is "-".
+func readFile(e *env, path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(e.stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func keygen(e *env, args []string) error {
+	var c commonFlags
+	fs := newFlagSet(e, "keygen", "[-alg set] [-format pem|raw] [-out key] [-pubout key.pub]")
+	c.register(fs, "ML-DSA-65")
+	c.registerOutput(fs, "private key")
+	pubOut := fs.String("pubout", "", "also write the public key to this file")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if err := c.checkFormat(); err != nil {
+		return err
+	}
+	p, err := mldsa.ParseParameterSet(c.alg)
+	if err != nil {
+		return err
+	}
+
+	sk, err := p.Scheme().GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	defer destroy(sk)
+	encoded, err := encodePrivateKey(sk, c.format)
+	if err != nil {
+		return err
+	}
+	defer clear(encoded)
+	if *pubOut != "" {
+		pub, err := encodePublicKey(sk.Public().(mldsa.PublicKey), c.format)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(e, *pubOut, pub, 0o644); err != nil {
+			return err
+		}
+	}
+	return c.writeOutput(e, encoded, 0o600)
+}
+
+func pubkey(e *env, args []string) error {
+	var c commonFlags
+	fs := newFlagSet(e, "pubkey", "[-alg set] [-format pem|raw] [-out key.pub] -key key")
+	c.register(fs, "")
+	c.registerOutput(fs, "public key")
+	fs.StringVar(&c.key, "key", "", "private key `file` (required)")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if err := c.checkFormat(); err != nil {
+		return err
+	}
+	pk, err := loadPublicKey(e, &c)
+	if err != nil {
+		return err
+	}
+	encoded, err := encodePublicKey(pk, c.format)
+	if err != nil {
+		return err
+	}
+	return c.writeOutput(e, encoded, 0o644)
+}
+
+func sign(e *env, args []string) error {
+	var c commonFlags
+	fs := newFlagSet(e, "sign", "[-alg set] [-context ctx | -context-hex hex] [-format pem|raw] [-out sig] -key key [file]")
+	c.register(fs, "")
+	c.registerOutput(fs, "signature")
+	c.registerContext(fs)
+	fs.StringVar(&c.key, "key", "", "private key `file` (required)")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	if err := c.checkFormat(); err != nil {
+		return err
+	}
+	context, err := c.contextBytes()
+	if err != nil {
+		return err
+	}
+	sk, err := loadPrivateKey(e, &c)
+	if err != nil {
+		return err
+	}
+	defer destroy(sk)
+
+	msg, done, err := openInput(e, fs.Args())
+	if err != nil {
+		return err
+	}
+	defer done()
+	signer, ok := sk.(interface {
+		SignReader(rand, msg io.Reader, context []byte) ([]byte, error)
+	})
+	if !ok {
+		return fmt.Errorf("%T cannot sign a stream", sk)
+	}
+	sig, err := signer.SignReader(rand.Reader, msg, context)
+	if err != nil {
+		return err
+	}
+	encoded, err := encodeSignature(sk.Scheme().Name(), sig, c.format)
+	if err != nil {
+		return err
+	}
+	return c.writeOutput(e, encoded, 0o644)
+}
+
+func verify(e *env, args []string) error {
+	var c commonFlags
+	fs := newFlagSet(e, "verify", "[-alg set] [-context ctx | -context-hex hex] -key key.pub -sig sig [file]")
+	c.register(fs, "")
+	c.registerContext(fs)
+	fs.StringVar(&c.key, "key", "", "public or private key `file` (required)")
+	sigPath := fs.String("sig", "", "signature `file` (required)")
+	quiet := fs.Bool("q", false, "do not print the result")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	if *sigPath == "" {
+		fmt.Fprintln(e.stderr, "-sig is required")
+		fs.Usage()
+		return errUsage
+	}
+	context, err := c.contextBytes()
+	if err != nil {
+		return err
+	}
+	pk, err := loadPublicKey(e, &c)
+	if err != nil {
+		return err
+	}
+	data, err := readFile(e, *sigPath)
+	if err != nil {
+		return err
+	}
+	sig, err := decodeSignature(data, pk.Scheme().Name())
+	if err != nil {
+		return err
+	}
+
+	msg, done, err := openInput(e, fs.Args())
+	if err != nil {
+		return err
+	}
+	defer done()
+	// Computing µ separately tells read errors apart from bad signatures.
+	v, ok := pk.(interface {
+		ComputeMuReader(r io.Reader, context []byte) ([mldsa.MuSize]byte, error)
+		VerifyMu(sig []byte, mu [mldsa.MuSize]byte) bool
+	})
+	if !ok {
+		return fmt.Errorf("%T cannot verify a stream", pk)
+	}
+	mu, err := v.ComputeMuReader(msg, context)
+	if err != nil {
+		return err
+	}
+	if !v.VerifyMu(sig, mu) {
+		return errInvalidSignature
+	}
+	if !*quiet {
+		fmt.Fprintln(e.stdout, "signature OK")
+	}
+	return nil
+}
+
+func inspect(e *env, args []string) error {
+	fs := newFlagSet(e, "inspect", "[file]")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	path := "-"
+	if fs.NArg() == 1 {
+		path = fs.Arg(0)
+	}
+	data, err := readFile(e, path)
+	if err != nil {
+		return err
+	}
+	defer clear(data)
+
+	if !isPEM(data) {
+		desc, err := describeRaw(data)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(e.stdout, desc)
+		return nil
+	}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil
+		}
+		desc, err := describeBlock(block)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(e.stdout, desc)
+	}
+}
+
+func fixtures(e *env, args []string) error {
+	var out string
+	fs := newFlagSet(e, "fixtures", "[-out fixtures.json]")
+	fs.StringVar(&out, "out", "", "write the fixtures to this file instead of standard output")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := mldsatest.WriteFixtures(&buf); err != nil {
+		return err
+	}
+	return writeFile(e, out, buf.Bytes(), 0o644)
+}
+
+// destroy wipes sk, if it supports it.
+func destroy(sk mldsa.PrivateKey) {
+	if d, ok := sk.(interface{ Destroy() }); ok {
+		d.Destroy()
+	}
+}
+
+// isPEM reports whether data holds at least one PEM block.
+func isPEM(data []byte) bool {
+	block, _ := pem.Decode(data)
+	return block != nil
+}
+
+// encodePrivateKey encodes sk in format: a key pair as its seed and a
+// standalone private key in expanded form.
+func encodePrivateKey(sk mldsa.PrivateKey, format string) ([]byte, error) {
+	if format == "pem" {
+		return (&mldsa.PEMBundle{PrivateKeys: []mldsa.PrivateKey{sk}}).Marshal()
+	}
+	return sk.(interface{ Bytes() []byte }).Bytes(), nil
+}
+
+// encodePublicKey encodes pk in format.
+func encodePublicKey(pk mldsa.PublicKey, format string) ([]byte, error) {
+	if format == "pem" {
+		return (&mldsa.PEMBundle{PublicKeys: []mldsa.PublicKey{pk}}).Marshal()
+	}
+	return pk.Bytes(), nil
+}
+
+// encodeSignature encodes a signature of the named parameter set in format.
+func encodeSignature(params string, sig []byte, format string) ([]byte, error) {
+	if format == "pem" {
+		return (&mldsa.PEMBundle{Signatures: []mldsa.PEMSignature{{ParameterSet: params, Signature: sig}}}).Marshal()
+	}
+	return sig, nil
+}
+
+// decodeSignature decodes a PEM or raw signature made with a key of the
+// named parameter set.
+func decodeSignature(data []byte, params string) ([]byte, error) {
+	if !isPEM(data) {
+		return data, nil
+	}
+	b, err := mldsa.ParsePEMBundle(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.Signatures) != 1 {
+		return nil, fmt.Errorf("found %d PEM signatures, want 1", len(b.Signatures))
+	}
+	if b.Signatures[0].ParameterSet != params {
+		return nil, fmt.Errorf("%s signature for an %s key", b.Signatures[0].ParameterSet, params)
+	}
+	return b.Signatures[0].Signature, nil
+}
+
+// loadPrivateKey reads the private key named by -key.
+func loadPrivateKey(e *env, c *commonFlags) (mldsa.PrivateKey, error) {
+	pubs, privs, err := loadKeys(e, c)
+	if err != nil {
+		return nil, err
+	}
+	if len(privs) == 0 && len(pubs) > 0 {
+		return nil, fmt.Errorf("%s holds a public key, not a private key", c.key)
+	}
+	return onlyKey(c, privs)
+}
+
+// loadPublicKey reads the public key named by -key, which may also be a
+// private key from which the public key is derived.
+func loadPublicKey(e *env, c *commonFlags) (mldsa.PublicKey, error) {
+	pubs, privs, err := loadKeys(e, c)
+	if err != nil {
+		return nil, err
+	}
+	for _, sk := range privs {
+		pubs = append(pubs, sk.Public().(mldsa.PublicKey))
+		destroy(sk)
+	}
+	return onlyKey(c, pubs)
+}
+
+// onlyKey returns the single key of keys.
+func onlyKey[K interface{ Scheme() mldsa.Scheme }](c *commonFlags, keys []K) (K, error) {
+	var zero K
+	if len(keys) != 1 {
+		return zero, fmt.Errorf("%s holds %d suitable keys, want 1", c.key, len(keys))
+	}
+	return keys[0], nil
+}
+
+// loadKeys reads and parses every key in the file named by -key. The keys
+// must belong to the parameter set named by -alg, if any.
+func loadKeys(e *env, c *commonFlags) ([]mldsa.PublicKey, []mldsa.PrivateKey, error) {
+	if c.key == "" {
+		return nil, nil, errors.New("-key is required")
+	}
+	p, err := c.parameterSet()
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := readFile(e, c.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer clear(data)
+	pubs, privs, err := parseKeys(data, p)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", c.key, err)
+	}
+	if p != 0 {
+		for _, pk := range pubs {
+			if pk.Scheme().Name() != p.String() {
+				return nil, nil, fmt.Errorf("%s: %s key, want %s", c.key, pk.Scheme().Name(), p)
+			}
+		}
+		for _, sk := range privs {
+			if sk.Scheme().Name() != p.String() {
+				return nil, nil, fmt.Errorf("%s: %s key, want %s", c.key, sk.Scheme().Name(), p)
+			}
+		}
+	}
+	return pubs, privs, nil
+}
+
+// parseKeys parses the keys in data, which is either PEM or a raw public
+// key, seed or expanded private key. p names the parameter set of a raw
+// seed.
+func parseKeys(data []byte, p mldsa.ParameterSet) ([]mldsa.PublicKey, []mldsa.PrivateKey, error) {
+	if !isPEM(data) {
+		if pk, err := mldsa.ParsePublicKey(data); err == nil {
+			return []mldsa.PublicKey{pk}, nil, nil
+		}
+		sk, err := mldsa.ParsePrivateKey(bytes.Clone(data), p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, []mldsa.PrivateKey{sk}, nil
+	}
+
+	b, err := mldsa.ParsePEMBundle(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	pubs, privs := b.PublicKeys, b.PrivateKeys
+	for _, block := range b.Other {
+		switch block.Type {
+		case "PUBLIC KEY":
+			pk, err := mldsa.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			pubs = append(pubs, pk)
+		case "PRIVATE KEY":
+			sk, err := mldsa.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			privs = append(privs, sk)
+		}
+	}
+	return pubs, privs, nil
+}
+
+// describeRaw describes a raw key or signature, identified by its length.
+func describeRaw(data []byte) (string, error) {
+	if len(data) == mldsa.SeedSize {
+		return "private key seed (parameter set not recorded)", nil
+	}
+	for _, p := range mldsa.ParameterSets() {
+		switch len(data) {
+		case p.PublicKeySize():
+			pk, err := p.Scheme().UnmarshalPublicKey(data)
+			if err != nil {
+				return "", err
+			}
+			return describePublicKey(pk), nil
+		case p.PrivateKeySize():
+			sk, err := p.Scheme().UnmarshalPrivateKey(bytes.Clone(data))
+			if err != nil {
+				return "", err
+			}
+			defer destroy(sk)
+			return describePrivateKey(sk), nil
+		case p.SignatureSize():
+			return fmt.Sprintf("%s signature, %d bytes", p, len(data)), nil
+		}
+	}
+	return "", fmt.Errorf("%d bytes is not the size of an ML-DSA key or signature", len(data))
+}
+
+// describeBlock describes a single PEM block.
+func describeBlock(block *pem.Block) (string, error) {
+	pubs, privs, err := parseKeys(pem.EncodeToMemory(block), 0)
+	if err != nil {
+		return "", fmt.Errorf("%s block: %w", block.Type, err)
+	}
+	switch {
+	case len(pubs) == 1:
+		return describePublicKey(pubs[0]), nil
+	case len(privs) == 1:
+		defer destroy(privs[0])
+		return describePrivateKey(privs[0]), nil
+	}
+	b, err := mldsa.ParsePEMBundle(pem.EncodeToMemory(block))
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case len(b.Signatures) == 1:
+		return fmt.Sprintf("%s signature, %d bytes", b.Signatures[0].ParameterSet, len(b.Signatures[0].Signature)), nil
+	case len(b.Certificates) == 1:
+		return fmt.Sprintf("certificate, %d bytes", len(b.Certificates[0])), nil
+	}
+	return fmt.Sprintf("unrecognized %q block, %d bytes", block.Type, len(block.Bytes)), nil
+}
+
+func describePublicKey(pk mldsa.PublicKey) string {
+	return fmt.Sprintf("%s public key, fingerprint %s", pk.Scheme().Name(), pk.Fingerprint())
+}
+
+func describePrivateKey(sk mldsa.PrivateKey) string {
+	form := "expanded"
+	if _, ok := sk.Seed(); ok {
+		form = "seed"
+	}
+	pk := sk.Public().(mldsa.PublicKey)
+	return fmt.Sprintf("%s private key (%s), public key fingerprint %s", sk.Scheme().Name(), form, pk.Fingerprint())
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+	"github.com/KarpelesLab/mldsa/mldsatest"
+)
+
+// runCmd runs the command line args with stdin as standard input and returns
+// the exit status and standard output.
+func runCmd(t *testing.T, stdin []byte, args ...string) (int, []byte) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	status := run(&env{bytes.NewReader(stdin), &stdout, &stderr}, args)
+	if status != 0 {
+		t.Logf("mldsa %s: exit status %d: %s", strings.Join(args, " "), status, stderr.String())
+	}
+	return status, stdout.Bytes()
+}
+
+func TestKeygenSignVerify(t *testing.T) {
+	dir := t.TempDir()
+	message := []byte("release artifact")
+	msgPath := filepath.Join(dir, "msg")
+	os.WriteFile(msgPath, message, 0o644)
+
+	for _, p := range mldsa.ParameterSets() {
+		for _, format := range []string{"pem", "raw"} {
+			name := p.String() + "-" + format
+			key := filepath.Join(dir, name+".key")
+			pub := filepath.Join(dir, name+".pub")
+			sig := filepath.Join(dir, name+".sig")
+
+			if status, _ := runCmd(t, nil, "keygen", "-alg", p.String(), "-format", format, "-out", key, "-pubout", pub); status != 0 {
+				t.Fatalf("%s: keygen failed", name)
+			}
+			if fi, err := os.Stat(key); err != nil || fi.Mode().Perm() != 0o600 {
+				t.Errorf("%s: private key file mode %v, %v", name, fi.Mode(), err)
+			}
+			status, derived := runCmd(t, nil, "pubkey", "-alg", p.String(), "-format", format, "-key", key)
+			if want, _ := os.ReadFile(pub); status != 0 || !bytes.Equal(derived, want) {
+				t.Errorf("%s: pubkey does not match the keygen public key", name)
+			}
+
+			// Sign from standard input, verify from the file.
+			if status, _ := runCmd(t, message, "sign", "-alg", p.String(), "-format", format, "-context", "ci", "-key", key, "-out", sig); status != 0 {
+				t.Fatalf("%s: sign failed", name)
+			}
+			if status, out := runCmd(t, nil, "verify", "-context", "ci", "-key", pub, "-sig", sig, msgPath); status != 0 || string(out) != "signature OK\n" {
+				t.Errorf("%s: verify failed: %q", name, out)
+			}
+			if status, _ := runCmd(t, nil, "verify", "-context-hex", "6369", "-alg", p.String(), "-key", key, "-sig", sig, msgPath); status != 0 {
+				t.Errorf("%s: verify with a hex context and the private key failed", name)
+			}
+			if status, _ := runCmd(t, nil, "verify", "-context", "cd", "-key", pub, "-sig", sig, msgPath); status != 1 {
+				t.Errorf("%s: verify with the wrong context: exit status %d, want 1", name, status)
+			}
+			if status, _ := runCmd(t, []byte("tampered"), "verify", "-q", "-context", "ci", "-key", pub, "-sig", sig); status != 1 {
+				t.Errorf("%s: verify of a different message: exit status %d, want 1", name, status)
+			}
+		}
+	}
+}
+
+func TestFixtures(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range mldsatest.Fixtures() {
+		pub := filepath.Join(dir, f.ParameterSet+".pub")
+		seed := filepath.Join(dir, f.ParameterSet+".seed")
+		sig := filepath.Join(dir, f.ParameterSet+".sig")
+		os.WriteFile(pub, f.PublicKey, 0o644)
+		os.WriteFile(seed, f.Seed, 0o600)
+		os.WriteFile(sig, f.Signature, 0o644)
+
+		if status, _ := runCmd(t, f.Message, "verify", "-context", string(f.Context), "-key", pub, "-sig", sig); status != 0 {
+			t.Errorf("%s: fixture signature rejected", f.ParameterSet)
+		}
+		if status, _ := runCmd(t, nil, "pubkey", "-key", seed); status != 1 {
+			t.Errorf("%s: raw seed accepted without -alg", f.ParameterSet)
+		}
+		if status, out := runCmd(t, nil, "pubkey", "-alg", f.ParameterSet, "-format", "raw", "-key", seed); status != 0 || !bytes.Equal(out, f.PublicKey) {
+			t.Errorf("%s: pubkey of the fixture seed is wrong", f.ParameterSet)
+		}
+		if status, _ := runCmd(t, nil, "pubkey", "-alg", "ML-DSA-44", "-key", pub); f.ParameterSet != "ML-DSA-44" && status != 1 {
+			t.Errorf("%s: key accepted for the wrong -alg", f.ParameterSet)
+		}
+	}
+}
+
+func TestRegenerateFixtures(t *testing.T) {
+	var want bytes.Buffer
+	mldsatest.WriteFixtures(&want)
+	if status, out := runCmd(t, nil, "fixtures"); status != 0 || !bytes.Equal(out, want.Bytes()) {
+		t.Error("fixtures wrote other test vectors")
+	}
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	if status, _ := runCmd(t, nil, "fixtures", "-out", path); status != 0 {
+		t.Fatal("fixtures -out failed")
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, want.Bytes()) {
+		t.Error("fixtures -out wrote other test vectors")
+	}
+}
+
+func TestInspect(t *testing.T) {
+	key := mldsatest.Key65()
+	fp := key.PublicKey().Fingerprint().String()
+	pemKey, _ := (&mldsa.PEMBundle{PrivateKeys: []mldsa.PrivateKey{key}}).Marshal()
+	pkix, _ := mldsa.MarshalPKIXPublicKey(key.PublicKey())
+
+	for _, tc := range []struct {
+		input []byte
+		want  string
+	}{
+		{pemKey, "ML-DSA-65 private key (seed), public key fingerprint " + fp + "\n"},
+		{key.PublicKey().Bytes(), "ML-DSA-65 public key, fingerprint " + fp + "\n"},
+		{key.PrivateKeyBytes(), "ML-DSA-65 private key (expanded), public key fingerprint " + fp + "\n"},
+		{make([]byte, mldsa.SignatureSize87), "ML-DSA-87 signature, 4627 bytes\n"},
+		{append(pemBlock("PUBLIC KEY", pkix), pemBlock("NOTE", []byte("x"))...),
+			"ML-DSA-65 public key, fingerprint " + fp + "\n" + `unrecognized "NOTE" block, 1 bytes` + "\n"},
+	} {
+		status, out := runCmd(t, tc.input, "inspect")
+		if status != 0 || string(out) != tc.want {
+			t.Errorf("inspect = %q, want %q", out, tc.want)
+		}
+	}
+	if status, _ := runCmd(t, []byte("junk"), "inspect"); status != 1 {
+		t.Errorf("inspect of junk: exit status %d, want 1", status)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"frobnicate"},
+		{"keygen", "-bogus"},
+		{"keygen", "extra"},
+		{"verify", "-key", "k"},
+	} {
+		if status, _ := runCmd(t, nil, args...); status != 2 {
+			t.Errorf("mldsa %q: exit status %d, want 2", args, status)
+		}
+	}
+	if status, _ := runCmd(t, nil, "keygen", "-format", "der"); status != 1 {
+		t.Errorf("keygen with an unknown format: exit status %d, want 1", status)
+	}
+	if status, _ := runCmd(t, nil, "sign", "-context", "a", "-context-hex", "62", "-key", "k"); status != 1 {
+		t.Errorf("sign with two contexts: exit status %d, want 1", status)
+	}
+}
+
+func pemBlock(typ string, b []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b})
+}
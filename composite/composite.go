@@ -0,0 +1,337 @@
+// Package composite implements composite ML-DSA signatures as specified by
+// draft-ietf-lamps-pq-composite-sigs: an ML-DSA signature and a traditional
+// signature (Ed25519, ECDSA or RSA) over the same message, both of which
+// must verify. They let CAs that mandate hybrid signatures during the
+// post-quantum migration use ML-DSA.
+//
+// Keys and signatures are the concatenation of their ML-DSA and traditional
+// components, the ML-DSA part first:
+//
+//	public key:  ML-DSA public key || traditional public key
+//	private key: ML-DSA seed       || traditional private key
+//	signature:   ML-DSA signature  || traditional signature
+//
+// Traditional public keys are raw Ed25519 keys, uncompressed EC points or
+// PKCS #1 RSAPublicKey structures; private keys are Ed25519 seeds, RFC 5915
+// ECPrivateKey or PKCS #1 RSAPrivateKey structures.
+//
+// Both components sign the pre-processed message
+//
+//	M' = Prefix || Label || len(ctx) || ctx || PH(M)
+//
+// where Prefix is "CompositeAlgorithmSignatures2025", Label names the
+// algorithm and PH is its pre-hash; ML-DSA signs M' with Label as its
+// context. The variants with brainpool curves and Ed448 are not
+// implemented, as the standard library has no support for them.
+package composite
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
+	_ "crypto/sha256" // for crypto.SHA256
+	_ "crypto/sha512" // for crypto.SHA384 and crypto.SHA512
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// prefix starts every pre-processed message.
+const prefix = "CompositeAlgorithmSignatures2025"
+
+// ErrInvalidSignature is returned when a composite signature does not
+// verify.
+var ErrInvalidSignature = errors.New("composite: invalid signature")
+
+// Algorithm is a composite signature algorithm.
+type Algorithm struct {
+	name    string
+	oid     asn1.ObjectIdentifier
+	params  mldsa.ParameterSet
+	prehash crypto.Hash
+	trad    traditional
+}
+
+func newAlgorithm(name string, arc int, params mldsa.ParameterSet, prehash crypto.Hash, trad traditional) *Algorithm {
+	return &Algorithm{
+		name:    name,
+		oid:     asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 6, arc},
+		params:  params,
+		prehash: prehash,
+		trad:    trad,
+	}
+}
+
+// Composite algorithms, named after their draft identifiers without the
+// "id-" prefix.
+var (
+	MLDSA44RSA2048PSS    = newAlgorithm("MLDSA44-RSA2048-PSS-SHA256", 37, mldsa.MLDSA44, crypto.SHA256, tradRSA{2048, crypto.SHA256, true})
+	MLDSA44RSA2048PKCS15 = newAlgorithm("MLDSA44-RSA2048-PKCS15-SHA256", 38, mldsa.MLDSA44, crypto.SHA256, tradRSA{2048, crypto.SHA256, false})
+	MLDSA44Ed25519       = newAlgorithm("MLDSA44-Ed25519-SHA512", 39, mldsa.MLDSA44, crypto.SHA512, tradEd25519{})
+	MLDSA44ECDSAP256     = newAlgorithm("MLDSA44-ECDSA-P256-SHA256", 40, mldsa.MLDSA44, crypto.SHA256, tradECDSA{elliptic.P256(), ecdh.P256(), crypto.SHA256})
+	MLDSA65RSA3072PSS    = newAlgorithm("MLDSA65-RSA3072-PSS-SHA512", 41, mldsa.MLDSA65, crypto.SHA512, tradRSA{3072, crypto.SHA256, true})
+	MLDSA65RSA3072PKCS15 = newAlgorithm("MLDSA65-RSA3072-PKCS15-SHA512", 42, mldsa.MLDSA65, crypto.SHA512, tradRSA{3072, crypto.SHA256, false})
+	MLDSA65RSA4096PSS    = newAlgorithm("MLDSA65-RSA4096-PSS-SHA512", 43, mldsa.MLDSA65, crypto.SHA512, tradRSA{4096, crypto.SHA384, true})
+	MLDSA65RSA4096PKCS15 = newAlgorithm("MLDSA65-RSA4096-PKCS15-SHA512", 44, mldsa.MLDSA65, crypto.SHA512, tradRSA{4096, crypto.SHA384, false})
+	MLDSA65ECDSAP256     = newAlgorithm("MLDSA65-ECDSA-P256-SHA512", 45, mldsa.MLDSA65, crypto.SHA512, tradECDSA{elliptic.P256(), ecdh.P256(), crypto.SHA256})
+	MLDSA65ECDSAP384     = newAlgorithm("MLDSA65-ECDSA-P384-SHA512", 46, mldsa.MLDSA65, crypto.SHA512, tradECDSA{elliptic.P384(), ecdh.P384(), crypto.SHA384})
+	MLDSA65Ed25519       = newAlgorithm("MLDSA65-Ed25519-SHA512", 48, mldsa.MLDSA65, crypto.SHA512, tradEd25519{})
+	MLDSA87ECDSAP384     = newAlgorithm("MLDSA87-ECDSA-P384-SHA512", 49, mldsa.MLDSA87, crypto.SHA512, tradECDSA{elliptic.P384(), ecdh.P384(), crypto.SHA384})
+	MLDSA87RSA3072PSS    = newAlgorithm("MLDSA87-RSA3072-PSS-SHA512", 52, mldsa.MLDSA87, crypto.SHA512, tradRSA{3072, crypto.SHA256, true})
+	MLDSA87RSA4096PSS    = newAlgorithm("MLDSA87-RSA4096-PSS-SHA512", 53, mldsa.MLDSA87, crypto.SHA512, tradRSA{4096, crypto.SHA384, true})
+	MLDSA87ECDSAP521     = newAlgorithm("MLDSA87-ECDSA-P521-SHA512", 54, mldsa.MLDSA87, crypto.SHA512, tradECDSA{elliptic.P521(), ecdh.P521(), crypto.SHA512})
+)
+
+// Algorithms returns the implemented composite algorithms, in OID order.
+func Algorithms() []*Algorithm {
+	return []*Algorithm{
+		MLDSA44RSA2048PSS, MLDSA44RSA2048PKCS15, MLDSA44Ed25519, MLDSA44ECDSAP256,
+		MLDSA65RSA3072PSS, MLDSA65RSA3072PKCS15, MLDSA65RSA4096PSS, MLDSA65RSA4096PKCS15,
+		MLDSA65ECDSAP256, MLDSA65ECDSAP384, MLDSA65Ed25519,
+		MLDSA87ECDSAP384, MLDSA87RSA3072PSS, MLDSA87RSA4096PSS, MLDSA87ECDSAP521,
+	}
+}
+
+// AlgorithmByName returns the algorithm with the given name, such as
+// "MLDSA65-ECDSA-P256-SHA512", or nil.
+func AlgorithmByName(name string) *Algorithm {
+	for _, a := range Algorithms() {
+		if a.name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// AlgorithmByOID returns the algorithm identified by oid, or nil.
+func AlgorithmByOID(oid asn1.ObjectIdentifier) *Algorithm {
+	for _, a := range Algorithms() {
+		if a.oid.Equal(oid) {
+			return a
+		}
+	}
+	return nil
+}
+
+// Name returns the algorithm name, such as "MLDSA65-ECDSA-P256-SHA512".
+func (a *Algorithm) Name() string { return a.name }
+
+// String returns the algorithm name.
+func (a *Algorithm) String() string { return a.name }
+
+// OID returns the object identifier of the algorithm, used for both keys
+// and signatures.
+func (a *Algorithm) OID() asn1.ObjectIdentifier { return append(asn1.ObjectIdentifier(nil), a.oid...) }
+
+// AlgorithmIdentifier returns the AlgorithmIdentifier of the algorithm,
+// whose parameters are absent.
+func (a *Algorithm) AlgorithmIdentifier() pkix.AlgorithmIdentifier {
+	return pkix.AlgorithmIdentifier{Algorithm: a.OID()}
+}
+
+// ParameterSet returns the parameter set of the ML-DSA component.
+func (a *Algorithm) ParameterSet() mldsa.ParameterSet { return a.params }
+
+// label returns the label of the algorithm, which is also the ML-DSA
+// context.
+func (a *Algorithm) label() []byte { return []byte("COMPSIG-" + a.name) }
+
+// message returns the pre-processed message M' for message and context.
+func (a *Algorithm) message(message, context []byte) ([]byte, error) {
+	if len(context) > 255 {
+		return nil, mldsa.ErrContextTooLong
+	}
+	h := a.prehash.New()
+	h.Write(message)
+	label := a.label()
+	b := make([]byte, 0, len(prefix)+len(label)+1+len(context)+h.Size())
+	b = append(b, prefix...)
+	b = append(b, label...)
+	b = append(b, byte(len(context)))
+	b = append(b, context...)
+	return h.Sum(b), nil
+}
+
+// PublicKey is a composite public key.
+type PublicKey struct {
+	Algorithm   *Algorithm
+	MLDSA       mldsa.PublicKey
+	Traditional crypto.PublicKey
+}
+
+// PrivateKey is a composite private key. It implements crypto.Signer.
+type PrivateKey struct {
+	Algorithm   *Algorithm
+	MLDSA       mldsa.PrivateKey
+	Traditional crypto.Signer
+}
+
+// GenerateKey generates a composite key pair. If rand is nil,
+// crypto/rand.Reader is used.
+func (a *Algorithm) GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	m, err := a.params.Scheme().GenerateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	t, err := a.trad.generateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{Algorithm: a, MLDSA: m, Traditional: t}, nil
+}
+
+// ParsePublicKey parses the concatenated encoding of a composite public key.
+func (a *Algorithm) ParsePublicKey(b []byte) (*PublicKey, error) {
+	n := a.params.PublicKeySize()
+	if len(b) <= n {
+		return nil, errors.New("composite: public key too short")
+	}
+	m, err := a.params.Scheme().UnmarshalPublicKey(b[:n])
+	if err != nil {
+		return nil, err
+	}
+	t, err := a.trad.parsePublicKey(bytes.Clone(b[n:]))
+	if err != nil {
+		return nil, err
+	}
+	return &PublicKey{Algorithm: a, MLDSA: m, Traditional: t}, nil
+}
+
+// ParsePrivateKey parses the concatenated encoding of a composite private
+// key.
+func (a *Algorithm) ParsePrivateKey(b []byte) (*PrivateKey, error) {
+	if len(b) <= mldsa.SeedSize {
+		return nil, errors.New("composite: private key too short")
+	}
+	m, err := a.params.Scheme().NewKeyFromSeed(b[:mldsa.SeedSize])
+	if err != nil {
+		return nil, err
+	}
+	t, err := a.trad.parsePrivateKey(b[mldsa.SeedSize:])
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{Algorithm: a, MLDSA: m, Traditional: t}, nil
+}
+
+// Bytes returns the concatenated encoding of the public key.
+func (pk *PublicKey) Bytes() ([]byte, error) {
+	t, err := pk.Algorithm.trad.marshalPublicKey(pk.Traditional)
+	if err != nil {
+		return nil, err
+	}
+	return append(pk.MLDSA.Bytes(), t...), nil
+}
+
+// Equal reports whether pk and x are the same composite public key.
+func (pk *PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(*PublicKey)
+	if !ok || other.Algorithm != pk.Algorithm || !pk.MLDSA.Equal(other.MLDSA) {
+		return false
+	}
+	t, ok := pk.Traditional.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && t.Equal(other.Traditional)
+}
+
+// Verify reports whether sig is a valid composite signature of message
+// with the optional context string: both component signatures must verify.
+func (pk *PublicKey) Verify(sig, message, context []byte) bool {
+	return pk.VerifyError(sig, message, context) == nil
+}
+
+// VerifyError is like Verify but reports why verification failed.
+func (pk *PublicKey) VerifyError(sig, message, context []byte) error {
+	n := pk.Algorithm.params.SignatureSize()
+	if len(sig) <= n {
+		return fmt.Errorf("%w: too short", ErrInvalidSignature)
+	}
+	m, err := pk.Algorithm.message(message, context)
+	if err != nil {
+		return err
+	}
+	// Both components are always checked, so that timing does not reveal
+	// which one failed.
+	mOK := pk.MLDSA.Verify(sig[:n], m, pk.Algorithm.label())
+	tOK := pk.Algorithm.trad.verify(pk.Traditional, m, sig[n:])
+	if !mOK || !tOK {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Public returns the composite public key of sk.
+func (sk *PrivateKey) Public() crypto.PublicKey {
+	return sk.PublicKey()
+}
+
+// PublicKey returns the composite public key of sk.
+func (sk *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{
+		Algorithm:   sk.Algorithm,
+		MLDSA:       sk.MLDSA.Public().(mldsa.PublicKey),
+		Traditional: sk.Traditional.Public(),
+	}
+}
+
+// Bytes returns the concatenated encoding of the private key. The ML-DSA
+// key must keep its seed.
+func (sk *PrivateKey) Bytes() ([]byte, error) {
+	seed, ok := sk.MLDSA.Seed()
+	if !ok {
+		return nil, errors.New("composite: ML-DSA key has no seed")
+	}
+	t, err := sk.Algorithm.trad.marshalPrivateKey(sk.Traditional)
+	if err != nil {
+		return nil, err
+	}
+	return append(bytes.Clone(seed), t...), nil
+}
+
+// SignWithContext returns a composite signature of message with the
+// optional context string. If rand is nil, crypto/rand.Reader is used.
+func (sk *PrivateKey) SignWithContext(rand io.Reader, message, context []byte) ([]byte, error) {
+	m, err := sk.Algorithm.message(message, context)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := sk.MLDSA.SignWithContext(rand, m, sk.Algorithm.label())
+	if err != nil {
+		return nil, err
+	}
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	t, err := sk.Algorithm.trad.sign(rand, sk.Traditional, m)
+	if err != nil {
+		return nil, err
+	}
+	return append(sig, t...), nil
+}
+
+// Sign signs message, which is not hashed, implementing crypto.Signer.
+// opts.HashFunc() must be zero. If opts is *mldsa.SignerOpts, its Context
+// is used.
+func (sk *PrivateKey) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var context []byte
+	if opts != nil {
+		if opts.HashFunc() != 0 {
+			return nil, errors.New("composite: pre-hashed messages are not supported")
+		}
+		if o, ok := opts.(*mldsa.SignerOpts); ok {
+			context = o.Context
+			if o.Rand != nil {
+				rand = o.Rand
+			}
+		}
+	}
+	return sk.SignWithContext(rand, message, context)
+}
@@ -0,0 +1,130 @@
+package composite
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"encoding/asn1"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestSignVerify(t *testing.T) {
+	message := []byte("to be signed")
+	for _, a := range Algorithms() {
+		if testing.Short() && strings.Contains(a.Name(), "RSA") && !strings.Contains(a.Name(), "RSA2048") {
+			continue
+		}
+		t.Run(a.Name(), func(t *testing.T) {
+			sk, err := a.GenerateKey(rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pk := sk.PublicKey()
+			sig, err := sk.SignWithContext(rand.Reader, message, []byte("ctx"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := pk.VerifyError(sig, message, []byte("ctx")); err != nil {
+				t.Fatalf("VerifyError: %v", err)
+			}
+			if pk.Verify(sig, message, nil) {
+				t.Error("Verify accepted another context")
+			}
+			if pk.Verify(sig, []byte("other"), []byte("ctx")) {
+				t.Error("Verify accepted another message")
+			}
+
+			// Each component must be checked.
+			n := a.ParameterSet().SignatureSize()
+			for _, i := range []int{0, n} {
+				bad := bytes.Clone(sig)
+				bad[i] ^= 1
+				if err := pk.VerifyError(bad, message, []byte("ctx")); !errors.Is(err, ErrInvalidSignature) {
+					t.Errorf("VerifyError with byte %d flipped: %v", i, err)
+				}
+			}
+
+			// A component signature alone does not verify as plain ML-DSA
+			// of the message.
+			if pk.MLDSA.Verify(sig[:n], message, []byte("ctx")) {
+				t.Error("ML-DSA component verifies without pre-processing")
+			}
+
+			pkb, err := pk.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			pk2, err := a.ParsePublicKey(pkb)
+			if err != nil || !pk2.Equal(pk) {
+				t.Fatalf("ParsePublicKey: %v", err)
+			}
+			skb, err := sk.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sk2, err := a.ParsePrivateKey(skb)
+			if err != nil || !sk2.PublicKey().Equal(pk) {
+				t.Fatalf("ParsePrivateKey: %v", err)
+			}
+
+			der, err := MarshalPKIXPublicKey(pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pk3, err := ParsePKIXPublicKey(der)
+			if err != nil || !pk3.Equal(pk) {
+				t.Fatalf("ParsePKIXPublicKey: %v", err)
+			}
+
+			sig2, err := sk2.Sign(rand.Reader, message, &mldsa.SignerOpts{Context: []byte("ctx")})
+			if err != nil || !pk.Verify(sig2, message, []byte("ctx")) {
+				t.Errorf("Sign: %v", err)
+			}
+		})
+	}
+}
+
+func TestPreProcessedMessage(t *testing.T) {
+	m, err := MLDSA65ECDSAP256.message([]byte("abc"), []byte{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "CompositeAlgorithmSignatures2025COMPSIG-MLDSA65-ECDSA-P256-SHA512\x02\x01\x02"
+	if !bytes.HasPrefix(m, []byte(want)) || len(m) != len(want)+64 {
+		t.Errorf("unexpected M' %q", m)
+	}
+	if _, err := MLDSA65ECDSAP256.message(nil, make([]byte, 256)); !errors.Is(err, mldsa.ErrContextTooLong) {
+		t.Errorf("long context: %v", err)
+	}
+}
+
+func TestAlgorithms(t *testing.T) {
+	if a := AlgorithmByOID(asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 6, 45}); a != MLDSA65ECDSAP256 {
+		t.Errorf("AlgorithmByOID = %v", a)
+	}
+	if a := AlgorithmByName("MLDSA44-Ed25519-SHA512"); a != MLDSA44Ed25519 {
+		t.Errorf("AlgorithmByName = %v", a)
+	}
+	if AlgorithmByName("MLDSA44") != nil || AlgorithmByOID(mldsa.OIDMLDSA44) != nil {
+		t.Error("unexpected algorithm for a pure ML-DSA name or OID")
+	}
+
+	sk, _ := MLDSA44Ed25519.GenerateKey(rand.Reader)
+	pkb, _ := sk.PublicKey().Bytes()
+	if _, err := MLDSA44Ed25519.ParsePublicKey(pkb[:len(pkb)-1]); err == nil {
+		t.Error("ParsePublicKey accepted a truncated key")
+	}
+	if _, err := MLDSA44ECDSAP256.ParsePublicKey(pkb); err == nil {
+		t.Error("ParsePublicKey accepted a key of another algorithm")
+	}
+	if _, err := sk.Sign(rand.Reader, make([]byte, 32), crypto.SHA256); err == nil {
+		t.Error("Sign accepted a pre-hashed message")
+	}
+	if sk.PublicKey().Verify(nil, nil, nil) {
+		t.Error("Verify accepted an empty signature")
+	}
+}
@@ -0,0 +1,51 @@
+package composite
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+)
+
+// subjectPublicKeyInfo is the SubjectPublicKeyInfo structure of RFC 5280.
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// MarshalPKIXPublicKey returns the DER SubjectPublicKeyInfo of pk, whose
+// BIT STRING holds the concatenated composite public key.
+func MarshalPKIXPublicKey(pk *PublicKey) ([]byte, error) {
+	b, err := pk.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: pk.Algorithm.AlgorithmIdentifier(),
+		PublicKey: asn1.BitString{Bytes: b, BitLength: 8 * len(b)},
+	})
+}
+
+// ParsePKIXPublicKey parses a DER SubjectPublicKeyInfo holding a composite
+// public key.
+func ParsePKIXPublicKey(der []byte) (*PublicKey, error) {
+	var spki subjectPublicKeyInfo
+	rest, err := asn1.Unmarshal(der, &spki)
+	if err != nil {
+		return nil, fmt.Errorf("composite: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("composite: trailing data after public key")
+	}
+	a := AlgorithmByOID(spki.Algorithm.Algorithm)
+	if a == nil {
+		return nil, fmt.Errorf("composite: unknown algorithm %v", spki.Algorithm.Algorithm)
+	}
+	if len(spki.Algorithm.Parameters.FullBytes) > 0 {
+		return nil, errors.New("composite: algorithm parameters must be absent")
+	}
+	if spki.PublicKey.BitLength%8 != 0 {
+		return nil, errors.New("composite: invalid public key bit string")
+	}
+	return a.ParsePublicKey(spki.PublicKey.Bytes)
+}
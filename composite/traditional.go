@@ -0,0 +1,220 @@
+package composite
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// traditional is the classical component of a composite algorithm, with
+// the key and signature encodings of the composite draft.
+type traditional interface {
+	generateKey(rand io.Reader) (crypto.Signer, error)
+	marshalPublicKey(pub crypto.PublicKey) ([]byte, error)
+	parsePublicKey(b []byte) (crypto.PublicKey, error)
+	marshalPrivateKey(priv crypto.Signer) ([]byte, error)
+	parsePrivateKey(b []byte) (crypto.Signer, error)
+	sign(rand io.Reader, priv crypto.Signer, message []byte) ([]byte, error)
+	verify(pub crypto.PublicKey, message, sig []byte) bool
+}
+
+// tradEd25519 encodes public keys as 32 raw bytes and private keys as
+// their 32-byte seed.
+type tradEd25519 struct{}
+
+func (tradEd25519) generateKey(rand io.Reader) (crypto.Signer, error) {
+	_, priv, err := ed25519.GenerateKey(rand)
+	return priv, err
+}
+
+func (tradEd25519) marshalPublicKey(pub crypto.PublicKey) ([]byte, error) {
+	k, ok := pub.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("composite: expected an Ed25519 key, got %T", pub)
+	}
+	return k, nil
+}
+
+func (tradEd25519) parsePublicKey(b []byte) (crypto.PublicKey, error) {
+	if len(b) != ed25519.PublicKeySize {
+		return nil, errors.New("composite: invalid Ed25519 public key")
+	}
+	return ed25519.PublicKey(b), nil
+}
+
+func (tradEd25519) marshalPrivateKey(priv crypto.Signer) ([]byte, error) {
+	k, ok := priv.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("composite: expected an Ed25519 key, got %T", priv)
+	}
+	return k.Seed(), nil
+}
+
+func (tradEd25519) parsePrivateKey(b []byte) (crypto.Signer, error) {
+	if len(b) != ed25519.SeedSize {
+		return nil, errors.New("composite: invalid Ed25519 private key")
+	}
+	return ed25519.NewKeyFromSeed(b), nil
+}
+
+func (tradEd25519) sign(_ io.Reader, priv crypto.Signer, message []byte) ([]byte, error) {
+	return ed25519.Sign(priv.(ed25519.PrivateKey), message), nil
+}
+
+func (tradEd25519) verify(pub crypto.PublicKey, message, sig []byte) bool {
+	k, ok := pub.(ed25519.PublicKey)
+	return ok && ed25519.Verify(k, message, sig)
+}
+
+// tradECDSA encodes public keys as uncompressed points, private keys as
+// ECPrivateKey structures (RFC 5915) and signatures as DER Ecdsa-Sig-Value.
+type tradECDSA struct {
+	curve elliptic.Curve
+	ecdh  ecdh.Curve
+	hash  crypto.Hash
+}
+
+func (t tradECDSA) generateKey(rand io.Reader) (crypto.Signer, error) {
+	return ecdsa.GenerateKey(t.curve, rand)
+}
+
+func (t tradECDSA) marshalPublicKey(pub crypto.PublicKey) ([]byte, error) {
+	k, ok := pub.(*ecdsa.PublicKey)
+	if !ok || k.Curve != t.curve {
+		return nil, fmt.Errorf("composite: expected an ECDSA %s key", t.curve.Params().Name)
+	}
+	e, err := k.ECDH()
+	if err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+func (t tradECDSA) parsePublicKey(b []byte) (crypto.PublicKey, error) {
+	// crypto/ecdh checks that the uncompressed point is on the curve.
+	if _, err := t.ecdh.NewPublicKey(b); err != nil || b[0] != 4 {
+		return nil, fmt.Errorf("composite: invalid ECDSA %s public key", t.curve.Params().Name)
+	}
+	n := (len(b) - 1) / 2
+	return &ecdsa.PublicKey{
+		Curve: t.curve,
+		X:     new(big.Int).SetBytes(b[1 : 1+n]),
+		Y:     new(big.Int).SetBytes(b[1+n:]),
+	}, nil
+}
+
+func (t tradECDSA) marshalPrivateKey(priv crypto.Signer) ([]byte, error) {
+	k, ok := priv.(*ecdsa.PrivateKey)
+	if !ok || k.Curve != t.curve {
+		return nil, fmt.Errorf("composite: expected an ECDSA %s key", t.curve.Params().Name)
+	}
+	return x509.MarshalECPrivateKey(k)
+}
+
+func (t tradECDSA) parsePrivateKey(b []byte) (crypto.Signer, error) {
+	k, err := x509.ParseECPrivateKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("composite: %w", err)
+	}
+	if k.Curve != t.curve {
+		return nil, fmt.Errorf("composite: expected an ECDSA %s key", t.curve.Params().Name)
+	}
+	return k, nil
+}
+
+func (t tradECDSA) sign(rand io.Reader, priv crypto.Signer, message []byte) ([]byte, error) {
+	h := t.hash.New()
+	h.Write(message)
+	return ecdsa.SignASN1(rand, priv.(*ecdsa.PrivateKey), h.Sum(nil))
+}
+
+func (t tradECDSA) verify(pub crypto.PublicKey, message, sig []byte) bool {
+	k, ok := pub.(*ecdsa.PublicKey)
+	if !ok || k.Curve != t.curve {
+		return false
+	}
+	h := t.hash.New()
+	h.Write(message)
+	return ecdsa.VerifyASN1(k, h.Sum(nil), sig)
+}
+
+// tradRSA encodes keys as PKCS #1 RSAPublicKey and RSAPrivateKey
+// structures. PSS signatures use MGF1 with the same hash and a salt as
+// long as the hash.
+type tradRSA struct {
+	bits int
+	hash crypto.Hash
+	pss  bool
+}
+
+func (t tradRSA) generateKey(rand io.Reader) (crypto.Signer, error) {
+	return rsa.GenerateKey(rand, t.bits)
+}
+
+func (t tradRSA) marshalPublicKey(pub crypto.PublicKey) ([]byte, error) {
+	k, ok := pub.(*rsa.PublicKey)
+	if !ok || k.N.BitLen() != t.bits {
+		return nil, fmt.Errorf("composite: expected an RSA-%d key", t.bits)
+	}
+	return x509.MarshalPKCS1PublicKey(k), nil
+}
+
+func (t tradRSA) parsePublicKey(b []byte) (crypto.PublicKey, error) {
+	k, err := x509.ParsePKCS1PublicKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("composite: %w", err)
+	}
+	if k.N.BitLen() != t.bits {
+		return nil, fmt.Errorf("composite: expected an RSA-%d key", t.bits)
+	}
+	return k, nil
+}
+
+func (t tradRSA) marshalPrivateKey(priv crypto.Signer) ([]byte, error) {
+	k, ok := priv.(*rsa.PrivateKey)
+	if !ok || k.N.BitLen() != t.bits {
+		return nil, fmt.Errorf("composite: expected an RSA-%d key", t.bits)
+	}
+	return x509.MarshalPKCS1PrivateKey(k), nil
+}
+
+func (t tradRSA) parsePrivateKey(b []byte) (crypto.Signer, error) {
+	k, err := x509.ParsePKCS1PrivateKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("composite: %w", err)
+	}
+	if k.N.BitLen() != t.bits {
+		return nil, fmt.Errorf("composite: expected an RSA-%d key", t.bits)
+	}
+	return k, nil
+}
+
+func (t tradRSA) sign(rand io.Reader, priv crypto.Signer, message []byte) ([]byte, error) {
+	h := t.hash.New()
+	h.Write(message)
+	if t.pss {
+		return rsa.SignPSS(rand, priv.(*rsa.PrivateKey), t.hash, h.Sum(nil), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	}
+	return rsa.SignPKCS1v15(rand, priv.(*rsa.PrivateKey), t.hash, h.Sum(nil))
+}
+
+func (t tradRSA) verify(pub crypto.PublicKey, message, sig []byte) bool {
+	k, ok := pub.(*rsa.PublicKey)
+	if !ok || k.N.BitLen() != t.bits {
+		return false
+	}
+	h := t.hash.New()
+	h.Write(message)
+	if t.pss {
+		return rsa.VerifyPSS(k, t.hash, h.Sum(nil), sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+	}
+	return rsa.VerifyPKCS1v15(k, t.hash, h.Sum(nil), sig) == nil
+}
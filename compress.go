@@ -9,11 +9,11 @@ func Power2Round(r FieldElement) (r1, r0 FieldElement) {
 
 	const half = 1 << (D - 1) // 4096
 
-	// If r0 > half, adjust to centered representation
-	if r0 > half {
-		r0 = fieldSub(r0, 1<<D)
-		r1++
-	}
+	// If r0 > half, adjust to centered representation: r0 - 2^D mod Q.
+	// r0 is secret during key generation, so this must not branch.
+	mask := FieldElement(int32(half-r0) >> 31)
+	r0 += (Q - 1<<D) & mask
+	r1 += 1 & mask
 	return r1, r0
 }
 
@@ -51,10 +51,8 @@ func Decompose(r FieldElement, gamma2 uint32) (r1 uint32, r0 int32) {
 // Implements FIPS 204 Algorithm 39.
 func MakeHint(z, r FieldElement, gamma2 uint32) FieldElement {
 	r0 := fieldAdd(r, z)
-	if HighBits(r0, gamma2) != HighBits(r, gamma2) {
-		return 1
-	}
-	return 0
+	x := HighBits(r0, gamma2) ^ HighBits(r, gamma2)
+	return FieldElement((x | -x) >> 31)
 }
 
 // UseHint uses the hint to recover the correct high bits.
@@ -88,20 +86,21 @@ func UseHint(hint, r FieldElement, gamma2 uint32) FieldElement {
 // InfinityNorm computes |a|, where a is interpreted as signed mod Q.
 // Returns min(a, Q-a).
 func InfinityNorm(a FieldElement) uint32 {
-	if uint32(a) <= QMinus1Div2 {
-		return uint32(a)
-	}
-	return Q - uint32(a)
+	mask := uint32(int32(QMinus1Div2-uint32(a)) >> 31) // a > (Q-1)/2
+	return uint32(a) ^ ((uint32(a) ^ (Q - uint32(a))) & mask)
+}
+
+// ctMax returns the larger of a and b, which must be below 2^31, without
+// branching on them.
+func ctMax(a, b uint32) uint32 {
+	return a ^ ((a ^ b) & uint32(int32(a-b)>>31))
 }
 
 // PolyInfinityNorm returns the maximum absolute value of any coefficient.
 func PolyInfinityNorm[T ~[N]FieldElement](f T) uint32 {
 	var max uint32
 	for i := range f {
-		v := InfinityNorm(f[i])
-		if v > max {
-			max = v
-		}
+		max = ctMax(max, InfinityNorm(f[i]))
 	}
 	return max
 }
@@ -110,10 +109,7 @@ func PolyInfinityNorm[T ~[N]FieldElement](f T) uint32 {
 func VectorInfinityNorm[T ~[N]FieldElement](v []T) uint32 {
 	var max uint32
 	for i := range v {
-		norm := PolyInfinityNorm(v[i])
-		if norm > max {
-			max = norm
-		}
+		max = ctMax(max, PolyInfinityNorm(v[i]))
 	}
 	return max
 }
@@ -124,12 +120,9 @@ func vectorInfinityNormSigned(v [][N]int32) int32 {
 	for i := range v {
 		for j := range v[i] {
 			val := v[i][j]
-			if val < 0 {
-				val = -val
-			}
-			if val > max {
-				max = val
-			}
+			sign := val >> 31
+			val = (val ^ sign) - sign
+			max ^= (max ^ val) & ((max - val) >> 31)
 		}
 	}
 	return max
@@ -140,9 +133,8 @@ func CountOnes[T ~[N]FieldElement](v []T) int {
 	count := 0
 	for i := range v {
 		for j := range v[i] {
-			if v[i][j] != 0 {
-				count++
-			}
+			x := uint32(v[i][j])
+			count += int((x | -x) >> 31)
 		}
 	}
 	return count
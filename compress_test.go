@@ -0,0 +1,91 @@
+package mldsa
+
+import (
+	mrand "math/rand/v2"
+	"testing"
+)
+
+// The norm and hint helpers avoid branches on their inputs; check them
+// against plain implementations, including at the boundaries of the masks.
+
+func refInfinityNorm(a FieldElement) uint32 {
+	if uint32(a) > QMinus1Div2 {
+		return Q - uint32(a)
+	}
+	return uint32(a)
+}
+
+func TestInfinityNorm(t *testing.T) {
+	values := []FieldElement{0, 1, QMinus1Div2 - 1, QMinus1Div2, QMinus1Div2 + 1, Q - 2, Q - 1}
+	for i := 0; i < 1000; i++ {
+		values = append(values, FieldElement(mrand.Uint32N(Q)))
+	}
+	for _, a := range values {
+		if got, want := InfinityNorm(a), refInfinityNorm(a); got != want {
+			t.Errorf("InfinityNorm(%d) = %d, want %d", a, got, want)
+		}
+	}
+
+	for _, pair := range [][2]uint32{{0, 0}, {0, 1}, {1, 0}, {5, 5}, {1<<31 - 1, 0}, {0, 1<<31 - 1}, {QMinus1Div2, QMinus1Div2 + 1}} {
+		if got, want := ctMax(pair[0], pair[1]), max(pair[0], pair[1]); got != want {
+			t.Errorf("ctMax(%d, %d) = %d, want %d", pair[0], pair[1], got, want)
+		}
+	}
+}
+
+func TestVectorNorms(t *testing.T) {
+	for trial := 0; trial < 100; trial++ {
+		v := make([]RingElement, 4)
+		s := make([][N]int32, 4)
+		var wantNorm uint32
+		var wantSigned int32
+		wantOnes := 0
+		for i := range v {
+			for j := range v[i] {
+				// Mix small values, values near the centre and zeros.
+				switch mrand.IntN(3) {
+				case 0:
+					v[i][j] = FieldElement(mrand.Uint32N(Q))
+				case 1:
+					v[i][j] = FieldElement(QMinus1Div2 + mrand.Uint32N(3) - 1)
+				}
+				wantNorm = max(wantNorm, refInfinityNorm(v[i][j]))
+				if v[i][j] != 0 {
+					wantOnes++
+				}
+				s[i][j] = mrand.Int32N(1<<24) - 1<<23
+				wantSigned = max(wantSigned, s[i][j], -s[i][j])
+			}
+		}
+		if got := VectorInfinityNorm(v); got != wantNorm {
+			t.Fatalf("VectorInfinityNorm = %d, want %d", got, wantNorm)
+		}
+		if got := vectorInfinityNormSigned(s); got != wantSigned {
+			t.Fatalf("vectorInfinityNormSigned = %d, want %d", got, wantSigned)
+		}
+		if got := CountOnes(v); got != wantOnes {
+			t.Fatalf("CountOnes = %d, want %d", got, wantOnes)
+		}
+	}
+}
+
+func TestPower2Round(t *testing.T) {
+	values := []FieldElement{0, 1<<(D-1) - 1, 1 << (D - 1), 1<<(D-1) + 1, 1<<D - 1, 1 << D, Q - 1}
+	for i := 0; i < 1000; i++ {
+		values = append(values, FieldElement(mrand.Uint32N(Q)))
+	}
+	for _, r := range values {
+		r1, r0 := Power2Round(r)
+		// r0 is centered in (-2^(D-1), 2^(D-1)], and r = r1*2^D + r0 mod Q.
+		c := int32(r0)
+		if c > QMinus1Div2 {
+			c -= Q
+		}
+		if c <= -1<<(D-1) || c > 1<<(D-1) {
+			t.Errorf("Power2Round(%d): r0 = %d out of range", r, c)
+		}
+		if got := fieldAdd(FieldElement(r1<<D), r0); got != r {
+			t.Errorf("Power2Round(%d) = (%d, %d), which recombines to %d", r, r1, r0, got)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package mldsa
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/KarpelesLab/mldsa/internal/fips204"
+)
+
+func init() {
+	fips204.SignInternal = signInternalHook
+	fips204.VerifyInternal = verifyInternalHook
+}
+
+// signInternalHook implements fips204.SignInternal for the key types of this
+// package.
+func signInternalHook(sk crypto.Signer, rnd, mPrime []byte) ([]byte, error) {
+	var priv interface {
+		signInternal(dst, rnd, mPrime []byte) ([]byte, error)
+	}
+	var destroyed bool
+	switch k := sk.(type) {
+	case *PrivateKey44:
+		priv, destroyed = k, k.destroyed
+	case *PrivateKey65:
+		priv, destroyed = k, k.destroyed
+	case *PrivateKey87:
+		priv, destroyed = k, k.destroyed
+	case *Key44:
+		priv, destroyed = k, k.destroyed
+	case *Key65:
+		priv, destroyed = k, k.destroyed
+	case *Key87:
+		priv, destroyed = k, k.destroyed
+	default:
+		return nil, fmt.Errorf("mldsa: unsupported private key type %T", sk)
+	}
+	if destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return priv.signInternal(nil, rnd, mPrime)
+}
+
+// verifyInternalHook implements fips204.VerifyInternal for the key types of
+// this package.
+func verifyInternalHook(pk crypto.PublicKey, sig, mPrime []byte) error {
+	pub, ok := pk.(interface {
+		PublicKey
+		verifyInternal(sig, mPrime []byte) error
+	})
+	if !ok {
+		return fmt.Errorf("mldsa: unsupported public key type %T", pk)
+	}
+	if want := pub.Scheme().SignatureSize(); len(sig) != want {
+		return &LengthError{Err: ErrBadSignatureLength, Got: len(sig), Want: want}
+	}
+	return pub.verifyInternal(sig, mPrime)
+}
@@ -0,0 +1,38 @@
+// Package conformance exposes the internal ML-DSA algorithms of FIPS 204,
+// ML-DSA.Sign_internal (Algorithm 7) and ML-DSA.Verify_internal (Algorithm 8),
+// for accredited testing laboratories and ACVP-style conformance harnesses.
+//
+// These functions take the message representative M' and the signing
+// randomness rnd directly. They perform no domain separation and no
+// hedging, so misuse can produce signatures that collide with other
+// protocols or leak the private key through reused randomness. Applications
+// must use the signing and verification methods of the mldsa package instead.
+package conformance
+
+import (
+	"fmt"
+
+	"github.com/KarpelesLab/mldsa"
+	"github.com/KarpelesLab/mldsa/internal/fips204"
+)
+
+// RandomnessSize is the size in bytes of the rnd input of SignInternal.
+const RandomnessSize = 32
+
+// SignInternal runs ML-DSA.Sign_internal with the private key sk, the
+// 32-byte randomness rnd and the message representative mPrime, which is
+// used verbatim. sk must be one of the private key or key pair types of the
+// mldsa package.
+func SignInternal(sk mldsa.PrivateKey, rnd, mPrime []byte) ([]byte, error) {
+	if len(rnd) != RandomnessSize {
+		return nil, fmt.Errorf("conformance: rnd must be %d bytes, got %d", RandomnessSize, len(rnd))
+	}
+	return fips204.SignInternal(sk, rnd, mPrime)
+}
+
+// VerifyInternal runs ML-DSA.Verify_internal with the public key pk on sig
+// and the message representative mPrime. It returns nil if the signature is
+// valid, or one of the verification errors of the mldsa package otherwise.
+func VerifyInternal(pk mldsa.PublicKey, sig, mPrime []byte) error {
+	return fips204.VerifyInternal(pk, sig, mPrime)
+}
@@ -0,0 +1,73 @@
+package conformance
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+	"github.com/KarpelesLab/mldsa/mldsatest"
+)
+
+func TestInternalMatchesFixtures(t *testing.T) {
+	for _, f := range mldsatest.Fixtures() {
+		sk, err := mldsa.ParsePrivateKey(f.PrivateKey, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", f.ParameterSet, err)
+		}
+		pk, _ := mldsa.ParsePublicKey(f.PublicKey)
+
+		// M' = 0 || len(ctx) || ctx || M, signed with the deterministic rnd.
+		mPrime := append([]byte{0, byte(len(f.Context))}, f.Context...)
+		mPrime = append(mPrime, f.Message...)
+		sig, err := SignInternal(sk, make([]byte, RandomnessSize), mPrime)
+		if err != nil {
+			t.Fatalf("%s: SignInternal failed: %v", f.ParameterSet, err)
+		}
+		if !bytes.Equal(sig, f.Signature) {
+			t.Errorf("%s: SignInternal does not reproduce the fixture", f.ParameterSet)
+		}
+		if err := VerifyInternal(pk, sig, mPrime); err != nil {
+			t.Errorf("%s: VerifyInternal failed: %v", f.ParameterSet, err)
+		}
+		if err := VerifyInternal(pk, sig, f.Message); !errors.Is(err, mldsa.ErrMismatch) {
+			t.Errorf("%s: VerifyInternal with the wrong M': %v", f.ParameterSet, err)
+		}
+		if err := VerifyInternal(pk, sig[1:], mPrime); !errors.Is(err, mldsa.ErrBadSignatureLength) {
+			t.Errorf("%s: VerifyInternal with a short signature: %v", f.ParameterSet, err)
+		}
+	}
+}
+
+func TestSignInternalErrors(t *testing.T) {
+	key := mldsatest.Key44()
+	if _, err := SignInternal(key, make([]byte, 31), nil); err == nil {
+		t.Error("SignInternal accepted a short rnd")
+	}
+	key, _ = mldsa.NewKey44(mldsatest.Seed("ML-DSA-44"))
+	key.Destroy()
+	if _, err := SignInternal(key, make([]byte, RandomnessSize), nil); !errors.Is(err, mldsa.ErrKeyDestroyed) {
+		t.Errorf("SignInternal with a destroyed key: %v", err)
+	}
+}
+
+func TestVerifyInternalMalformed(t *testing.T) {
+	for _, s := range mldsa.Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		pk := key.Public().(mldsa.PublicKey)
+		for _, n := range []int{0, 1, s.SignatureSize() - 1, s.SignatureSize() + 1} {
+			if err := VerifyInternal(pk, make([]byte, n), nil); !errors.Is(err, mldsa.ErrBadSignatureLength) {
+				t.Errorf("%s: VerifyInternal with a %d-byte signature: %v", s.Name(), n, err)
+			}
+		}
+		// All-0xff hint bytes exceed omega.
+		sig := make([]byte, s.SignatureSize())
+		for i := range sig {
+			sig[i] = 0xff
+		}
+		if err := VerifyInternal(pk, sig, nil); err == nil {
+			t.Errorf("%s: VerifyInternal accepted garbage", s.Name())
+		}
+	}
+}
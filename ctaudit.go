@@ -0,0 +1,49 @@
+//go:build mldsa_ctaudit
+
+package mldsa
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// AuditHooks instrument signing for side-channel audits. They exist only
+// in builds with the mldsa_ctaudit tag; in other builds the
+// instrumentation compiles away.
+type AuditHooks struct {
+	// Secret is called with each buffer that holds secret or
+	// secret-dependent values while signing, labelled with its role:
+	// "rhoPrime", "s1NTT", "s2NTT", "t0NTT", "y", "yNTT", "w", "z", "cs2",
+	// "r0", "ct0" and "hints". Harnesses can record, taint or inspect the
+	// memory, for instance to check that it is wiped afterwards.
+	Secret func(label string, b []byte)
+
+	// Phase is called as signing enters each phase: "expand" before the
+	// secret vectors are prepared, "attempt" at the start of every
+	// iteration of the rejection loop, "checks" once z, the challenge and
+	// w1 are computed and the norm checks begin, and "encode" when an
+	// attempt is accepted. The span from "attempt" to "checks" is the
+	// secret-dependent arithmetic, whose timing must not depend on the key;
+	// a dudect-style harness such as the ctaudit package can time it.
+	Phase func(phase string)
+}
+
+var auditHooks atomic.Pointer[AuditHooks]
+
+// SetAuditHooks installs h, or removes the hooks if h is nil. The hooks
+// run synchronously on the signing goroutine.
+func SetAuditHooks(h *AuditHooks) {
+	auditHooks.Store(h)
+}
+
+func auditSecret(label string, p unsafe.Pointer, size uintptr) {
+	if h := auditHooks.Load(); h != nil && h.Secret != nil {
+		h.Secret(label, unsafe.Slice((*byte)(p), size))
+	}
+}
+
+func auditPhase(phase string) {
+	if h := auditHooks.Load(); h != nil && h.Phase != nil {
+		h.Phase(phase)
+	}
+}
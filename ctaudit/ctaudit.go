@@ -0,0 +1,110 @@
+// Package ctaudit implements the statistical timing test of dudect
+// (Reparaz, Balasch and Verbauwhede, "Dude, is my code constant time?",
+// DATE 2017), so that security teams can check the side-channel posture of
+// the mldsa package on their own hardware and toolchain.
+//
+// The timings of an operation are collected for two classes of secret
+// inputs, typically one fixed secret and random secrets, and Welch's
+// t-test compares their distributions. A large |t| shows that the timing
+// depends on the secret; a small one after many measurements is evidence,
+// not proof, that it does not. As in dudect, the test is repeated on
+// measurements cropped at a range of percentiles, which removes the long
+// tail caused by interrupts and scheduling, and the largest |t| is kept.
+//
+// The mldsa package instruments signing in builds with the mldsa_ctaudit
+// tag; see mldsa.AuditHooks.
+package ctaudit
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// Threshold is the |t| above which timings are taken to depend on the
+// input class, as in dudect.
+const Threshold = 10
+
+// percentiles is the number of cropped tests, as in dudect.
+const percentiles = 100
+
+// Test accumulates timing measurements of two input classes. The zero
+// value is ready to use. A Test is not safe for concurrent use.
+type Test struct {
+	samples [2][]float64
+}
+
+// Add records that an operation on an input of class 0 or 1 took d.
+func (t *Test) Add(class int, d time.Duration) {
+	t.samples[class&1] = append(t.samples[class&1], float64(d))
+}
+
+// Measurements returns the number of measurements recorded.
+func (t *Test) Measurements() int {
+	return len(t.samples[0]) + len(t.samples[1])
+}
+
+// T returns the largest |t| of Welch's t-test over all measurements and
+// over the measurements below each cropping percentile. It returns 0 until
+// each class has at least two measurements.
+func (t *Test) T() float64 {
+	all := slices.Concat(t.samples[0], t.samples[1])
+	if len(all) == 0 {
+		return 0
+	}
+	slices.Sort(all)
+	max := welch(t.samples, math.Inf(1))
+	for i := 0; i < percentiles; i++ {
+		p := 1 - math.Pow(0.5, 10*float64(i+1)/percentiles)
+		max = math.Max(max, welch(t.samples, all[int(p*float64(len(all)-1))]))
+	}
+	return max
+}
+
+// Leaky reports whether T exceeds Threshold.
+func (t *Test) Leaky() bool {
+	return t.T() > Threshold
+}
+
+// welch returns |t| of Welch's t-test on the measurements of the two
+// classes below limit.
+func welch(samples [2][]float64, limit float64) float64 {
+	var n, mean, m2 [2]float64
+	for c := range samples {
+		for _, x := range samples[c] {
+			if x >= limit {
+				continue
+			}
+			// Welford's online algorithm.
+			n[c]++
+			delta := x - mean[c]
+			mean[c] += delta / n[c]
+			m2[c] += delta * (x - mean[c])
+		}
+	}
+	if n[0] < 2 || n[1] < 2 {
+		return 0
+	}
+	se := math.Sqrt(m2[0]/(n[0]-1)/n[0] + m2[1]/(n[1]-1)/n[1])
+	if se == 0 {
+		return 0
+	}
+	return math.Abs(mean[0]-mean[1]) / se
+}
+
+// Run times n operations, drawing the input class of each at random, and
+// returns the resulting Test. prepare is called outside the timed region
+// to set up an input of the given class, and returns the operation to
+// time.
+func Run(n int, prepare func(class int) func()) *Test {
+	t := new(Test)
+	for i := 0; i < n; i++ {
+		class := rand.IntN(2)
+		op := prepare(class)
+		start := time.Now()
+		op()
+		t.Add(class, time.Since(start))
+	}
+	return t
+}
@@ -0,0 +1,45 @@
+package ctaudit
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestSameDistribution(t *testing.T) {
+	var test Test
+	for i := 0; i < 20000; i++ {
+		test.Add(i%2, time.Duration(1000+rand.IntN(100)))
+	}
+	if test.Leaky() {
+		t.Errorf("identical distributions flagged: |t| = %.2f", test.T())
+	}
+	if test.Measurements() != 20000 {
+		t.Errorf("Measurements = %d", test.Measurements())
+	}
+}
+
+func TestShiftedDistribution(t *testing.T) {
+	var test Test
+	for i := 0; i < 20000; i++ {
+		test.Add(i%2, time.Duration(1000+rand.IntN(100)+5*(i%2)))
+	}
+	if !test.Leaky() {
+		t.Errorf("shifted distributions not flagged: |t| = %.2f", test.T())
+	}
+}
+
+func TestRun(t *testing.T) {
+	var classes [2]int
+	test := Run(1000, func(class int) func() {
+		classes[class]++
+		return func() {}
+	})
+	if test.Measurements() != 1000 || classes[0] == 0 || classes[1] == 0 {
+		t.Errorf("Run recorded %d measurements, classes %v", test.Measurements(), classes)
+	}
+	var empty Test
+	if empty.T() != 0 {
+		t.Error("T of an empty test is not 0")
+	}
+}
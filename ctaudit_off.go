@@ -0,0 +1,12 @@
+//go:build !mldsa_ctaudit
+
+package mldsa
+
+import "unsafe"
+
+// auditSecret and auditPhase report to the hooks of builds with the
+// mldsa_ctaudit tag, see ctaudit.go. They are no-ops otherwise.
+
+func auditSecret(label string, p unsafe.Pointer, size uintptr) {}
+
+func auditPhase(phase string) {}
@@ -0,0 +1,150 @@
+//go:build mldsa_ctaudit
+
+package mldsa
+
+import (
+	"crypto/rand"
+	mrand "math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/mldsa/ctaudit"
+)
+
+// auditEnabled reports whether the audit hooks are compiled in. Handing
+// secret buffers to them moves stack values to the heap, so allocation
+// counts are not meaningful.
+const auditEnabled = true
+
+func TestAuditHooks(t *testing.T) {
+	key, _ := GenerateKey44(rand.Reader)
+	secrets := map[string]int{}
+	var phases []string
+	SetAuditHooks(&AuditHooks{
+		Secret: func(label string, b []byte) { secrets[label] = len(b) },
+		Phase:  func(phase string) { phases = append(phases, phase) },
+	})
+	defer SetAuditHooks(nil)
+	if _, err := key.SignWithContext(rand.Reader, []byte("message"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, label := range []string{"rhoPrime", "s1NTT", "s2NTT", "t0NTT", "y", "yNTT", "w", "z", "cs2", "r0", "ct0", "hints"} {
+		if secrets[label] == 0 {
+			t.Errorf("secret %q not reported", label)
+		}
+	}
+	if secrets["s1NTT"] != L44*N*4 {
+		t.Errorf("s1NTT reported as %d bytes", secrets["s1NTT"])
+	}
+	if len(phases) < 4 || phases[0] != "expand" || phases[1] != "attempt" || phases[len(phases)-1] != "encode" {
+		t.Errorf("unexpected phases %v", phases)
+	}
+}
+
+// TestSignWipesSecrets checks that no reported secret survives signing.
+func TestSignWipesSecrets(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	secrets := map[string][]byte{}
+	SetAuditHooks(&AuditHooks{Secret: func(label string, b []byte) { secrets[label] = b }})
+	defer SetAuditHooks(nil)
+	if _, err := key.SignWithContext(rand.Reader, []byte("message"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for label, b := range secrets {
+		for _, v := range b {
+			if v != 0 {
+				t.Errorf("secret %q not wiped", label)
+				break
+			}
+		}
+	}
+}
+
+// TestSignAttemptTiming runs the dudect test on the secret-dependent span
+// of signing attempts, with a fixed key against random keys.
+func TestSignAttemptTiming(t *testing.T) {
+	if testing.Short() {
+		t.Skip("timing test")
+	}
+	fixed, _ := GenerateKey44(rand.Reader)
+	fixed.Precompute()
+	var keys []*Key44
+	for i := 0; i < 16; i++ {
+		k, _ := GenerateKey44(rand.Reader)
+		k.Precompute()
+		keys = append(keys, k)
+	}
+
+	var test ctaudit.Test
+	var class int
+	var start time.Time
+	SetAuditHooks(&AuditHooks{Phase: func(phase string) {
+		switch phase {
+		case "attempt":
+			start = time.Now()
+		case "checks":
+			test.Add(class, time.Since(start))
+		}
+	}})
+	defer SetAuditHooks(nil)
+
+	message := []byte("message")
+	for i := 0; i < 5000; i++ {
+		class = mrand.IntN(2)
+		key := fixed
+		if class == 1 {
+			key = keys[i%len(keys)]
+		}
+		if _, err := key.SignWithContext(rand.Reader, message, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Logf("max |t| = %.2f over %d attempts", test.T(), test.Measurements())
+	if test.Leaky() {
+		t.Errorf("attempt timing depends on the key: |t| = %.2f", test.T())
+	}
+}
+
+// TestBoundCheckTiming runs the dudect test on the norms and hint count
+// computed by the checks of signing, with all-zero vectors against random
+// vectors of coefficients up to the rejection bounds.
+func TestBoundCheckTiming(t *testing.T) {
+	if testing.Short() {
+		t.Skip("timing test")
+	}
+	var z [L87]RingElement
+	var r0 [K87][N]int32
+	var ct0, hints [K87]RingElement
+	var sink int
+	test := ctaudit.Run(20000, func(class int) func() {
+		// Both classes draw the same random numbers, so that preparing
+		// them leaves the caches and predictors in the same state.
+		keep := uint32(class)
+		for i := range z {
+			for j := range z[i] {
+				z[i][j] = fieldSub(FieldElement(Gamma1Pow19), FieldElement(mrand.Uint32N(2*Gamma1Pow19))) * FieldElement(keep)
+			}
+		}
+		for i := range r0 {
+			for j := range r0[i] {
+				r0[i][j] = (mrand.Int32N(2*Gamma2QMinus1Div32) - Gamma2QMinus1Div32) * int32(keep)
+				ct0[i][j] = fieldSub(FieldElement(Gamma2QMinus1Div32), FieldElement(mrand.Uint32N(2*Gamma2QMinus1Div32))) * FieldElement(keep)
+				hints[i][j] = FieldElement(mrand.Uint32N(2) * keep)
+			}
+		}
+		// The comparisons with the bounds are left out: the rejection
+		// decision they make is public.
+		return func() {
+			sink += int(VectorInfinityNorm(z[:]))
+			sink += int(vectorInfinityNormSigned(r0[:]))
+			sink += int(VectorInfinityNorm(ct0[:]))
+			sink += CountOnes(hints[:])
+		}
+	})
+	t.Logf("max |t| = %.2f over %d measurements", test.T(), test.Measurements())
+	if test.Leaky() {
+		t.Errorf("bound check timing depends on the coefficients: |t| = %.2f", test.T())
+	}
+}
@@ -0,0 +1,200 @@
+// Package dsse implements Dead Simple Signing Envelopes (DSSE v1) with
+// ML-DSA keys, the envelope used by in-toto attestations and SLSA
+// provenance.
+//
+// An envelope carries a payload, its type and any number of signatures over
+// the pre-authentication encoding (PAE) of both. Signatures are pure ML-DSA
+// with an empty context:
+//
+//	env := dsse.New(dsse.PayloadTypeInToto, statement)
+//	err := env.Sign(rand.Reader, dsse.Signer{KeyID: "builder", Key: key})
+//	data, err := json.Marshal(env)
+//	...
+//	env, err := dsse.Parse(data)
+//	accepted, err := env.Verify(1, dsse.Verifier{KeyID: "builder", Key: pub})
+package dsse
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// PayloadTypeInToto is the payload type of in-toto statements.
+const PayloadTypeInToto = "application/vnd.in-toto+json"
+
+// ErrThreshold is returned by Verify when fewer than the required number of
+// verifiers accept a signature of the envelope.
+var ErrThreshold = errors.New("dsse: signature threshold not met")
+
+// PAE returns the DSSE v1 pre-authentication encoding of a payload and its
+// type, which is what signatures cover:
+//
+//	"DSSEv1" SP LEN(type) SP type SP LEN(payload) SP payload
+//
+// where LEN is the length in bytes as ASCII decimal.
+func PAE(payloadType string, payload []byte) []byte {
+	b := []byte("DSSEv1 ")
+	b = strconv.AppendInt(b, int64(len(payloadType)), 10)
+	b = append(b, ' ')
+	b = append(b, payloadType...)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(len(payload)), 10)
+	b = append(b, ' ')
+	return append(b, payload...)
+}
+
+// Envelope is a DSSE envelope. It marshals to the JSON envelope format,
+// with the payload and signatures base64-encoded.
+type Envelope struct {
+	PayloadType string
+	Payload     []byte
+	Signatures  []Signature
+}
+
+// Signature is a signature of an envelope. KeyID is an optional, unsigned
+// hint of the key that made it.
+type Signature struct {
+	KeyID string
+	Sig   []byte
+}
+
+// New returns an unsigned envelope for payload.
+func New(payloadType string, payload []byte) *Envelope {
+	return &Envelope{PayloadType: payloadType, Payload: payload}
+}
+
+// Signer is a signing key with its optional key ID.
+type Signer struct {
+	KeyID string
+	Key   mldsa.PrivateKey
+}
+
+// Verifier is a trusted public key with its optional key ID.
+type Verifier struct {
+	KeyID string
+	Key   mldsa.PublicKey
+}
+
+// Sign appends a signature of e by each signer. If rand is nil,
+// crypto/rand.Reader is used.
+func (e *Envelope) Sign(rand io.Reader, signers ...Signer) error {
+	pae := PAE(e.PayloadType, e.Payload)
+	for _, s := range signers {
+		sig, err := s.Key.SignWithContext(rand, pae, nil)
+		if err != nil {
+			return err
+		}
+		e.Signatures = append(e.Signatures, Signature{KeyID: s.KeyID, Sig: sig})
+	}
+	return nil
+}
+
+// Verify checks the signatures of e against the trusted verifiers and
+// returns the verifiers that accepted one. At least threshold verifiers with
+// distinct keys must accept a signature, or ErrThreshold is returned; threshold
+// must be at least 1. When both a signature and a verifier have a key ID,
+// they must match.
+func (e *Envelope) Verify(threshold int, verifiers ...Verifier) ([]Verifier, error) {
+	if threshold < 1 {
+		return nil, errors.New("dsse: threshold must be at least 1")
+	}
+	if len(e.Signatures) == 0 {
+		return nil, errors.New("dsse: envelope has no signatures")
+	}
+	pae := PAE(e.PayloadType, e.Payload)
+	var accepted []Verifier
+	for _, v := range verifiers {
+		if slices.ContainsFunc(accepted, func(a Verifier) bool { return a.Key.Equal(v.Key) }) {
+			continue
+		}
+		for _, s := range e.Signatures {
+			if s.KeyID != "" && v.KeyID != "" && s.KeyID != v.KeyID {
+				continue
+			}
+			if v.Key.Verify(s.Sig, pae, nil) {
+				accepted = append(accepted, v)
+				break
+			}
+		}
+	}
+	if len(accepted) < threshold {
+		return accepted, fmt.Errorf("%w: %d of %d", ErrThreshold, len(accepted), threshold)
+	}
+	return accepted, nil
+}
+
+type jsonEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     string          `json:"payload"`
+	Signatures  []jsonSignature `json:"signatures"`
+}
+
+type jsonSignature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   string `json:"sig"`
+}
+
+// MarshalJSON encodes e in the DSSE JSON envelope format, using standard
+// base64.
+func (e *Envelope) MarshalJSON() ([]byte, error) {
+	j := jsonEnvelope{
+		PayloadType: e.PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(e.Payload),
+		Signatures:  []jsonSignature{},
+	}
+	for _, s := range e.Signatures {
+		j.Signatures = append(j.Signatures, jsonSignature{KeyID: s.KeyID, Sig: base64.StdEncoding.EncodeToString(s.Sig)})
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes the DSSE JSON envelope format, accepting standard
+// and URL-safe base64, with or without padding, as the specification
+// requires.
+func (e *Envelope) UnmarshalJSON(data []byte) error {
+	var j jsonEnvelope
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	payload, err := decodeBase64(j.Payload)
+	if err != nil {
+		return fmt.Errorf("dsse: malformed payload: %w", err)
+	}
+	*e = Envelope{PayloadType: j.PayloadType, Payload: payload}
+	for _, s := range j.Signatures {
+		sig, err := decodeBase64(s.Sig)
+		if err != nil {
+			return fmt.Errorf("dsse: malformed signature: %w", err)
+		}
+		e.Signatures = append(e.Signatures, Signature{KeyID: s.KeyID, Sig: sig})
+	}
+	return nil
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(s); err == nil {
+			return b, nil
+		}
+	}
+	return nil, errors.New("invalid base64")
+}
+
+// Parse parses a JSON envelope. The signatures are not verified.
+func Parse(data []byte) (*Envelope, error) {
+	e := new(Envelope)
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, err
+	}
+	if e.PayloadType == "" {
+		return nil, errors.New("dsse: missing payload type")
+	}
+	return e, nil
+}
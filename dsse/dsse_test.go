@@ -0,0 +1,92 @@
+package dsse
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestPAE(t *testing.T) {
+	// Test vector from the DSSE protocol specification.
+	got := string(PAE("http://example.com/HelloWorld", []byte("hello world")))
+	want := "DSSEv1 29 http://example.com/HelloWorld 11 hello world"
+	if got != want {
+		t.Errorf("PAE = %q, want %q", got, want)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	statement := []byte(`{"_type":"https://in-toto.io/Statement/v1"}`)
+	for _, s := range mldsa.Schemes() {
+		k1, _ := s.GenerateKey(rand.Reader)
+		k2, _ := s.GenerateKey(rand.Reader)
+		v1 := Verifier{KeyID: "k1", Key: k1.Public().(mldsa.PublicKey)}
+		v2 := Verifier{KeyID: "k2", Key: k2.Public().(mldsa.PublicKey)}
+
+		env := New(PayloadTypeInToto, statement)
+		if err := env.Sign(rand.Reader, Signer{KeyID: "k1", Key: k1}, Signer{Key: k2}); err != nil {
+			t.Fatalf("%s: Sign: %v", s.Name(), err)
+		}
+		data, err := json.Marshal(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := Parse(data)
+		if err != nil {
+			t.Fatalf("%s: Parse: %v", s.Name(), err)
+		}
+		if parsed.PayloadType != PayloadTypeInToto || string(parsed.Payload) != string(statement) || len(parsed.Signatures) != 2 {
+			t.Fatalf("%s: envelope did not round-trip", s.Name())
+		}
+
+		accepted, err := parsed.Verify(2, v1, v2)
+		if err != nil || len(accepted) != 2 {
+			t.Errorf("%s: Verify: %d accepted, %v", s.Name(), len(accepted), err)
+		}
+		if _, err := parsed.Verify(2, v1, v1); !errors.Is(err, ErrThreshold) {
+			t.Errorf("%s: Verify counted a signature twice: %v", s.Name(), err)
+		}
+		// The first signature's key ID does not match, so k1 is rejected.
+		if _, err := parsed.Verify(1, Verifier{KeyID: "other", Key: v1.Key}); !errors.Is(err, ErrThreshold) {
+			t.Errorf("%s: Verify ignored a key ID mismatch: %v", s.Name(), err)
+		}
+
+		parsed.PayloadType = "application/json"
+		if _, err := parsed.Verify(1, v1, v2); !errors.Is(err, ErrThreshold) {
+			t.Errorf("%s: Verify accepted a modified payload type: %v", s.Name(), err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	// URL-safe unpadded base64 must be accepted.
+	data := `{"payloadType":"text/plain","payload":"aGk_","signatures":[{"sig":"-_8"}]}`
+	env, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(env.Payload) != "hi?" || string(env.Signatures[0].Sig) != "\xfb\xff" {
+		t.Errorf("unexpected envelope %+v", env)
+	}
+	for _, bad := range []string{
+		`{"payload":"aGk="}`,
+		`{"payloadType":"t","payload":"!!"}`,
+		`{"payloadType":"t","payload":"","signatures":[{"sig":"!!"}]}`,
+	} {
+		if _, err := Parse([]byte(bad)); err == nil {
+			t.Errorf("Parse accepted %s", bad)
+		}
+	}
+	env = New("t", nil)
+	b, _ := json.Marshal(env)
+	if !strings.Contains(string(b), `"signatures":[]`) {
+		t.Errorf("unsigned envelope marshals as %s", b)
+	}
+	if _, err := env.Verify(1); err == nil {
+		t.Error("Verify accepted an unsigned envelope")
+	}
+}
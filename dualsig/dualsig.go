@@ -0,0 +1,273 @@
+// Package dualsig implements a lightweight dual-signature container: an
+// ML-DSA signature and a classical signature of the same message, checked
+// under an AND or OR policy. It is meant for internal systems that want
+// belt-and-suspenders signatures during the post-quantum migration without
+// the ASN.1 machinery of composite signatures.
+//
+// Each component is an ordinary signature of the message, so it can also be
+// checked on its own: ML-DSA signs the message with the caller's context
+// string, and the classical algorithm signs it as it would standalone. The
+// container is encoded as:
+//
+//	"MLD2" || classical algorithm (1 byte) ||
+//	uint16(len(ML-DSA signature)) || ML-DSA signature ||
+//	uint16(len(classical signature)) || classical signature
+//
+// with big-endian lengths.
+package dualsig
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256" // for crypto.SHA256
+	_ "crypto/sha512" // for crypto.SHA384 and crypto.SHA512
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+const magic = "MLD2"
+
+var (
+	// ErrInvalidSignature is returned when the signatures do not satisfy
+	// the verification policy.
+	ErrInvalidSignature = errors.New("dualsig: invalid signature")
+	// ErrMalformed is returned for containers that cannot be parsed.
+	ErrMalformed = errors.New("dualsig: malformed signature container")
+)
+
+// Algorithm identifies the classical component of a container.
+type Algorithm byte
+
+// Classical algorithms. ECDSA signatures are ASN.1 DER encoded, as
+// produced by crypto/ecdsa; RSA-PSS uses a salt as long as the hash.
+const (
+	Ed25519           Algorithm = 1
+	ECDSAP256SHA256   Algorithm = 2
+	ECDSAP384SHA384   Algorithm = 3
+	ECDSAP521SHA512   Algorithm = 4
+	RSAPSSSHA256      Algorithm = 5
+	RSAPKCS1v15SHA256 Algorithm = 6
+)
+
+// String returns the name of the algorithm.
+func (a Algorithm) String() string {
+	switch a {
+	case Ed25519:
+		return "Ed25519"
+	case ECDSAP256SHA256:
+		return "ECDSA-P256-SHA256"
+	case ECDSAP384SHA384:
+		return "ECDSA-P384-SHA384"
+	case ECDSAP521SHA512:
+		return "ECDSA-P521-SHA512"
+	case RSAPSSSHA256:
+		return "RSA-PSS-SHA256"
+	case RSAPKCS1v15SHA256:
+		return "RSA-PKCS1v15-SHA256"
+	}
+	return fmt.Sprintf("Algorithm(%d)", byte(a))
+}
+
+// hash returns the hash the algorithm applies to the message, or 0 for
+// Ed25519.
+func (a Algorithm) hash() crypto.Hash {
+	switch a {
+	case ECDSAP256SHA256, RSAPSSSHA256, RSAPKCS1v15SHA256:
+		return crypto.SHA256
+	case ECDSAP384SHA384:
+		return crypto.SHA384
+	case ECDSAP521SHA512:
+		return crypto.SHA512
+	}
+	return 0
+}
+
+// algorithmFor returns the algorithm used with a classical public key. RSA
+// keys use RSA-PSS.
+func algorithmFor(pub crypto.PublicKey) (Algorithm, error) {
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		return Ed25519, nil
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return ECDSAP256SHA256, nil
+		case elliptic.P384():
+			return ECDSAP384SHA384, nil
+		case elliptic.P521():
+			return ECDSAP521SHA512, nil
+		}
+	case *rsa.PublicKey:
+		return RSAPSSSHA256, nil
+	}
+	return 0, fmt.Errorf("dualsig: unsupported classical key type %T", pub)
+}
+
+// matches reports whether pub is a key of algorithm a.
+func (a Algorithm) matches(pub crypto.PublicKey) bool {
+	if _, ok := pub.(*rsa.PublicKey); ok {
+		return a == RSAPSSSHA256 || a == RSAPKCS1v15SHA256
+	}
+	got, err := algorithmFor(pub)
+	return err == nil && got == a
+}
+
+// Policy decides which components must verify.
+type Policy int
+
+const (
+	// RequireBoth accepts a container only if both signatures verify.
+	RequireBoth Policy = iota
+	// RequireEither accepts a container if either signature verifies, so
+	// that a break of one algorithm does not lock signers out.
+	RequireEither
+)
+
+// Signer signs with an ML-DSA key and a classical key: an
+// ed25519.PrivateKey, an *ecdsa.PrivateKey on P-256, P-384 or P-521, or an
+// *rsa.PrivateKey.
+type Signer struct {
+	MLDSA     mldsa.PrivateKey
+	Classical crypto.Signer
+
+	// Algorithm optionally selects RSAPKCS1v15SHA256 for RSA keys. If
+	// zero, it is derived from the classical key.
+	Algorithm Algorithm
+}
+
+// Sign returns a container with both signatures of message. The context
+// string only applies to ML-DSA. If rand is nil, crypto/rand.Reader is used
+// by ML-DSA; the classical signature needs a non-nil rand unless it is
+// Ed25519.
+func (s *Signer) Sign(rand io.Reader, message, context []byte) ([]byte, error) {
+	alg := s.Algorithm
+	if alg == 0 {
+		var err error
+		if alg, err = algorithmFor(s.Classical.Public()); err != nil {
+			return nil, err
+		}
+	} else if !alg.matches(s.Classical.Public()) {
+		return nil, fmt.Errorf("dualsig: classical key cannot be used with %v", alg)
+	}
+	m, err := s.MLDSA.SignWithContext(rand, message, context)
+	if err != nil {
+		return nil, err
+	}
+	c, err := signClassical(rand, s.Classical, alg, message)
+	if err != nil {
+		return nil, err
+	}
+	b := append([]byte(magic), byte(alg))
+	b = binary.BigEndian.AppendUint16(b, uint16(len(m)))
+	b = append(b, m...)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(c)))
+	return append(b, c...), nil
+}
+
+func signClassical(rand io.Reader, key crypto.Signer, alg Algorithm, message []byte) ([]byte, error) {
+	h := alg.hash()
+	if h == 0 {
+		return key.Sign(rand, message, crypto.Hash(0))
+	}
+	d := h.New()
+	d.Write(message)
+	var opts crypto.SignerOpts = h
+	if alg == RSAPSSSHA256 {
+		opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: h}
+	}
+	return key.Sign(rand, d.Sum(nil), opts)
+}
+
+func verifyClassical(pub crypto.PublicKey, alg Algorithm, message, sig []byte) bool {
+	if !alg.matches(pub) {
+		return false
+	}
+	if alg == Ed25519 {
+		return ed25519.Verify(pub.(ed25519.PublicKey), message, sig)
+	}
+	d := alg.hash().New()
+	d.Write(message)
+	digest := d.Sum(nil)
+	switch alg {
+	case RSAPSSSHA256:
+		return rsa.VerifyPSS(pub.(*rsa.PublicKey), crypto.SHA256, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+	case RSAPKCS1v15SHA256:
+		return rsa.VerifyPKCS1v15(pub.(*rsa.PublicKey), crypto.SHA256, digest, sig) == nil
+	}
+	return ecdsa.VerifyASN1(pub.(*ecdsa.PublicKey), digest, sig)
+}
+
+// Container is a parsed dual-signature container.
+type Container struct {
+	Algorithm Algorithm
+	MLDSA     []byte
+	Classical []byte
+}
+
+// Parse parses a container without verifying it. The signatures alias b.
+func Parse(b []byte) (*Container, error) {
+	rest, ok := bytes.CutPrefix(b, []byte(magic))
+	if !ok || len(rest) < 1 {
+		return nil, ErrMalformed
+	}
+	c := &Container{Algorithm: Algorithm(rest[0])}
+	if c.Algorithm < Ed25519 || c.Algorithm > RSAPKCS1v15SHA256 {
+		return nil, fmt.Errorf("%w: unknown classical algorithm %d", ErrMalformed, rest[0])
+	}
+	rest = rest[1:]
+	for _, field := range []*[]byte{&c.MLDSA, &c.Classical} {
+		if len(rest) < 2 {
+			return nil, ErrMalformed
+		}
+		n := int(binary.BigEndian.Uint16(rest))
+		if len(rest) < 2+n {
+			return nil, ErrMalformed
+		}
+		*field, rest = rest[2:2+n], rest[2+n:]
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing data", ErrMalformed)
+	}
+	return c, nil
+}
+
+// Verifier checks containers against trusted keys under a policy. With
+// RequireEither, one of the keys may be nil.
+type Verifier struct {
+	MLDSA     mldsa.PublicKey
+	Classical crypto.PublicKey
+	Policy    Policy
+}
+
+// Verify checks the container sig over message and the ML-DSA context.
+func (v *Verifier) Verify(sig, message, context []byte) error {
+	c, err := Parse(sig)
+	if err != nil {
+		return err
+	}
+	// Both components are checked regardless of the policy, so that timing
+	// does not reveal which one failed.
+	mOK := v.MLDSA != nil && v.MLDSA.Verify(c.MLDSA, message, context)
+	cOK := v.Classical != nil && verifyClassical(v.Classical, c.Algorithm, message, c.Classical)
+	switch v.Policy {
+	case RequireBoth:
+		if mOK && cOK {
+			return nil
+		}
+	case RequireEither:
+		if mOK || cOK {
+			return nil
+		}
+	default:
+		return fmt.Errorf("dualsig: unknown policy %d", v.Policy)
+	}
+	return ErrInvalidSignature
+}
@@ -0,0 +1,100 @@
+package dualsig
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestSignVerify(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p521, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	mKey, _ := mldsa.GenerateKey65(rand.Reader)
+	message := []byte("firmware image")
+
+	for _, tc := range []struct {
+		key crypto.Signer
+		alg Algorithm
+	}{
+		{edKey, 0},
+		{p256, 0},
+		{p384, 0},
+		{p521, 0},
+		{rsaKey, 0},
+		{rsaKey, RSAPKCS1v15SHA256},
+	} {
+		s := &Signer{MLDSA: mKey, Classical: tc.key, Algorithm: tc.alg}
+		sig, err := s.Sign(rand.Reader, message, []byte("ctx"))
+		if err != nil {
+			t.Fatalf("%T: Sign: %v", tc.key, err)
+		}
+		c, err := Parse(sig)
+		if err != nil {
+			t.Fatalf("%T: Parse: %v", tc.key, err)
+		}
+		name := c.Algorithm.String()
+		if !mKey.PublicKey().Verify(c.MLDSA, message, []byte("ctx")) {
+			t.Errorf("%s: ML-DSA component does not verify on its own", name)
+		}
+
+		both := &Verifier{MLDSA: mKey.PublicKey(), Classical: tc.key.Public()}
+		if err := both.Verify(sig, message, []byte("ctx")); err != nil {
+			t.Errorf("%s: Verify: %v", name, err)
+		}
+		if err := both.Verify(sig, []byte("other"), []byte("ctx")); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: Verify of another message: %v", name, err)
+		}
+		// A wrong context only breaks the ML-DSA component.
+		if err := both.Verify(sig, message, nil); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: RequireBoth accepted a broken ML-DSA signature: %v", name, err)
+		}
+		either := &Verifier{MLDSA: mKey.PublicKey(), Classical: tc.key.Public(), Policy: RequireEither}
+		if err := either.Verify(sig, message, nil); err != nil {
+			t.Errorf("%s: RequireEither rejected a valid classical signature: %v", name, err)
+		}
+		mldsaOnly := &Verifier{MLDSA: mKey.PublicKey(), Policy: RequireEither}
+		if err := mldsaOnly.Verify(sig, message, []byte("ctx")); err != nil {
+			t.Errorf("%s: RequireEither with only an ML-DSA key: %v", name, err)
+		}
+	}
+
+	other, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	sig, _ := (&Signer{MLDSA: mKey, Classical: p256}).Sign(rand.Reader, message, nil)
+	v := &Verifier{MLDSA: mKey.PublicKey(), Classical: &other.PublicKey}
+	if err := v.Verify(sig, message, nil); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify with a key of another curve: %v", err)
+	}
+	if _, err := (&Signer{MLDSA: mKey, Classical: p256, Algorithm: Ed25519}).Sign(rand.Reader, message, nil); err == nil {
+		t.Error("Sign accepted a mismatched algorithm")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	mKey, _ := mldsa.GenerateKey44(rand.Reader)
+	sig, _ := (&Signer{MLDSA: mKey, Classical: edKey}).Sign(rand.Reader, []byte("m"), nil)
+	bad := bytes.Clone(sig)
+	bad[4] = 99
+	for name, b := range map[string][]byte{
+		"empty":     nil,
+		"magic":     append([]byte("XXXX"), sig[4:]...),
+		"algorithm": bad,
+		"truncated": sig[:len(sig)-1],
+		"trailing":  append(bytes.Clone(sig), 0),
+	} {
+		if _, err := Parse(b); !errors.Is(err, ErrMalformed) {
+			t.Errorf("Parse(%s): %v", name, err)
+		}
+	}
+}
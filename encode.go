@@ -1,7 +1,5 @@
 package mldsa
 
-import "errors"
-
 // PackT1 packs a polynomial with 10-bit coefficients (for public key t1).
 // Each coefficient is in [0, 2^10).
 func PackT1(f RingElement) []byte {
@@ -18,9 +16,20 @@ func PackT1(f RingElement) []byte {
 }
 
 // UnpackT1 unpacks a polynomial with 10-bit coefficients.
-func UnpackT1(b []byte) RingElement {
+// It returns a *LengthError wrapping ErrShortEncoding if b is shorter than
+// EncodingSize10 bytes.
+func UnpackT1(b []byte) (RingElement, error) {
+	if len(b) < EncodingSize10 {
+		return RingElement{}, &LengthError{Err: ErrShortEncoding, Got: len(b), Want: EncodingSize10}
+	}
+	return unpackT1(b), nil
+}
+
+// unpackT1 is UnpackT1 for input known to be long enough.
+func unpackT1(b []byte) RingElement {
 	var f RingElement
-	for i := 0; i < N; i += 4 {
+	i := unpackVec(&f, b, 10)
+	for b = b[i/4*5:]; i < N; i += 4 {
 		x := uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24 | uint64(b[4])<<32
 		f[i] = FieldElement(x & 0x3FF)
 		f[i+1] = FieldElement((x >> 10) & 0x3FF)
@@ -70,11 +79,22 @@ func PackT0(f RingElement) []byte {
 }
 
 // UnpackT0 unpacks a polynomial with 13-bit signed coefficients.
-func UnpackT0(b []byte) RingElement {
+// It returns a *LengthError wrapping ErrShortEncoding if b is shorter than
+// EncodingSize13 bytes.
+func UnpackT0(b []byte) (RingElement, error) {
+	if len(b) < EncodingSize13 {
+		return RingElement{}, &LengthError{Err: ErrShortEncoding, Got: len(b), Want: EncodingSize13}
+	}
+	return unpackT0(b), nil
+}
+
+// unpackT0 is UnpackT0 for input known to be long enough.
+func unpackT0(b []byte) RingElement {
 	var f RingElement
 	const center = 1 << 12
 	const mask = (1 << 13) - 1
-	for i := 0; i < N; i += 8 {
+	i := unpackVec(&f, b, 13)
+	for b = b[i/8*13:]; i < N; i += 8 {
 		x1 := uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24 |
 			uint64(b[4])<<32 | uint64(b[5])<<40 | uint64(b[6])<<48 | uint64(b[7])<<56
 		x2 := uint64(b[8]) | uint64(b[9])<<8 | uint64(b[10])<<16 | uint64(b[11])<<24 | uint64(b[12])<<32
@@ -109,6 +129,9 @@ func PackEta2(f RingElement) []byte {
 
 // UnpackEta2 unpacks a polynomial with coefficients in [-2, 2].
 func UnpackEta2(b []byte) (RingElement, error) {
+	if len(b) < EncodingSize3 {
+		return RingElement{}, &LengthError{Err: ErrInvalidEtaEncoding, Got: len(b), Want: EncodingSize3}
+	}
 	var f RingElement
 	for i := 0; i < N; i += 8 {
 		x := uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
@@ -116,7 +139,7 @@ func UnpackEta2(b []byte) (RingElement, error) {
 		msbs := x & 0o44444444 // octal: select MSB of each 3-bit group
 		mask := (msbs >> 1) | (msbs >> 2)
 		if mask&x != 0 {
-			return RingElement{}, errors.New("mldsa: invalid eta encoding")
+			return RingElement{}, ErrInvalidEtaEncoding
 		}
 		b = b[3:]
 		for j := 0; j < 8; j++ {
@@ -137,6 +160,9 @@ func PackEta4(f RingElement) []byte {
 
 // UnpackEta4 unpacks a polynomial with coefficients in [-4, 4].
 func UnpackEta4(b []byte) (RingElement, error) {
+	if len(b) < EncodingSize4 {
+		return RingElement{}, &LengthError{Err: ErrInvalidEtaEncoding, Got: len(b), Want: EncodingSize4}
+	}
 	var f RingElement
 	for i := 0; i < N; i += 8 {
 		x := uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
@@ -144,7 +170,7 @@ func UnpackEta4(b []byte) (RingElement, error) {
 		msbs := x & 0x88888888
 		mask := (msbs >> 1) | (msbs >> 2) | (msbs >> 3)
 		if mask&x != 0 {
-			return RingElement{}, errors.New("mldsa: invalid eta encoding")
+			return RingElement{}, ErrInvalidEtaEncoding
 		}
 		b = b[4:]
 		for j := 0; j < 8; j++ {
@@ -157,7 +183,12 @@ func UnpackEta4(b []byte) (RingElement, error) {
 // PackZ17 packs a polynomial z with coefficients in [-(gamma1-1), gamma1]
 // where gamma1 = 2^17. Uses 18 bits per coefficient.
 func PackZ17(f RingElement) []byte {
-	b := make([]byte, EncodingSize18)
+	return AppendZ17(make([]byte, 0, EncodingSize18), f)
+}
+
+// AppendZ17 appends the packed form of f to b and returns the extended slice.
+func AppendZ17(b []byte, f RingElement) []byte {
+	b, out := sliceForAppend(b, EncodingSize18)
 	const gamma1 = 1 << 17
 	idx := 0
 	for i := 0; i < N; i += 4 {
@@ -169,26 +200,37 @@ func PackZ17(f RingElement) []byte {
 		x1 |= x2 << 54
 		x2 >>= 10
 
-		b[idx] = byte(x1)
-		b[idx+1] = byte(x1 >> 8)
-		b[idx+2] = byte(x1 >> 16)
-		b[idx+3] = byte(x1 >> 24)
-		b[idx+4] = byte(x1 >> 32)
-		b[idx+5] = byte(x1 >> 40)
-		b[idx+6] = byte(x1 >> 48)
-		b[idx+7] = byte(x1 >> 56)
-		b[idx+8] = byte(x2)
+		out[idx] = byte(x1)
+		out[idx+1] = byte(x1 >> 8)
+		out[idx+2] = byte(x1 >> 16)
+		out[idx+3] = byte(x1 >> 24)
+		out[idx+4] = byte(x1 >> 32)
+		out[idx+5] = byte(x1 >> 40)
+		out[idx+6] = byte(x1 >> 48)
+		out[idx+7] = byte(x1 >> 56)
+		out[idx+8] = byte(x2)
 		idx += 9
 	}
 	return b
 }
 
 // UnpackZ17 unpacks a polynomial z packed with PackZ17.
-func UnpackZ17(b []byte) RingElement {
+// It returns a *LengthError wrapping ErrShortEncoding if b is shorter than
+// EncodingSize18 bytes.
+func UnpackZ17(b []byte) (RingElement, error) {
+	if len(b) < EncodingSize18 {
+		return RingElement{}, &LengthError{Err: ErrShortEncoding, Got: len(b), Want: EncodingSize18}
+	}
+	return unpackZ17(b), nil
+}
+
+// unpackZ17 is UnpackZ17 for input known to be long enough.
+func unpackZ17(b []byte) RingElement {
 	var f RingElement
 	const gamma1 = 1 << 17
 	const mask = (1 << 18) - 1
-	for i := 0; i < N; i += 4 {
+	i := unpackVec(&f, b, 18)
+	for b = b[i/4*9:]; i < N; i += 4 {
 		x1 := uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24 |
 			uint64(b[4])<<32 | uint64(b[5])<<40 | uint64(b[6])<<48 | uint64(b[7])<<56
 		x2 := uint64(b[8])
@@ -204,7 +246,12 @@ func UnpackZ17(b []byte) RingElement {
 // PackZ19 packs a polynomial z with coefficients in [-(gamma1-1), gamma1]
 // where gamma1 = 2^19. Uses 20 bits per coefficient.
 func PackZ19(f RingElement) []byte {
-	b := make([]byte, EncodingSize20)
+	return AppendZ19(make([]byte, 0, EncodingSize20), f)
+}
+
+// AppendZ19 appends the packed form of f to b and returns the extended slice.
+func AppendZ19(b []byte, f RingElement) []byte {
+	b, out := sliceForAppend(b, EncodingSize20)
 	const gamma1 = 1 << 19
 	idx := 0
 	for i := 0; i < N; i += 4 {
@@ -216,27 +263,38 @@ func PackZ19(f RingElement) []byte {
 		x1 |= x2 << 60
 		x2 >>= 4
 
-		b[idx] = byte(x1)
-		b[idx+1] = byte(x1 >> 8)
-		b[idx+2] = byte(x1 >> 16)
-		b[idx+3] = byte(x1 >> 24)
-		b[idx+4] = byte(x1 >> 32)
-		b[idx+5] = byte(x1 >> 40)
-		b[idx+6] = byte(x1 >> 48)
-		b[idx+7] = byte(x1 >> 56)
-		b[idx+8] = byte(x2)
-		b[idx+9] = byte(x2 >> 8)
+		out[idx] = byte(x1)
+		out[idx+1] = byte(x1 >> 8)
+		out[idx+2] = byte(x1 >> 16)
+		out[idx+3] = byte(x1 >> 24)
+		out[idx+4] = byte(x1 >> 32)
+		out[idx+5] = byte(x1 >> 40)
+		out[idx+6] = byte(x1 >> 48)
+		out[idx+7] = byte(x1 >> 56)
+		out[idx+8] = byte(x2)
+		out[idx+9] = byte(x2 >> 8)
 		idx += 10
 	}
 	return b
 }
 
 // UnpackZ19 unpacks a polynomial z packed with PackZ19.
-func UnpackZ19(b []byte) RingElement {
+// It returns a *LengthError wrapping ErrShortEncoding if b is shorter than
+// EncodingSize20 bytes.
+func UnpackZ19(b []byte) (RingElement, error) {
+	if len(b) < EncodingSize20 {
+		return RingElement{}, &LengthError{Err: ErrShortEncoding, Got: len(b), Want: EncodingSize20}
+	}
+	return unpackZ19(b), nil
+}
+
+// unpackZ19 is UnpackZ19 for input known to be long enough.
+func unpackZ19(b []byte) RingElement {
 	var f RingElement
 	const gamma1 = 1 << 19
 	const mask = (1 << 20) - 1
-	for i := 0; i < N; i += 4 {
+	i := unpackVec(&f, b, 20)
+	for b = b[i/4*10:]; i < N; i += 4 {
 		x1 := uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24 |
 			uint64(b[4])<<32 | uint64(b[5])<<40 | uint64(b[6])<<48 | uint64(b[7])<<56
 		x2 := uint64(b[8]) | uint64(b[9])<<8
@@ -251,45 +309,67 @@ func UnpackZ19(b []byte) RingElement {
 
 // PackW1_4 packs w1 with 4-bit coefficients (for ML-DSA-65/87).
 func PackW1_4(f RingElement) []byte {
-	b := make([]byte, EncodingSize4)
+	return AppendW1_4(make([]byte, 0, EncodingSize4), f)
+}
+
+// AppendW1_4 appends the packed form of f to b and returns the extended slice.
+func AppendW1_4(b []byte, f RingElement) []byte {
+	b, out := sliceForAppend(b, EncodingSize4)
 	for i := 0; i < N; i += 2 {
-		b[i/2] = byte(f[i]) | byte(f[i+1])<<4
+		out[i/2] = byte(f[i]) | byte(f[i+1])<<4
 	}
 	return b
 }
 
 // PackW1_6 packs w1 with 6-bit coefficients (for ML-DSA-44).
 func PackW1_6(f RingElement) []byte {
-	b := make([]byte, EncodingSize6)
+	return AppendW1_6(make([]byte, 0, EncodingSize6), f)
+}
+
+// AppendW1_6 appends the packed form of f to b and returns the extended slice.
+func AppendW1_6(b []byte, f RingElement) []byte {
+	b, out := sliceForAppend(b, EncodingSize6)
 	for i := 0; i < N; i += 4 {
 		x := uint32(f[i]) | uint32(f[i+1])<<6 | uint32(f[i+2])<<12 | uint32(f[i+3])<<18
-		b[i/4*3] = byte(x)
-		b[i/4*3+1] = byte(x >> 8)
-		b[i/4*3+2] = byte(x >> 16)
+		out[i/4*3] = byte(x)
+		out[i/4*3+1] = byte(x >> 8)
+		out[i/4*3+2] = byte(x >> 16)
 	}
 	return b
 }
 
 // PackHint packs the hint vector into a byte slice.
 func PackHint[T ~[N]FieldElement](hints []T, omega int) []byte {
+	return AppendHint(make([]byte, 0, omega+len(hints)), hints, omega)
+}
+
+// AppendHint appends the packed hint vector to b and returns the extended slice.
+func AppendHint[T ~[N]FieldElement](b []byte, hints []T, omega int) []byte {
 	k := len(hints)
-	b := make([]byte, omega+k)
+	b, out := sliceForAppend(b, omega+k)
+	clear(out)
 	idx := 0
 	for i := 0; i < k; i++ {
 		for j := 0; j < N; j++ {
 			if hints[i][j] != 0 {
-				b[idx] = byte(j)
+				out[idx] = byte(j)
 				idx++
 			}
 		}
-		b[omega+i] = byte(idx)
+		out[omega+i] = byte(idx)
 	}
 	return b
 }
 
-// UnpackHint unpacks the hint vector from a byte slice.
+// UnpackHint unpacks the hint vector from a byte slice. It returns false if
+// the encoding is malformed, including when b is shorter than omega+len(hints)
+// bytes. hints is overwritten, so it need not be zeroed.
 func UnpackHint[T ~[N]FieldElement](b []byte, hints []T, omega int) bool {
+	clear(hints)
 	k := len(hints)
+	if omega < 0 || omega > N || len(b) < omega+k {
+		return false
+	}
 	idx := 0
 	for i := 0; i < k; i++ {
 		limit := int(b[omega+i])
@@ -314,3 +394,18 @@ func UnpackHint[T ~[N]FieldElement](b []byte, hints []T, omega int) bool {
 	}
 	return true
 }
+
+// sliceForAppend takes a slice and a requested number of bytes. It returns a
+// slice with the contents of the given slice followed by that many bytes and
+// a second slice that aliases into it and contains only the extra bytes. If
+// the original slice has sufficient capacity then no allocation is performed.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
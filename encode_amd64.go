@@ -0,0 +1,71 @@
+//go:build amd64 && !purego
+
+package mldsa
+
+// unpackTable drives unpackAVX2 for one coefficient width. Each iteration
+// unpacks eight coefficients from width bytes: the low 128-bit lane of a Y
+// register is loaded from the start of the group and the high lane from
+// byte half, then every 32-bit lane gathers its four bytes with shuf,
+// shifts them right by shift and keeps the low bits with mask. Offsets are
+// in bytes and the layout is relied on by encode_amd64.s.
+type unpackTable struct {
+	shuf   [32]byte
+	shift  [8]uint32
+	mask   [8]uint32
+	center [8]uint32 // the centered variant returns center - x mod q
+	q      [8]uint32
+	width  int
+	half   int
+}
+
+//go:noescape
+func unpackAVX2(f *RingElement, b *byte, groups int, t *unpackTable)
+
+//go:noescape
+func unpackCenteredAVX2(f *RingElement, b *byte, groups int, t *unpackTable)
+
+// useUnpackAVX2 reports whether unpackAVX2 can run on this CPU.
+var useUnpackAVX2 = hasAVX2()
+
+var unpackTables = [...]*unpackTable{
+	10: newUnpackTable(10, 0),           // t1
+	13: newUnpackTable(13, 1<<12),       // t0
+	18: newUnpackTable(18, Gamma1Pow17), // z, ML-DSA-44
+	20: newUnpackTable(20, Gamma1Pow19), // z, ML-DSA-65 and ML-DSA-87
+}
+
+func newUnpackTable(bits int, center uint32) *unpackTable {
+	t := &unpackTable{width: bits, half: 4 * bits / 8}
+	for i := 0; i < 8; i++ {
+		bit := i * bits
+		if i >= 4 {
+			bit -= 8 * t.half
+		}
+		for k := 0; k < 4; k++ {
+			t.shuf[4*i+k] = byte(bit/8 + k)
+		}
+		t.shift[i] = uint32(bit % 8)
+		t.mask[i] = 1<<bits - 1
+		t.center[i] = center
+		t.q[i] = Q
+	}
+	return t
+}
+
+// unpackVec unpacks the leading coefficients of f from their bits-bit
+// encoding in b, as the scalar loop of the caller would, and returns how
+// many it unpacked. It stops short of the end so that its 16-byte loads
+// stay within the encoding; b must hold at least N*bits/8 bytes.
+func unpackVec(f *RingElement, b []byte, bits int) int {
+	if !useUnpackAVX2 {
+		return 0
+	}
+	t := unpackTables[bits]
+	groups := (N/8*t.width-t.half-16)/t.width + 1
+	if t.center[0] == 0 {
+		unpackAVX2(f, &b[0], groups, t)
+	} else {
+		unpackCenteredAVX2(f, &b[0], groups, t)
+	}
+	return 8 * groups
+}
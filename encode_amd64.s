@@ -0,0 +1,65 @@
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// Unpacking of fixed-width coefficients with AVX2, eight per iteration.
+// See unpackTable in encode_amd64.go for the table layout.
+
+// LOADTABLE loads the shuffle, shift and mask vectors of the table at t
+// into Y1-Y3, and the group width and upper lane offset into R8 and R9.
+#define LOADTABLE \
+	MOVQ    f+0(FP), DI; \
+	MOVQ    b+8(FP), SI; \
+	MOVQ    groups+16(FP), CX; \
+	MOVQ    t+24(FP), BX; \
+	VMOVDQU 0(BX), Y1; \
+	VMOVDQU 32(BX), Y2; \
+	VMOVDQU 64(BX), Y3; \
+	MOVQ    160(BX), R8; \
+	MOVQ    168(BX), R9
+
+// UNPACK sets Y0 to the eight coefficients of the group at SI.
+#define UNPACK \
+	VMOVDQU     (SI), X0; \
+	VINSERTI128 $1, (SI)(R9*1), Y0, Y0; \
+	VPSHUFB     Y1, Y0, Y0; \
+	VPSRLVD     Y2, Y0, Y0; \
+	VPAND       Y3, Y0, Y0
+
+// func unpackAVX2(f *RingElement, b *byte, groups int, t *unpackTable)
+TEXT ·unpackAVX2(SB), NOSPLIT, $0-32
+	LOADTABLE
+
+loop:
+	UNPACK
+	VMOVDQU Y0, (DI)
+	ADDQ    $32, DI
+	ADDQ    R8, SI
+	DECQ    CX
+	JNZ     loop
+
+	VZEROUPPER
+	RET
+
+// func unpackCenteredAVX2(f *RingElement, b *byte, groups int, t *unpackTable)
+TEXT ·unpackCenteredAVX2(SB), NOSPLIT, $0-32
+	LOADTABLE
+	VMOVDQU 96(BX), Y4
+	VMOVDQU 128(BX), Y6
+
+loop:
+	UNPACK
+
+	// center - x, plus q if negative
+	VPSUBD  Y0, Y4, Y0
+	VPSRAD  $31, Y0, Y5
+	VPAND   Y6, Y5, Y5
+	VPADDD  Y5, Y0, Y0
+	VMOVDQU Y0, (DI)
+	ADDQ    $32, DI
+	ADDQ    R8, SI
+	DECQ    CX
+	JNZ     loop
+
+	VZEROUPPER
+	RET
@@ -0,0 +1,67 @@
+//go:build amd64 && !purego
+
+package mldsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestUnpackAVX2(t *testing.T) {
+	if !useUnpackAVX2 {
+		t.Skip("AVX2 not available")
+	}
+	unpackers := []struct {
+		name   string
+		size   int
+		unpack func([]byte) RingElement
+	}{
+		{"T1", EncodingSize10, unpackT1},
+		{"T0", EncodingSize13, unpackT0},
+		{"Z17", EncodingSize18, unpackZ17},
+		{"Z19", EncodingSize20, unpackZ19},
+	}
+	for _, u := range unpackers {
+		inputs := [][]byte{
+			make([]byte, u.size),
+			bytes.Repeat([]byte{0xff}, u.size),
+		}
+		for i := 0; i < 16; i++ {
+			b := make([]byte, u.size)
+			rand.Read(b)
+			inputs = append(inputs, b)
+		}
+		for _, b := range inputs {
+			got := u.unpack(b)
+			useUnpackAVX2 = false
+			want := u.unpack(b)
+			useUnpackAVX2 = true
+			if got != want {
+				t.Fatalf("Unpack%s(%x): AVX2 and scalar results differ", u.name, b)
+			}
+		}
+	}
+}
+
+func BenchmarkUnpackT1(b *testing.B) {
+	enc := make([]byte, EncodingSize10)
+	rand.Read(enc)
+	for _, avx2 := range []bool{false, true} {
+		name := "scalar"
+		if avx2 {
+			name = "avx2"
+		}
+		b.Run(name, func(b *testing.B) {
+			if avx2 && !hasAVX2() {
+				b.Skip("AVX2 not available")
+			}
+			saved := useUnpackAVX2
+			useUnpackAVX2 = avx2
+			defer func() { useUnpackAVX2 = saved }()
+			for b.Loop() {
+				UnpackT1(enc)
+			}
+		})
+	}
+}
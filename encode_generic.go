@@ -0,0 +1,9 @@
+//go:build !amd64 || purego
+
+package mldsa
+
+// unpackVec unpacks no coefficients: there are no vector unpacking
+// routines on this platform.
+func unpackVec(f *RingElement, b []byte, bits int) int {
+	return 0
+}
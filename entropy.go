@@ -0,0 +1,86 @@
+package mldsa
+
+import (
+	cryptorand "crypto/rand"
+	"crypto/sha3"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync/atomic"
+)
+
+// DeterministicRand, passed as the rand argument of a signing method, selects
+// the deterministic variant of ML-DSA (FIPS 204, Section 3.4): rnd is all
+// zeros, so that signing the same message with the same key and context
+// always gives the same signature. The signature still depends on the
+// private key and the message, and is secure, but is not protected by fresh
+// randomness against side channels and fault attacks. Entropy mixing does
+// not apply to it, and key generation rejects it.
+var DeterministicRand io.Reader = deterministicRand{}
+
+type deterministicRand struct{}
+
+func (deterministicRand) Read(b []byte) (int, error) {
+	clear(b)
+	return len(b), nil
+}
+
+// errDeterministicKey is returned when DeterministicRand is passed to key
+// generation.
+var errDeterministicKey = errors.New("mldsa: DeterministicRand cannot generate keys")
+
+// HedgedRand returns a rand argument for signing methods that reads rnd from
+// r, or from crypto/rand.Reader if r is nil, and falls back to deterministic
+// signing, as with DeterministicRand, if r fails or returns only zeros.
+// Signing then survives a broken randomness source, whose failure only
+// removes the hedging: the signature is derived from the key and message
+// regardless. Key generation reads from r directly and fails with its error.
+func HedgedRand(r io.Reader) io.Reader {
+	return hedgedRand{r}
+}
+
+type hedgedRand struct {
+	r io.Reader
+}
+
+func (h hedgedRand) Read(b []byte) (int, error) {
+	if _, err := io.ReadFull(randReader(h.r), b); err != nil {
+		clear(b)
+	}
+	return len(b), nil
+}
+
+var entropyMixing atomic.Bool
+
+// SetEntropyMixing controls how the 32-byte hedging value rnd of each
+// signature is obtained. By default it is read from the rand argument of the
+// signing method. With mixing enabled, rnd is the SHAKE256 hash of 32 bytes
+// read from rand, 32 bytes from crypto/rand if rand is a different source,
+// and a counter of the signatures made with the key. A single weak or
+// backdoored source then cannot determine rnd, and rnd still differs between
+// signatures if every source is stuck. Mixing makes signing nondeterministic
+// even when rand is stuck; DeterministicRand still selects deterministic
+// ML-DSA.
+func SetEntropyMixing(enabled bool) {
+	entropyMixing.Store(enabled)
+}
+
+// mixRnd replaces rnd, as read from r, with its hash together with fresh
+// bytes from crypto/rand, unless r is crypto/rand, and the counter value n.
+func mixRnd(rnd *[32]byte, r io.Reader, n uint64) error {
+	h := sha3.NewSHAKE256()
+	h.Write([]byte("mldsa rnd mixing"))
+	h.Write(rnd[:])
+	if r != nil && r != cryptorand.Reader {
+		if _, err := io.ReadFull(cryptorand.Reader, rnd[:]); err != nil {
+			return err
+		}
+		h.Write(rnd[:])
+	}
+	var ctr [8]byte
+	binary.LittleEndian.PutUint64(ctr[:], n)
+	h.Write(ctr[:])
+	h.Read(rnd[:])
+	h.Reset()
+	return nil
+}
@@ -0,0 +1,141 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+// zeroReader is a rand source returning only zeros, like a misconfigured
+// RNG.
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	clear(b)
+	return len(b), nil
+}
+
+// stuckReader is a rand source returning the same non-zero bytes every time.
+type stuckReader struct{}
+
+func (stuckReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0x5a
+	}
+	return len(b), nil
+}
+
+func TestEntropyMixing(t *testing.T) {
+	message := []byte("message")
+	key, _ := GenerateKey44(rand.Reader)
+	sign := func() []byte {
+		t.Helper()
+		sig, err := key.SignWithContext(stuckReader{}, message, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !key.PublicKey().Verify(sig, message, nil) {
+			t.Fatal("signature does not verify")
+		}
+		return sig
+	}
+
+	if !bytes.Equal(sign(), sign()) {
+		t.Fatal("signatures with a stuck source differ")
+	}
+
+	SetEntropyMixing(true)
+	defer SetEntropyMixing(false)
+	if bytes.Equal(sign(), sign()) {
+		t.Error("mixed signatures with a stuck source are equal")
+	}
+	if n := key.rndCount.Load(); n != 2 {
+		t.Errorf("signature counter is %d, want 2", n)
+	}
+
+	// With every source stuck, the counter alone separates signatures.
+	var a, b [32]byte
+	if err := mixRnd(&a, nil, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := mixRnd(&b, nil, 2); err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Error("mixRnd ignores the counter")
+	}
+}
+
+// failingReader is a rand source that always fails.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("entropy source failed")
+}
+
+func TestDeterministicAndHedgedRand(t *testing.T) {
+	message := []byte("message")
+	key, _ := GenerateKey65(rand.Reader)
+	sign := func(r io.Reader) []byte {
+		t.Helper()
+		sig, err := key.SignWithContext(r, message, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !key.PublicKey().Verify(sig, message, nil) {
+			t.Fatal("signature does not verify")
+		}
+		return sig
+	}
+
+	// Deterministic ML-DSA signs with an all-zero rnd.
+	mu := pureMu(key.tr[:], message, nil)
+	deterministic, err := key.signMu(nil, make([]byte, 32), &mu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sign(DeterministicRand), deterministic) {
+		t.Error("DeterministicRand does not give deterministic ML-DSA signatures")
+	}
+	if !bytes.Equal(sign(HedgedRand(zeroReader{})), deterministic) {
+		t.Error("HedgedRand did not fall back to deterministic signing on a zero source")
+	}
+	if !bytes.Equal(sign(HedgedRand(failingReader{})), deterministic) {
+		t.Error("HedgedRand did not fall back to deterministic signing")
+	}
+	if bytes.Equal(sign(HedgedRand(nil)), deterministic) {
+		t.Error("HedgedRand with a working source signed deterministically")
+	}
+	if _, err := key.SignWithContext(failingReader{}, message, nil); err == nil {
+		t.Error("signing with a failing source succeeded")
+	}
+	if _, err := key.SignWithContext(zeroReader{}, message, nil); !errors.Is(err, ErrZeroRandomness) {
+		t.Errorf("signing with a zero source: got %v, want ErrZeroRandomness", err)
+	}
+
+	SetEntropyMixing(true)
+	if !bytes.Equal(sign(DeterministicRand), deterministic) {
+		t.Error("entropy mixing applied to DeterministicRand")
+	}
+	SetEntropyMixing(false)
+
+	if err := SetStrictFIPS(true); err != nil {
+		t.Fatal(err)
+	}
+	defer SetStrictFIPS(false)
+	if !bytes.Equal(sign(DeterministicRand), deterministic) {
+		t.Error("DeterministicRand rejected in strict FIPS mode")
+	}
+	if _, err := key.SignWithContext(HedgedRand(zeroReader{}), message, nil); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("HedgedRand hid an unapproved source in strict FIPS mode: %v", err)
+	}
+
+	if _, err := GenerateKey65(DeterministicRand); err == nil {
+		t.Error("GenerateKey65 accepted DeterministicRand")
+	}
+	if _, err := GenerateKey65(HedgedRand(failingReader{})); err == nil {
+		t.Error("GenerateKey65 hid the failure of a hedged source")
+	}
+}
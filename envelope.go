@@ -0,0 +1,116 @@
+package mldsa
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// A sealed envelope bundles a message with its signature, like NaCl's
+// crypto_sign, so that callers handle a single blob instead of a message
+// and a detached signature. It is laid out as:
+//
+//	"MLDE" || parameter set (1 byte) || len(context) (1 byte) || context ||
+//	signature || message
+//
+// The parameter set byte is 1, 2 or 3 for ML-DSA-44, ML-DSA-65 and
+// ML-DSA-87. The signature is a pure ML-DSA signature of the message with the context,
+// and its length is fixed by the parameter set, so the message is the rest
+// of the blob.
+
+// envelopeMagic starts every sealed envelope.
+const envelopeMagic = "MLDE"
+
+// Envelope is a parsed sealed envelope. Its message must not be trusted
+// before Verify succeeds.
+type Envelope struct {
+	ParameterSet ParameterSet
+	Context      []byte
+	Signature    []byte
+	Message      []byte
+}
+
+// Seal signs message with key and the optional context string, and returns
+// the sealed envelope. If rand is nil, crypto/rand.Reader is used.
+func Seal(rand io.Reader, key PrivateKey, message, context []byte) ([]byte, error) {
+	p, err := ParseParameterSet(key.Scheme().Name())
+	if err != nil {
+		return nil, err
+	}
+	sig, err := key.SignWithContext(rand, message, context)
+	if err != nil {
+		return nil, err
+	}
+	e := &Envelope{ParameterSet: p, Context: context, Signature: sig, Message: message}
+	return e.MarshalBinary()
+}
+
+// Open verifies a sealed envelope with pk and returns its message. The
+// envelope must have been sealed with context, which is compared like the
+// message: a mismatch is reported as ErrMismatch. The returned message
+// aliases sealed.
+func Open(pk PublicKey, sealed, context []byte) ([]byte, error) {
+	e, err := ParseEnvelope(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(e.Context, context) {
+		return nil, fmt.Errorf("%w: unexpected context %q", ErrMismatch, e.Context)
+	}
+	if err := e.Verify(pk); err != nil {
+		return nil, err
+	}
+	return e.Message, nil
+}
+
+// ParseEnvelope parses a sealed envelope without verifying it. The fields
+// of the result alias sealed.
+func ParseEnvelope(sealed []byte) (*Envelope, error) {
+	b, ok := bytes.CutPrefix(sealed, []byte(envelopeMagic))
+	if !ok || len(b) < 2 {
+		return nil, ErrInvalidEnvelope
+	}
+	e := &Envelope{ParameterSet: ParameterSet(b[0])}
+	if !e.ParameterSet.Valid() {
+		return nil, fmt.Errorf("%w: %w %d", ErrInvalidEnvelope, ErrUnknownParameterSet, b[0])
+	}
+	n, b := int(b[1]), b[2:]
+	sigSize := e.ParameterSet.SignatureSize()
+	if len(b) < n+sigSize {
+		return nil, fmt.Errorf("%w: truncated", ErrInvalidEnvelope)
+	}
+	e.Context, b = b[:n:n], b[n:]
+	e.Signature, e.Message = b[:sigSize:sigSize], b[sigSize:]
+	return e, nil
+}
+
+// MarshalBinary returns the sealed envelope.
+func (e *Envelope) MarshalBinary() ([]byte, error) {
+	if !e.ParameterSet.Valid() {
+		return nil, ErrUnknownParameterSet
+	}
+	if len(e.Context) > 255 {
+		return nil, ErrContextTooLong
+	}
+	if want := e.ParameterSet.SignatureSize(); len(e.Signature) != want {
+		return nil, &LengthError{Err: ErrBadSignatureLength, Got: len(e.Signature), Want: want}
+	}
+	b := make([]byte, 0, len(envelopeMagic)+2+len(e.Context)+len(e.Signature)+len(e.Message))
+	b = append(b, envelopeMagic...)
+	b = append(b, byte(e.ParameterSet), byte(len(e.Context)))
+	b = append(b, e.Context...)
+	b = append(b, e.Signature...)
+	return append(b, e.Message...), nil
+}
+
+// Verify checks the signature of the envelope with pk, which must be of the
+// envelope's parameter set.
+func (e *Envelope) Verify(pk PublicKey) error {
+	if pk.Scheme().Name() != e.ParameterSet.String() {
+		return fmt.Errorf("%w: %v envelope, %s key", ErrMismatch, e.ParameterSet, pk.Scheme().Name())
+	}
+	if !pk.Verify(e.Signature, e.Message, e.Context) {
+		return ErrMismatch
+	}
+	return nil
+}
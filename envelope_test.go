@@ -0,0 +1,78 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	message := []byte("attack at dawn")
+	context := []byte("orders")
+	for _, s := range Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		pk := key.Public().(PublicKey)
+
+		sealed, err := Seal(rand.Reader, key, message, context)
+		if err != nil {
+			t.Fatalf("%s: Seal: %v", s.Name(), err)
+		}
+		p, _ := ParseParameterSet(s.Name())
+		if want := 4 + 2 + len(context) + p.SignatureSize() + len(message); len(sealed) != want {
+			t.Errorf("%s: sealed length %d, want %d", s.Name(), len(sealed), want)
+		}
+		got, err := Open(pk, sealed, context)
+		if err != nil || !bytes.Equal(got, message) {
+			t.Errorf("%s: Open = %q, %v", s.Name(), got, err)
+		}
+		if _, err := Open(pk, sealed, nil); !errors.Is(err, ErrMismatch) {
+			t.Errorf("%s: Open with another context: %v", s.Name(), err)
+		}
+
+		tampered := bytes.Clone(sealed)
+		tampered[len(tampered)-1] ^= 1
+		if _, err := Open(pk, tampered, context); !errors.Is(err, ErrMismatch) {
+			t.Errorf("%s: Open of a tampered message: %v", s.Name(), err)
+		}
+
+		e, err := ParseEnvelope(sealed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.ParameterSet != p || !bytes.Equal(e.Context, context) || !bytes.Equal(e.Message, message) {
+			t.Errorf("%s: unexpected envelope fields", s.Name())
+		}
+		if b, err := e.MarshalBinary(); err != nil || !bytes.Equal(b, sealed) {
+			t.Errorf("%s: MarshalBinary did not round-trip: %v", s.Name(), err)
+		}
+	}
+
+	key44, _ := GenerateKey44(rand.Reader)
+	key65, _ := GenerateKey65(rand.Reader)
+	sealed, _ := Seal(rand.Reader, key44, nil, nil)
+	if _, err := Open(key65.PublicKey(), sealed, nil); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Open with a key of another parameter set: %v", err)
+	}
+	if got, err := Open(key44.PublicKey(), sealed, nil); err != nil || len(got) != 0 {
+		t.Errorf("Open of an empty message = %q, %v", got, err)
+	}
+}
+
+func TestParseEnvelopeErrors(t *testing.T) {
+	key, _ := GenerateKey44(rand.Reader)
+	sealed, _ := Seal(rand.Reader, key, []byte("m"), []byte("ctx"))
+	for name, b := range map[string][]byte{
+		"empty":          nil,
+		"bad magic":      append([]byte("XXXX"), sealed[4:]...),
+		"bad parameters": append([]byte("MLDE\x04"), sealed[5:]...),
+		"truncated":      sealed[:100],
+	} {
+		if _, err := ParseEnvelope(b); !errors.Is(err, ErrInvalidEnvelope) {
+			t.Errorf("ParseEnvelope(%s): %v", name, err)
+		}
+	}
+	if _, err := Seal(rand.Reader, key, nil, make([]byte, 256)); !errors.Is(err, ErrContextTooLong) {
+		t.Errorf("Seal with a long context: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package mldsa
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by this package. Errors about malformed input are
+// often wrapped, e.g. in a *LengthError, so callers should compare them with
+// errors.Is rather than ==.
+var (
+	ErrInvalidSeedLength   = errors.New("mldsa: invalid seed length")
+	ErrInvalidPublicKey    = errors.New("mldsa: invalid public key")
+	ErrInvalidPrivateKey   = errors.New("mldsa: invalid private key")
+	ErrInvalidEtaEncoding  = errors.New("mldsa: invalid eta encoding")
+	ErrShortEncoding       = errors.New("mldsa: polynomial encoding too short")
+	ErrContextTooLong      = errors.New("mldsa: context too long")
+	ErrUnknownParameterSet = errors.New("mldsa: unknown parameter set")
+	ErrKeyDestroyed        = errors.New("mldsa: key has been destroyed")
+	ErrSigningFailed       = errors.New("mldsa: signing did not converge")
+	ErrInvalidEnvelope     = errors.New("mldsa: invalid envelope")
+	ErrZeroRandomness      = errors.New("mldsa: randomness source returned only zeros")
+)
+
+// ErrPreHashed was returned when SignerOpts requested a hash function.
+//
+// Deprecated: such requests now select HashML-DSA and this error is no longer
+// returned.
+var ErrPreHashed = errors.New("mldsa: cannot sign pre-hashed messages")
+
+// Errors returned by VerifyError. ErrBadSignatureLength, ErrNormExceeded and
+// ErrHintEncoding indicate a malformed signature; ErrMismatch indicates a
+// well-formed signature that does not match the message, context or key.
+var (
+	ErrBadSignatureLength = errors.New("mldsa: invalid signature length")
+	ErrNormExceeded       = errors.New("mldsa: signature response vector out of range")
+	ErrHintEncoding       = errors.New("mldsa: malformed signature hint encoding")
+	ErrMismatch           = errors.New("mldsa: signature does not match")
+)
+
+// Errors returned by SignerPool.
+var (
+	ErrPoolClosed = errors.New("mldsa: signer pool is closed")
+	ErrUnknownKey = errors.New("mldsa: key not held by the signer pool")
+)
+
+// Errors about locked memory, see SetLockedMemory.
+var (
+	ErrLockedMemory            = errors.New("mldsa: cannot lock key memory")
+	ErrLockedMemoryUnsupported = errors.New("mldsa: locked memory is not supported on this platform")
+)
+
+// LengthError reports an encoded seed, key or signature of the wrong length.
+// It unwraps to Err, so errors.Is(err, ErrInvalidPublicKey) and similar
+// checks keep working.
+type LengthError struct {
+	Err  error // ErrInvalidSeedLength, ErrInvalidPublicKey, ErrInvalidPrivateKey or ErrBadSignatureLength
+	Got  int   // length of the input
+	Want int   // expected length, or 0 if several lengths are accepted
+}
+
+func (e *LengthError) Error() string {
+	if e.Want == 0 {
+		return fmt.Sprintf("%v: unexpected length %d", e.Err, e.Got)
+	}
+	return fmt.Sprintf("%v: got %d bytes, want %d", e.Err, e.Got, e.Want)
+}
+
+func (e *LengthError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError reports a signature of a batch that failed verification. Err
+// is the reason, as returned by VerifyError.
+type BatchError struct {
+	Index int // position of the signature in the batch
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("mldsa: batch signature %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,106 @@
+package mldsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	var lerr *LengthError
+
+	_, err := NewKey65(make([]byte, 31))
+	if !errors.Is(err, ErrInvalidSeedLength) || !errors.As(err, &lerr) || lerr.Got != 31 || lerr.Want != SeedSize {
+		t.Errorf("NewKey65 with a short seed: %v", err)
+	}
+	_, err = NewPublicKey44(make([]byte, 10))
+	if !errors.Is(err, ErrInvalidPublicKey) || !errors.As(err, &lerr) || lerr.Want != PublicKeySize44 {
+		t.Errorf("NewPublicKey44 with a short key: %v", err)
+	}
+	_, err = ParsePrivateKey(make([]byte, 10), 0)
+	if !errors.Is(err, ErrInvalidPrivateKey) || !errors.As(err, &lerr) || lerr.Want != 0 {
+		t.Errorf("ParsePrivateKey with a short key: %v", err)
+	}
+
+	// An all-0xff s1 is not a valid eta encoding.
+	b := make([]byte, PrivateKeySize87)
+	for i := 128; i < 128+EncodingSize3; i++ {
+		b[i] = 0xff
+	}
+	_, err = NewPrivateKey87(b)
+	if !errors.Is(err, ErrInvalidPrivateKey) || !errors.Is(err, ErrInvalidEtaEncoding) {
+		t.Errorf("NewPrivateKey87 with bad eta encoding: %v", err)
+	}
+
+	key, _ := GenerateKey44(rand.Reader)
+	if _, err := key.SignWithContext(rand.Reader, nil, make([]byte, 256)); !errors.Is(err, ErrContextTooLong) {
+		t.Errorf("SignWithContext with a long context: %v", err)
+	}
+	if err := key.PublicKey().VerifyError(nil, nil, nil); !errors.Is(err, ErrBadSignatureLength) || !errors.As(err, &lerr) {
+		t.Errorf("VerifyError with an empty signature: %v", err)
+	}
+	if _, err := ParseParameterSet("ML-DSA-1"); !errors.Is(err, ErrUnknownParameterSet) {
+		t.Errorf("ParseParameterSet with an unknown name: %v", err)
+	}
+}
+
+func TestMalformedInputsDoNotPanic(t *testing.T) {
+	var lerr *LengthError
+	key44, _ := GenerateKey44(rand.Reader)
+	key65, _ := GenerateKey65(rand.Reader)
+	key87, _ := GenerateKey87(rand.Reader)
+	for name, verify := range map[string]func(sig, mPrime []byte) error{
+		"ML-DSA-44": key44.PublicKey().verifyInternal,
+		"ML-DSA-65": key65.PublicKey().verifyInternal,
+		"ML-DSA-87": key87.PublicKey().verifyInternal,
+	} {
+		for _, n := range []int{0, 1, 100, SignatureSize44 - 1, SignatureSize87 + 1} {
+			if err := verify(make([]byte, n), nil); !errors.As(err, &lerr) || !errors.Is(err, ErrBadSignatureLength) {
+				t.Errorf("%s: verifyInternal with a %d-byte signature: %v", name, n, err)
+			}
+		}
+	}
+
+	short := make([]byte, 7)
+	if _, err := UnpackEta2(short); !errors.Is(err, ErrInvalidEtaEncoding) || !errors.As(err, &lerr) {
+		t.Errorf("UnpackEta2 with short input: %v", err)
+	}
+	if _, err := UnpackEta4(short); !errors.Is(err, ErrInvalidEtaEncoding) || !errors.As(err, &lerr) {
+		t.Errorf("UnpackEta4 with short input: %v", err)
+	}
+	hints := make([]RingElement, K44)
+	if UnpackHint(short, hints, Omega80) || UnpackHint44(short, hints) {
+		t.Error("UnpackHint accepted short input")
+	}
+	if UnpackHint(make([]byte, 1024), hints, -1) || UnpackHint(make([]byte, 1024), hints, N+1) {
+		t.Error("UnpackHint accepted an invalid omega")
+	}
+	if c := SampleChallenge(nil, N+1); c != (RingElement{}) {
+		t.Error("SampleChallenge with tau > N returned a non-zero polynomial")
+	}
+}
+
+func TestUnpackShortInput(t *testing.T) {
+	for _, u := range []struct {
+		name   string
+		size   int
+		unpack func([]byte) (RingElement, error)
+	}{
+		{"UnpackT1", EncodingSize10, UnpackT1},
+		{"UnpackT0", EncodingSize13, UnpackT0},
+		{"UnpackZ17", EncodingSize18, UnpackZ17},
+		{"UnpackZ19", EncodingSize20, UnpackZ19},
+		{"UnpackPolyQ", PackPolyQSize, UnpackPolyQ},
+	} {
+		for _, n := range []int{0, 7, u.size - 1} {
+			_, err := u.unpack(make([]byte, n))
+			var le *LengthError
+			if !errors.Is(err, ErrShortEncoding) || !errors.As(err, &le) || le.Got != n || le.Want != u.size {
+				t.Errorf("%s with %d bytes: %v", u.name, n, err)
+			}
+		}
+		if _, err := u.unpack(make([]byte, u.size)); err != nil {
+			t.Errorf("%s with %d bytes: %v", u.name, u.size, err)
+		}
+	}
+}
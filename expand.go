@@ -0,0 +1,86 @@
+package mldsa
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// minParallelProcs is the GOMAXPROCS below which parallel expansion is not
+// worth the goroutine overhead.
+const minParallelProcs = 4
+
+var parallelExpansion atomic.Bool
+
+// SetParallelExpansion enables expanding the matrix A of new and parsed
+// keys across GOMAXPROCS goroutines, and running the independent steps of
+// key generation concurrently. It speeds up bulk key parsing, as in
+// certificate validators, and key generation on hosts with at least 4 CPUs;
+// on smaller hosts everything stays serial. It is disabled by default.
+func SetParallelExpansion(enabled bool) {
+	parallelExpansion.Store(enabled)
+}
+
+// expansionWorkers returns the number of goroutines expanding n entries.
+func expansionWorkers(n int) int {
+	if !parallelExpansion.Load() {
+		return 1
+	}
+	procs := runtime.GOMAXPROCS(0)
+	if procs < minParallelProcs {
+		return 1
+	}
+	return min(procs, (n+3)/4)
+}
+
+// forEach calls f(i) for each i in [0, n), each in its own goroutine when
+// parallel expansion is enabled and worthwhile. The calls must be
+// independent.
+func forEach(n int, f func(i int)) {
+	if !parallelExpansion.Load() || runtime.GOMAXPROCS(0) < minParallelProcs {
+		for i := 0; i < n; i++ {
+			f(i)
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			f(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// expandA fills a with the matrix Â generated from rho, in row-major order
+// with l columns (FIPS 204 Algorithm 32): a[i*l+j] = RejNTTPoly(rho || j || i).
+func expandA(a []NttElement, rho []byte, l int) {
+	workers := expansionWorkers(len(a))
+	if workers == 1 {
+		expandARange(a, rho, l, 0)
+		return
+	}
+	// Chunks are multiples of four entries, which expandA4 samples at once.
+	chunk := (len(a)/workers + 3) &^ 3
+	var wg sync.WaitGroup
+	for start := 0; start < len(a); start += chunk {
+		end := min(start+chunk, len(a))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			expandARange(a[start:end], rho, l, start)
+		}()
+	}
+	wg.Wait()
+}
+
+// expandARange fills a with the entries of Â from index first on. Where
+// available, four entries at a time are sampled with interleaved SHAKE128
+// instances.
+func expandARange(a []NttElement, rho []byte, l, first int) {
+	for idx := expandA4(a, rho, l, first); idx < len(a); idx++ {
+		a[idx] = SampleNTTPoly(rho, byte((first+idx)%l), byte((first+idx)/l))
+	}
+}
@@ -0,0 +1,74 @@
+package mldsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	"runtime"
+	"testing"
+)
+
+func TestExpandA(t *testing.T) {
+	rho := make([]byte, 32)
+	rand.Read(rho)
+	for _, parallel := range []bool{false, true} {
+		SetParallelExpansion(parallel)
+		for _, procs := range []int{1, 3, 4, 8} {
+			prev := runtime.GOMAXPROCS(procs)
+			for _, dims := range [][2]int{{K44, L44}, {K65, L65}, {K87, L87}} {
+				k, l := dims[0], dims[1]
+				a := make([]NttElement, k*l)
+				expandA(a, rho, l)
+				for i := 0; i < k; i++ {
+					for j := 0; j < l; j++ {
+						if a[i*l+j] != SampleNTTPoly(rho, byte(j), byte(i)) {
+							t.Fatalf("parallel %v, GOMAXPROCS %d, %dx%d: entry (%d, %d) mismatch", parallel, procs, k, l, i, j)
+						}
+					}
+				}
+			}
+			runtime.GOMAXPROCS(prev)
+		}
+	}
+	SetParallelExpansion(false)
+}
+
+func BenchmarkParsePublicKey87(b *testing.B) {
+	key, _ := GenerateKey87(rand.Reader)
+	enc := key.PublicKey().Bytes()
+	for _, parallel := range []bool{false, true} {
+		name := map[bool]string{false: "serial", true: "parallel"}[parallel]
+		b.Run(name, func(b *testing.B) {
+			SetParallelExpansion(parallel)
+			defer SetParallelExpansion(false)
+			for i := 0; i < b.N; i++ {
+				NewPublicKey87(enc)
+			}
+		})
+	}
+	b.Run("lazy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NewPublicKey87Lazy(enc)
+		}
+	})
+}
+
+func TestParallelKeyGeneration(t *testing.T) {
+	prev := runtime.GOMAXPROCS(8)
+	defer runtime.GOMAXPROCS(prev)
+	defer SetParallelExpansion(false)
+
+	seed := make([]byte, SeedSize)
+	rand.Read(seed)
+	for _, s := range Schemes() {
+		SetParallelExpansion(false)
+		serial, _ := s.NewKeyFromSeed(seed)
+		SetParallelExpansion(true)
+		parallel, _ := s.NewKeyFromSeed(seed)
+		if !serial.Public().(PublicKey).Equal(parallel.Public()) {
+			t.Errorf("%s: parallel key generation derived another public key", s.Name())
+		}
+		if !serial.(interface{ Equal(crypto.PrivateKey) bool }).Equal(parallel) {
+			t.Errorf("%s: parallel key generation derived another private key", s.Name())
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package mldsa
+
+import (
+	"crypto"
+	"fmt"
+	"io"
+)
+
+// ExternalSigner adapts a crypto.Signer whose private key lives outside this
+// package, such as a key on a PKCS #11 token or in a cloud HSM, and which
+// returns raw FIPS 204 signatures. It detects the parameter set from the
+// public key and checks the length and validity of every signature before
+// returning it, so that a misbehaving device, or one that silently ignores
+// the context string, cannot hand out invalid signatures. Keys wrapped this
+// way can be used wherever a PrivateKey is accepted, including the
+// certificate and message encodings of the subpackages; the private key
+// encodings fail, as the key never leaves the device.
+//
+// The wrapped signer receives the arguments of Sign unchanged, and a
+// *SignerOpts carrying the context string from SignWithContext. It must
+// treat a zero HashFunc as pure ML-DSA over the full message. ExternalSigner
+// implements crypto.Signer, crypto.MessageSigner and PrivateKey, and is safe
+// for concurrent use if the wrapped signer is.
+type ExternalSigner struct {
+	signer crypto.Signer
+	pub    PublicKey
+}
+
+// NewExternalSigner wraps signer. The public key is pub, or signer.Public()
+// if pub is nil. It may be a PublicKey of this package, or a []byte holding
+// either its raw encoding or a DER SubjectPublicKeyInfo, as devices commonly
+// export it.
+func NewExternalSigner(signer crypto.Signer, pub crypto.PublicKey) (*ExternalSigner, error) {
+	if pub == nil {
+		pub = signer.Public()
+	}
+	pk, err := externalPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalSigner{signer: signer, pub: pk}, nil
+}
+
+// externalPublicKey returns the public key held or encoded by pub.
+func externalPublicKey(pub crypto.PublicKey) (PublicKey, error) {
+	switch pk := pub.(type) {
+	case PublicKey:
+		return pk, nil
+	case []byte:
+		if k, err := ParsePublicKey(pk); err == nil {
+			return k, nil
+		}
+		k, err := ParsePKIXPublicKey(pk)
+		if err != nil {
+			return nil, fmt.Errorf("%w: neither a raw nor a PKIX ML-DSA public key", ErrInvalidPublicKey)
+		}
+		return k, nil
+	}
+	return nil, fmt.Errorf("mldsa: unsupported public key type %T", pub)
+}
+
+// Unwrap returns the wrapped signer.
+func (s *ExternalSigner) Unwrap() crypto.Signer {
+	return s.signer
+}
+
+// Public returns the public key, a PublicKey.
+func (s *ExternalSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Scheme returns the parameter set of the key.
+func (s *ExternalSigner) Scheme() Scheme {
+	return s.pub.Scheme()
+}
+
+// Seed always returns false: the seed never leaves the device.
+func (s *ExternalSigner) Seed() ([]byte, bool) {
+	return nil, false
+}
+
+// check returns sig if it has the length of the parameter set and valid
+// accepts it.
+func (s *ExternalSigner) check(sig []byte, err error, valid func([]byte) bool) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	if want := s.pub.Scheme().SignatureSize(); len(sig) != want {
+		return nil, &LengthError{Err: ErrBadSignatureLength, Got: len(sig), Want: want}
+	}
+	if !valid(sig) {
+		return nil, fmt.Errorf("%w: invalid signature from %T", ErrSignatureFault, s.signer)
+	}
+	return sig, nil
+}
+
+// verifyDigest reports whether sig is a HashML-DSA signature of digest.
+func (s *ExternalSigner) verifyDigest(sig, digest []byte, ph crypto.Hash, context []byte) bool {
+	v, ok := s.pub.(interface {
+		VerifyPreHash(sig, digest []byte, ph crypto.Hash, context []byte) bool
+	})
+	return ok && v.VerifyPreHash(sig, digest, ph, context)
+}
+
+// Sign passes its arguments to the wrapped signer and checks the returned
+// signature. If opts.HashFunc() is not zero, digest is a message digest and
+// the signature is checked as HashML-DSA; otherwise digest is the message.
+// If opts is *SignerOpts, its Context field is used for the check.
+func (s *ExternalSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	context, ph, _ := signerOptions(opts)
+	sig, err := s.signer.Sign(rand, digest, opts)
+	return s.check(sig, err, func(sig []byte) bool {
+		if ph != 0 {
+			return s.verifyDigest(sig, digest, ph, context)
+		}
+		return s.pub.Verify(sig, digest, context)
+	})
+}
+
+// SignMessage signs msg, hashing it first if opts.HashFunc() is not zero.
+// It uses the SignMessage method of the wrapped signer if it has one, and
+// Sign otherwise. It implements crypto.MessageSigner.
+func (s *ExternalSigner) SignMessage(rand io.Reader, msg []byte, opts crypto.SignerOpts) ([]byte, error) {
+	context, ph, _ := signerOptions(opts)
+	var digest []byte
+	if ph != 0 {
+		var err error
+		if digest, err = PreHash(ph, msg); err != nil {
+			return nil, err
+		}
+	}
+	valid := func(sig []byte) bool {
+		if ph != 0 {
+			return s.verifyDigest(sig, digest, ph, context)
+		}
+		return s.pub.Verify(sig, msg, context)
+	}
+	if ms, ok := s.signer.(interface {
+		SignMessage(io.Reader, []byte, crypto.SignerOpts) ([]byte, error)
+	}); ok {
+		sig, err := ms.SignMessage(rand, msg, opts)
+		return s.check(sig, err, valid)
+	}
+	if ph == 0 {
+		digest = msg
+	}
+	sig, err := s.signer.Sign(rand, digest, opts)
+	return s.check(sig, err, valid)
+}
+
+// SignWithContext signs message with an optional context string.
+func (s *ExternalSigner) SignWithContext(rand io.Reader, message, context []byte) ([]byte, error) {
+	if len(context) > 255 {
+		return nil, ErrContextTooLong
+	}
+	return s.Sign(rand, message, &SignerOpts{Context: context})
+}
+
+// SignMu signs the message representative µ, if the wrapped signer has a
+// SignMu method with the signature of PrivateKey65.SignMu, and checks the
+// returned signature with VerifyMu.
+func (s *ExternalSigner) SignMu(rand io.Reader, mu [MuSize]byte) ([]byte, error) {
+	ms, ok := s.signer.(interface {
+		SignMu(rand io.Reader, mu [MuSize]byte) ([]byte, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("mldsa: %T cannot sign an external µ", s.signer)
+	}
+	v, ok := s.pub.(interface {
+		VerifyMu(sig []byte, mu [MuSize]byte) bool
+	})
+	if !ok {
+		return nil, fmt.Errorf("mldsa: %T cannot verify an external µ", s.pub)
+	}
+	sig, err := ms.SignMu(rand, mu)
+	return s.check(sig, err, func(sig []byte) bool { return v.VerifyMu(sig, mu) })
+}
@@ -0,0 +1,113 @@
+package mldsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+// deviceSigner stands in for a PKCS #11 or cloud HSM signer: it exposes
+// only crypto.Signer, and its public key as a DER SubjectPublicKeyInfo.
+type deviceSigner struct {
+	key  PrivateKey
+	spki []byte
+
+	// tamper, if not nil, alters the signatures returned.
+	tamper func([]byte) []byte
+	// ignoreContext makes the device drop the context string.
+	ignoreContext bool
+}
+
+func (d *deviceSigner) Public() crypto.PublicKey { return d.spki }
+
+func (d *deviceSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if d.ignoreContext {
+		opts = opts.HashFunc()
+	}
+	sig, err := d.key.Sign(rand, digest, opts)
+	if err == nil && d.tamper != nil {
+		sig = d.tamper(sig)
+	}
+	return sig, err
+}
+
+func TestExternalSigner(t *testing.T) {
+	message, context := []byte("message"), []byte("ctx")
+	for _, p := range ParameterSets() {
+		key, _ := p.Scheme().GenerateKey(rand.Reader)
+		pk := key.Public().(PublicKey)
+		spki, _ := MarshalPKIXPublicKey(pk)
+		dev := &deviceSigner{key: key, spki: spki}
+		s, err := NewExternalSigner(dev, nil)
+		if err != nil {
+			t.Fatalf("%v: %v", p, err)
+		}
+		if s.Scheme() != p.Scheme() || !pk.Equal(s.Public()) {
+			t.Fatalf("%v: detected another key", p)
+		}
+		if raw, err := NewExternalSigner(dev, pk.Bytes()); err != nil || !pk.Equal(raw.Public()) {
+			t.Errorf("%v: raw public key: %v", p, err)
+		}
+
+		sig, err := s.SignWithContext(rand.Reader, message, context)
+		if err != nil || !pk.Verify(sig, message, context) {
+			t.Errorf("%v: SignWithContext: %v", p, err)
+		}
+		digest, _ := PreHash(crypto.SHA256, message)
+		sig, err = s.Sign(rand.Reader, digest, &SignerOpts{Hash: crypto.SHA256, Context: context})
+		if err != nil || !pk.(interface {
+			VerifyPreHash(sig, digest []byte, ph crypto.Hash, context []byte) bool
+		}).VerifyPreHash(sig, digest, crypto.SHA256, context) {
+			t.Errorf("%v: Sign with HashML-DSA: %v", p, err)
+		}
+		if _, err := s.SignMessage(rand.Reader, message, crypto.SHA512); err != nil {
+			t.Errorf("%v: SignMessage with HashML-DSA: %v", p, err)
+		}
+		if _, err := s.SignMu(rand.Reader, [MuSize]byte{}); err == nil {
+			t.Errorf("%v: SignMu succeeded with a device that cannot sign µ", p)
+		}
+	}
+}
+
+func TestExternalSignerChecks(t *testing.T) {
+	key, _ := GenerateKey44(rand.Reader)
+	message := []byte("message")
+	dev := &deviceSigner{key: key}
+	s, err := NewExternalSigner(dev, key.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dev.tamper = func(sig []byte) []byte { sig[10] ^= 1; return sig }
+	if _, err := s.SignWithContext(rand.Reader, message, nil); !errors.Is(err, ErrSignatureFault) {
+		t.Errorf("corrupted signature: got %v, want ErrSignatureFault", err)
+	}
+	dev.tamper = func(sig []byte) []byte { return append([]byte{0x30, 0x82}, sig...) }
+	if _, err := s.SignWithContext(rand.Reader, message, nil); !errors.Is(err, ErrBadSignatureLength) {
+		t.Errorf("wrapped signature: got %v, want ErrBadSignatureLength", err)
+	}
+	dev.tamper = nil
+	dev.ignoreContext = true
+	if _, err := s.SignWithContext(rand.Reader, message, nil); err != nil {
+		t.Errorf("empty context: %v", err)
+	}
+	if _, err := s.SignWithContext(rand.Reader, message, []byte("ctx")); !errors.Is(err, ErrSignatureFault) {
+		t.Errorf("device ignoring the context: got %v, want ErrSignatureFault", err)
+	}
+
+	// A key that can sign µ keeps that ability behind the adapter.
+	s, _ = NewExternalSigner(key, nil)
+	mu, _ := key.PublicKey().ComputeMu(message, nil)
+	if _, err := s.SignMu(rand.Reader, mu); err != nil {
+		t.Errorf("SignMu: %v", err)
+	}
+
+	if _, err := NewExternalSigner(dev, []byte("not a key")); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("invalid public key: %v", err)
+	}
+	if _, err := NewExternalSigner(dev, "ML-DSA-44"); err == nil {
+		t.Error("unsupported public key type accepted")
+	}
+}
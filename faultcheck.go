@@ -0,0 +1,37 @@
+package mldsa
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrSignatureFault is returned when a freshly produced signature does not
+// verify with SetVerifyAfterSign enabled, which indicates a fault or memory
+// corruption during signing.
+var ErrSignatureFault = errors.New("mldsa: produced signature does not verify")
+
+var verifyAfterSign atomic.Bool
+
+// SetVerifyAfterSign enables or disables verifying every signature against
+// the signer's public key before it is returned. A fault injected while
+// signing, or a corrupted private key, can produce an invalid signature that
+// reveals information about the key; with the check enabled such a
+// signature is wiped and signing fails with ErrSignatureFault instead. This
+// suits hostile environments, such as devices exposed to glitching, and adds
+// the cost of one verification to every signature. Paranoid mode includes
+// the check.
+func SetVerifyAfterSign(enabled bool) {
+	verifyAfterSign.Store(enabled)
+}
+
+// signatureCheckError returns the error for a signature that fails the
+// check after signing, or nil if the check is disabled.
+func signatureCheckError() error {
+	if paranoid.Load() {
+		return ErrParanoidCheckFailed
+	}
+	if verifyAfterSign.Load() {
+		return ErrSignatureFault
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestVerifyAfterSign(t *testing.T) {
+	message := []byte("message")
+	key, _ := GenerateKey65(rand.Reader)
+	pk := key.PublicKey()
+
+	SetVerifyAfterSign(true)
+	defer SetVerifyAfterSign(false)
+	sig, err := key.SignWithContext(rand.Reader, message, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Verify(sig, message, nil) {
+		t.Fatal("signature does not verify")
+	}
+
+	// Simulate a fault that flips a coefficient of s1 after the public key
+	// was derived.
+	key.s1[0][0] = fieldAdd(key.s1[0][0], 1)
+	buf := make([]byte, 0, SignatureSize65)
+	if _, err := key.AppendSign(buf, rand.Reader, message, nil); !errors.Is(err, ErrSignatureFault) {
+		t.Fatalf("signing with a faulty key: got %v, want ErrSignatureFault", err)
+	}
+	if !bytes.Equal(buf[:SignatureSize65], make([]byte, SignatureSize65)) {
+		t.Error("faulty signature left in dst")
+	}
+
+	SetVerifyAfterSign(false)
+	sig, err = key.SignWithContext(rand.Reader, message, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk.Verify(sig, message, nil) {
+		t.Error("faulty key produced a valid signature")
+	}
+}
@@ -0,0 +1,63 @@
+package mldsa
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+)
+
+// Fingerprint is the SHA-256 digest of an encoded public key, used for key
+// pinning and display.
+type Fingerprint [sha256.Size]byte
+
+// fingerprintPrefix prefixes the text form of a Fingerprint.
+const fingerprintPrefix = "SHA256:"
+
+// String returns the fingerprint in the short human-readable form
+// "SHA256:<unpadded base64>", as used by OpenSSH.
+func (f Fingerprint) String() string {
+	return fingerprintPrefix + base64.RawStdEncoding.EncodeToString(f[:])
+}
+
+// ParseFingerprint parses a fingerprint in the form returned by
+// Fingerprint.String.
+func ParseFingerprint(s string) (Fingerprint, bool) {
+	var f Fingerprint
+	rest, ok := strings.CutPrefix(s, fingerprintPrefix)
+	if !ok {
+		return f, false
+	}
+	b, err := base64.RawStdEncoding.DecodeString(rest)
+	if err != nil || len(b) != len(f) {
+		return f, false
+	}
+	copy(f[:], b)
+	return f, true
+}
+
+// MatchFingerprint reports whether pk has the fingerprint fp, given in the
+// form returned by Fingerprint.String. The comparison is constant time.
+func MatchFingerprint(pk PublicKey, fp string) bool {
+	want, ok := ParseFingerprint(fp)
+	if !ok {
+		return false
+	}
+	got := pk.Fingerprint()
+	return subtle.ConstantTimeCompare(got[:], want[:]) == 1
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the encoded public key.
+func (pk *PublicKey44) Fingerprint() Fingerprint {
+	return sha256.Sum256(pk.Bytes())
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the encoded public key.
+func (pk *PublicKey65) Fingerprint() Fingerprint {
+	return sha256.Sum256(pk.Bytes())
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the encoded public key.
+func (pk *PublicKey87) Fingerprint() Fingerprint {
+	return sha256.Sum256(pk.Bytes())
+}
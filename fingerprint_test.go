@@ -0,0 +1,38 @@
+package mldsa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	pk := key.PublicKey()
+
+	fp := pk.Fingerprint()
+	if fp != Fingerprint(sha256.Sum256(pk.Bytes())) {
+		t.Error("Fingerprint is not the SHA-256 of the encoded key")
+	}
+	s := fp.String()
+	if !strings.HasPrefix(s, "SHA256:") || strings.HasSuffix(s, "=") || len(s) != len("SHA256:")+43 {
+		t.Errorf("unexpected fingerprint form %q", s)
+	}
+	if parsed, ok := ParseFingerprint(s); !ok || parsed != fp {
+		t.Errorf("ParseFingerprint(%q) failed", s)
+	}
+
+	if !MatchFingerprint(pk, s) {
+		t.Error("MatchFingerprint rejected the key's own fingerprint")
+	}
+	other, _ := GenerateKey44(rand.Reader)
+	if MatchFingerprint(other.PublicKey(), s) {
+		t.Error("MatchFingerprint accepted another key")
+	}
+	for _, bad := range []string{"", "SHA256:", "MD5:" + s[7:], s[:len(s)-1]} {
+		if MatchFingerprint(pk, bad) {
+			t.Errorf("MatchFingerprint accepted %q", bad)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package mldsa
+
+// Strict FIPS mode.
+//
+// When enabled, the package refuses services that are not approved under
+// FIPS 204 and SP 800-140 for use inside a FIPS 140-3 module: randomness must
+// come from an approved DRBG, and contexts may be required by policy.
+// Enabling the mode first runs the power-up self-tests. Operations that take
+// a *SignerOpts report a per-operation service indicator through its
+// Indicator field, whether or not strict mode is enabled.
+//
+// When Go's FIPS 140-3 mode is enabled (GODEBUG=fips140=on, or building with
+// GOFIPS140), strict mode is enabled at initialization and cannot be turned
+// off. SHAKE and the default randomness source then come from the Go
+// Cryptographic Module through crypto/sha3 and crypto/rand.
+
+import (
+	"crypto"
+	"crypto/fips140"
+	cryptorand "crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrNotApproved is returned in strict FIPS mode for services that are not
+// approved, such as signing with randomness from an unapproved source.
+var ErrNotApproved = errors.New("mldsa: service not approved in strict FIPS mode")
+
+// ApprovedRandom is implemented by randomness sources backed by an approved
+// DRBG (SP 800-90A). crypto/rand.Reader is always considered approved.
+type ApprovedRandom interface {
+	io.Reader
+	// FIPSApproved reports whether the source is currently an approved DRBG.
+	FIPSApproved() bool
+}
+
+// FIPSPolicy holds the optional restrictions of strict FIPS mode.
+type FIPSPolicy struct {
+	// RequireContext rejects signing with an empty context string.
+	RequireContext bool
+}
+
+// ServiceIndicator reports whether an operation was an approved service.
+type ServiceIndicator int
+
+const (
+	// IndicatorUnset means no operation has reported an indicator yet.
+	IndicatorUnset ServiceIndicator = iota
+	// IndicatorApproved means the operation was an approved service.
+	IndicatorApproved
+	// IndicatorNotApproved means the operation was not an approved service.
+	IndicatorNotApproved
+)
+
+// String returns "unset", "approved" or "not approved".
+func (i ServiceIndicator) String() string {
+	switch i {
+	case IndicatorApproved:
+		return "approved"
+	case IndicatorNotApproved:
+		return "not approved"
+	default:
+		return "unset"
+	}
+}
+
+var (
+	strictFIPS atomic.Bool
+	fipsPolicy atomic.Pointer[FIPSPolicy]
+	keygenPCT  atomic.Bool
+	importVal  atomic.Bool
+)
+
+func init() {
+	if fips140.Enabled() {
+		if err := SetStrictFIPS(true); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// SetStrictFIPS enables or disables strict FIPS mode for the whole package.
+// Enabling it runs SelfTest first and leaves the mode disabled if a
+// self-test fails. It cannot be disabled while crypto/fips140 is enabled.
+func SetStrictFIPS(enabled bool) error {
+	if !enabled && fips140.Enabled() {
+		return fmt.Errorf("%w: strict FIPS mode is required while crypto/fips140 is enabled", ErrNotApproved)
+	}
+	if enabled {
+		if err := SelfTest(); err != nil {
+			return err
+		}
+	}
+	strictFIPS.Store(enabled)
+	return nil
+}
+
+// StrictFIPS reports whether strict FIPS mode is enabled.
+func StrictFIPS() bool {
+	return strictFIPS.Load()
+}
+
+// SetFIPSPolicy sets the restrictions enforced in strict FIPS mode and
+// reflected in service indicators.
+func SetFIPSPolicy(p FIPSPolicy) {
+	fipsPolicy.Store(&p)
+}
+
+// CurrentFIPSPolicy returns the policy set by SetFIPSPolicy.
+func CurrentFIPSPolicy() FIPSPolicy {
+	if p := fipsPolicy.Load(); p != nil {
+		return *p
+	}
+	return FIPSPolicy{}
+}
+
+// SetPairwiseConsistencyTest enables or disables the pairwise consistency
+// test of newly generated keys outside strict FIPS mode. In strict FIPS mode
+// the test always runs, as FIPS 140-3 requires it for signature keys.
+func SetPairwiseConsistencyTest(enabled bool) {
+	keygenPCT.Store(enabled)
+}
+
+// SetPrivateKeyValidation enables or disables the validation of expanded
+// private keys by NewPrivateKey44, NewPrivateKey65 and NewPrivateKey87
+// outside strict FIPS mode. In strict FIPS mode keys are always validated.
+// Validation recomputes the public key and its hash tr, and rejects keys that
+// are not internally consistent, see PrivateKey65.Validate. It costs about
+// as much as key generation.
+func SetPrivateKeyValidation(enabled bool) {
+	importVal.Store(enabled)
+}
+
+// validateImports reports whether parsed private keys must be validated.
+func validateImports() bool {
+	return strictFIPS.Load() || importVal.Load()
+}
+
+var pctMessage = []byte("mldsa pairwise consistency test")
+
+// pairwiseConsistencyTest signs and verifies a test message with a newly
+// generated key, if the test is enabled. The context keeps the test valid
+// under a policy that requires one.
+func pairwiseConsistencyTest(sk PrivateKey) error {
+	if !strictFIPS.Load() && !keygenPCT.Load() {
+		return nil
+	}
+	sig, err := sk.SignWithContext(nil, pctMessage, pctMessage)
+	if err != nil {
+		return fmt.Errorf("%w: pairwise consistency test: %w", ErrSelfTestFailed, err)
+	}
+	if !sk.Public().(PublicKey).Verify(sig, pctMessage, pctMessage) {
+		return fmt.Errorf("%w: pairwise consistency test", ErrSelfTestFailed)
+	}
+	return nil
+}
+
+// approvedRandom reports whether r, as passed to a signing or key
+// generation function, is an approved randomness source.
+func approvedRandom(r io.Reader) bool {
+	if r == nil || r == cryptorand.Reader {
+		return true
+	}
+	a, ok := r.(ApprovedRandom)
+	return ok && a.FIPSApproved()
+}
+
+// approvedContext reports whether context satisfies the FIPS policy.
+func approvedContext(context []byte) bool {
+	return len(context) > 0 || !CurrentFIPSPolicy().RequireContext
+}
+
+// readRandom fills b from r, or from crypto/rand.Reader if r is nil. In
+// strict FIPS mode, unapproved sources are rejected.
+func readRandom(r io.Reader, b []byte) error {
+	switch rr := r.(type) {
+	case deterministicRand:
+		return errDeterministicKey
+	case hedgedRand:
+		r = rr.r
+	}
+	if strictFIPS.Load() && !approvedRandom(r) {
+		return fmt.Errorf("%w: randomness source %T is not an approved DRBG", ErrNotApproved, r)
+	}
+	_, err := io.ReadFull(randReader(r), b)
+	return err
+}
+
+// rndPool holds the buffers readRnd reads into. A slice handed to an
+// io.Reader escapes to the heap, so reading rnd directly into a stack array
+// would cost an allocation per signature.
+var rndPool = sync.Pool{New: func() any { return new([32]byte) }}
+
+// readRnd returns the 32-byte signing randomness rnd, read as readRandom
+// does. A source returning only zeros fails with ErrZeroRandomness. With
+// entropy mixing enabled, rnd is mixed with crypto/rand and the next value
+// of the per-key signature counter. DeterministicRand gives the all-zero
+// rnd, which HedgedRand falls back to when its source fails.
+func readRnd(r io.Reader, counter *atomic.Uint64) ([32]byte, error) {
+	switch rr := r.(type) {
+	case deterministicRand:
+		return [32]byte{}, nil
+	case hedgedRand:
+		rnd, err := readRnd(rr.r, counter)
+		if err != nil && !errors.Is(err, ErrNotApproved) {
+			return [32]byte{}, nil
+		}
+		return rnd, err
+	}
+	buf := rndPool.Get().(*[32]byte)
+	defer rndPool.Put(buf)
+	err := readRandom(r, buf[:])
+	if err == nil && isZero(buf[:]) {
+		// A source of zeros is a misconfigured or broken RNG; deterministic
+		// signing must be asked for with DeterministicRand.
+		err = ErrZeroRandomness
+	}
+	if err == nil && entropyMixing.Load() {
+		err = mixRnd(buf, r, counter.Add(1))
+	}
+	rnd := *buf
+	clear(buf[:])
+	return rnd, err
+}
+
+// isZero reports whether b holds only zeros, without branching on its
+// contents.
+func isZero(b []byte) bool {
+	var acc byte
+	for _, v := range b {
+		acc |= v
+	}
+	return acc == 0
+}
+
+// checkSignContext enforces the context policy in strict FIPS mode.
+func checkSignContext(context []byte) error {
+	if strictFIPS.Load() && !approvedContext(context) {
+		return fmt.Errorf("%w: the FIPS policy requires a context string", ErrNotApproved)
+	}
+	return nil
+}
+
+// reportIndicator stores the service indicator in opts, if it asks for one.
+func reportIndicator(opts crypto.SignerOpts, approved bool) {
+	o, ok := opts.(*SignerOpts)
+	if !ok || o == nil || o.Indicator == nil {
+		return
+	}
+	if approved {
+		*o.Indicator = IndicatorApproved
+	} else {
+		*o.Indicator = IndicatorNotApproved
+	}
+}
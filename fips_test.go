@@ -0,0 +1,120 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+type approvedReader struct{ approved bool }
+
+func (approvedReader) Read(p []byte) (int, error) { return rand.Read(p) }
+func (r approvedReader) FIPSApproved() bool       { return r.approved }
+
+func TestStrictFIPS(t *testing.T) {
+	if err := SetStrictFIPS(true); err != nil {
+		t.Fatal(err)
+	}
+	defer SetStrictFIPS(false)
+	defer SetFIPSPolicy(FIPSPolicy{})
+
+	key, _ := GenerateKey44(nil)
+	message := []byte("message")
+
+	if _, err := GenerateKey44(bytes.NewReader(make([]byte, 32))); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("GenerateKey44 with an unapproved source: %v", err)
+	}
+	if _, err := key.SignWithContext(bytes.NewReader(make([]byte, 32)), message, nil); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("SignWithContext with an unapproved source: %v", err)
+	}
+	if _, err := key.SignWithContext(approvedReader{false}, message, nil); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("SignWithContext with a withdrawn DRBG: %v", err)
+	}
+	if _, err := key.SignWithContext(approvedReader{true}, message, nil); err != nil {
+		t.Errorf("SignWithContext with an approved DRBG: %v", err)
+	}
+
+	SetFIPSPolicy(FIPSPolicy{RequireContext: true})
+	if _, err := key.SignWithContext(rand.Reader, message, nil); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("SignWithContext without a context: %v", err)
+	}
+	digest, _ := PreHash(SHAKE128, message)
+	if _, err := key.SignPreHash(rand.Reader, digest, SHAKE128, nil); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("SignPreHash without a context: %v", err)
+	}
+	if _, err := key.SignWithContext(rand.Reader, message, []byte("ctx")); err != nil {
+		t.Errorf("SignWithContext with a context: %v", err)
+	}
+}
+
+func TestServiceIndicator(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	message := []byte("message")
+
+	var ind ServiceIndicator
+	opts := &SignerOpts{Context: []byte("ctx"), Indicator: &ind}
+	sig, err := key.SignMessage(nil, message, opts)
+	if err != nil || ind != IndicatorApproved {
+		t.Errorf("SignMessage: indicator %v, err %v", ind, err)
+	}
+	ind = IndicatorUnset
+	if !key.PublicKey().VerifyWithOpts(sig, message, opts) || ind != IndicatorApproved {
+		t.Errorf("VerifyWithOpts: indicator %v", ind)
+	}
+
+	// Outside strict mode, unapproved services succeed but are flagged.
+	opts.Rand = bytes.NewReader(bytes.Repeat([]byte{1}, 32))
+	if _, err := key.Sign(nil, message, opts); err != nil || ind != IndicatorNotApproved {
+		t.Errorf("Sign with an unapproved source: indicator %v, err %v", ind, err)
+	}
+	if ind.String() != "not approved" {
+		t.Errorf("String() = %q", ind.String())
+	}
+}
+
+func TestPairwiseConsistencyTest(t *testing.T) {
+	key, _ := GenerateKey87(rand.Reader)
+	if err := pairwiseConsistencyTest(key); err != nil {
+		t.Fatalf("disabled test failed: %v", err)
+	}
+
+	SetPairwiseConsistencyTest(true)
+	defer SetPairwiseConsistencyTest(false)
+	for _, s := range Schemes() {
+		if _, err := s.GenerateKey(rand.Reader); err != nil {
+			t.Errorf("%s: GenerateKey with the test enabled: %v", s.Name(), err)
+		}
+	}
+
+	// A private key that no longer matches its public key must fail.
+	key.s1[0][0] = (key.s1[0][0] + 1) % Q
+	if err := pairwiseConsistencyTest(key); !errors.Is(err, ErrSelfTestFailed) {
+		t.Errorf("pairwise consistency test on a corrupted key: %v", err)
+	}
+}
+
+func TestFIPS140Mode(t *testing.T) {
+	if os.Getenv("MLDSA_TEST_FIPS140_CHILD") == "1" {
+		if !StrictFIPS() {
+			t.Fatal("strict FIPS mode is not enabled under GODEBUG=fips140=on")
+		}
+		if err := SetStrictFIPS(false); !errors.Is(err, ErrNotApproved) {
+			t.Fatalf("SetStrictFIPS(false) under crypto/fips140: %v", err)
+		}
+		return
+	}
+	if testing.Short() {
+		t.Skip("skipping subprocess test in short mode")
+	}
+	if puregoEnabled {
+		t.Skip("FIPS 140-3 mode is incompatible with the purego build tag")
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFIPS140Mode$")
+	cmd.Env = append(os.Environ(), "MLDSA_TEST_FIPS140_CHILD=1", "GODEBUG=fips140=on")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("child process failed: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,146 @@
+package mldsa
+
+// Keys implement gob.GobEncoder and gob.GobDecoder using their binary
+// encodings: the standard encoding for public keys, the expanded encoding
+// for private keys and the seed for key pairs.
+
+// GobEncode implements gob.GobEncoder.
+func (pk *PublicKey44) GobEncode() ([]byte, error) {
+	return pk.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder.
+func (pk *PublicKey44) GobDecode(b []byte) error {
+	parsed, err := NewPublicKey44(b)
+	if err != nil {
+		return err
+	}
+	pk.set(parsed)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder. It returns ErrKeyDestroyed for a
+// destroyed key.
+func (sk *PrivateKey44) GobEncode() ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return sk.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder.
+func (sk *PrivateKey44) GobDecode(b []byte) error {
+	parsed, err := NewPrivateKey44(b)
+	if err != nil {
+		return err
+	}
+	return sk.set(parsed)
+}
+
+// GobEncode implements gob.GobEncoder. It returns ErrKeyDestroyed for a
+// destroyed key.
+func (key *Key44) GobEncode() ([]byte, error) {
+	if key.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return key.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder.
+func (key *Key44) GobDecode(b []byte) error {
+	return key.Regenerate(b)
+}
+
+// GobEncode implements gob.GobEncoder.
+func (pk *PublicKey65) GobEncode() ([]byte, error) {
+	return pk.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder.
+func (pk *PublicKey65) GobDecode(b []byte) error {
+	parsed, err := NewPublicKey65(b)
+	if err != nil {
+		return err
+	}
+	pk.set(parsed)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder. It returns ErrKeyDestroyed for a
+// destroyed key.
+func (sk *PrivateKey65) GobEncode() ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return sk.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder.
+func (sk *PrivateKey65) GobDecode(b []byte) error {
+	parsed, err := NewPrivateKey65(b)
+	if err != nil {
+		return err
+	}
+	return sk.set(parsed)
+}
+
+// GobEncode implements gob.GobEncoder. It returns ErrKeyDestroyed for a
+// destroyed key.
+func (key *Key65) GobEncode() ([]byte, error) {
+	if key.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return key.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder.
+func (key *Key65) GobDecode(b []byte) error {
+	return key.Regenerate(b)
+}
+
+// GobEncode implements gob.GobEncoder.
+func (pk *PublicKey87) GobEncode() ([]byte, error) {
+	return pk.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder.
+func (pk *PublicKey87) GobDecode(b []byte) error {
+	parsed, err := NewPublicKey87(b)
+	if err != nil {
+		return err
+	}
+	pk.set(parsed)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder. It returns ErrKeyDestroyed for a
+// destroyed key.
+func (sk *PrivateKey87) GobEncode() ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return sk.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder.
+func (sk *PrivateKey87) GobDecode(b []byte) error {
+	parsed, err := NewPrivateKey87(b)
+	if err != nil {
+		return err
+	}
+	return sk.set(parsed)
+}
+
+// GobEncode implements gob.GobEncoder. It returns ErrKeyDestroyed for a
+// destroyed key.
+func (key *Key87) GobEncode() ([]byte, error) {
+	if key.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return key.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder.
+func (key *Key87) GobDecode(b []byte) error {
+	return key.Regenerate(b)
+}
@@ -0,0 +1,37 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/gob"
+	"testing"
+)
+
+func TestGob(t *testing.T) {
+	type cache struct {
+		Key     *Key65
+		Private *PrivateKey44
+		Public  *PublicKey87
+	}
+	key65, _ := GenerateKey65(rand.Reader)
+	key44, _ := GenerateKey44(rand.Reader)
+	key87, _ := GenerateKey87(rand.Reader)
+	in := cache{Key: key65, Private: &key44.PrivateKey44, Public: key87.PublicKey()}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var out cache
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !out.Key.Equal(key65) || !out.Private.Equal(key44) || !out.Public.Equal(key87.PublicKey()) {
+		t.Error("keys did not roundtrip through gob")
+	}
+
+	var pk PublicKey44
+	if err := pk.GobDecode(make([]byte, 10)); err == nil {
+		t.Error("GobDecode accepted a short public key")
+	}
+}
@@ -0,0 +1,18 @@
+// Package fips204 links the mldsa package to the conformance subpackage.
+//
+// The mldsa package fills in these hooks at init time, so the internal
+// algorithms of FIPS 204 can be reached from the conformance package without
+// being exported from mldsa itself.
+package fips204
+
+import "crypto"
+
+var (
+	// SignInternal runs ML-DSA.Sign_internal (Algorithm 7) with the private
+	// key sk, the 32-byte rnd and the message representative mPrime.
+	SignInternal func(sk crypto.Signer, rnd, mPrime []byte) ([]byte, error)
+
+	// VerifyInternal runs ML-DSA.Verify_internal (Algorithm 8) with the public
+	// key pk on sig and the message representative mPrime.
+	VerifyInternal func(pk crypto.PublicKey, sig, mPrime []byte) error
+)
@@ -0,0 +1,349 @@
+package mldsa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Keys marshal to JSON as an object carrying the parameter set next to the
+// base64url-encoded key, such as {"alg":"ML-DSA-65","key":"..."}, so that a
+// key is never decoded as the wrong parameter set. Key pairs encode their
+// seed, standalone private keys their expanded encoding. Use AnyPublicKey
+// and AnyPrivateKey for fields that may hold a key of any parameter set.
+
+// jsonKey is the JSON form of a key.
+type jsonKey struct {
+	Alg string `json:"alg"`
+	Key string `json:"key"`
+}
+
+// ParameterSetMismatchError is returned when decoding data tagged with a
+// different parameter set than the destination type.
+type ParameterSetMismatchError struct {
+	Got  string // parameter set found in the input
+	Want string // parameter set of the destination
+}
+
+func (e *ParameterSetMismatchError) Error() string {
+	return fmt.Sprintf("mldsa: parameter set mismatch: got %s, want %s", e.Got, e.Want)
+}
+
+// marshalJSONKey encodes b as a JSON key tagged with alg.
+func marshalJSONKey(alg string, b []byte) ([]byte, error) {
+	return json.Marshal(jsonKey{Alg: alg, Key: string(marshalText(b))})
+}
+
+// unmarshalJSONKey decodes a JSON key and returns its parameter set and
+// encoded bytes. The parameter set must be registered.
+func unmarshalJSONKey(data []byte) (string, []byte, error) {
+	var k jsonKey
+	if err := json.Unmarshal(data, &k); err != nil {
+		return "", nil, err
+	}
+	if SchemeByName(k.Alg) == nil {
+		return "", nil, fmt.Errorf("%w %q", ErrUnknownParameterSet, k.Alg)
+	}
+	b, err := unmarshalText([]byte(k.Key))
+	if err != nil {
+		return "", nil, err
+	}
+	return k.Alg, b, nil
+}
+
+// unmarshalJSONKeyFor is like unmarshalJSONKey, but fails with a
+// *ParameterSetMismatchError unless the key is tagged with want.
+func unmarshalJSONKeyFor(data []byte, want string) ([]byte, error) {
+	alg, b, err := unmarshalJSONKey(data)
+	if err != nil {
+		return nil, err
+	}
+	if alg != want {
+		return nil, &ParameterSetMismatchError{Got: alg, Want: want}
+	}
+	return b, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (pk *PublicKey44) MarshalJSON() ([]byte, error) {
+	return marshalJSONKey("ML-DSA-44", pk.Bytes())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (pk *PublicKey44) UnmarshalJSON(data []byte) error {
+	b, err := unmarshalJSONKeyFor(data, "ML-DSA-44")
+	if err != nil {
+		return err
+	}
+	parsed, err := NewPublicKey44(b)
+	if err != nil {
+		return err
+	}
+	pk.set(parsed)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. It returns ErrKeyDestroyed for a
+// destroyed key.
+func (sk *PrivateKey44) MarshalJSON() ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	b := sk.Bytes()
+	defer clear(b)
+	return marshalJSONKey("ML-DSA-44", b)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both a seed and an
+// expanded private key.
+func (sk *PrivateKey44) UnmarshalJSON(data []byte) error {
+	b, err := unmarshalJSONKeyFor(data, "ML-DSA-44")
+	if err != nil {
+		return err
+	}
+	defer clear(b)
+	var parsed *PrivateKey44
+	if len(b) == SeedSize {
+		parsed, err = NewPrivateKey44FromSeed(b)
+	} else {
+		parsed, err = NewPrivateKey44(b)
+	}
+	if err != nil {
+		return err
+	}
+	defer parsed.wipe()
+	return sk.set(parsed)
+}
+
+// MarshalJSON implements json.Marshaler. It returns ErrKeyDestroyed for a
+// destroyed key.
+func (key *Key44) MarshalJSON() ([]byte, error) {
+	if key.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return marshalJSONKey("ML-DSA-44", key.seed[:])
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Only seeds are accepted, since
+// a key pair cannot be recovered from an expanded private key.
+func (key *Key44) UnmarshalJSON(data []byte) error {
+	b, err := unmarshalJSONKeyFor(data, "ML-DSA-44")
+	if err != nil {
+		return err
+	}
+	defer clear(b)
+	return key.Regenerate(b)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (pk *PublicKey65) MarshalJSON() ([]byte, error) {
+	return marshalJSONKey("ML-DSA-65", pk.Bytes())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (pk *PublicKey65) UnmarshalJSON(data []byte) error {
+	b, err := unmarshalJSONKeyFor(data, "ML-DSA-65")
+	if err != nil {
+		return err
+	}
+	parsed, err := NewPublicKey65(b)
+	if err != nil {
+		return err
+	}
+	pk.set(parsed)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. It returns ErrKeyDestroyed for a
+// destroyed key.
+func (sk *PrivateKey65) MarshalJSON() ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	b := sk.Bytes()
+	defer clear(b)
+	return marshalJSONKey("ML-DSA-65", b)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both a seed and an
+// expanded private key.
+func (sk *PrivateKey65) UnmarshalJSON(data []byte) error {
+	b, err := unmarshalJSONKeyFor(data, "ML-DSA-65")
+	if err != nil {
+		return err
+	}
+	defer clear(b)
+	var parsed *PrivateKey65
+	if len(b) == SeedSize {
+		parsed, err = NewPrivateKey65FromSeed(b)
+	} else {
+		parsed, err = NewPrivateKey65(b)
+	}
+	if err != nil {
+		return err
+	}
+	defer parsed.wipe()
+	return sk.set(parsed)
+}
+
+// MarshalJSON implements json.Marshaler. It returns ErrKeyDestroyed for a
+// destroyed key.
+func (key *Key65) MarshalJSON() ([]byte, error) {
+	if key.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return marshalJSONKey("ML-DSA-65", key.seed[:])
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Only seeds are accepted, since
+// a key pair cannot be recovered from an expanded private key.
+func (key *Key65) UnmarshalJSON(data []byte) error {
+	b, err := unmarshalJSONKeyFor(data, "ML-DSA-65")
+	if err != nil {
+		return err
+	}
+	defer clear(b)
+	return key.Regenerate(b)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (pk *PublicKey87) MarshalJSON() ([]byte, error) {
+	return marshalJSONKey("ML-DSA-87", pk.Bytes())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (pk *PublicKey87) UnmarshalJSON(data []byte) error {
+	b, err := unmarshalJSONKeyFor(data, "ML-DSA-87")
+	if err != nil {
+		return err
+	}
+	parsed, err := NewPublicKey87(b)
+	if err != nil {
+		return err
+	}
+	pk.set(parsed)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. It returns ErrKeyDestroyed for a
+// destroyed key.
+func (sk *PrivateKey87) MarshalJSON() ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	b := sk.Bytes()
+	defer clear(b)
+	return marshalJSONKey("ML-DSA-87", b)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both a seed and an
+// expanded private key.
+func (sk *PrivateKey87) UnmarshalJSON(data []byte) error {
+	b, err := unmarshalJSONKeyFor(data, "ML-DSA-87")
+	if err != nil {
+		return err
+	}
+	defer clear(b)
+	var parsed *PrivateKey87
+	if len(b) == SeedSize {
+		parsed, err = NewPrivateKey87FromSeed(b)
+	} else {
+		parsed, err = NewPrivateKey87(b)
+	}
+	if err != nil {
+		return err
+	}
+	defer parsed.wipe()
+	return sk.set(parsed)
+}
+
+// MarshalJSON implements json.Marshaler. It returns ErrKeyDestroyed for a
+// destroyed key.
+func (key *Key87) MarshalJSON() ([]byte, error) {
+	if key.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return marshalJSONKey("ML-DSA-87", key.seed[:])
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Only seeds are accepted, since
+// a key pair cannot be recovered from an expanded private key.
+func (key *Key87) UnmarshalJSON(data []byte) error {
+	b, err := unmarshalJSONKeyFor(data, "ML-DSA-87")
+	if err != nil {
+		return err
+	}
+	defer clear(b)
+	return key.Regenerate(b)
+}
+
+// AnyPublicKey wraps a public key of any parameter set for JSON encoding.
+// Unmarshaling selects the parameter set from the "alg" field.
+type AnyPublicKey struct {
+	PublicKey
+}
+
+// MarshalJSON implements json.Marshaler.
+func (k AnyPublicKey) MarshalJSON() ([]byte, error) {
+	if k.PublicKey == nil {
+		return []byte("null"), nil
+	}
+	return marshalJSONKey(k.Scheme().Name(), k.Bytes())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (k *AnyPublicKey) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		k.PublicKey = nil
+		return nil
+	}
+	alg, b, err := unmarshalJSONKey(data)
+	if err != nil {
+		return err
+	}
+	pk, err := parsePublicKeyFor(alg, b)
+	if err != nil {
+		return err
+	}
+	k.PublicKey = pk
+	return nil
+}
+
+// AnyPrivateKey wraps a private key or key pair of any parameter set for
+// JSON encoding. Unmarshaling selects the parameter set from the "alg"
+// field and yields a key pair for seeds and a private key otherwise.
+type AnyPrivateKey struct {
+	PrivateKey
+}
+
+// MarshalJSON implements json.Marshaler.
+func (k AnyPrivateKey) MarshalJSON() ([]byte, error) {
+	if k.PrivateKey == nil {
+		return []byte("null"), nil
+	}
+	b, err := privateKeyEncoding(k.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(b)
+	return marshalJSONKey(k.Scheme().Name(), b)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (k *AnyPrivateKey) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		k.PrivateKey = nil
+		return nil
+	}
+	alg, b, err := unmarshalJSONKey(data)
+	if err != nil {
+		return err
+	}
+	defer clear(b)
+	sk, err := parsePrivateKeyFor(alg, b)
+	if err != nil {
+		return err
+	}
+	k.PrivateKey = sk
+	return nil
+}
@@ -0,0 +1,104 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSONKeys(t *testing.T) {
+	key, _ := GenerateKey87(rand.Reader)
+
+	data, err := json.Marshal(key.PublicKey())
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"alg":"ML-DSA-87"`) {
+		t.Errorf("missing algorithm identifier: %s", data)
+	}
+	var pk PublicKey87
+	if err := json.Unmarshal(data, &pk); err != nil || !pk.Equal(key.PublicKey()) {
+		t.Errorf("public key did not roundtrip: %v", err)
+	}
+
+	var mismatch *ParameterSetMismatchError
+	var pk65 PublicKey65
+	if err := json.Unmarshal(data, &pk65); !errors.As(err, &mismatch) || mismatch.Got != "ML-DSA-87" || mismatch.Want != "ML-DSA-65" {
+		t.Errorf("decoding ML-DSA-87 as ML-DSA-65: %v", err)
+	}
+
+	// A key pair encodes its seed, which also decodes as a private key.
+	data, _ = json.Marshal(key)
+	var key2 Key87
+	if err := json.Unmarshal(data, &key2); err != nil || !key2.Equal(key) {
+		t.Errorf("key pair did not roundtrip: %v", err)
+	}
+	var sk PrivateKey87
+	if err := json.Unmarshal(data, &sk); err != nil || !sk.Equal(key) {
+		t.Errorf("private key from seed: %v", err)
+	}
+	data, _ = json.Marshal(&key.PrivateKey87)
+	if err := json.Unmarshal(data, &key2); err == nil {
+		t.Error("key pair decoded from an expanded private key")
+	}
+
+	if err := json.Unmarshal([]byte(`{"alg":"ML-DSA-1","key":""}`), &pk); !errors.Is(err, ErrUnknownParameterSet) {
+		t.Errorf("unknown algorithm: %v", err)
+	}
+}
+
+func TestJSONAnyKey(t *testing.T) {
+	type config struct {
+		Signing AnyPrivateKey `json:"signing"`
+		Peer    AnyPublicKey  `json:"peer"`
+		Unset   AnyPublicKey  `json:"unset"`
+	}
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		in := config{Signing: AnyPrivateKey{sk}, Peer: AnyPublicKey{sk.Public().(PublicKey)}}
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Fatalf("%s: json.Marshal failed: %v", s.Name(), err)
+		}
+		var out config
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatalf("%s: json.Unmarshal failed: %v", s.Name(), err)
+		}
+		if out.Signing.Scheme().Name() != s.Name() || !out.Peer.Equal(sk.Public()) || out.Unset.PublicKey != nil {
+			t.Errorf("%s: keys did not roundtrip", s.Name())
+		}
+		sig, _ := out.Signing.SignWithContext(rand.Reader, []byte("m"), nil)
+		if !out.Peer.Verify(sig, []byte("m"), nil) {
+			t.Errorf("%s: decoded keys do not match", s.Name())
+		}
+
+		// A seed key is written as its seed, like the key pair.
+		p, _ := ParseParameterSet(s.Name())
+		seed, _ := sk.Seed()
+		seedKey, _ := NewSeedKey(p, seed)
+		want, _ := json.Marshal(AnyPrivateKey{sk})
+		if got, err := json.Marshal(AnyPrivateKey{seedKey}); err != nil || !bytes.Equal(got, want) {
+			t.Errorf("%s: seed key: got %s, %v; want %s", s.Name(), got, err, want)
+		}
+	}
+}
+
+func TestJSONMarshalDestroyed(t *testing.T) {
+	for _, p := range ParameterSets() {
+		key, _ := p.Scheme().GenerateKey(rand.Reader)
+		b, _ := expandedPrivateKey(key)
+		sk, _ := p.Scheme().UnmarshalPrivateKey(b)
+		for _, k := range []PrivateKey{key, sk} {
+			k.(interface{ Destroy() }).Destroy()
+			if _, err := json.Marshal(k); !errors.Is(err, ErrKeyDestroyed) {
+				t.Errorf("%v: marshaling a destroyed %T: %v", p, k, err)
+			}
+			if _, err := json.Marshal(AnyPrivateKey{k}); !errors.Is(err, ErrKeyDestroyed) {
+				t.Errorf("%v: marshaling a destroyed %T as AnyPrivateKey: %v", p, k, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+//go:build amd64 && !purego
+
+package mldsa
+
+import "encoding/binary"
+
+// keccakF1600x4 applies Keccak-f[1600] to four interleaved states, lane i
+// of state n being a[4*i+n]. It requires AVX2.
+//
+//go:noescape
+func keccakF1600x4(a *[100]uint64)
+
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+func xgetbv() (eax, edx uint32)
+
+// useKeccak4 reports whether keccakF1600x4 can run on this CPU.
+var useKeccak4 = hasAVX2()
+
+func hasAVX2() bool {
+	if maxID, _, _, _ := cpuid(0, 0); maxID < 7 {
+		return false
+	}
+	const osxsave, avx = 1 << 27, 1 << 28
+	if _, _, ecx, _ := cpuid(1, 0); ecx&osxsave == 0 || ecx&avx == 0 {
+		return false
+	}
+	// The OS must preserve the XMM and YMM state.
+	if xcr0, _ := xgetbv(); xcr0&6 != 6 {
+		return false
+	}
+	const avx2 = 1 << 5
+	_, ebx, _, _ := cpuid(7, 0)
+	return ebx&avx2 != 0
+}
+
+// expandA4 samples the entries of Â from index first on into a, four at a
+// time, and returns how many it filled; the caller samples the remaining
+// ones.
+func expandA4(a []NttElement, rho []byte, l, first int) int {
+	if !useKeccak4 || len(rho) != 32 {
+		return 0
+	}
+	n := len(a) &^ 3
+	for idx := 0; idx < n; idx += 4 {
+		var s, r [4]byte
+		for m := 0; m < 4; m++ {
+			s[m], r[m] = byte((first+idx+m)%l), byte((first+idx+m)/l)
+		}
+		polys := sampleNTTPoly4(rho, s, r)
+		copy(a[idx:idx+4], polys[:])
+	}
+	return n
+}
+
+// sampleNTTPoly4 returns SampleNTTPoly(rho, s[n], r[n]) for n = 0..3,
+// running the four SHAKE128 instances interleaved. rho must be 32 bytes, so
+// that each input fits in a single block.
+func sampleNTTPoly4(rho []byte, s, r [4]byte) (a [4]NttElement) {
+	var state [100]uint64
+	for i := 0; i < 4; i++ {
+		w := binary.LittleEndian.Uint64(rho[8*i:])
+		for n := 0; n < 4; n++ {
+			state[4*i+n] = w
+		}
+	}
+	for n := 0; n < 4; n++ {
+		// Bytes 32 and 33 are s and r, followed by the SHAKE domain
+		// separator 0x1f; the final bit of the 168-byte block is set.
+		state[4*4+n] = uint64(s[n]) | uint64(r[n])<<8 | 0x1f<<16
+		state[4*20+n] = 0x80 << 56
+	}
+
+	var j [4]int
+	var buf [168]byte
+	for j[0] < N || j[1] < N || j[2] < N || j[3] < N {
+		keccakF1600x4(&state)
+		for n := 0; n < 4; n++ {
+			if j[n] == N {
+				continue
+			}
+			for i := 0; i < len(buf)/8; i++ {
+				binary.LittleEndian.PutUint64(buf[8*i:], state[4*i+n])
+			}
+			j[n] = rejNTTPoly(&a[n], j[n], buf[:])
+		}
+	}
+	return a
+}
@@ -0,0 +1,190 @@
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// Keccak-f[1600] on four interleaved states with AVX2. Lane i of instance
+// n is at a[4*i+n], so each 32-byte row holds one lane of all four states
+// and fits a Y register.
+
+// Round constants for ι.
+DATA roundConstants<>+0x00(SB)/8, $0x0000000000000001
+DATA roundConstants<>+0x08(SB)/8, $0x0000000000008082
+DATA roundConstants<>+0x10(SB)/8, $0x800000000000808a
+DATA roundConstants<>+0x18(SB)/8, $0x8000000080008000
+DATA roundConstants<>+0x20(SB)/8, $0x000000000000808b
+DATA roundConstants<>+0x28(SB)/8, $0x0000000080000001
+DATA roundConstants<>+0x30(SB)/8, $0x8000000080008081
+DATA roundConstants<>+0x38(SB)/8, $0x8000000000008009
+DATA roundConstants<>+0x40(SB)/8, $0x000000000000008a
+DATA roundConstants<>+0x48(SB)/8, $0x0000000000000088
+DATA roundConstants<>+0x50(SB)/8, $0x0000000080008009
+DATA roundConstants<>+0x58(SB)/8, $0x000000008000000a
+DATA roundConstants<>+0x60(SB)/8, $0x000000008000808b
+DATA roundConstants<>+0x68(SB)/8, $0x800000000000008b
+DATA roundConstants<>+0x70(SB)/8, $0x8000000000008089
+DATA roundConstants<>+0x78(SB)/8, $0x8000000000008003
+DATA roundConstants<>+0x80(SB)/8, $0x8000000000008002
+DATA roundConstants<>+0x88(SB)/8, $0x8000000000000080
+DATA roundConstants<>+0x90(SB)/8, $0x000000000000800a
+DATA roundConstants<>+0x98(SB)/8, $0x800000008000000a
+DATA roundConstants<>+0xa0(SB)/8, $0x8000000080008081
+DATA roundConstants<>+0xa8(SB)/8, $0x8000000000008080
+DATA roundConstants<>+0xb0(SB)/8, $0x0000000080000001
+DATA roundConstants<>+0xb8(SB)/8, $0x8000000080008008
+GLOBL roundConstants<>(SB), RODATA|NOPTR, $192
+
+// ROL1 sets dst to src rotated left by one bit.
+#define ROL1(src, dst) \
+	VPSLLQ $1, src, dst; \
+	VPSRLQ $63, src, Y15; \
+	VPOR   Y15, dst, dst
+
+// RHO sets B to the lane at off(src), after θ with D, rotated left by n.
+#define RHO(off, src, D, n, B) \
+	VPXOR  off(src), D, B; \
+	VPSLLQ $n, B, Y15; \
+	VPSRLQ $(64-n), B, B; \
+	VPOR   Y15, B, B
+
+// CHI stores b0 ^ (^b1 & b2) to m.
+#define CHI(b0, b1, b2, m) \
+	VPANDN b2, b1, Y15; \
+	VPXOR  b0, Y15, Y15; \
+	VMOVDQU Y15, m
+
+// ROUND computes one round of Keccak-f[1600] from the state at src into
+// dst, except for ι on lane 0. Y0-Y4 hold the column parities C, Y5-Y9
+// the θ offsets D, Y10-Y14 the five lanes of an output plane after ρ and π,
+// and Y15 is scratch.
+#define ROUND(src, dst) \
+	VMOVDQU 0(src), Y0; \
+	VPXOR 160(src), Y0, Y0; \
+	VPXOR 320(src), Y0, Y0; \
+	VPXOR 480(src), Y0, Y0; \
+	VPXOR 640(src), Y0, Y0; \
+	VMOVDQU 32(src), Y1; \
+	VPXOR 192(src), Y1, Y1; \
+	VPXOR 352(src), Y1, Y1; \
+	VPXOR 512(src), Y1, Y1; \
+	VPXOR 672(src), Y1, Y1; \
+	VMOVDQU 64(src), Y2; \
+	VPXOR 224(src), Y2, Y2; \
+	VPXOR 384(src), Y2, Y2; \
+	VPXOR 544(src), Y2, Y2; \
+	VPXOR 704(src), Y2, Y2; \
+	VMOVDQU 96(src), Y3; \
+	VPXOR 256(src), Y3, Y3; \
+	VPXOR 416(src), Y3, Y3; \
+	VPXOR 576(src), Y3, Y3; \
+	VPXOR 736(src), Y3, Y3; \
+	VMOVDQU 128(src), Y4; \
+	VPXOR 288(src), Y4, Y4; \
+	VPXOR 448(src), Y4, Y4; \
+	VPXOR 608(src), Y4, Y4; \
+	VPXOR 768(src), Y4, Y4; \
+	ROL1(Y1, Y5); \
+	VPXOR Y4, Y5, Y5; \
+	ROL1(Y2, Y6); \
+	VPXOR Y0, Y6, Y6; \
+	ROL1(Y3, Y7); \
+	VPXOR Y1, Y7, Y7; \
+	ROL1(Y4, Y8); \
+	VPXOR Y2, Y8, Y8; \
+	ROL1(Y0, Y9); \
+	VPXOR Y3, Y9, Y9; \
+	VPXOR 0(src), Y5, Y10; \
+	RHO(192, src, Y6, 44, Y11); \
+	RHO(384, src, Y7, 43, Y12); \
+	RHO(576, src, Y8, 21, Y13); \
+	RHO(768, src, Y9, 14, Y14); \
+	CHI(Y10, Y11, Y12, 0(dst)); \
+	CHI(Y11, Y12, Y13, 32(dst)); \
+	CHI(Y12, Y13, Y14, 64(dst)); \
+	CHI(Y13, Y14, Y10, 96(dst)); \
+	CHI(Y14, Y10, Y11, 128(dst)); \
+	RHO(96, src, Y8, 28, Y10); \
+	RHO(288, src, Y9, 20, Y11); \
+	RHO(320, src, Y5, 3, Y12); \
+	RHO(512, src, Y6, 45, Y13); \
+	RHO(704, src, Y7, 61, Y14); \
+	CHI(Y10, Y11, Y12, 160(dst)); \
+	CHI(Y11, Y12, Y13, 192(dst)); \
+	CHI(Y12, Y13, Y14, 224(dst)); \
+	CHI(Y13, Y14, Y10, 256(dst)); \
+	CHI(Y14, Y10, Y11, 288(dst)); \
+	RHO(32, src, Y6, 1, Y10); \
+	RHO(224, src, Y7, 6, Y11); \
+	RHO(416, src, Y8, 25, Y12); \
+	RHO(608, src, Y9, 8, Y13); \
+	RHO(640, src, Y5, 18, Y14); \
+	CHI(Y10, Y11, Y12, 320(dst)); \
+	CHI(Y11, Y12, Y13, 352(dst)); \
+	CHI(Y12, Y13, Y14, 384(dst)); \
+	CHI(Y13, Y14, Y10, 416(dst)); \
+	CHI(Y14, Y10, Y11, 448(dst)); \
+	RHO(128, src, Y9, 27, Y10); \
+	RHO(160, src, Y5, 36, Y11); \
+	RHO(352, src, Y6, 10, Y12); \
+	RHO(544, src, Y7, 15, Y13); \
+	RHO(736, src, Y8, 56, Y14); \
+	CHI(Y10, Y11, Y12, 480(dst)); \
+	CHI(Y11, Y12, Y13, 512(dst)); \
+	CHI(Y12, Y13, Y14, 544(dst)); \
+	CHI(Y13, Y14, Y10, 576(dst)); \
+	CHI(Y14, Y10, Y11, 608(dst)); \
+	RHO(64, src, Y7, 62, Y10); \
+	RHO(256, src, Y8, 55, Y11); \
+	RHO(448, src, Y9, 39, Y12); \
+	RHO(480, src, Y5, 41, Y13); \
+	RHO(672, src, Y6, 2, Y14); \
+	CHI(Y10, Y11, Y12, 640(dst)); \
+	CHI(Y11, Y12, Y13, 672(dst)); \
+	CHI(Y12, Y13, Y14, 704(dst)); \
+	CHI(Y13, Y14, Y10, 736(dst)); \
+	CHI(Y14, Y10, Y11, 768(dst))
+
+
+// IOTA applies ι with the round constant at rc to the state at dst.
+#define IOTA(rc, dst) \
+	VPBROADCASTQ rc, Y15; \
+	VPXOR   0(dst), Y15, Y15; \
+	VMOVDQU Y15, 0(dst)
+
+// func keccakF1600x4(a *[100]uint64)
+// Rounds alternate between a and a scratch state on the stack.
+TEXT ·keccakF1600x4(SB), 0, $800-8
+	MOVQ a+0(FP), DI
+	MOVQ SP, R8
+	LEAQ roundConstants<>(SB), SI
+	MOVQ $12, CX
+
+loop:
+	ROUND(DI, R8)
+	IOTA(0(SI), R8)
+	ROUND(R8, DI)
+	IOTA(8(SI), DI)
+	ADDQ $16, SI
+	DECQ CX
+	JNZ  loop
+
+	VZEROUPPER
+	RET
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
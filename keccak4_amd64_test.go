@@ -0,0 +1,85 @@
+//go:build amd64 && !purego
+
+package mldsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha3"
+	"encoding/binary"
+	"testing"
+)
+
+func TestKeccakF1600x4(t *testing.T) {
+	if !useKeccak4 {
+		t.Skip("AVX2 not available")
+	}
+	// Squeeze four SHAKE128 streams of single-block inputs and compare them
+	// with crypto/sha3.
+	var inputs [4][100]byte
+	var state [100]uint64
+	for n := range inputs {
+		in := inputs[n][:n*30]
+		rand.Read(in)
+		var block [168]byte
+		copy(block[:], in)
+		block[len(in)] ^= 0x1f
+		block[167] ^= 0x80
+		for i := 0; i < 21; i++ {
+			state[4*i+n] = binary.LittleEndian.Uint64(block[8*i:])
+		}
+	}
+	var got [4][]byte
+	for iter := 0; iter < 8; iter++ {
+		keccakF1600x4(&state)
+		for n := range got {
+			for i := 0; i < 21; i++ {
+				got[n] = binary.LittleEndian.AppendUint64(got[n], state[4*i+n])
+			}
+		}
+	}
+	for n := range got {
+		want := make([]byte, len(got[n]))
+		h := sha3.NewSHAKE128()
+		h.Write(inputs[n][:n*30])
+		h.Read(want)
+		if !bytes.Equal(got[n], want) {
+			t.Errorf("instance %d: SHAKE128 stream mismatch", n)
+		}
+	}
+}
+
+func TestSampleNTTPoly4(t *testing.T) {
+	if !useKeccak4 {
+		t.Skip("AVX2 not available")
+	}
+	rho := make([]byte, 32)
+	for iter := 0; iter < 50; iter++ {
+		rand.Read(rho)
+		s, r := [4]byte{0, 1, 2, 3}, [4]byte{byte(iter), 7, 0, 255}
+		got := sampleNTTPoly4(rho, s, r)
+		for n := range got {
+			if got[n] != SampleNTTPoly(rho, s[n], r[n]) {
+				t.Fatalf("rho %x, (%d, %d): mismatch", rho, s[n], r[n])
+			}
+		}
+	}
+}
+
+func BenchmarkExpandA65(b *testing.B) {
+	rho := make([]byte, 32)
+	a := make([]NttElement, K65*L65)
+	for _, fast := range []bool{false, true} {
+		if fast && !useKeccak4 {
+			continue
+		}
+		name := map[bool]string{false: "scalar", true: "x4"}[fast]
+		b.Run(name, func(b *testing.B) {
+			defer func(v bool) { useKeccak4 = v }(useKeccak4)
+			useKeccak4 = fast
+			for i := 0; i < b.N; i++ {
+				expandA(a, rho, L65)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !amd64 || purego
+
+package mldsa
+
+// expandA4 samples no entries of Â: there is no interleaved Keccak on this
+// platform.
+func expandA4(a []NttElement, rho []byte, l, first int) int {
+	return 0
+}
@@ -0,0 +1,213 @@
+package mldsa
+
+import (
+	"crypto"
+	"fmt"
+	"io"
+)
+
+// PublicKey is an ML-DSA public key of any parameter set. It is implemented
+// by *PublicKey44, *PublicKey65 and *PublicKey87, so code can accept a key
+// of any security level without a type switch.
+type PublicKey interface {
+	// Bytes returns the encoded public key.
+	Bytes() []byte
+	// Equal reports whether the key equals other.
+	Equal(other crypto.PublicKey) bool
+	// Verify checks the signature on message with optional context.
+	Verify(sig, message, context []byte) bool
+	// Scheme returns the parameter set of the key.
+	Scheme() Scheme
+	// Fingerprint returns the SHA-256 fingerprint of the encoded key.
+	Fingerprint() Fingerprint
+}
+
+// PrivateKey is an ML-DSA private key of any parameter set. It is
+// implemented by *PrivateKey44, *PrivateKey65 and *PrivateKey87, and by the
+// key pair types *Key44, *Key65 and *Key87.
+type PrivateKey interface {
+	crypto.Signer
+	// SignWithContext signs message with an optional context string.
+	SignWithContext(rand io.Reader, message, context []byte) ([]byte, error)
+	// Scheme returns the parameter set of the key.
+	Scheme() Scheme
+	// Seed returns the 32-byte seed of the key, or false if it is not
+	// available, as for keys parsed from the expanded encoding.
+	Seed() ([]byte, bool)
+}
+
+// Compile-time interface assertions for PublicKey and PrivateKey.
+var (
+	_ PublicKey  = (*PublicKey44)(nil)
+	_ PublicKey  = (*PublicKey65)(nil)
+	_ PublicKey  = (*PublicKey87)(nil)
+	_ PrivateKey = (*PrivateKey44)(nil)
+	_ PrivateKey = (*PrivateKey65)(nil)
+	_ PrivateKey = (*PrivateKey87)(nil)
+	_ PrivateKey = (*Key44)(nil)
+	_ PrivateKey = (*Key65)(nil)
+	_ PrivateKey = (*Key87)(nil)
+	_ PrivateKey = (*SeedKey)(nil)
+	_ PrivateKey = (*ExternalSigner)(nil)
+)
+
+// ParsePublicKey parses an encoded public key of any parameter set. The
+// parameter set is inferred from the length of b.
+func ParsePublicKey(b []byte) (PublicKey, error) {
+	for _, p := range ParameterSets() {
+		if len(b) == p.PublicKeySize() {
+			return p.Scheme().UnmarshalPublicKey(b)
+		}
+	}
+	return nil, &LengthError{Err: ErrInvalidPublicKey, Got: len(b)}
+}
+
+// ParsePublicKeyLazy is like ParsePublicKey, but defers expanding the
+// matrix A until the key first verifies a signature, as NewPublicKey65Lazy
+// does.
+func ParsePublicKeyLazy(b []byte) (PublicKey, error) {
+	var pk PublicKey
+	var err error
+	switch len(b) {
+	case PublicKeySize44:
+		pk, err = NewPublicKey44Lazy(b)
+	case PublicKeySize65:
+		pk, err = NewPublicKey65Lazy(b)
+	case PublicKeySize87:
+		pk, err = NewPublicKey87Lazy(b)
+	default:
+		return nil, &LengthError{Err: ErrInvalidPublicKey, Got: len(b)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pk, nil
+}
+
+// ParsePublicKeyCompact is like ParsePublicKey, but returns a key that
+// never stores the matrix A, as NewPublicKey65Compact does.
+func ParsePublicKeyCompact(b []byte) (PublicKey, error) {
+	var pk PublicKey
+	var err error
+	switch len(b) {
+	case PublicKeySize44:
+		pk, err = NewPublicKey44Compact(b)
+	case PublicKeySize65:
+		pk, err = NewPublicKey65Compact(b)
+	case PublicKeySize87:
+		pk, err = NewPublicKey87Compact(b)
+	default:
+		return nil, &LengthError{Err: ErrInvalidPublicKey, Got: len(b)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pk, nil
+}
+
+// ValidatePublicKey checks that b is a valid encoded public key of one of
+// the ML-DSA parameter sets, as told apart by length. Every bit pattern of
+// the right length is a canonical encoding, so this amounts to parsing and
+// validating the key; it is provided for protocols that require an explicit
+// validation step before trusting a peer key.
+func ValidatePublicKey(b []byte) error {
+	pk, err := ParsePublicKey(b)
+	if err != nil {
+		return err
+	}
+	return pk.(interface{ Validate() error }).Validate()
+}
+
+// ParsePrivateKey parses a private key encoded either as a 32-byte seed or as
+// an expanded private key. A seed yields a key pair (*Key44, *Key65 or
+// *Key87) and requires p to name the parameter set, since a seed does not
+// identify one. For an expanded key p may be zero, in which case the
+// parameter set is inferred from the length of b; otherwise the length must
+// match p.
+func ParsePrivateKey(b []byte, p ParameterSet) (PrivateKey, error) {
+	if p != 0 && !p.Valid() {
+		return nil, fmt.Errorf("%w %v", ErrUnknownParameterSet, p)
+	}
+	if len(b) == SeedSize {
+		if p == 0 {
+			return nil, fmt.Errorf("%w: parameter set required to parse a seed", ErrInvalidPrivateKey)
+		}
+		return p.Scheme().NewKeyFromSeed(b)
+	}
+	for _, q := range ParameterSets() {
+		if len(b) == q.PrivateKeySize() && (p == 0 || p == q) {
+			return q.Scheme().UnmarshalPrivateKey(b)
+		}
+	}
+	return nil, &LengthError{Err: ErrInvalidPrivateKey, Got: len(b)}
+}
+
+// keyDestroyed reports whether sk is a private key of this package that has
+// been destroyed.
+func keyDestroyed(sk PrivateKey) bool {
+	d, ok := sk.(interface{ isDestroyed() bool })
+	return ok && d.isDestroyed()
+}
+
+// privateKeyEncoding returns the serialized form of sk: the seed for key
+// pairs and seed keys, and the expanded encoding for standalone private
+// keys. It returns ErrKeyDestroyed for a destroyed key.
+func privateKeyEncoding(sk PrivateKey) ([]byte, error) {
+	if keyDestroyed(sk) {
+		return nil, ErrKeyDestroyed
+	}
+	switch k := sk.(type) {
+	case *Key44:
+		return k.Bytes(), nil
+	case *Key65:
+		return k.Bytes(), nil
+	case *Key87:
+		return k.Bytes(), nil
+	case *PrivateKey44:
+		return k.Bytes(), nil
+	case *PrivateKey65:
+		return k.Bytes(), nil
+	case *PrivateKey87:
+		return k.Bytes(), nil
+	case *SeedKey:
+		if seed, ok := k.Seed(); ok {
+			return seed, nil
+		}
+		return nil, ErrKeyDestroyed
+	}
+	return nil, fmt.Errorf("mldsa: unsupported private key type %T", sk)
+}
+
+// expandedPrivateKey returns the expanded private key encoding of sk, for
+// key pairs, seed keys and standalone private keys alike. Seed keys are
+// expanded for the call. It returns ErrKeyDestroyed
+// for a destroyed key.
+func expandedPrivateKey(sk PrivateKey) ([]byte, error) {
+	if keyDestroyed(sk) {
+		return nil, ErrKeyDestroyed
+	}
+	switch k := sk.(type) {
+	case *Key44:
+		return k.PrivateKeyBytes(), nil
+	case *Key65:
+		return k.PrivateKeyBytes(), nil
+	case *Key87:
+		return k.PrivateKeyBytes(), nil
+	case *PrivateKey44:
+		return k.Bytes(), nil
+	case *PrivateKey65:
+		return k.Bytes(), nil
+	case *PrivateKey87:
+		return k.Bytes(), nil
+	case *SeedKey:
+		key, wipe, err := k.expand()
+		if err != nil {
+			return nil, err
+		}
+		if wipe {
+			defer key.(wiper).wipe()
+		}
+		return expandedPrivateKey(key)
+	}
+	return nil, fmt.Errorf("mldsa: unsupported private key type %T", sk)
+}
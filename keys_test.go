@@ -0,0 +1,341 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestKeyInterfaces(t *testing.T) {
+	key44, _ := GenerateKey44(rand.Reader)
+	key65, _ := GenerateKey65(rand.Reader)
+	key87, _ := GenerateKey87(rand.Reader)
+	keys := []PrivateKey{key44, key65, key87, &key44.PrivateKey44, &key65.PrivateKey65, &key87.PrivateKey87}
+
+	message := []byte("interface message")
+	context := []byte("interface context")
+	for _, sk := range keys {
+		sig, err := sk.SignWithContext(rand.Reader, message, context)
+		if err != nil {
+			t.Fatalf("%s: SignWithContext failed: %v", sk.Scheme().Name(), err)
+		}
+		pk, ok := sk.Public().(PublicKey)
+		if !ok {
+			t.Fatalf("%s: Public() is %T, not a PublicKey", sk.Scheme().Name(), sk.Public())
+		}
+		if pk.Scheme().Name() != sk.Scheme().Name() {
+			t.Errorf("public key scheme %s, private key scheme %s", pk.Scheme().Name(), sk.Scheme().Name())
+		}
+		if len(pk.Bytes()) != pk.Scheme().PublicKeySize() {
+			t.Errorf("%s: public key size %d", pk.Scheme().Name(), len(pk.Bytes()))
+		}
+		if !pk.Verify(sig, message, context) {
+			t.Errorf("%s: Verify failed", pk.Scheme().Name())
+		}
+		if pk.Verify(sig, message, nil) {
+			t.Errorf("%s: Verify accepted the wrong context", pk.Scheme().Name())
+		}
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		want := sk.Public().(PublicKey)
+		pk, err := ParsePublicKey(want.Bytes())
+		if err != nil {
+			t.Fatalf("%s: ParsePublicKey failed: %v", s.Name(), err)
+		}
+		if pk.Scheme().Name() != s.Name() || !pk.Equal(want) {
+			t.Errorf("%s: ParsePublicKey returned the wrong key", s.Name())
+		}
+	}
+	if pk, err := ParsePublicKey(make([]byte, 100)); err == nil || pk != nil {
+		t.Errorf("ParsePublicKey accepted a bad length: %v, %v", pk, err)
+	}
+}
+
+func TestParsePublicKeyLazy(t *testing.T) {
+	message := []byte("message")
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		want := sk.Public().(PublicKey)
+		sig, err := sk.SignWithContext(rand.Reader, message, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk, err := ParsePublicKeyLazy(want.Bytes())
+		if err != nil {
+			t.Fatalf("%s: ParsePublicKeyLazy failed: %v", s.Name(), err)
+		}
+		if pk.Scheme().Name() != s.Name() || !pk.Equal(want) || pk.Fingerprint() != want.Fingerprint() {
+			t.Errorf("%s: ParsePublicKeyLazy returned the wrong key", s.Name())
+		}
+		if !pk.Verify(sig, message, nil) || pk.Verify(sig, []byte("other"), nil) {
+			t.Errorf("%s: lazily parsed key verifies incorrectly", s.Name())
+		}
+	}
+	if pk, err := ParsePublicKeyLazy(make([]byte, 100)); err == nil || pk != nil {
+		t.Errorf("ParsePublicKeyLazy accepted a bad length: %v, %v", pk, err)
+	}
+
+	key, _ := GenerateKey65(rand.Reader)
+	pk, _ := NewPublicKey65Lazy(key.PublicKey().Bytes())
+	if pk.a != nil {
+		t.Error("NewPublicKey65Lazy expanded A")
+	}
+	if pk.matrix() == nil || *pk.matrix() != *key.PublicKey().a {
+		t.Error("lazily expanded A differs")
+	}
+}
+
+func TestParsePublicKeyCompact(t *testing.T) {
+	message := []byte("message")
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		want := sk.Public().(PublicKey)
+		sig, err := sk.SignWithContext(rand.Reader, message, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk, err := ParsePublicKeyCompact(want.Bytes())
+		if err != nil {
+			t.Fatalf("%s: ParsePublicKeyCompact failed: %v", s.Name(), err)
+		}
+		if pk.Scheme().Name() != s.Name() || !pk.Equal(want) {
+			t.Errorf("%s: ParsePublicKeyCompact returned the wrong key", s.Name())
+		}
+		for i := 0; i < 2; i++ {
+			if !pk.Verify(sig, message, nil) || pk.Verify(sig, []byte("other"), nil) {
+				t.Errorf("%s: compact key verifies incorrectly", s.Name())
+			}
+		}
+	}
+
+	key, _ := GenerateKey87(rand.Reader)
+	pk, _ := NewPublicKey87Compact(key.PublicKey().Bytes())
+	sig, _ := key.SignWithContext(rand.Reader, message, nil)
+	pk.Verify(sig, message, nil)
+	var copied PublicKey87
+	copied.set(pk)
+	if pk.a != nil || !copied.compact {
+		t.Error("compact key stored A")
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	for _, p := range ParameterSets() {
+		seed := make([]byte, SeedSize)
+		seed[0] = byte(p)
+		key, err := p.Scheme().NewKeyFromSeed(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expanded := key.(interface{ PrivateKeyBytes() []byte }).PrivateKeyBytes()
+
+		fromSeed, err := ParsePrivateKey(seed, p)
+		if err != nil {
+			t.Fatalf("%v: ParsePrivateKey(seed) failed: %v", p, err)
+		}
+		if !fromSeed.Public().(PublicKey).Equal(key.Public()) {
+			t.Errorf("%v: seed parsed to a different key", p)
+		}
+		for _, hint := range []ParameterSet{0, p} {
+			sk, err := ParsePrivateKey(expanded, hint)
+			if err != nil {
+				t.Fatalf("%v: ParsePrivateKey(expanded, %d) failed: %v", p, hint, err)
+			}
+			if sk.Scheme().Name() != p.String() || !sk.Public().(PublicKey).Equal(key.Public()) {
+				t.Errorf("%v: expanded key parsed to a different key", p)
+			}
+		}
+		if _, err := ParsePrivateKey(expanded, p%3+1); err == nil {
+			t.Errorf("%v: ParsePrivateKey accepted a mismatched parameter set", p)
+		}
+	}
+	if _, err := ParsePrivateKey(make([]byte, SeedSize), 0); err == nil {
+		t.Error("ParsePrivateKey accepted a seed without a parameter set")
+	}
+	if _, err := ParsePrivateKey(make([]byte, 100), 0); err == nil {
+		t.Error("ParsePrivateKey accepted a bad length")
+	}
+}
+
+func TestSeed(t *testing.T) {
+	for _, p := range ParameterSets() {
+		seed := make([]byte, SeedSize)
+		seed[1] = byte(p)
+		key, _ := ParsePrivateKey(seed, p)
+		got, ok := key.Seed()
+		if !ok || !bytes.Equal(got, seed) {
+			t.Errorf("%v: key pair Seed() = %x, %v", p, got, ok)
+		}
+
+		expanded, _ := privateKeyEncoding(key)
+		sk, _ := p.Scheme().UnmarshalPrivateKey(key.(interface{ PrivateKeyBytes() []byte }).PrivateKeyBytes())
+		if _, ok := sk.Seed(); ok {
+			t.Errorf("%v: expanded private key reported a seed", p)
+		}
+		if !bytes.Equal(expanded, seed) {
+			t.Errorf("%v: key pair is not serialized as its seed", p)
+		}
+	}
+
+	key, _ := GenerateKey44(rand.Reader)
+	key.Destroy()
+	if _, ok := key.Seed(); ok {
+		t.Error("destroyed key pair reported a seed")
+	}
+}
+
+func TestPrivateKeyValidate(t *testing.T) {
+	for _, s := range Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		b := key.(interface{ PrivateKeyBytes() []byte }).PrivateKeyBytes()
+
+		validator := func(b []byte) error {
+			var sk interface{ Validate() error }
+			var err error
+			switch s.Name() {
+			case "ML-DSA-44":
+				sk, err = NewPrivateKey44Strict(b)
+			case "ML-DSA-65":
+				sk, err = NewPrivateKey65Strict(b)
+			case "ML-DSA-87":
+				sk, err = NewPrivateKey87Strict(b)
+			}
+			if err != nil {
+				return err
+			}
+			return sk.Validate()
+		}
+		if err := validator(b); err != nil {
+			t.Errorf("%s: valid key rejected: %v", s.Name(), err)
+		}
+		if err := key.(interface{ Validate() error }).Validate(); err != nil {
+			t.Errorf("%s: Validate on a generated key pair: %v", s.Name(), err)
+		}
+
+		// Flipping a bit of tr (offset 64) or of t0 (at the end) must be caught.
+		for _, off := range []int{64, len(b) - 1} {
+			bad := bytes.Clone(b)
+			bad[off] ^= 1
+			if err := validator(bad); !errors.Is(err, ErrInvalidPrivateKey) {
+				t.Errorf("%s: corruption at byte %d not detected: %v", s.Name(), off, err)
+			}
+		}
+	}
+}
+
+func TestPrivateKeyValidation(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	bad := key.PrivateKey65.Bytes()
+	bad[64] ^= 1 // tr
+
+	if _, err := NewPrivateKey65(bad); err != nil {
+		t.Fatalf("tampered tr rejected without validation: %v", err)
+	}
+
+	SetPrivateKeyValidation(true)
+	defer SetPrivateKeyValidation(false)
+	if _, err := NewPrivateKey65(bad); !errors.Is(err, ErrInvalidPrivateKey) {
+		t.Errorf("NewPrivateKey65 with a tampered tr: %v", err)
+	}
+	if _, err := MLDSA65.Scheme().UnmarshalPrivateKey(bad); !errors.Is(err, ErrInvalidPrivateKey) {
+		t.Errorf("Scheme.UnmarshalPrivateKey with a tampered tr: %v", err)
+	}
+	if _, err := NewPrivateKey65(key.PrivateKey65.Bytes()); err != nil {
+		t.Errorf("valid key rejected: %v", err)
+	}
+	SetPrivateKeyValidation(false)
+
+	if err := SetStrictFIPS(true); err != nil {
+		t.Fatal(err)
+	}
+	defer SetStrictFIPS(false)
+	if _, err := NewPrivateKey65(bad); !errors.Is(err, ErrInvalidPrivateKey) {
+		t.Errorf("NewPrivateKey65 in strict FIPS mode with a tampered tr: %v", err)
+	}
+}
+
+func TestPublicKeyValidate(t *testing.T) {
+	for _, s := range Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		b := key.Public().(PublicKey).Bytes()
+		if err := ValidatePublicKey(b); err != nil {
+			t.Errorf("%s: valid key rejected: %v", s.Name(), err)
+		}
+	}
+	if err := ValidatePublicKey(make([]byte, 100)); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("ValidatePublicKey with a bad length: %v", err)
+	}
+
+	if err := new(PublicKey65).Validate(); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("Validate on a zero key: %v", err)
+	}
+	key, _ := GenerateKey44(rand.Reader)
+	pk, _ := NewPublicKey44(key.PublicKey().Bytes())
+	pk.t1[0][0] = 1 << 10
+	if err := pk.Validate(); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("Validate with an out-of-range coefficient: %v", err)
+	}
+}
+
+func TestEncodersRejectDestroyedKeys(t *testing.T) {
+	encoders := []struct {
+		name string
+		enc  func(PrivateKey) ([]byte, error)
+	}{
+		{"gob", func(k PrivateKey) ([]byte, error) {
+			return nil, gob.NewEncoder(io.Discard).Encode(k)
+		}},
+		{"CBOR", func(k PrivateKey) ([]byte, error) {
+			return k.(interface{ MarshalCBOR() ([]byte, error) }).MarshalCBOR()
+		}},
+		{"AnyPrivateKey CBOR", func(k PrivateKey) ([]byte, error) { return AnyPrivateKey{k}.MarshalCBOR() }},
+		{"JSON", func(k PrivateKey) ([]byte, error) { return json.Marshal(k) }},
+		{"AnyPrivateKey JSON", func(k PrivateKey) ([]byte, error) { return json.Marshal(AnyPrivateKey{k}) }},
+		{"text", func(k PrivateKey) ([]byte, error) {
+			return k.(interface{ MarshalText() ([]byte, error) }).MarshalText()
+		}},
+		{"PEM", func(k PrivateKey) ([]byte, error) { return (&PEMBundle{PrivateKeys: []PrivateKey{k}}).Marshal() }},
+		{"PKCS #8 seed", MarshalPKCS8PrivateKey},
+		{"PKCS #8 expanded key", func(k PrivateKey) ([]byte, error) {
+			return MarshalPKCS8PrivateKeyWithOptions(k, &PKCS8Options{Form: PKCS8ExpandedKey})
+		}},
+		{"PKCS #8 both", func(k PrivateKey) ([]byte, error) {
+			return MarshalPKCS8PrivateKeyWithOptions(k, &PKCS8Options{Form: PKCS8Both})
+		}},
+		{"encrypted PKCS #8", func(k PrivateKey) ([]byte, error) {
+			return MarshalEncryptedPKCS8PrivateKey(k, []byte("password"), &EncryptedPKCS8Options{PKCS8Options: PKCS8Options{Form: PKCS8ExpandedKey}})
+		}},
+	}
+
+	for _, p := range ParameterSets() {
+		key, err := p.Scheme().GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := expandedPrivateKey(key)
+		sk, err := p.Scheme().UnmarshalPrivateKey(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range []PrivateKey{key, sk} {
+			k.(interface{ Destroy() }).Destroy()
+			for _, e := range encoders {
+				if _, err := e.enc(k); !errors.Is(err, ErrKeyDestroyed) {
+					t.Errorf("%v: %s of a destroyed %T: got %v, want ErrKeyDestroyed", p, e.name, k, err)
+				}
+			}
+			if k.(interface{ Equal(crypto.PrivateKey) bool }).Equal(k) {
+				t.Errorf("%v: a destroyed %T is equal to itself", p, k)
+			}
+		}
+	}
+}
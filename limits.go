@@ -0,0 +1,30 @@
+package mldsa
+
+import "sync/atomic"
+
+// DefaultMaxSignAttempts is the default cap on rejection-sampling
+// iterations per signature. Each iteration succeeds with probability of
+// roughly 1/4 to 1/5 depending on the parameter set, so the cap is never
+// reached with a valid key.
+const DefaultMaxSignAttempts = 1000
+
+var maxSignAttempts atomic.Int64
+
+// SetMaxSignAttempts sets the number of rejection-sampling iterations after
+// which signing gives up with ErrSigningFailed. A value of zero or less
+// restores DefaultMaxSignAttempts. Independently of the cap, signing stops
+// before the 16-bit counter kappa of FIPS 204 would wrap around.
+func SetMaxSignAttempts(n int) {
+	if n <= 0 {
+		n = 0
+	}
+	maxSignAttempts.Store(int64(n))
+}
+
+// MaxSignAttempts returns the current cap on rejection-sampling iterations.
+func MaxSignAttempts() int {
+	if n := maxSignAttempts.Load(); n > 0 {
+		return int(n)
+	}
+	return DefaultMaxSignAttempts
+}
@@ -0,0 +1,55 @@
+package mldsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestMaxSignAttempts(t *testing.T) {
+	if MaxSignAttempts() != DefaultMaxSignAttempts {
+		t.Fatalf("MaxSignAttempts() = %d, want %d", MaxSignAttempts(), DefaultMaxSignAttempts)
+	}
+	defer SetMaxSignAttempts(0)
+
+	// With a single attempt, most signatures are rejected by the loop.
+	SetMaxSignAttempts(1)
+	key, _ := GenerateKey65(rand.Reader)
+	var failed int
+	for i := 0; i < 32; i++ {
+		sig, err := key.SignWithContext(rand.Reader, []byte("message"), nil)
+		switch {
+		case errors.Is(err, ErrSigningFailed):
+			failed++
+		case err != nil:
+			t.Fatalf("unexpected error: %v", err)
+		case !key.PublicKey().Verify(sig, []byte("message"), nil):
+			t.Fatal("signature produced under the cap does not verify")
+		}
+	}
+	if failed == 0 {
+		t.Error("signing never hit the attempt cap")
+	}
+
+	SetMaxSignAttempts(-1)
+	if MaxSignAttempts() != DefaultMaxSignAttempts {
+		t.Errorf("SetMaxSignAttempts(-1) did not restore the default")
+	}
+}
+
+func TestSignKappaBound(t *testing.T) {
+	// A key whose signatures are always rejected must stop once kappa
+	// would wrap, even with an unreachable attempt cap.
+	defer SetMaxSignAttempts(0)
+	SetMaxSignAttempts(1 << 30)
+
+	key, _ := GenerateKey44(rand.Reader)
+	for i := range key.s1 {
+		for j := range key.s1[i] {
+			key.s1[i][j] = Q / 2
+		}
+	}
+	if _, err := key.signInternal(nil, make([]byte, 32), []byte("message")); !errors.Is(err, ErrSigningFailed) {
+		t.Errorf("signInternal with a corrupted key: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package mldsa
+
+import (
+	"crypto/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+var lockedMemory atomic.Bool
+
+// SetLockedMemory controls whether private keys created afterwards keep their
+// secret components (the seed, the signing seed K, s1, s2 and t0) in locked
+// memory, so they never reach swap. Each key then gets its own mapping,
+// locked with mlock and surrounded by inaccessible guard pages, with a random
+// canary filling the gap before the key material. The mapping is wiped and
+// released once the key is garbage collected, and a corrupted canary then
+// panics. Locked memory is disabled by default.
+//
+// Keys in locked memory ignore Precompute, whose NTT forms would live in
+// ordinary memory. The vectors used while signing are not locked, but are
+// wiped after each signature, and encodings returned by methods such as
+// Bytes are ordinary slices that the caller must wipe.
+//
+// Enabling locked memory returns ErrLockedMemoryUnsupported on platforms
+// other than Linux and macOS. Once it is enabled, creating a key fails with
+// ErrLockedMemory if the memory cannot be locked, typically because of
+// RLIMIT_MEMLOCK.
+func SetLockedMemory(enabled bool) error {
+	if enabled && !lockedMemorySupported {
+		return ErrLockedMemoryUnsupported
+	}
+	lockedMemory.Store(enabled)
+	return nil
+}
+
+// newRegion is newLockedRegion, replaced by tests to simulate allocation
+// failures.
+var newRegion = newLockedRegion
+
+// canarySize is the size of the random canary value.
+const canarySize = 32
+
+var (
+	canaryOnce sync.Once
+	canary     [canarySize]byte
+)
+
+// lockedRegion is a locked mapping holding the secret components of a key.
+type lockedRegion struct {
+	mem   []byte // the whole mapping, including the guard pages
+	inner []byte // the locked pages between the guard pages
+	data  []byte // the end of inner, holding the key material
+}
+
+// allocLocked returns size zeroed bytes of locked memory, aligned to 8 bytes
+// and released once owner is unreachable.
+func allocLocked[O any](owner *O, size uintptr) (unsafe.Pointer, error) {
+	r, err := newRegion((int(size) + 7) &^ 7)
+	if err != nil {
+		return nil, err
+	}
+	runtime.AddCleanup(owner, (*lockedRegion).free, r)
+	return unsafe.Pointer(&r.data[0]), nil
+}
+
+// fillCanary writes the canary over the gap between the guard page and the
+// key material.
+func (r *lockedRegion) fillCanary() {
+	canaryOnce.Do(func() { rand.Read(canary[:]) })
+	gap := r.inner[:len(r.inner)-len(r.data)]
+	for i := range gap {
+		gap[i] = canary[i%canarySize]
+	}
+}
+
+// canaryIntact reports whether the canary written by fillCanary is unchanged.
+func (r *lockedRegion) canaryIntact() bool {
+	gap := r.inner[:len(r.inner)-len(r.data)]
+	var diff byte
+	for i := range gap {
+		diff |= gap[i] ^ canary[i%canarySize]
+	}
+	return diff == 0
+}
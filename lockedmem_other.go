@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package mldsa
+
+const lockedMemorySupported = false
+
+func newLockedRegion(n int) (*lockedRegion, error) {
+	return nil, ErrLockedMemoryUnsupported
+}
+
+func (r *lockedRegion) free() {}
@@ -0,0 +1,143 @@
+package mldsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"os"
+	"runtime/debug"
+	"testing"
+)
+
+func enableLockedMemory(t *testing.T) {
+	t.Helper()
+	if err := SetLockedMemory(true); err != nil {
+		if errors.Is(err, ErrLockedMemoryUnsupported) {
+			t.Skip(err)
+		}
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetLockedMemory(false) })
+	if _, err := newLockedRegion(8); err != nil {
+		t.Skip(err)
+	}
+}
+
+// failLockedMemory enables locked memory with an allocator that always
+// fails, as when RLIMIT_MEMLOCK is exhausted.
+func failLockedMemory(t *testing.T) {
+	t.Helper()
+	lockedMemory.Store(true)
+	newRegion = func(int) (*lockedRegion, error) { return nil, ErrLockedMemory }
+	t.Cleanup(func() {
+		lockedMemory.Store(false)
+		newRegion = newLockedRegion
+	})
+}
+
+func TestLockedMemoryFailure(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	seed, _ := key.Seed()
+	failLockedMemory(t)
+
+	if _, err := NewKey65(seed); !errors.Is(err, ErrLockedMemory) {
+		t.Errorf("NewKey65: %v", err)
+	}
+	if _, err := GenerateKey65(rand.Reader); !errors.Is(err, ErrLockedMemory) {
+		t.Errorf("GenerateKey65: %v", err)
+	}
+	if _, err := NewPrivateKey65FromSeed(seed); !errors.Is(err, ErrLockedMemory) {
+		t.Errorf("NewPrivateKey65FromSeed: %v", err)
+	}
+	if _, err := NewPrivateKey65(key.PrivateKeyBytes()); !errors.Is(err, ErrLockedMemory) {
+		t.Errorf("NewPrivateKey65: %v", err)
+	}
+	for _, p := range ParameterSets() {
+		if _, err := p.Scheme().NewKeyFromSeed(seed); !errors.Is(err, ErrLockedMemory) {
+			t.Errorf("%v: Scheme.NewKeyFromSeed: %v", p, err)
+		}
+	}
+}
+
+func TestLockedMemory(t *testing.T) {
+	enableLockedMemory(t)
+	message := []byte("message")
+
+	key, err := GenerateKey65(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.locked {
+		t.Fatal("key is not in locked memory")
+	}
+	key.Precompute()
+	if key.ntt.Load() != nil {
+		t.Error("Precompute cached transforms of a locked key")
+	}
+	sig, err := key.SignWithContext(rand.Reader, message, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.PublicKey().Verify(sig, message, nil) {
+		t.Error("signature of a locked key does not verify")
+	}
+
+	sk, err := NewPrivateKey65(key.PrivateKey65.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sk.locked || !sk.Equal(key) {
+		t.Error("parsed private key is not an equal locked key")
+	}
+	seed, _ := key.Seed()
+	if err := key.Regenerate(seed); err != nil {
+		t.Fatal(err)
+	}
+	if !key.locked || !sk.Equal(key) {
+		t.Error("regenerated key is not an equal locked key")
+	}
+
+	key.Destroy()
+	if key.key != [32]byte{} || key.seed != [32]byte{} || key.s1 != [L65]RingElement{} {
+		t.Error("Destroy did not wipe the locked key")
+	}
+}
+
+// guardSink keeps reads of guard pages from being optimized away.
+var guardSink byte
+
+func TestLockedRegion(t *testing.T) {
+	enableLockedMemory(t)
+	page := os.Getpagesize()
+
+	r, err := newLockedRegion(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.data) != 100 || len(r.mem) != len(r.inner)+2*page || &r.inner[len(r.inner)-1] != &r.data[99] {
+		t.Fatal("unexpected region layout")
+	}
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	for _, off := range []int{page - 1, len(r.mem) - page} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("reading guard page byte %d did not fault", off)
+				}
+			}()
+			guardSink = r.mem[off]
+		}()
+	}
+	r.free()
+
+	r, err = newLockedRegion(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.inner[len(r.inner)-len(r.data)-1] ^= 1
+	defer func() {
+		if recover() == nil {
+			t.Error("free did not detect the overwritten canary")
+		}
+	}()
+	r.free()
+}
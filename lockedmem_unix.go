@@ -0,0 +1,46 @@
+//go:build linux || darwin
+
+package mldsa
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+const lockedMemorySupported = true
+
+// newLockedRegion maps n bytes of locked memory, placed at the end of the
+// locked pages so that an overrun faults on the guard page that follows.
+func newLockedRegion(n int) (*lockedRegion, error) {
+	page := os.Getpagesize()
+	size := (n + canarySize + page - 1) &^ (page - 1)
+	mem, err := syscall.Mmap(-1, 0, size+2*page, syscall.PROT_NONE, syscall.MAP_PRIVATE|syscall.MAP_ANON)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLockedMemory, err)
+	}
+	r := &lockedRegion{mem: mem, inner: mem[page : page+size]}
+	if err := syscall.Mprotect(r.inner, syscall.PROT_READ|syscall.PROT_WRITE); err != nil {
+		syscall.Munmap(mem)
+		return nil, fmt.Errorf("%w: %w", ErrLockedMemory, err)
+	}
+	if err := syscall.Mlock(r.inner); err != nil {
+		syscall.Munmap(mem)
+		return nil, fmt.Errorf("%w: %w", ErrLockedMemory, err)
+	}
+	r.data = r.inner[size-n:]
+	r.fillCanary()
+	return r, nil
+}
+
+// free wipes and releases r. It panics if the canary was overwritten, as the
+// key material next to it may be corrupted too.
+func (r *lockedRegion) free() {
+	intact := r.canaryIntact()
+	clear(r.inner)
+	syscall.Munlock(r.inner)
+	syscall.Munmap(r.mem)
+	if !intact {
+		panic("mldsa: canary of locked key memory was overwritten")
+	}
+}
@@ -0,0 +1,286 @@
+// Package minisig implements a minisign-style detached signature file
+// format for ML-DSA, for release signing where X.509 and PGP are overkill.
+//
+// A public key file holds an untrusted comment line and the base64 of the
+// algorithm, an 8-byte key ID and the public key:
+//
+//	untrusted comment: minisig public key 3F2A91C07D5B8E14
+//	TTZGKpHAfVuOFO...
+//
+// A signature file holds an untrusted comment, the base64 of the algorithm,
+// key ID and signature of the SHA-512 digest of the file, a trusted comment,
+// and a global signature binding the trusted comment to the signature:
+//
+//	untrusted comment: signature from minisig secret key
+//	TTZGKpHAfVuOFO...
+//	trusted comment: timestamp:1700000000	file:release.tar.gz
+//	uNDzqx...
+//
+// The algorithm is "M4", "M6" or "M8" for ML-DSA-44, ML-DSA-65 and
+// ML-DSA-87, and the key ID is the first 8 bytes of the key's SHA-256
+// fingerprint. Files are read and written as minisign does; the formats are
+// not interchangeable with minisign's Ed25519 keys.
+//
+// Basic usage:
+//
+//	sig, err := minisig.Sign(key, file, &minisig.SignOptions{TrustedComment: "file:release.tar.gz"})
+//	os.WriteFile("release.tar.gz.minisig", sig.Marshal(), 0o644)
+//	...
+//	pub, err := minisig.ParsePublicKey(pubFile)
+//	sig, err := minisig.ParseSignature(sigFile)
+//	err = pub.Verify(sig, file)
+package minisig
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+const (
+	untrustedPrefix = "untrusted comment: "
+	trustedPrefix   = "trusted comment: "
+)
+
+// ML-DSA context strings separating file signatures from global signatures.
+var (
+	fileContext    = []byte("mldsa-minisig-v1")
+	globalContext  = []byte("mldsa-minisig-v1-trusted-comment")
+	algorithmNames = map[string]string{"M4": "ML-DSA-44", "M6": "ML-DSA-65", "M8": "ML-DSA-87"}
+)
+
+var (
+	// ErrInvalidSignature is returned when a signature or global signature
+	// does not verify.
+	ErrInvalidSignature = errors.New("minisig: invalid signature")
+	// ErrKeyIDMismatch is returned when a signature was made by another key.
+	ErrKeyIDMismatch = errors.New("minisig: signature made by a different key")
+)
+
+// KeyID identifies a public key in signature files.
+type KeyID [8]byte
+
+// String returns the key ID in upper-case hex, as in minisign comments.
+func (id KeyID) String() string {
+	return strings.ToUpper(hex.EncodeToString(id[:]))
+}
+
+// algorithm returns the 2-byte algorithm tag of a scheme.
+func algorithm(s mldsa.Scheme) string {
+	return "M" + strings.TrimPrefix(s.Name(), "ML-DSA-")[:1]
+}
+
+// PublicKey is a public key with its key ID.
+type PublicKey struct {
+	KeyID KeyID
+	Key   mldsa.PublicKey
+}
+
+// NewPublicKey returns pk with its key ID.
+func NewPublicKey(pk mldsa.PublicKey) *PublicKey {
+	p := &PublicKey{Key: pk}
+	f := pk.Fingerprint()
+	copy(p.KeyID[:], f[:])
+	return p
+}
+
+// Marshal returns the public key file.
+func (p *PublicKey) Marshal() []byte {
+	blob := append([]byte(algorithm(p.Key.Scheme())), p.KeyID[:]...)
+	blob = append(blob, p.Key.Bytes()...)
+	return fmt.Appendf(nil, "%sminisig public key %s\n%s\n", untrustedPrefix, p.KeyID, base64.StdEncoding.EncodeToString(blob))
+}
+
+// ParsePublicKey parses a public key file, or the bare base64 line.
+func ParsePublicKey(data []byte) (*PublicKey, error) {
+	lines := splitLines(data)
+	if len(lines) == 2 && strings.HasPrefix(lines[0], untrustedPrefix) {
+		lines = lines[1:]
+	}
+	if len(lines) != 1 {
+		return nil, errors.New("minisig: malformed public key file")
+	}
+	s, id, key, err := decodeBlob(lines[0])
+	if err != nil {
+		return nil, err
+	}
+	pk, err := s.UnmarshalPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicKey{KeyID: id, Key: pk}, nil
+}
+
+// decodeBlob decodes the base64 of an algorithm, key ID and payload.
+func decodeBlob(line string) (mldsa.Scheme, KeyID, []byte, error) {
+	var id KeyID
+	b, err := base64.StdEncoding.DecodeString(line)
+	if err != nil {
+		return nil, id, nil, fmt.Errorf("minisig: %w", err)
+	}
+	if len(b) < 2+len(id) {
+		return nil, id, nil, errors.New("minisig: truncated data")
+	}
+	name, ok := algorithmNames[string(b[:2])]
+	if !ok {
+		return nil, id, nil, fmt.Errorf("minisig: unsupported algorithm %q", b[:2])
+	}
+	copy(id[:], b[2:])
+	return mldsa.SchemeByName(name), id, b[2+len(id):], nil
+}
+
+// splitLines returns the non-empty lines of data.
+func splitLines(data []byte) []string {
+	var lines []string
+	for _, l := range strings.Split(string(data), "\n") {
+		if l = strings.TrimRight(l, "\r"); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// Signature is a parsed signature file.
+type Signature struct {
+	UntrustedComment string
+	TrustedComment   string
+
+	Algorithm string // "M4", "M6" or "M8"
+	KeyID     KeyID
+
+	// Signature signs the SHA-512 digest of the file; GlobalSignature signs
+	// Signature followed by TrustedComment.
+	Signature       []byte
+	GlobalSignature []byte
+}
+
+// SignOptions configures Sign.
+type SignOptions struct {
+	// TrustedComment is covered by the signature. If empty, it is
+	// "timestamp:<Unix time>".
+	TrustedComment string
+
+	// UntrustedComment is informational only. If empty, it is
+	// "signature from minisig secret key".
+	UntrustedComment string
+
+	// Rand is the randomness source for hedged signing. If nil,
+	// crypto/rand.Reader is used.
+	Rand io.Reader
+}
+
+// Sign signs the file read from message with key.
+func Sign(key mldsa.PrivateKey, message io.Reader, opts *SignOptions) (*Signature, error) {
+	if opts == nil {
+		opts = &SignOptions{}
+	}
+	pk, ok := key.Public().(mldsa.PublicKey)
+	if !ok {
+		return nil, errors.New("minisig: private key has no ML-DSA public key")
+	}
+	s := &Signature{
+		UntrustedComment: opts.UntrustedComment,
+		TrustedComment:   opts.TrustedComment,
+		Algorithm:        algorithm(key.Scheme()),
+		KeyID:            NewPublicKey(pk).KeyID,
+	}
+	if s.UntrustedComment == "" {
+		s.UntrustedComment = "signature from minisig secret key"
+	}
+	if s.TrustedComment == "" {
+		s.TrustedComment = "timestamp:" + strconv.FormatInt(time.Now().Unix(), 10)
+	}
+	if strings.ContainsAny(s.TrustedComment+s.UntrustedComment, "\r\n") {
+		return nil, errors.New("minisig: comments must fit on one line")
+	}
+	digest, err := hashMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if s.Signature, err = key.SignWithContext(opts.Rand, digest, fileContext); err != nil {
+		return nil, err
+	}
+	s.GlobalSignature, err = key.SignWithContext(opts.Rand, s.globalMessage(), globalContext)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func hashMessage(message io.Reader) ([]byte, error) {
+	h := sha512.New()
+	if _, err := io.Copy(h, message); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func (s *Signature) globalMessage() []byte {
+	return append(bytes.Clone(s.Signature), s.TrustedComment...)
+}
+
+// Marshal returns the signature file.
+func (s *Signature) Marshal() []byte {
+	blob := append([]byte(s.Algorithm), s.KeyID[:]...)
+	blob = append(blob, s.Signature...)
+	return fmt.Appendf(nil, "%s%s\n%s\n%s%s\n%s\n",
+		untrustedPrefix, s.UntrustedComment,
+		base64.StdEncoding.EncodeToString(blob),
+		trustedPrefix, s.TrustedComment,
+		base64.StdEncoding.EncodeToString(s.GlobalSignature))
+}
+
+// ParseSignature parses a signature file. The signature is not verified.
+func ParseSignature(data []byte) (*Signature, error) {
+	lines := splitLines(data)
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], untrustedPrefix) || !strings.HasPrefix(lines[2], trustedPrefix) {
+		return nil, errors.New("minisig: malformed signature file")
+	}
+	scheme, id, sig, err := decodeBlob(lines[1])
+	if err != nil {
+		return nil, err
+	}
+	global, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil {
+		return nil, fmt.Errorf("minisig: %w", err)
+	}
+	return &Signature{
+		UntrustedComment: strings.TrimPrefix(lines[0], untrustedPrefix),
+		TrustedComment:   strings.TrimPrefix(lines[2], trustedPrefix),
+		Algorithm:        algorithm(scheme),
+		KeyID:            id,
+		Signature:        sig,
+		GlobalSignature:  global,
+	}, nil
+}
+
+// Verify verifies the signature of the file read from message and the
+// trusted comment. Once it returns nil, s.TrustedComment can be trusted.
+func (p *PublicKey) Verify(s *Signature, message io.Reader) error {
+	if s.KeyID != p.KeyID {
+		return fmt.Errorf("%w: key ID %s, want %s", ErrKeyIDMismatch, s.KeyID, p.KeyID)
+	}
+	if s.Algorithm != algorithm(p.Key.Scheme()) {
+		return fmt.Errorf("minisig: %s signature cannot be verified with an %s key", s.Algorithm, p.Key.Scheme().Name())
+	}
+	digest, err := hashMessage(message)
+	if err != nil {
+		return err
+	}
+	if !p.Key.Verify(s.Signature, digest, fileContext) {
+		return ErrInvalidSignature
+	}
+	if !p.Key.Verify(s.GlobalSignature, s.globalMessage(), globalContext) {
+		return fmt.Errorf("%w: trusted comment", ErrInvalidSignature)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package minisig
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestSignVerify(t *testing.T) {
+	message := []byte("release tarball contents")
+	for _, s := range mldsa.Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		pub := NewPublicKey(key.Public().(mldsa.PublicKey))
+
+		parsedPub, err := ParsePublicKey(pub.Marshal())
+		if err != nil {
+			t.Fatalf("%s: ParsePublicKey: %v", s.Name(), err)
+		}
+		if parsedPub.KeyID != pub.KeyID || !parsedPub.Key.Equal(pub.Key) {
+			t.Errorf("%s: public key did not round-trip", s.Name())
+		}
+		if !strings.Contains(string(pub.Marshal()), pub.KeyID.String()) {
+			t.Errorf("%s: key ID missing from the untrusted comment", s.Name())
+		}
+
+		sig, err := Sign(key, bytes.NewReader(message), &SignOptions{TrustedComment: "file:release.tar.gz"})
+		if err != nil {
+			t.Fatalf("%s: Sign: %v", s.Name(), err)
+		}
+		parsed, err := ParseSignature(sig.Marshal())
+		if err != nil {
+			t.Fatalf("%s: ParseSignature: %v", s.Name(), err)
+		}
+		if parsed.TrustedComment != "file:release.tar.gz" || parsed.UntrustedComment != sig.UntrustedComment ||
+			parsed.Algorithm != sig.Algorithm || parsed.KeyID != pub.KeyID {
+			t.Errorf("%s: unexpected signature fields", s.Name())
+		}
+		if err := parsedPub.Verify(parsed, bytes.NewReader(message)); err != nil {
+			t.Errorf("%s: Verify: %v", s.Name(), err)
+		}
+		if err := parsedPub.Verify(parsed, strings.NewReader("tampered")); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: Verify of another message: %v", s.Name(), err)
+		}
+
+		parsed.TrustedComment = "file:other.tar.gz"
+		if err := parsedPub.Verify(parsed, bytes.NewReader(message)); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: Verify of a modified trusted comment: %v", s.Name(), err)
+		}
+		parsed.TrustedComment = sig.TrustedComment
+		parsed.UntrustedComment = "anything"
+		if err := parsedPub.Verify(parsed, bytes.NewReader(message)); err != nil {
+			t.Errorf("%s: Verify with a modified untrusted comment: %v", s.Name(), err)
+		}
+	}
+}
+
+func TestVerifyWrongKey(t *testing.T) {
+	key, _ := mldsa.GenerateKey44(rand.Reader)
+	other, _ := mldsa.GenerateKey44(rand.Reader)
+	sig, err := Sign(key, strings.NewReader("m"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sig.TrustedComment, "timestamp:") {
+		t.Errorf("default trusted comment = %q", sig.TrustedComment)
+	}
+	pub := NewPublicKey(other.Public().(mldsa.PublicKey))
+	if err := pub.Verify(sig, strings.NewReader("m")); !errors.Is(err, ErrKeyIDMismatch) {
+		t.Errorf("Verify with another key: %v", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	key, _ := mldsa.GenerateKey44(rand.Reader)
+	sig, _ := Sign(key, strings.NewReader("m"), nil)
+	lines := strings.Split(string(sig.Marshal()), "\n")
+
+	for name, data := range map[string]string{
+		"empty":         "",
+		"missing lines": strings.Join(lines[:2], "\n"),
+		"no trusted":    strings.Join([]string{lines[0], lines[1], "comment: x", lines[3]}, "\n"),
+		"bad base64":    strings.Join([]string{lines[0], "!!", lines[2], lines[3]}, "\n"),
+		"bad algorithm": strings.Join([]string{lines[0], "RWQAAAAAAAAAAAAA", lines[2], lines[3]}, "\n"),
+	} {
+		if _, err := ParseSignature([]byte(data)); err == nil {
+			t.Errorf("ParseSignature accepted %s", name)
+		}
+	}
+	if _, err := ParsePublicKey([]byte("untrusted comment: x\nTTQAAAAAAAAAAA==\n")); err == nil {
+		t.Error("ParsePublicKey accepted a truncated key")
+	}
+	if _, err := Sign(key, strings.NewReader("m"), &SignOptions{TrustedComment: "a\nb"}); err == nil {
+		t.Error("Sign accepted a multi-line comment")
+	}
+}
@@ -20,7 +20,11 @@
 //	valid := key.PublicKey().Verify(sig, message, nil)
 package mldsa
 
-import "crypto"
+import (
+	"crypto"
+	cryptorand "crypto/rand"
+	"io"
+)
 
 // Global ML-DSA constants from FIPS 204.
 const (
@@ -127,12 +131,53 @@ type SignerOpts struct {
 	// Context is an optional context string for domain separation (max 255 bytes).
 	// If nil, no context is used.
 	Context []byte
+
+	// Hash, if not zero, selects HashML-DSA with this pre-hash function
+	// instead of pure ML-DSA. See PreHashFunctions for the supported values.
+	Hash crypto.Hash
+
+	// Rand, if not nil, is the source of the 32-byte hedging value (rnd in
+	// FIPS 204) and takes precedence over the rand argument of Sign. This
+	// lets deployments route rnd from an approved DRBG without changing
+	// generic crypto.Signer call sites.
+	Rand io.Reader
+
+	// Indicator, if not nil, receives the FIPS 140-3 service indicator of
+	// the operation: IndicatorApproved or IndicatorNotApproved.
+	Indicator *ServiceIndicator
 }
 
-// HashFunc returns 0 to indicate that ML-DSA does not use pre-hashing.
-// ML-DSA signs messages directly rather than message digests.
+// HashFunc returns the pre-hash function selected by opts.Hash, or 0 for
+// pure ML-DSA, which signs messages directly rather than message digests.
 func (opts *SignerOpts) HashFunc() crypto.Hash {
-	return 0
+	if opts == nil {
+		return 0
+	}
+	return opts.Hash
+}
+
+// randReader returns r, or crypto/rand.Reader if r is nil.
+func randReader(r io.Reader) io.Reader {
+	if r == nil {
+		return cryptorand.Reader
+	}
+	return r
+}
+
+// signerOptions extracts the context string, the pre-hash function and the
+// randomness override from opts. A non-zero hash function selects
+// HashML-DSA; any crypto.SignerOpts may request it, not only *SignerOpts.
+func signerOptions(opts crypto.SignerOpts) (context []byte, ph crypto.Hash, rand io.Reader) {
+	if opts == nil {
+		return nil, 0, nil
+	}
+	if o, ok := opts.(*SignerOpts); ok {
+		if o == nil {
+			return nil, 0, nil
+		}
+		return o.Context, o.Hash, o.Rand
+	}
+	return nil, opts.HashFunc(), nil
 }
 
 // Compile-time interface assertions for crypto.Signer.
@@ -140,4 +185,7 @@ var (
 	_ crypto.Signer = (*PrivateKey44)(nil)
 	_ crypto.Signer = (*PrivateKey65)(nil)
 	_ crypto.Signer = (*PrivateKey87)(nil)
+	_ crypto.Signer = (*Key44)(nil)
+	_ crypto.Signer = (*Key65)(nil)
+	_ crypto.Signer = (*Key87)(nil)
 )
@@ -1,59 +1,296 @@
 package mldsa
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/sha3"
-	"errors"
+	"crypto/subtle"
+	"fmt"
 	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
 )
 
 // PrivateKey65 is the private key for ML-DSA-65.
 type PrivateKey65 struct {
 	rho [32]byte              // Public seed
-	key [32]byte              // Private seed for signing
 	tr  [64]byte              // H(pk)
-	s1  [L65]RingElement      // Secret vector
-	s2  [K65]RingElement      // Secret vector
-	t0  [K65]RingElement      // Low bits of t
 	a   [K65 * L65]NttElement // Matrix A in NTT form
+
+	*keySecret65 // seed, key, s1, s2 and t0
+
+	pubOnce sync.Once    // guards pub
+	pub     *PublicKey65 // cached public key, see PublicKey
+
+	ntt atomic.Pointer[secretNTT65] // cached by Precompute
+
+	rndCount atomic.Uint64 // signatures made, see SetEntropyMixing
+
+	destroyed bool // set by Destroy
+}
+
+// keySecret65 holds the secret components of an ML-DSA-65 key, apart from
+// the rest of the key so that they can be placed in locked memory, see
+// SetLockedMemory.
+type keySecret65 struct {
+	seed [32]byte         // Original seed, for key pairs
+	key  [32]byte         // Private seed for signing
+	s1   [L65]RingElement // Secret vector
+	s2   [K65]RingElement // Secret vector
+	t0   [K65]RingElement // Low bits of t
+
+	locked bool // stored in locked memory
+}
+
+// wipe clears the secret components, keeping their storage.
+func (sec *keySecret65) wipe() {
+	clear(sec.seed[:])
+	clear(sec.key[:])
+	clear(sec.s1[:])
+	clear(sec.s2[:])
+	clear(sec.t0[:])
 }
 
 // PublicKey65 is the public key for ML-DSA-65.
 type PublicKey65 struct {
-	rho [32]byte              // Public seed
-	t1  [K65]RingElement      // High bits of t
-	tr  [64]byte              // H(pk)
-	a   [K65 * L65]NttElement // Matrix A in NTT form
+	rho [32]byte         // Public seed
+	t1  [K65]RingElement // High bits of t
+	tr  [64]byte         // H(pk)
+
+	a       *[K65 * L65]NttElement // Matrix A in NTT form, see matrix
+	aOnce   sync.Once              // guards the lazy expansion of a
+	compact bool                   // A is never stored, see NewPublicKey65Compact
+
+	t1NTT atomic.Pointer[[K65]NttElement] // cached by Precompute
+}
+
+// secretNTT65 holds the secret vectors of an ML-DSA-65 private key in
+// NTT form.
+type secretNTT65 struct {
+	s1     [L65]NttElement
+	s2, t0 [K65]NttElement
+}
+
+// signScratch65 holds the vectors of an ML-DSA-65 signature computation.
+type signScratch65 struct {
+	secret            secretNTT65
+	yNTT              [L65]NttElement
+	y, z              [L65]RingElement
+	w, w1, ct0, hints [K65]RingElement
+	cs2               [K65]RingElement
+	r0                [K65][N]int32
+	c                 RingElement
+	cNTT, prod        NttElement
 }
 
+// audit reports the secret-dependent vectors of sc to the audit hooks.
+func (sc *signScratch65) audit() {
+	auditSecret("y", unsafe.Pointer(&sc.y), unsafe.Sizeof(sc.y))
+	auditSecret("yNTT", unsafe.Pointer(&sc.yNTT), unsafe.Sizeof(sc.yNTT))
+	auditSecret("w", unsafe.Pointer(&sc.w), unsafe.Sizeof(sc.w))
+	auditSecret("z", unsafe.Pointer(&sc.z), unsafe.Sizeof(sc.z))
+	auditSecret("cs2", unsafe.Pointer(&sc.cs2), unsafe.Sizeof(sc.cs2))
+	auditSecret("r0", unsafe.Pointer(&sc.r0), unsafe.Sizeof(sc.r0))
+	auditSecret("ct0", unsafe.Pointer(&sc.ct0), unsafe.Sizeof(sc.ct0))
+	auditSecret("hints", unsafe.Pointer(&sc.hints), unsafe.Sizeof(sc.hints))
+}
+
+// wipeAttempt wipes the values of a rejected attempt, which are as secret
+// as the key. The scratch space is wiped as a whole when it is released.
+func (sc *signScratch65) wipeAttempt() {
+	clear(sc.yNTT[:])
+	clear(sc.y[:])
+	clear(sc.z[:])
+	clear(sc.w[:])
+	clear(sc.w1[:])
+	clear(sc.ct0[:])
+	clear(sc.hints[:])
+	clear(sc.cs2[:])
+	clear(sc.r0[:])
+	sc.prod = NttElement{}
+}
+
+// verifyScratch65 holds the vectors of an ML-DSA-65 verification.
+type verifyScratch65 struct {
+	z     [L65]RingElement
+	zNTT  [L65]NttElement
+	t1NTT [K65]NttElement
+	hints [K65]RingElement
+	w1    [K65]RingElement
+	aRow  [L65]NttElement // a row of A, for compact keys
+	c     RingElement
+	cNTT  NttElement
+	acc   NttElement
+	w     RingElement
+}
+
+var (
+	signScratchPool65   scratchPool[signScratch65]
+	verifyScratchPool65 scratchPool[verifyScratch65]
+	s1NTTPool65         scratchPool[[L65]NttElement]
+)
+
 // Key65 is a key pair for ML-DSA-65, containing both private and public components.
 type Key65 struct {
 	PrivateKey65
-	seed [32]byte         // Original seed
-	t1   [K65]RingElement // Public key component
+	t1 [K65]RingElement // Public key component
 }
 
 // GenerateKey65 generates a new ML-DSA-65 key pair.
+// If rand is nil, crypto/rand.Reader is used. In strict FIPS mode, or when
+// enabled with SetPairwiseConsistencyTest, the new key must pass a pairwise
+// consistency test before it is returned.
 func GenerateKey65(rand io.Reader) (*Key65, error) {
 	var seed [SeedSize]byte
-	if _, err := io.ReadFull(rand, seed[:]); err != nil {
+	if err := readRandom(rand, seed[:]); err != nil {
 		return nil, err
 	}
-	return NewKey65(seed[:])
+	key, err := NewKey65(seed[:])
+	if err != nil {
+		return nil, err
+	}
+	clear(seed[:])
+	if err := pairwiseConsistencyTest(key); err != nil {
+		key.Destroy()
+		return nil, err
+	}
+	return key, nil
 }
 
 // NewKey65 creates a key pair from a seed.
 func NewKey65(seed []byte) (*Key65, error) {
 	if len(seed) != SeedSize {
-		return nil, errors.New("mldsa: invalid seed length")
+		return nil, &LengthError{Err: ErrInvalidSeedLength, Got: len(seed), Want: SeedSize}
 	}
 
 	key := &Key65{}
+	if err := key.allocSecret(); err != nil {
+		return nil, err
+	}
 	copy(key.seed[:], seed)
 	key.generate()
 	return key, nil
 }
 
+// NewPrivateKey65FromSeed derives a standalone private key from a seed using
+// the full FIPS 204 key generation. The seed itself is not retained.
+func NewPrivateKey65FromSeed(seed []byte) (*PrivateKey65, error) {
+	key, err := NewKey65(seed)
+	if err != nil {
+		return nil, err
+	}
+	defer key.wipe()
+	sk := &PrivateKey65{}
+	if err := sk.set(&key.PrivateKey65); err != nil {
+		return nil, err
+	}
+	pk := key.PublicKey()
+	sk.pubOnce.Do(func() { sk.pub = pk })
+	return sk, nil
+}
+
+// set replaces the key material of sk with that of o and drops the cached
+// public key and transforms. It is used instead of a struct copy, which
+// would copy pubOnce and share the secret components. The seed is not
+// copied.
+func (sk *PrivateKey65) set(o *PrivateKey65) error {
+	if sk.keySecret65 == nil {
+		if err := sk.allocSecret(); err != nil {
+			return err
+		}
+	}
+	sk.dropPrecomputed()
+	// Assigning field by field avoids building the key in a temporary on
+	// the stack.
+	sec := sk.keySecret65
+	sec.wipe()
+	*sk = PrivateKey65{}
+	sk.keySecret65 = sec
+	sk.rho = o.rho
+	sk.key = o.key
+	sk.tr = o.tr
+	sk.s1 = o.s1
+	sk.s2 = o.s2
+	sk.t0 = o.t0
+	sk.a = o.a
+	return nil
+}
+
+// allocSecret gives sk zeroed storage for its secret components, in locked
+// memory if SetLockedMemory is enabled.
+func (sk *PrivateKey65) allocSecret() error {
+	if !lockedMemory.Load() {
+		sk.keySecret65 = new(keySecret65)
+		return nil
+	}
+	p, err := allocLocked(sk, unsafe.Sizeof(keySecret65{}))
+	if err != nil {
+		return err
+	}
+	sk.keySecret65 = (*keySecret65)(p)
+	sk.locked = true
+	return nil
+}
+
+// Destroy wipes the secret components of the private key and makes it
+// unusable: subsequent signing operations return ErrKeyDestroyed. The public
+// components are kept, so Public still works. Destroy must not be called
+// concurrently with other methods of sk.
+func (sk *PrivateKey65) Destroy() {
+	// The public key is derived from s1 and s2, so cache it first.
+	sk.PublicKey()
+	sk.wipe()
+}
+
+// wipe is like Destroy, but does not cache the public key first.
+func (sk *PrivateKey65) wipe() {
+	if sk.keySecret65 != nil {
+		sk.keySecret65.wipe()
+	}
+	sk.dropPrecomputed()
+	sk.destroyed = true
+}
+
+// isDestroyed reports whether the key was destroyed.
+func (sk *PrivateKey65) isDestroyed() bool {
+	return sk.destroyed
+}
+
+// Destroy wipes the seed and the secret components of the key pair, see
+// PrivateKey65.Destroy. A destroyed key pair can be reused with Regenerate.
+func (key *Key65) Destroy() {
+	key.PrivateKey65.Destroy()
+}
+
+// Regenerate re-derives every component of the key pair from seed into the
+// existing allocation. The previous key material is wiped first, so nothing
+// of the old key survives in the reused memory.
+func (key *Key65) Regenerate(seed []byte) error {
+	if len(seed) != SeedSize {
+		return &LengthError{Err: ErrInvalidSeedLength, Got: len(seed), Want: SeedSize}
+	}
+
+	// seed may alias key.seed, so take a copy before wiping.
+	var s [SeedSize]byte
+	copy(s[:], seed)
+	if key.keySecret65 == nil {
+		if err := key.allocSecret(); err != nil {
+			return err
+		}
+	}
+	key.dropPrecomputed()
+	sec := key.keySecret65
+	sec.wipe()
+	*key = Key65{}
+	key.keySecret65 = sec
+	key.seed = s
+	clear(s[:])
+	key.generate()
+	return nil
+}
+
 // generate derives all key components from the seed.
 func (key *Key65) generate() {
 	// Expand seed: SHAKE256(seed || k || l)
@@ -61,47 +298,54 @@ func (key *Key65) generate() {
 	h.Write(key.seed[:])
 	h.Write([]byte{K65, L65})
 
+	// expanded determines the whole private key: wipe it, and the sponge
+	// that produced it, once the key is derived.
 	var expanded [128]byte
+	defer clear(expanded[:])
 	h.Read(expanded[:])
+	h.Reset()
 
 	copy(key.rho[:], expanded[:32])
 	rho1 := expanded[32:96]
 	copy(key.key[:], expanded[96:128])
 
-	// Generate secret vectors s1, s2
-	for i := 0; i < L65; i++ {
-		key.s1[i] = SampleBoundedPoly(rho1, Eta4, uint16(i))
-	}
-	for i := 0; i < K65; i++ {
-		key.s2[i] = SampleBoundedPoly(rho1, Eta4, uint16(L65+i))
-	}
-
-	// Generate matrix A in NTT form
-	for i := 0; i < K65; i++ {
-		for j := 0; j < L65; j++ {
-			key.a[i*L65+j] = SampleNTTPoly(key.rho[:], byte(j), byte(i))
+	// Generate secret vectors s1, s2 and matrix A in NTT form. They are
+	// independent, and so are the rows of t; forEach computes them
+	// concurrently when parallel expansion is enabled.
+	forEach(L65+K65+1, func(i int) {
+		switch {
+		case i < L65:
+			key.s1[i] = SampleBoundedPoly(rho1, Eta4, uint16(i))
+		case i < L65+K65:
+			key.s2[i-L65] = SampleBoundedPoly(rho1, Eta4, uint16(i))
+		default:
+			expandA(key.a[:], key.rho[:], L65)
 		}
-	}
+	})
 
 	// Compute t = A*s1 + s2
-	var s1NTT [L65]NttElement
+	s1NTT := s1NTTPool65.get()
+	defer s1NTTPool65.put(s1NTT)
 	for i := 0; i < L65; i++ {
-		s1NTT[i] = NTT(key.s1[i])
+		nttTo(&s1NTT[i], &key.s1[i])
 	}
 
-	var t [K65]RingElement
-	for i := 0; i < K65; i++ {
+	forEach(K65, func(i int) {
 		var acc NttElement
 		for j := 0; j < L65; j++ {
-			acc = PolyAdd(acc, NttMul(key.a[i*L65+j], s1NTT[j]))
+			nttMulAdd(&acc, &key.a[i*L65+j], &s1NTT[j])
+		}
+		var t RingElement
+		invNTTTo(&t, &acc)
+		for j := 0; j < N; j++ {
+			t[j] = fieldAdd(t[j], key.s2[i][j])
 		}
-		t[i] = PolyAdd(InvNTT(acc), key.s2[i])
 
 		// Power2Round: t = t1*2^D + t0
 		for j := 0; j < N; j++ {
-			key.t1[i][j], key.t0[i][j] = Power2Round(t[i][j])
+			key.t1[i][j], key.t0[i][j] = Power2Round(t[j])
 		}
-	}
+	})
 
 	// Compute tr = H(pk)
 	pkBytes := key.publicKeyBytes()
@@ -125,26 +369,78 @@ func (key *Key65) publicKeyBytes() []byte {
 
 // PublicKey returns the public key for this key pair.
 func (key *Key65) PublicKey() *PublicKey65 {
-	return &PublicKey65{
+	pk := &PublicKey65{
 		rho: key.rho,
 		t1:  key.t1,
 		tr:  key.tr,
-		a:   key.a,
+		a:   new([K65 * L65]NttElement),
 	}
+	*pk.a = key.a
+	return pk
 }
 
-// Bytes returns the seed (32 bytes).
+// Bytes returns the seed (32 bytes). After Destroy the seed is all zeros,
+// and the key encoders return ErrKeyDestroyed instead.
 func (key *Key65) Bytes() []byte {
 	b := make([]byte, SeedSize)
 	copy(b, key.seed[:])
 	return b
 }
 
+// Seed returns a copy of the 32-byte seed the key pair was derived from. It
+// returns false if the key has been destroyed.
+func (key *Key65) Seed() ([]byte, bool) {
+	if key.destroyed {
+		return nil, false
+	}
+	return key.Bytes(), true
+}
+
+// Seed always returns false: a standalone private key does not retain the
+// seed it may have been derived from, so only the expanded encoding returned
+// by Bytes can be exported. Use Key65 to keep the compact seed form.
+func (sk *PrivateKey65) Seed() ([]byte, bool) {
+	return nil, false
+}
+
 // PrivateKeyBytes returns the full encoded private key.
 func (key *Key65) PrivateKeyBytes() []byte {
 	return key.PrivateKey65.Bytes()
 }
 
+// Equal reports whether key and other are the same private key. Two key
+// pairs are compared by seed; a key pair and a standalone *PrivateKey65 are
+// compared by their encoded private keys. The comparison is constant time.
+// A destroyed key is not equal to any key.
+func (key *Key65) Equal(other crypto.PrivateKey) bool {
+	if o, ok := other.(*Key65); ok {
+		if key.destroyed || o.destroyed {
+			return false
+		}
+		return subtle.ConstantTimeCompare(key.seed[:], o.seed[:]) == 1
+	}
+	return key.PrivateKey65.Equal(other)
+}
+
+// Equal reports whether sk and other are the same private key. other may be
+// a *PrivateKey65 or a *Key65. The encoded keys are compared in constant
+// time. A destroyed key is not equal to any key.
+func (sk *PrivateKey65) Equal(other crypto.PrivateKey) bool {
+	var o *PrivateKey65
+	switch k := other.(type) {
+	case *PrivateKey65:
+		o = k
+	case *Key65:
+		o = &k.PrivateKey65
+	default:
+		return false
+	}
+	if sk.destroyed || o.destroyed {
+		return false
+	}
+	return subtle.ConstantTimeCompare(sk.Bytes(), o.Bytes()) == 1
+}
+
 // Bytes returns the encoded private key.
 func (sk *PrivateKey65) Bytes() []byte {
 	b := make([]byte, PrivateKeySize65)
@@ -193,43 +489,96 @@ func (pk *PublicKey65) Equal(other crypto.PublicKey) bool {
 	return pk.rho == o.rho && pk.t1 == o.t1
 }
 
+// Scheme returns the ML-DSA-65 Scheme.
+func (pk *PublicKey65) Scheme() Scheme {
+	return scheme65{}
+}
+
 // NewPublicKey65 parses an encoded public key.
 func NewPublicKey65(b []byte) (*PublicKey65, error) {
+	return newPublicKey65(b, false)
+}
+
+// NewPublicKey65Compact is like NewPublicKey65, but returns a key that
+// never stores the matrix A: each verification expands it row by row
+// instead. The key takes several times less memory, at the cost of
+// sampling A again for every signature, which suits verifiers holding
+// thousands of keys.
+func NewPublicKey65Compact(b []byte) (*PublicKey65, error) {
+	pk, err := newPublicKey65(b, true)
+	if err != nil {
+		return nil, err
+	}
+	pk.compact = true
+	return pk, nil
+}
+
+// NewPublicKey65Lazy is like NewPublicKey65, but defers expanding the
+// matrix A, which dominates the time and memory of parsing, until the key
+// first verifies a signature. It suits applications that load many keys
+// and use few of them, such as key directories.
+func NewPublicKey65Lazy(b []byte) (*PublicKey65, error) {
+	return newPublicKey65(b, true)
+}
+
+func newPublicKey65(b []byte, lazy bool) (*PublicKey65, error) {
 	if len(b) != PublicKeySize65 {
-		return nil, errors.New("mldsa: invalid public key length")
+		return nil, &LengthError{Err: ErrInvalidPublicKey, Got: len(b), Want: PublicKeySize65}
 	}
 
 	pk := &PublicKey65{}
+	pk.decode(b)
+	if !lazy {
+		// Generate A matrix
+		pk.a = new([K65 * L65]NttElement)
+		expandA(pk.a[:], pk.rho[:], L65)
+	}
+	return pk, nil
+}
+
+// decode sets rho, t1 and tr from the public key encoding b, which must be
+// PublicKeySize65 bytes long.
+func (pk *PublicKey65) decode(b []byte) {
 	copy(pk.rho[:], b[:32])
 
 	offset := 32
 	for i := 0; i < K65; i++ {
-		pk.t1[i] = UnpackT1(b[offset : offset+EncodingSize10])
+		pk.t1[i] = unpackT1(b[offset : offset+EncodingSize10])
 		offset += EncodingSize10
 	}
 
-	// Generate A matrix
-	for i := 0; i < K65; i++ {
-		for j := 0; j < L65; j++ {
-			pk.a[i*L65+j] = SampleNTTPoly(pk.rho[:], byte(j), byte(i))
-		}
-	}
-
 	// Compute tr = H(pk)
 	h := sha3.NewSHAKE256()
 	h.Write(b)
 	h.Read(pk.tr[:])
-
-	return pk, nil
 }
 
-// NewPrivateKey65 parses an encoded private key.
+// NewPrivateKey65 parses an encoded private key. The key is validated if
+// SetPrivateKeyValidation or strict FIPS mode is enabled, see
+// NewPrivateKey65Strict.
 func NewPrivateKey65(b []byte) (*PrivateKey65, error) {
+	sk, err := parsePrivateKey65(b)
+	if err != nil {
+		return nil, err
+	}
+	if validateImports() {
+		if err := sk.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return sk, nil
+}
+
+// parsePrivateKey65 decodes an encoded private key without validating it.
+func parsePrivateKey65(b []byte) (*PrivateKey65, error) {
 	if len(b) != PrivateKeySize65 {
-		return nil, errors.New("mldsa: invalid private key length")
+		return nil, &LengthError{Err: ErrInvalidPrivateKey, Got: len(b), Want: PrivateKeySize65}
 	}
 
 	sk := &PrivateKey65{}
+	if err := sk.allocSecret(); err != nil {
+		return nil, err
+	}
 	copy(sk.rho[:], b[:32])
 	copy(sk.key[:], b[32:64])
 	copy(sk.tr[:], b[64:128])
@@ -239,52 +588,83 @@ func NewPrivateKey65(b []byte) (*PrivateKey65, error) {
 	for i := 0; i < L65; i++ {
 		sk.s1[i], err = UnpackEta4(b[offset : offset+EncodingSize4])
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
 		}
 		offset += EncodingSize4
 	}
 	for i := 0; i < K65; i++ {
 		sk.s2[i], err = UnpackEta4(b[offset : offset+EncodingSize4])
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
 		}
 		offset += EncodingSize4
 	}
 	for i := 0; i < K65; i++ {
-		sk.t0[i] = UnpackT0(b[offset : offset+EncodingSize13])
+		sk.t0[i] = unpackT0(b[offset : offset+EncodingSize13])
 		offset += EncodingSize13
 	}
 
 	// Generate A matrix
-	for i := 0; i < K65; i++ {
-		for j := 0; j < L65; j++ {
-			sk.a[i*L65+j] = SampleNTTPoly(sk.rho[:], byte(j), byte(i))
-		}
-	}
+	expandA(sk.a[:], sk.rho[:], L65)
 
 	return sk, nil
 }
 
+// NewPrivateKey65Strict is like NewPrivateKey65, but also runs Validate
+// and rejects keys that are not internally consistent.
+func NewPrivateKey65Strict(b []byte) (*PrivateKey65, error) {
+	sk, err := parsePrivateKey65(b)
+	if err != nil {
+		return nil, err
+	}
+	if err := sk.Validate(); err != nil {
+		return nil, err
+	}
+	return sk, nil
+}
+
+// Scheme returns the ML-DSA-65 Scheme.
+func (sk *PrivateKey65) Scheme() Scheme {
+	return scheme65{}
+}
+
 // Public returns the public key corresponding to this private key.
 // This implements the crypto.Signer interface.
 func (sk *PrivateKey65) Public() crypto.PublicKey {
-	// Reconstruct public key from private key components
+	return sk.PublicKey()
+}
+
+// PublicKey returns the public key corresponding to this private key. It is
+// computed on first use and cached, so repeated calls are cheap.
+func (sk *PrivateKey65) PublicKey() *PublicKey65 {
+	sk.pubOnce.Do(func() { sk.pub = sk.computePublicKey() })
+	return sk.pub
+}
+
+// computePublicKey reconstructs the public key from the private key components.
+func (sk *PrivateKey65) computePublicKey() *PublicKey65 {
 	pk := &PublicKey65{
 		rho: sk.rho,
 		tr:  sk.tr,
-		a:   sk.a,
+		a:   new([K65 * L65]NttElement),
 	}
+	*pk.a = sk.a
 	// Compute t1 from s1, s2 via A*s1 + s2, then take high bits
-	var s1NTT [L65]NttElement
+	s1NTT := s1NTTPool65.get()
+	defer s1NTTPool65.put(s1NTT)
 	for i := 0; i < L65; i++ {
-		s1NTT[i] = NTT(sk.s1[i])
+		nttTo(&s1NTT[i], &sk.s1[i])
 	}
 	for i := 0; i < K65; i++ {
 		var acc NttElement
 		for j := 0; j < L65; j++ {
-			acc = PolyAdd(acc, NttMul(sk.a[i*L65+j], s1NTT[j]))
+			nttMulAdd(&acc, &sk.a[i*L65+j], &s1NTT[j])
+		}
+		var t RingElement
+		invNTTTo(&t, &acc)
+		for j := 0; j < N; j++ {
+			t[j] = fieldAdd(t[j], sk.s2[i][j])
 		}
-		t := PolyAdd(InvNTT(acc), sk.s2[i])
 		for j := 0; j < N; j++ {
 			pk.t1[i][j], _ = Power2Round(t[j])
 		}
@@ -292,114 +672,367 @@ func (sk *PrivateKey65) Public() crypto.PublicKey {
 	return pk
 }
 
+// Validate checks that the private key is internally consistent: t0 must
+// hold the low bits of t = A*s1 + s2, and tr must be the hash of the public
+// key (rho, t1) derived from the same t. NewPrivateKey65 trusts these
+// encoded values unless SetPrivateKeyValidation is enabled, and a corrupted
+// or maliciously modified key can produce signatures that leak the secret,
+// so keys from untrusted storage should be validated before use. The check
+// costs about as much as key generation.
+func (sk *PrivateKey65) Validate() error {
+	if sk.destroyed {
+		return ErrKeyDestroyed
+	}
+
+	s1NTT := s1NTTPool65.get()
+	defer s1NTTPool65.put(s1NTT)
+	for i := 0; i < L65; i++ {
+		nttTo(&s1NTT[i], &sk.s1[i])
+	}
+	// Hash the public key encoding as it is produced, rather than
+	// building the key.
+	h := sha3.NewSHAKE256()
+	h.Write(sk.rho[:])
+	var diff FieldElement
+	for i := 0; i < K65; i++ {
+		var acc NttElement
+		for j := 0; j < L65; j++ {
+			nttMulAdd(&acc, &sk.a[i*L65+j], &s1NTT[j])
+		}
+		var t RingElement
+		invNTTTo(&t, &acc)
+		for j := 0; j < N; j++ {
+			t[j] = fieldAdd(t[j], sk.s2[i][j])
+		}
+		var t1 RingElement
+		for j := 0; j < N; j++ {
+			var t0 FieldElement
+			t1[j], t0 = Power2Round(t[j])
+			diff |= t0 ^ sk.t0[i][j]
+		}
+		h.Write(PackT1(t1))
+	}
+	if diff != 0 {
+		return fmt.Errorf("%w: t0 does not match A*s1 + s2", ErrInvalidPrivateKey)
+	}
+
+	var tr [64]byte
+	h.Read(tr[:])
+	if subtle.ConstantTimeCompare(tr[:], sk.tr[:]) != 1 {
+		return fmt.Errorf("%w: tr does not match the public key", ErrInvalidPrivateKey)
+	}
+	return nil
+}
+
 // Sign signs digest with the private key.
 // This implements the crypto.Signer interface.
 //
-// For ML-DSA, the digest is the message to be signed (not a hash).
-// If opts is *SignerOpts, its Context field is used for domain separation.
-// If opts is nil or not *SignerOpts, no context is used.
+// If opts.HashFunc() is zero, digest is the message itself and is signed with
+// pure ML-DSA. Otherwise digest must be the output of that hash function over
+// the message, and it is signed with HashML-DSA. If opts is *SignerOpts, its
+// Context field is used for domain separation.
 func (sk *PrivateKey65) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
-	return sk.SignMessage(rand, digest, opts)
+	context, ph, r := signerOptions(opts)
+	if r != nil {
+		rand = r
+	}
+	var sig []byte
+	var err error
+	if ph != 0 {
+		sig, err = sk.SignPreHash(rand, digest, ph, context)
+	} else {
+		sig, err = sk.SignWithContext(rand, digest, context)
+	}
+	reportIndicator(opts, err == nil && approvedRandom(rand) && approvedContext(context))
+	return sig, err
 }
 
 // SignMessage signs msg with the private key.
 // This implements the crypto.MessageSigner interface.
 //
 // If opts is *SignerOpts, its Context field is used for domain separation.
-// If opts is nil or not *SignerOpts, no context is used.
-// Returns an error if opts specifies a hash function, as ML-DSA signs messages directly.
+// If opts.HashFunc() is not zero, msg is hashed with that function and signed
+// with HashML-DSA, which lets generic code that always passes a crypto.Hash,
+// such as certificate request creation, use ML-DSA keys.
 func (sk *PrivateKey65) SignMessage(rand io.Reader, msg []byte, opts crypto.SignerOpts) ([]byte, error) {
-	if opts != nil && opts.HashFunc() != 0 {
-		return nil, errors.New("mldsa: cannot sign pre-hashed messages")
+	context, ph, r := signerOptions(opts)
+	if r != nil {
+		rand = r
 	}
-	var context []byte
-	if o, ok := opts.(*SignerOpts); ok && o != nil {
-		context = o.Context
+	var sig []byte
+	var err error
+	if ph != 0 {
+		var digest []byte
+		if digest, err = PreHash(ph, msg); err == nil {
+			sig, err = sk.SignPreHash(rand, digest, ph, context)
+		}
+	} else {
+		sig, err = sk.SignWithContext(rand, msg, context)
 	}
-	return sk.SignWithContext(rand, msg, context)
+	reportIndicator(opts, err == nil && approvedRandom(rand) && approvedContext(context))
+	return sig, err
 }
 
 // SignWithContext signs a message with an optional context string.
-// Context must be at most 255 bytes.
+// Context must be at most 255 bytes. If rand is nil, crypto/rand.Reader is
+// used.
 func (sk *PrivateKey65) SignWithContext(rand io.Reader, message, context []byte) ([]byte, error) {
+	return sk.AppendSign(nil, rand, message, context)
+}
+
+// AppendSign is like SignWithContext, but appends the signature to dst and
+// returns the extended slice. If dst has at least SignatureSize65 bytes of
+// spare capacity, the signature is encoded in place and signing performs no
+// heap allocations.
+func (sk *PrivateKey65) AppendSign(dst []byte, rand io.Reader, message, context []byte) ([]byte, error) {
+	sc := signScratchPool65.get()
+	defer signScratchPool65.put(sc)
+	return sk.appendSign(sc, dst, rand, message, context)
+}
+
+// appendSign implements AppendSign, working in sc.
+func (sk *PrivateKey65) appendSign(sc *signScratch65, dst []byte, rand io.Reader, message, context []byte) ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
 	if len(context) > 255 {
-		return nil, errors.New("mldsa: context too long")
+		return nil, ErrContextTooLong
+	}
+	if err := checkSignContext(context); err != nil {
+		return nil, err
+	}
+
+	rnd, err := readRnd(rand, &sk.rndCount)
+	if err != nil {
+		return nil, err
 	}
 
-	var rnd [32]byte
-	if _, err := io.ReadFull(rand, rnd[:]); err != nil {
+	mu := pureMu(sk.tr[:], message, context)
+	out, err := sk.signMuWith(sc, dst, rnd[:], &mu)
+	if err != nil {
 		return nil, err
 	}
+	if b := crossCheckBackend(); b != nil {
+		pk := sk.PublicKey()
+		if !b.Verify("ML-DSA-65", pk.Bytes(), out[len(dst):], message, context) {
+			clear(out[len(dst):])
+			return nil, ErrParanoidCheckFailed
+		}
+	}
+	return out, nil
+}
 
-	// M' = 0 || len(ctx) || ctx || msg
-	mPrime := make([]byte, 2+len(context)+len(message))
-	mPrime[0] = 0
-	mPrime[1] = byte(len(context))
-	copy(mPrime[2:], context)
-	copy(mPrime[2+len(context):], message)
+// ComputeMu returns the message representative µ = H(tr || M') for message
+// and context. It matches PublicKey65.ComputeMu for the same key pair.
+func (sk *PrivateKey65) ComputeMu(message, context []byte) ([MuSize]byte, error) {
+	return computeMu(sk.tr[:], bytes.NewReader(message), context)
+}
 
-	return sk.signInternal(rnd[:], mPrime)
+// ComputeMuReader is like ComputeMu, but streams the message from r.
+func (sk *PrivateKey65) ComputeMuReader(r io.Reader, context []byte) ([MuSize]byte, error) {
+	return computeMu(sk.tr[:], r, context)
+}
+
+// SignMu signs the message representative µ, as computed by ComputeMu with
+// the public key, for external-µ signing (FIPS 204 Section 6.2) where the
+// message is hashed away from the private key. If rand is nil,
+// crypto/rand.Reader is used.
+func (sk *PrivateKey65) SignMu(rand io.Reader, mu [MuSize]byte) ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	rnd, err := readRnd(rand, &sk.rndCount)
+	if err != nil {
+		return nil, err
+	}
+	return sk.signMu(nil, rnd[:], &mu)
+}
+
+// SignReader is like SignWithContext, but streams the message from msg
+// into the hash, so that messages of any size, such as large files, are
+// signed without being held in memory. Errors reading msg are returned. If
+// rand is nil, crypto/rand.Reader is used.
+func (sk *PrivateKey65) SignReader(rand io.Reader, msg io.Reader, context []byte) ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	if err := checkSignContext(context); err != nil {
+		return nil, err
+	}
+	mu, err := computeMu(sk.tr[:], msg, context)
+	if err != nil {
+		return nil, err
+	}
+	rnd, err := readRnd(rand, &sk.rndCount)
+	if err != nil {
+		return nil, err
+	}
+	return sk.signMu(nil, rnd[:], &mu)
+}
+
+// SignPreHash signs a message digest with HashML-DSA (FIPS 204 Algorithm 4).
+// digest must be the output of ph over the message. If rand is nil,
+// crypto/rand.Reader is used.
+func (sk *PrivateKey65) SignPreHash(rand io.Reader, digest []byte, ph crypto.Hash, context []byte) ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	mPrime, err := preHashMPrime(digest, ph, context)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSignContext(context); err != nil {
+		return nil, err
+	}
+
+	rnd, err := readRnd(rand, &sk.rndCount)
+	if err != nil {
+		return nil, err
+	}
+	return sk.signInternal(nil, rnd[:], mPrime)
+}
+
+// Precompute computes and caches the secret vectors s1, s2 and t0 in NTT
+// form, which signing otherwise recomputes for every signature. It suits
+// servers signing many messages with one key, at the cost of keeping the
+// transforms in memory until Destroy. It is safe to call concurrently with
+// signing. Keys in locked memory are not precomputed, see SetLockedMemory.
+func (sk *PrivateKey65) Precompute() {
+	if sk.destroyed || sk.locked || sk.ntt.Load() != nil {
+		return
+	}
+	secret := new(secretNTT65)
+	sk.transformSecret(secret)
+	if !sk.ntt.CompareAndSwap(nil, secret) {
+		*secret = secretNTT65{}
+	}
+}
+
+// transformSecret stores the NTT forms of s1, s2 and t0 in dst.
+func (sk *PrivateKey65) transformSecret(dst *secretNTT65) {
+	for i := 0; i < L65; i++ {
+		nttTo(&dst.s1[i], &sk.s1[i])
+	}
+	for i := 0; i < K65; i++ {
+		nttTo(&dst.s2[i], &sk.s2[i])
+		nttTo(&dst.t0[i], &sk.t0[i])
+	}
+}
+
+// dropPrecomputed wipes and forgets the transforms cached by Precompute.
+func (sk *PrivateKey65) dropPrecomputed() {
+	if secret := sk.ntt.Swap(nil); secret != nil {
+		*secret = secretNTT65{}
+	}
+}
+
+// checkSignature reports whether sig is a valid signature of µ. In paranoid
+// mode, the public key is recomputed from the secret components instead of
+// taken from the cache, and must hash to tr, and a MuBackend cross-checks
+// the signature.
+func (sk *PrivateKey65) checkSignature(sig []byte, mu *[MuSize]byte) bool {
+	if !paranoid.Load() {
+		return sk.PublicKey().verifyMu(sig, mu) == nil
+	}
+	pk := sk.computePublicKey()
+	return pk.Validate() == nil && pk.verifyMu(sig, mu) == nil &&
+		crossCheckMu("ML-DSA-65", pk.Bytes(), sig, mu)
 }
 
 // signInternal implements ML-DSA.Sign_internal (FIPS 204 Algorithm 7).
-// mPrime is the message M' (for external signing: 0 || len(ctx) || ctx || msg)
-func (sk *PrivateKey65) signInternal(rnd, mPrime []byte) ([]byte, error) {
+// mPrime is the message M' (for external signing: 0 || len(ctx) || ctx || msg).
+// The signature is appended to dst.
+func (sk *PrivateKey65) signInternal(dst, rnd, mPrime []byte) ([]byte, error) {
 	// Compute mu = H(tr || M')
 	h := sha3.NewSHAKE256()
 	h.Write(sk.tr[:])
 	h.Write(mPrime)
 
-	var mu [64]byte
+	var mu [MuSize]byte
 	h.Read(mu[:])
+	return sk.signMu(dst, rnd, &mu)
+}
+
+// signMu implements ML-DSA.Sign_internal from µ on. The signature is
+// appended to dst.
+func (sk *PrivateKey65) signMu(dst, rnd []byte, mu *[MuSize]byte) ([]byte, error) {
+	sc := signScratchPool65.get()
+	defer signScratchPool65.put(sc)
+	return sk.signMuWith(sc, dst, rnd, mu)
+}
+
+// signMuWith is signMu working in sc.
+func (sk *PrivateKey65) signMuWith(sc *signScratch65, dst, rnd []byte, mu *[MuSize]byte) ([]byte, error) {
+	stats := SignStats{ParameterSet: MLDSA65}
+	if obs := signObserver.Load(); obs != nil {
+		defer observeSign(obs, &stats, time.Now())
+	}
+	auditPhase("expand")
 
 	// Compute rho' = H(key || rnd || mu)
-	h.Reset()
+	h := sha3.NewSHAKE256()
 	h.Write(sk.key[:])
 	h.Write(rnd)
 	h.Write(mu[:])
 
-	var rhoPrime [64]byte
-	h.Read(rhoPrime[:])
+	// rho' is read straight into the seed of ExpandMask, which is wiped
+	// on return.
+	var seedBuf [66]byte
+	defer clear(seedBuf[:])
+	h.Read(seedBuf[:64])
 
 	// Precompute NTT of secret vectors
-	var s1NTT [L65]NttElement
-	var s2NTT [K65]NttElement
-	var t0NTT [K65]NttElement
-	for i := 0; i < L65; i++ {
-		s1NTT[i] = NTT(sk.s1[i])
-	}
-	for i := 0; i < K65; i++ {
-		s2NTT[i] = NTT(sk.s2[i])
-		t0NTT[i] = NTT(sk.t0[i])
+	secret := sk.ntt.Load()
+	if secret == nil {
+		secret = &sc.secret
+		sk.transformSecret(secret)
 	}
+	s1NTT, s2NTT, t0NTT := &secret.s1, &secret.s2, &secret.t0
+	auditSecret("rhoPrime", unsafe.Pointer(&seedBuf), 64)
+	auditSecret("s1NTT", unsafe.Pointer(s1NTT), unsafe.Sizeof(*s1NTT))
+	auditSecret("s2NTT", unsafe.Pointer(s2NTT), unsafe.Sizeof(*s2NTT))
+	auditSecret("t0NTT", unsafe.Pointer(t0NTT), unsafe.Sizeof(*t0NTT))
+	sc.audit()
 
 	// Rejection sampling loop
-	var seedBuf [66]byte
-	copy(seedBuf[:64], rhoPrime[:])
+	var w1Buf [EncodingSize4]byte
+
+	// The attempt cap and the 16-bit range of kappa bound the loop, so a
+	// corrupted key cannot hang the caller.
+	maxAttempts := MaxSignAttempts()
+	for attempt, kappa := 0, 0; ; attempt, kappa = attempt+1, kappa+L65 {
+		if attempt > 0 {
+			sc.wipeAttempt()
+		}
+		if attempt == maxAttempts || kappa+L65 > 1<<16 {
+			stats.Err = ErrSigningFailed
+			return nil, ErrSigningFailed
+		}
+		stats.Attempts++
+		auditPhase("attempt")
 
-	for kappa := uint16(0); ; kappa += L65 {
 		// Generate masking vector y
-		var y [L65]RingElement
+		y := &sc.y
 		for i := 0; i < L65; i++ {
-			seedBuf[64] = byte(kappa + uint16(i))
-			seedBuf[65] = byte((kappa + uint16(i)) >> 8)
-			y[i] = ExpandMask(seedBuf[:], Gamma1Bits19)
+			seedBuf[64] = byte(kappa + i)
+			seedBuf[65] = byte((kappa + i) >> 8)
+			expandMaskTo(&y[i], seedBuf[:], Gamma1Bits19)
 		}
 
 		// Compute w = A*y
-		var yNTT [L65]NttElement
+		yNTT := &sc.yNTT
 		for i := 0; i < L65; i++ {
-			yNTT[i] = NTT(y[i])
+			nttTo(&yNTT[i], &y[i])
 		}
 
-		var w [K65]RingElement
-		var w1 [K65]RingElement
+		w, w1 := &sc.w, &sc.w1
 		for i := 0; i < K65; i++ {
-			var acc NttElement
+			acc := &sc.prod
+			*acc = NttElement{}
 			for j := 0; j < L65; j++ {
-				acc = PolyAdd(acc, NttMul(sk.a[i*L65+j], yNTT[j]))
+				nttMulAdd(acc, &sk.a[i*L65+j], &yNTT[j])
 			}
-			w[i] = InvNTT(acc)
+			invNTTTo(&w[i], acc)
 
 			// Compute w1 = HighBits(w)
 			for j := 0; j < N; j++ {
@@ -411,174 +1044,491 @@ func (sk *PrivateKey65) signInternal(rnd, mPrime []byte) ([]byte, error) {
 		h.Reset()
 		h.Write(mu[:])
 		for i := 0; i < K65; i++ {
-			h.Write(PackW1_4(w1[i]))
+			h.Write(AppendW1_4(w1Buf[:0], w1[i]))
 		}
 		var cTilde [Lambda192 / 4]byte
 		h.Read(cTilde[:])
 
 		// Sample challenge polynomial c
-		c := SampleChallenge(cTilde[:], Tau49)
-		cNTT := NTT(c)
+		c, cNTT := &sc.c, &sc.cNTT
+		*c = SampleChallenge(cTilde[:], Tau49)
+		nttTo(cNTT, c)
 
 		// Compute z = y + c*s1
-		var z [L65]RingElement
+		z := &sc.z
 		for i := 0; i < L65; i++ {
-			cs1 := InvNTT(NttMul(cNTT, s1NTT[i]))
-			z[i] = PolyAdd(y[i], cs1)
+			nttMulTo(&sc.prod, cNTT, &s1NTT[i])
+			invNTTTo(&z[i], &sc.prod)
+			for j := 0; j < N; j++ {
+				z[i][j] = fieldAdd(y[i][j], z[i][j])
+			}
 		}
 
+		auditPhase("checks")
 		// Check ||z||_inf < gamma1 - beta
 		if VectorInfinityNorm(z[:]) >= Gamma1Pow19-Beta65 {
+			stats.ZRejections++
 			continue
 		}
 
 		// Compute r0 = LowBits(w - c*s2)
-		var r0 [K65][N]int32
+		r0, cs2 := &sc.r0, &sc.cs2
 		for i := 0; i < K65; i++ {
-			cs2 := InvNTT(NttMul(cNTT, s2NTT[i]))
+			nttMulTo(&sc.prod, cNTT, &s2NTT[i])
+			invNTTTo(&cs2[i], &sc.prod)
 			for j := 0; j < N; j++ {
-				_, r0[i][j] = Decompose(fieldSub(w[i][j], cs2[j]), Gamma2QMinus1Div32)
+				_, r0[i][j] = Decompose(fieldSub(w[i][j], cs2[i][j]), Gamma2QMinus1Div32)
 			}
 		}
 
 		// Check ||r0||_inf < gamma2 - beta
 		if vectorInfinityNormSigned(r0[:]) >= int32(Gamma2QMinus1Div32-Beta65) {
+			stats.R0Rejections++
 			continue
 		}
 
 		// Compute ct0
-		var ct0 [K65]RingElement
+		ct0 := &sc.ct0
 		for i := 0; i < K65; i++ {
-			ct0[i] = InvNTT(NttMul(cNTT, t0NTT[i]))
+			nttMulTo(&sc.prod, cNTT, &t0NTT[i])
+			invNTTTo(&ct0[i], &sc.prod)
 		}
 
 		// Check ||ct0||_inf < gamma2
 		if VectorInfinityNorm(ct0[:]) >= Gamma2QMinus1Div32 {
+			stats.CT0Rejections++
 			continue
 		}
 
 		// Compute hints
-		var hints [K65]RingElement
+		hints := &sc.hints
 		for i := 0; i < K65; i++ {
-			cs2 := InvNTT(NttMul(cNTT, s2NTT[i]))
 			for j := 0; j < N; j++ {
 				// r = w - cs2, z = ct0
-				r := fieldSub(w[i][j], cs2[j])
+				r := fieldSub(w[i][j], cs2[i][j])
 				hints[i][j] = MakeHint(ct0[i][j], r, Gamma2QMinus1Div32)
 			}
 		}
 
 		// Check number of hints <= omega
 		if CountOnes(hints[:]) > Omega55 {
+			stats.HintRejections++
 			continue
 		}
 
+		auditPhase("encode")
 		// Encode signature
-		sig := make([]byte, SignatureSize65)
-		copy(sig[:len(cTilde)], cTilde[:])
+		ret, sig := sliceForAppend(dst, SignatureSize65)
+		copy(sig, cTilde[:])
 		offset := len(cTilde)
 		for i := 0; i < L65; i++ {
-			packed := PackZ19(z[i])
-			copy(sig[offset:], packed)
+			AppendZ19(sig[offset:offset], z[i])
 			offset += EncodingSize20
 		}
-		hintPacked := PackHint(hints[:], Omega55)
-		copy(sig[offset:], hintPacked)
+		AppendHint(sig[offset:offset], hints[:], Omega55)
+
+		// A signature that does not verify may stem from a fault, and can
+		// leak the key: it must not reach the caller, even in dst.
+		if checkErr := signatureCheckError(); checkErr != nil {
+			if !sk.checkSignature(sig, mu) {
+				clear(sig)
+				stats.Err = checkErr
+				return nil, checkErr
+			}
+		}
+
+		return ret, nil
+	}
+}
+
+// Validate checks that the public key is well formed: every t1 coefficient
+// must fit in 10 bits, so the key has a canonical encoding, and tr must be
+// the hash of that encoding. Keys parsed with NewPublicKey65 always pass;
+// Validate catches zero or corrupted values, and lets protocols that require
+// explicit validation of peer keys perform it.
+func (pk *PublicKey65) Validate() error {
+	for i := 0; i < K65; i++ {
+		for j := 0; j < N; j++ {
+			if pk.t1[i][j] >= 1<<10 {
+				return fmt.Errorf("%w: t1 coefficient out of range", ErrInvalidPublicKey)
+			}
+		}
+	}
+	var tr [64]byte
+	h := sha3.NewSHAKE256()
+	h.Write(pk.Bytes())
+	h.Read(tr[:])
+	if subtle.ConstantTimeCompare(tr[:], pk.tr[:]) != 1 {
+		return fmt.Errorf("%w: tr does not match the encoding", ErrInvalidPublicKey)
+	}
+	return nil
+}
+
+// ComputeMu returns the message representative µ = H(tr || M') for message
+// and context, as used by pure ML-DSA signing and verification. It lets a
+// relay hash a large message and hand only µ to a remote signer.
+func (pk *PublicKey65) ComputeMu(message, context []byte) ([MuSize]byte, error) {
+	return computeMu(pk.tr[:], bytes.NewReader(message), context)
+}
+
+// ComputeMuReader is like ComputeMu, but streams the message from r.
+func (pk *PublicKey65) ComputeMuReader(r io.Reader, context []byte) ([MuSize]byte, error) {
+	return computeMu(pk.tr[:], r, context)
+}
+
+// VerifyMu reports whether sig is a valid signature of the message
+// representative µ, for external-µ verification.
+func (pk *PublicKey65) VerifyMu(sig []byte, mu [MuSize]byte) bool {
+	return pk.verifyMu(sig, &mu) == nil && pk.paranoidCheckMu(sig, &mu)
+}
+
+// VerifyPreHash checks a HashML-DSA signature (FIPS 204 Algorithm 5) on a
+// message digest computed with ph.
+func (pk *PublicKey65) VerifyPreHash(sig, digest []byte, ph crypto.Hash, context []byte) bool {
+	if len(sig) != SignatureSize65 {
+		return false
+	}
+	mPrime, err := preHashMPrime(digest, ph, context)
+	if err != nil {
+		return false
+	}
+	var mu [MuSize]byte
+	h := sha3.NewSHAKE256()
+	h.Write(pk.tr[:])
+	h.Write(mPrime)
+	h.Read(mu[:])
+	return pk.verifyMu(sig, &mu) == nil && pk.paranoidCheckMu(sig, &mu)
+}
 
-		return sig, nil
+// paranoidCheckMu repeats a successful verification of sig over µ in
+// paranoid mode, against a public key re-parsed from its encoding so that A
+// and tr are re-derived independently of the cached copies, and with a
+// MuBackend. It returns true when paranoid mode is disabled.
+func (pk *PublicKey65) paranoidCheckMu(sig []byte, mu *[MuSize]byte) bool {
+	if !paranoid.Load() {
+		return true
 	}
+	b := pk.Bytes()
+	pk2, err := NewPublicKey65(b)
+	return err == nil && pk2.tr == pk.tr && pk2.verifyMu(sig, mu) == nil &&
+		crossCheckMu("ML-DSA-65", b, sig, mu)
+}
+
+// VerifyWithOpts checks the signature on message using the context carried
+// by opts, so the same opts value passed to SignMessage can be reused. If
+// opts.HashFunc() is not zero, message is hashed with that function and the
+// signature is checked as HashML-DSA.
+func (pk *PublicKey65) VerifyWithOpts(sig, message []byte, opts crypto.SignerOpts) bool {
+	context, ph, _ := signerOptions(opts)
+	_, supported := lookupPreHash(ph)
+	reportIndicator(opts, ph == 0 || supported)
+	if ph != 0 {
+		digest, err := PreHash(ph, message)
+		if err != nil {
+			return false
+		}
+		return pk.VerifyPreHash(sig, digest, ph, context)
+	}
+	return pk.Verify(sig, message, context)
 }
 
 // Verify checks the signature on message with optional context.
 func (pk *PublicKey65) Verify(sig, message, context []byte) bool {
+	return pk.VerifyError(sig, message, context) == nil
+}
+
+// VerifyReader is like Verify, but streams the message from msg into the
+// hash, so that large messages such as disk images are verified without
+// being held in memory. It returns false if reading msg fails.
+func (pk *PublicKey65) VerifyReader(sig []byte, msg io.Reader, context []byte) bool {
 	if len(sig) != SignatureSize65 {
 		return false
 	}
+	mu, err := computeMu(pk.tr[:], msg, context)
+	// The message cannot be read twice, so in paranoid mode only µ is
+	// checked again.
+	return err == nil && pk.verifyMu(sig, &mu) == nil && pk.paranoidCheckMu(sig, &mu)
+}
+
+// VerifyError is like Verify, but returns an error describing why the
+// signature was rejected: ErrBadSignatureLength, ErrNormExceeded or
+// ErrHintEncoding for malformed signatures, and ErrMismatch for well-formed
+// signatures that do not match.
+func (pk *PublicKey65) VerifyError(sig, message, context []byte) error {
+	sc := verifyScratchPool65.get()
+	defer verifyScratchPool65.put(sc)
+	return pk.verifyError(sc, sig, message, context)
+}
+
+// verifyError implements VerifyError, working in sc.
+func (pk *PublicKey65) verifyError(sc *verifyScratch65, sig, message, context []byte) error {
+	if len(sig) != SignatureSize65 {
+		return &LengthError{Err: ErrBadSignatureLength, Got: len(sig), Want: SignatureSize65}
+	}
 	if len(context) > 255 {
-		return false
+		return ErrContextTooLong
 	}
 
-	// M' = 0 || len(ctx) || ctx || msg
-	mPrime := make([]byte, 2+len(context)+len(message))
-	mPrime[0] = 0
-	mPrime[1] = byte(len(context))
-	copy(mPrime[2:], context)
-	copy(mPrime[2+len(context):], message)
+	mu := pureMu(pk.tr[:], message, context)
+	if err := pk.verifyMuWith(sc, nil, nil, sig, &mu); err != nil {
+		return err
+	}
+	if paranoid.Load() {
+		// Re-derive A and tr from the encoding and verify again.
+		pk2, err := NewPublicKey65(pk.Bytes())
+		if err != nil {
+			return ErrParanoidCheckFailed
+		}
+		if mu2 := pureMu(pk2.tr[:], message, context); pk2.verifyMu(sig, &mu2) != nil {
+			return ErrParanoidCheckFailed
+		}
+		if b := crossCheckBackend(); b != nil && !b.Verify("ML-DSA-65", pk.Bytes(), sig, message, context) {
+			return ErrParanoidCheckFailed
+		}
+	}
+	return nil
+}
+
+// VerifyMany verifies a run of signatures made with this key, as Verify
+// does for each item, and reports which are valid. The NTT form of t1 and
+// the scratch space are set up once for the run, and the matrix A of a
+// compact key is expanded once rather than row by row for every
+// signature, so that validators of long chains of signatures from one
+// signer pay the per-key work once.
+func (pk *PublicKey65) VerifyMany(items []SigMsg) []bool {
+	valid := make([]bool, len(items))
+	if len(items) == 0 {
+		return valid
+	}
+	if paranoid.Load() {
+		for i, it := range items {
+			valid[i] = pk.Verify(it.Signature, it.Message, it.Context)
+		}
+		return valid
+	}
 
-	return pk.verifyInternal(sig, mPrime)
+	sc := verifyScratchPool65.get()
+	defer verifyScratchPool65.put(sc)
+	t1NTT := pk.t1NTT.Load()
+	if t1NTT == nil {
+		t1NTT = &sc.t1NTT
+		pk.transformT1(t1NTT)
+	}
+	a := pk.matrix()
+	if a == nil && len(items) > 1 {
+		a = new([K65 * L65]NttElement)
+		expandA(a[:], pk.rho[:], L65)
+	}
+	for i, it := range items {
+		if len(it.Signature) != SignatureSize65 || len(it.Context) > 255 {
+			continue
+		}
+		mu := pureMu(pk.tr[:], it.Message, it.Context)
+		valid[i] = pk.verifyMuWith(sc, t1NTT, a, it.Signature, &mu) == nil
+	}
+	return valid
+}
+
+// Workspace65 is caller-owned memory for ML-DSA-65 operations, for
+// real-time and GC-sensitive programs that need full control over memory
+// reuse. Signing, verification and public key parsing through a workspace
+// run entirely in its memory, about 145 KB: they perform no heap
+// allocations and leave the scratch pools alone. Paranoid mode, which
+// re-derives keys, and the error values of failed operations are the
+// exceptions.
+//
+// The zero value is ready to use. A Workspace65 must not be used
+// concurrently, and is usually allocated once per goroutine.
+type Workspace65 struct {
+	sign   signScratch65
+	verify verifyScratch65
+	pub    PublicKey65
+	a      [K65 * L65]NttElement
+}
+
+// AppendSign is like sk.AppendSign, but works in ws. The signature is
+// encoded in place if dst has at least SignatureSize65 bytes of spare
+// capacity. The secret values left in ws are wiped before it returns.
+func (ws *Workspace65) AppendSign(sk *PrivateKey65, dst []byte, rand io.Reader, message, context []byte) ([]byte, error) {
+	defer ws.wipeSign()
+	return sk.appendSign(&ws.sign, dst, rand, message, context)
+}
+
+func (ws *Workspace65) wipeSign() {
+	ws.sign = signScratch65{}
+}
+
+// Verify is like pk.Verify, but works in ws. pk should be parsed with
+// ParsePublicKey, or otherwise have its matrix A expanded: verifying with
+// a key from NewPublicKey65Lazy allocates A on first use.
+func (ws *Workspace65) Verify(pk *PublicKey65, sig, message, context []byte) bool {
+	return pk.verifyError(&ws.verify, sig, message, context) == nil
+}
+
+// ParsePublicKey is like NewPublicKey65, but decodes the key into ws,
+// including its matrix A. The key is only valid until the next call to
+// ParsePublicKey on ws.
+func (ws *Workspace65) ParsePublicKey(b []byte) (*PublicKey65, error) {
+	if len(b) != PublicKeySize65 {
+		return nil, &LengthError{Err: ErrInvalidPublicKey, Got: len(b), Want: PublicKeySize65}
+	}
+	pk := &ws.pub
+	*pk = PublicKey65{}
+	pk.decode(b)
+	pk.a = &ws.a
+	expandARange(pk.a[:], pk.rho[:], L65, 0)
+	return pk, nil
+}
+
+// matrix returns the matrix A, expanding it on first use for keys parsed
+// with NewPublicKey65Lazy. It returns nil for compact keys.
+func (pk *PublicKey65) matrix() *[K65 * L65]NttElement {
+	pk.aOnce.Do(func() {
+		if pk.a == nil && !pk.compact {
+			a := new([K65 * L65]NttElement)
+			expandA(a[:], pk.rho[:], L65)
+			pk.a = a
+		}
+	})
+	return pk.a
+}
+
+// set replaces the key material of pk with that of o and drops the cached
+// transform of t1. It is used instead of a struct copy, which would copy
+// t1NTT.
+func (pk *PublicKey65) set(o *PublicKey65) {
+	*pk = PublicKey65{}
+	pk.rho = o.rho
+	pk.t1 = o.t1
+	pk.tr = o.tr
+	pk.a = o.a
+	pk.compact = o.compact
+}
+
+// Precompute computes and caches t1·2^d in NTT form, which verification
+// otherwise recomputes for every signature. It suits long-lived peer keys,
+// such as pinned service identities, that verify many signatures. It is
+// safe to call concurrently with verification.
+func (pk *PublicKey65) Precompute() {
+	if pk.t1NTT.Load() != nil {
+		return
+	}
+	t1NTT := new([K65]NttElement)
+	pk.transformT1(t1NTT)
+	pk.t1NTT.CompareAndSwap(nil, t1NTT)
+}
+
+// transformT1 stores the NTT form of t1·2^d in dst.
+func (pk *PublicKey65) transformT1(dst *[K65]NttElement) {
+	for i := 0; i < K65; i++ {
+		for j := 0; j < N; j++ {
+			dst[i][j] = pk.t1[i][j] << D
+		}
+		nttInPlace((*[N]FieldElement)(&dst[i]))
+	}
 }
 
 // verifyInternal implements ML-DSA.Verify_internal (FIPS 204 Algorithm 8).
 // mPrime is the message M' (for external verification: 0 || len(ctx) || ctx || msg)
-func (pk *PublicKey65) verifyInternal(sig, mPrime []byte) bool {
+func (pk *PublicKey65) verifyInternal(sig, mPrime []byte) error {
 	// Compute mu = H(tr || M')
 	h := sha3.NewSHAKE256()
 	h.Write(pk.tr[:])
 	h.Write(mPrime)
 
-	var mu [64]byte
+	var mu [MuSize]byte
 	h.Read(mu[:])
+	return pk.verifyMu(sig, &mu)
+}
+
+// verifyMu implements ML-DSA.Verify_internal from µ on.
+func (pk *PublicKey65) verifyMu(sig []byte, mu *[MuSize]byte) error {
+	sc := verifyScratchPool65.get()
+	defer verifyScratchPool65.put(sc)
+	return pk.verifyMuWith(sc, nil, nil, sig, mu)
+}
+
+// verifyMuWith is verifyMu working in sc, with the NTT form of t1·2^d and
+// the matrix A supplied by the caller. If nil, they are taken from the key,
+// and t1NTT is computed into sc if the key was not precomputed.
+func (pk *PublicKey65) verifyMuWith(sc *verifyScratch65, t1NTT *[K65]NttElement, a *[K65 * L65]NttElement, sig []byte, mu *[MuSize]byte) error {
+	// Callers normally check the length, but the conformance hooks and ACVP
+	// harness, and VerifyMu, reach this directly with untrusted input.
+	if len(sig) != SignatureSize65 {
+		return &LengthError{Err: ErrBadSignatureLength, Got: len(sig), Want: SignatureSize65}
+	}
 
 	// Decode signature
 	cTilde := sig[:Lambda192/4]
 	offset := Lambda192 / 4
 
-	var z [L65]RingElement
+	z := &sc.z
 	for i := 0; i < L65; i++ {
-		z[i] = UnpackZ19(sig[offset : offset+EncodingSize20])
+		z[i] = unpackZ19(sig[offset : offset+EncodingSize20])
 		offset += EncodingSize20
 	}
 
 	// Check ||z||_inf < gamma1 - beta
 	if VectorInfinityNorm(z[:]) >= Gamma1Pow19-Beta65 {
-		return false
+		return ErrNormExceeded
 	}
 
-	var hints [K65]RingElement
+	hints := &sc.hints
 	if !UnpackHint(sig[offset:], hints[:], Omega55) {
-		return false
+		return ErrHintEncoding
 	}
 
 	// Sample challenge
-	c := SampleChallenge(cTilde, Tau49)
-	cNTT := NTT(c)
+	c, cNTT := &sc.c, &sc.cNTT
+	*c = SampleChallenge(cTilde, Tau49)
+	nttTo(cNTT, c)
 
 	// Compute NTT of z
-	var zNTT [L65]NttElement
+	zNTT := &sc.zNTT
 	for i := 0; i < L65; i++ {
-		zNTT[i] = NTT(z[i])
+		nttTo(&zNTT[i], &z[i])
 	}
 
 	// Compute t1*2^D in NTT form
-	var t1NTT [K65]NttElement
-	for i := 0; i < K65; i++ {
-		var t1Scaled RingElement
-		for j := 0; j < N; j++ {
-			t1Scaled[j] = pk.t1[i][j] << D
-		}
-		t1NTT[i] = NTT(t1Scaled)
+	if t1NTT == nil {
+		t1NTT = pk.t1NTT.Load()
+	}
+	if t1NTT == nil {
+		t1NTT = &sc.t1NTT
+		pk.transformT1(t1NTT)
 	}
 
+	var w1Buf [EncodingSize4]byte
 	// Compute w' = A*z - c*t1*2^D
-	var w1 [K65]RingElement
-	h.Reset()
+	w1 := &sc.w1
+	h := sha3.NewSHAKE256()
 	h.Write(mu[:])
 
+	if a == nil {
+		a = pk.matrix()
+	}
 	for i := 0; i < K65; i++ {
-		var acc NttElement
+		var row []NttElement
+		if a != nil {
+			row = a[i*L65 : (i+1)*L65]
+		} else {
+			expandARange(sc.aRow[:], pk.rho[:], L65, i*L65)
+			row = sc.aRow[:]
+		}
+		acc, wApprox := &sc.acc, &sc.w
+		*acc = NttElement{}
 		for j := 0; j < L65; j++ {
-			acc = PolyAdd(acc, NttMul(pk.a[i*L65+j], zNTT[j]))
+			nttMulAdd(acc, &row[j], &zNTT[j])
 		}
-		ct1 := NttMul(cNTT, t1NTT[i])
-		acc = PolySub(acc, ct1)
-		wApprox := InvNTT(acc)
+		nttMulSub(acc, cNTT, &t1NTT[i])
+		invNTTTo(wApprox, acc)
 
 		// Use hints to recover w1
 		for j := 0; j < N; j++ {
 			w1[i][j] = UseHint(hints[i][j], wApprox[j], Gamma2QMinus1Div32)
 		}
 
-		h.Write(PackW1_4(w1[i]))
+		h.Write(AppendW1_4(w1Buf[:0], w1[i]))
 	}
 
 	// Verify c~ = H(mu || w1)
@@ -590,7 +1540,10 @@ func (pk *PublicKey65) verifyInternal(sig, mPrime []byte) bool {
 	for i := range cTilde {
 		diff |= cTilde[i] ^ cTildeCheck[i]
 	}
-	return diff == 0
+	if diff != 0 {
+		return ErrMismatch
+	}
+	return nil
 }
 
 // Sign signs digest with the key pair's private key.
@@ -609,3 +1562,14 @@ func (key *Key65) SignMessage(rand io.Reader, msg []byte, opts crypto.SignerOpts
 func (key *Key65) SignWithContext(rand io.Reader, message, context []byte) ([]byte, error) {
 	return key.PrivateKey65.SignWithContext(rand, message, context)
 }
+
+// AppendSign signs a message with an optional context string using the key
+// pair and appends the signature to dst.
+func (key *Key65) AppendSign(dst []byte, rand io.Reader, message, context []byte) ([]byte, error) {
+	return key.PrivateKey65.AppendSign(dst, rand, message, context)
+}
+
+// SignPreHash signs a message digest with HashML-DSA using the key pair.
+func (key *Key65) SignPreHash(rand io.Reader, digest []byte, ph crypto.Hash, context []byte) ([]byte, error) {
+	return key.PrivateKey65.SignPreHash(rand, digest, ph, context)
+}
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"crypto"
 	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
 	"testing"
 )
 
@@ -206,6 +209,56 @@ func TestSignWithSignerOpts65(t *testing.T) {
 	}
 }
 
+func TestSignWithSignerOpts44(t *testing.T) {
+	key, err := GenerateKey44(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey44 failed: %v", err)
+	}
+
+	message := []byte("hello, world!")
+	context := []byte("test context")
+
+	var signer crypto.Signer = key
+	sig, err := signer.Sign(rand.Reader, message, &SignerOpts{Context: context})
+	if err != nil {
+		t.Fatalf("Sign with SignerOpts failed: %v", err)
+	}
+	if !key.PublicKey().Verify(sig, message, context) {
+		t.Error("Verify returned false for valid signature with context via SignerOpts")
+	}
+
+	// A nil opts signs with an empty context
+	sig, err = signer.Sign(rand.Reader, message, nil)
+	if err != nil {
+		t.Fatalf("Sign with nil opts failed: %v", err)
+	}
+	if !key.PublicKey().Verify(sig, message, nil) {
+		t.Error("Verify returned false for valid signature without context")
+	}
+}
+
+func TestSignWithSignerOpts87(t *testing.T) {
+	key, err := GenerateKey87(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey87 failed: %v", err)
+	}
+
+	message := []byte("hello, world!")
+	context := []byte("test context")
+
+	var signer crypto.Signer = &key.PrivateKey87
+	sig, err := signer.Sign(rand.Reader, message, &SignerOpts{Context: context})
+	if err != nil {
+		t.Fatalf("Sign with SignerOpts failed: %v", err)
+	}
+	if !key.PublicKey().Verify(sig, message, context) {
+		t.Error("Verify returned false for valid signature with context via SignerOpts")
+	}
+	if !signer.Public().(*PublicKey87).Equal(key.PublicKey()) {
+		t.Error("Public() does not match the key pair's public key")
+	}
+}
+
 func TestSignRejectsPreHashed(t *testing.T) {
 	key, err := GenerateKey65(rand.Reader)
 	if err != nil {
@@ -214,15 +267,79 @@ func TestSignRejectsPreHashed(t *testing.T) {
 
 	message := []byte("hello, world!")
 
-	// crypto.SHA256 has HashFunc() != 0, so it should be rejected
+	// crypto.SHA256 has HashFunc() != 0, so Sign expects a digest and
+	// should reject the message
 	_, err = key.Sign(rand.Reader, message, crypto.SHA256)
 	if err == nil {
-		t.Error("Sign should reject pre-hashed SignerOpts")
+		t.Error("Sign should reject pre-hashed SignerOpts with a message")
 	}
 
-	_, err = key.SignMessage(rand.Reader, message, crypto.SHA256)
+	// MD5 is not an approved pre-hash function
+	_, err = key.SignMessage(rand.Reader, message, crypto.MD5)
 	if err == nil {
-		t.Error("SignMessage should reject pre-hashed SignerOpts")
+		t.Error("SignMessage should reject unsupported pre-hashed SignerOpts")
+	}
+}
+
+func TestSignWithHashFunc(t *testing.T) {
+	key44, err := GenerateKey44(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey44 failed: %v", err)
+	}
+	key65, err := GenerateKey65(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey65 failed: %v", err)
+	}
+	key87, err := GenerateKey87(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey87 failed: %v", err)
+	}
+
+	message := []byte("hello, world!")
+	digest := sha256.Sum256(message)
+
+	type messageSigner interface {
+		crypto.Signer
+		SignMessage(rand io.Reader, msg []byte, opts crypto.SignerOpts) ([]byte, error)
+	}
+	type preHashVerifier interface {
+		VerifyWithOpts(sig, message []byte, opts crypto.SignerOpts) bool
+		VerifyPreHash(sig, digest []byte, ph crypto.Hash, context []byte) bool
+	}
+	for _, key := range []messageSigner{key44, key65, key87, &key44.PrivateKey44, &key87.PrivateKey87} {
+		pk := key.Public().(preHashVerifier)
+
+		// crypto.SHA256 has HashFunc() != 0, so Sign expects a digest.
+		sig, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+		if err != nil {
+			t.Fatalf("%T: Sign with crypto.SHA256 failed: %v", key, err)
+		}
+		if !pk.VerifyPreHash(sig, digest[:], crypto.SHA256, nil) {
+			t.Errorf("%T: Sign with crypto.SHA256 did not produce a HashML-DSA signature", key)
+		}
+		if !pk.VerifyWithOpts(sig, message, crypto.SHA256) {
+			t.Errorf("%T: VerifyWithOpts rejected a HashML-DSA signature", key)
+		}
+
+		// SignMessage hashes the message itself.
+		opts := &SignerOpts{Context: []byte("ctx"), Hash: crypto.SHA256}
+		sig, err = key.SignMessage(rand.Reader, message, opts)
+		if err != nil {
+			t.Fatalf("%T: SignMessage with a hash failed: %v", key, err)
+		}
+		if !pk.VerifyPreHash(sig, digest[:], crypto.SHA256, opts.Context) || !pk.VerifyWithOpts(sig, message, opts) {
+			t.Errorf("%T: SignMessage with a hash did not produce a HashML-DSA signature", key)
+		}
+		if pk.VerifyWithOpts(sig, message, &SignerOpts{Context: opts.Context}) {
+			t.Errorf("%T: HashML-DSA signature verified as pure ML-DSA", key)
+		}
+
+		if _, err := key.SignMessage(rand.Reader, message, crypto.MD5); !errors.Is(err, ErrUnsupportedPreHash) {
+			t.Errorf("%T: SignMessage with crypto.MD5: %v", key, err)
+		}
+		if _, err := key.Sign(rand.Reader, message, crypto.SHA256); err == nil {
+			t.Errorf("%T: Sign with crypto.SHA256 accepted a message that is not a digest", key)
+		}
 	}
 }
 
@@ -444,6 +561,16 @@ func BenchmarkSign65(b *testing.B) {
 	}
 }
 
+func BenchmarkSignPrecomputed65(b *testing.B) {
+	key, _ := GenerateKey65(rand.Reader)
+	key.Precompute()
+	message := []byte("benchmark message")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key.Sign(rand.Reader, message, nil)
+	}
+}
+
 func BenchmarkSign87(b *testing.B) {
 	key, _ := GenerateKey87(rand.Reader)
 	message := []byte("benchmark message")
@@ -475,6 +602,29 @@ func BenchmarkVerify65(b *testing.B) {
 	}
 }
 
+func BenchmarkVerifyPrecomputed65(b *testing.B) {
+	key, _ := GenerateKey65(rand.Reader)
+	message := []byte("benchmark message")
+	sig, _ := key.Sign(rand.Reader, message, nil)
+	pk := key.PublicKey()
+	pk.Precompute()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pk.Verify(sig, message, nil)
+	}
+}
+
+func BenchmarkVerifyCompact65(b *testing.B) {
+	key, _ := GenerateKey65(rand.Reader)
+	message := []byte("benchmark message")
+	sig, _ := key.Sign(rand.Reader, message, nil)
+	pk, _ := NewPublicKey65Compact(key.PublicKey().Bytes())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pk.Verify(sig, message, nil)
+	}
+}
+
 func BenchmarkVerify87(b *testing.B) {
 	key, _ := GenerateKey87(rand.Reader)
 	message := []byte("benchmark message")
@@ -485,3 +635,569 @@ func BenchmarkVerify87(b *testing.B) {
 		pk.Verify(sig, message, nil)
 	}
 }
+
+func TestRegenerate(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	old := key.PrivateKeyBytes()
+
+	seed := make([]byte, SeedSize)
+	for i := range seed {
+		seed[i] = byte(255 - i)
+	}
+	want, _ := NewKey65(seed)
+
+	ptr := key
+	if err := key.Regenerate(seed); err != nil {
+		t.Fatalf("Regenerate failed: %v", err)
+	}
+	if key != ptr {
+		t.Error("Regenerate changed the key pointer")
+	}
+	if !bytes.Equal(key.PrivateKeyBytes(), want.PrivateKeyBytes()) {
+		t.Error("Regenerate did not derive the same key as NewKey65")
+	}
+	if bytes.Equal(key.PrivateKeyBytes(), old) {
+		t.Error("Regenerate left the old key in place")
+	}
+
+	// Regenerating from the key's own seed must be a no-op.
+	if err := key.Regenerate(key.seed[:]); err != nil {
+		t.Fatalf("Regenerate from own seed failed: %v", err)
+	}
+	if !bytes.Equal(key.PrivateKeyBytes(), want.PrivateKeyBytes()) {
+		t.Error("Regenerate from an aliased seed changed the key")
+	}
+
+	if err := key.Regenerate(seed[:16]); err == nil {
+		t.Error("Regenerate accepted a short seed")
+	}
+
+	key44, _ := GenerateKey44(rand.Reader)
+	want44, _ := NewKey44(seed)
+	if err := key44.Regenerate(seed); err != nil || !bytes.Equal(key44.PrivateKeyBytes(), want44.PrivateKeyBytes()) {
+		t.Error("ML-DSA-44 Regenerate mismatch")
+	}
+	key87, _ := GenerateKey87(rand.Reader)
+	want87, _ := NewKey87(seed)
+	if err := key87.Regenerate(seed); err != nil || !bytes.Equal(key87.PrivateKeyBytes(), want87.PrivateKeyBytes()) {
+		t.Error("ML-DSA-87 Regenerate mismatch")
+	}
+}
+
+func TestPrivateKeyPublicKey(t *testing.T) {
+	key, err := GenerateKey44(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey44 failed: %v", err)
+	}
+	sk, err := NewPrivateKey44(key.PrivateKeyBytes())
+	if err != nil {
+		t.Fatalf("NewPrivateKey44 failed: %v", err)
+	}
+	pk := sk.PublicKey()
+	if !pk.Equal(key.PublicKey()) {
+		t.Error("PublicKey() does not match the key pair's public key")
+	}
+	if sk.PublicKey() != pk {
+		t.Error("PublicKey() was not cached")
+	}
+	if sk.Public() != crypto.PublicKey(pk) {
+		t.Error("Public() does not return the cached public key")
+	}
+
+	sk65, _ := GenerateKey65(rand.Reader)
+	if !sk65.PrivateKey65.PublicKey().Equal(sk65.PublicKey()) {
+		t.Error("ML-DSA-65 PublicKey() mismatch")
+	}
+	sk87, _ := GenerateKey87(rand.Reader)
+	if !sk87.PrivateKey87.PublicKey().Equal(sk87.PublicKey()) {
+		t.Error("ML-DSA-87 PublicKey() mismatch")
+	}
+}
+
+func TestPrivateKeyEqual(t *testing.T) {
+	key1, _ := GenerateKey65(rand.Reader)
+	key2, _ := GenerateKey65(rand.Reader)
+	same, _ := NewKey65(key1.Bytes())
+	sk1, _ := NewPrivateKey65(key1.PrivateKeyBytes())
+	key44, _ := GenerateKey44(rand.Reader)
+
+	if !key1.Equal(same) || !key1.Equal(sk1) || !sk1.Equal(key1) || !sk1.Equal(sk1) {
+		t.Error("Equal returned false for the same key")
+	}
+	if key1.Equal(key2) || sk1.Equal(key2) || sk1.Equal(&key2.PrivateKey65) {
+		t.Error("Equal returned true for different keys")
+	}
+	if key1.Equal(key44) || sk1.Equal(&key44.PrivateKey44) || key44.Equal(key1) {
+		t.Error("Equal returned true across parameter sets")
+	}
+
+	key87, _ := GenerateKey87(rand.Reader)
+	sk87, _ := NewPrivateKey87(key87.PrivateKeyBytes())
+	if !key87.Equal(sk87) || !sk87.Equal(key87) {
+		t.Error("ML-DSA-87 Equal returned false for the same key")
+	}
+}
+
+func TestVerifyWithOpts(t *testing.T) {
+	key, _ := GenerateKey87(rand.Reader)
+	message := []byte("hello, world!")
+	opts := &SignerOpts{Context: []byte("test context")}
+
+	sig, err := key.Sign(rand.Reader, message, opts)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	pk := key.PublicKey()
+	if !pk.VerifyWithOpts(sig, message, opts) {
+		t.Error("VerifyWithOpts returned false for the opts used to sign")
+	}
+	if pk.VerifyWithOpts(sig, message, nil) {
+		t.Error("VerifyWithOpts returned true without the context")
+	}
+	if pk.VerifyWithOpts(sig, message, crypto.SHA256) {
+		t.Error("VerifyWithOpts accepted a pure signature as HashML-DSA")
+	}
+
+	key44, _ := GenerateKey44(rand.Reader)
+	sig, _ = key44.Sign(rand.Reader, message, nil)
+	if !key44.PublicKey().VerifyWithOpts(sig, message, nil) {
+		t.Error("ML-DSA-44 VerifyWithOpts returned false with nil opts")
+	}
+	key65, _ := GenerateKey65(rand.Reader)
+	sig, _ = key65.Sign(rand.Reader, message, opts)
+	if !key65.PublicKey().VerifyWithOpts(sig, message, opts) {
+		t.Error("ML-DSA-65 VerifyWithOpts returned false")
+	}
+}
+
+func TestVerifyError(t *testing.T) {
+	key, _ := GenerateKey44(rand.Reader)
+	pk := key.PublicKey()
+	message := []byte("hello, world!")
+	sig, _ := key.SignWithContext(rand.Reader, message, nil)
+
+	if err := pk.VerifyError(sig, message, nil); err != nil {
+		t.Fatalf("VerifyError failed for a valid signature: %v", err)
+	}
+	if err := pk.VerifyError(sig[1:], message, nil); !errors.Is(err, ErrBadSignatureLength) {
+		t.Errorf("short signature: got %v", err)
+	}
+	if err := pk.VerifyError(sig, []byte("other"), nil); !errors.Is(err, ErrMismatch) {
+		t.Errorf("wrong message: got %v", err)
+	}
+
+	// Set the first z coefficient to gamma1, which is out of range.
+	bad := bytes.Clone(sig)
+	off := Lambda128 / 4
+	bad[off], bad[off+1], bad[off+2] = 0, 0, 0
+	if err := pk.VerifyError(bad, message, nil); !errors.Is(err, ErrNormExceeded) {
+		t.Errorf("out-of-range z: got %v", err)
+	}
+
+	// Hint indices for the first polynomial must not exceed omega.
+	bad = bytes.Clone(sig)
+	bad[SignatureSize44-K44] = Omega80 + 1
+	if err := pk.VerifyError(bad, message, nil); !errors.Is(err, ErrHintEncoding) {
+		t.Errorf("malformed hint: got %v", err)
+	}
+
+	key87, _ := GenerateKey87(rand.Reader)
+	sig, _ = key87.SignWithContext(rand.Reader, message, nil)
+	if err := key87.PublicKey().VerifyError(sig, message, []byte("ctx")); !errors.Is(err, ErrMismatch) {
+		t.Errorf("ML-DSA-87 wrong context: got %v", err)
+	}
+}
+
+func TestNewPrivateKeyFromSeed(t *testing.T) {
+	seed := make([]byte, SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+
+	key44, _ := NewKey44(seed)
+	sk44, err := NewPrivateKey44FromSeed(seed)
+	if err != nil {
+		t.Fatalf("NewPrivateKey44FromSeed failed: %v", err)
+	}
+	if !bytes.Equal(sk44.Bytes(), key44.PrivateKeyBytes()) || !sk44.PublicKey().Equal(key44.PublicKey()) {
+		t.Error("ML-DSA-44 private key from seed does not match the key pair")
+	}
+
+	key65, _ := NewKey65(seed)
+	sk65, err := NewPrivateKey65FromSeed(seed)
+	if err != nil {
+		t.Fatalf("NewPrivateKey65FromSeed failed: %v", err)
+	}
+	if !bytes.Equal(sk65.Bytes(), key65.PrivateKeyBytes()) || !sk65.PublicKey().Equal(key65.PublicKey()) {
+		t.Error("ML-DSA-65 private key from seed does not match the key pair")
+	}
+
+	key87, _ := NewKey87(seed)
+	sk87, err := NewPrivateKey87FromSeed(seed)
+	if err != nil {
+		t.Fatalf("NewPrivateKey87FromSeed failed: %v", err)
+	}
+	if !bytes.Equal(sk87.Bytes(), key87.PrivateKeyBytes()) || !sk87.PublicKey().Equal(key87.PublicKey()) {
+		t.Error("ML-DSA-87 private key from seed does not match the key pair")
+	}
+	sig, err := sk87.SignWithContext(rand.Reader, []byte("m"), nil)
+	if err != nil || !key87.PublicKey().Verify(sig, []byte("m"), nil) {
+		t.Error("ML-DSA-87 private key from seed does not sign correctly")
+	}
+
+	if _, err := NewPrivateKey65FromSeed(seed[1:]); err == nil {
+		t.Error("NewPrivateKey65FromSeed accepted a short seed")
+	}
+}
+
+// countingReader counts the bytes read from it.
+type countingReader struct {
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.n += len(p)
+	return rand.Read(p)
+}
+
+func TestSignerOptsRand(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	message := []byte("hello, world!")
+
+	drbg := &countingReader{}
+	other := &countingReader{}
+	opts := &SignerOpts{Context: []byte("ctx"), Rand: drbg}
+	sig, err := key.Sign(other, message, opts)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if drbg.n != 32 || other.n != 0 {
+		t.Errorf("read %d bytes from opts.Rand and %d from rand, want 32 and 0", drbg.n, other.n)
+	}
+	if !key.PublicKey().VerifyWithOpts(sig, message, opts) {
+		t.Error("signature with opts.Rand does not verify")
+	}
+
+	key44, _ := GenerateKey44(rand.Reader)
+	drbg.n = 0
+	if _, err := key44.SignMessage(nil, message, opts); err != nil || drbg.n != 32 {
+		t.Errorf("ML-DSA-44 SignMessage did not use opts.Rand: %v", err)
+	}
+	key87, _ := GenerateKey87(rand.Reader)
+	drbg.n = 0
+	if _, err := key87.Sign(nil, message, opts); err != nil || drbg.n != 32 {
+		t.Errorf("ML-DSA-87 Sign did not use opts.Rand: %v", err)
+	}
+}
+
+func TestNilRand(t *testing.T) {
+	key44, err := GenerateKey44(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey44(nil) failed: %v", err)
+	}
+	key65, err := GenerateKey65(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey65(nil) failed: %v", err)
+	}
+	key87, err := GenerateKey87(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey87(nil) failed: %v", err)
+	}
+
+	message := []byte("hello, world!")
+	for _, key := range []PrivateKey{key44, key65, key87} {
+		sig, err := key.Sign(nil, message, nil)
+		if err != nil {
+			t.Fatalf("%s: Sign(nil) failed: %v", key.Scheme().Name(), err)
+		}
+		if !key.Public().(PublicKey).Verify(sig, message, nil) {
+			t.Errorf("%s: signature with nil rand does not verify", key.Scheme().Name())
+		}
+	}
+}
+
+func TestAppendSign(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	message := []byte("hello, world!")
+	context := []byte("ctx")
+
+	prefix := []byte("prefix")
+	buf := make([]byte, len(prefix), len(prefix)+SignatureSize65)
+	copy(buf, prefix)
+	out, err := key.AppendSign(buf, rand.Reader, message, context)
+	if err != nil {
+		t.Fatalf("AppendSign failed: %v", err)
+	}
+	if len(out) != len(prefix)+SignatureSize65 || !bytes.Equal(out[:len(prefix)], prefix) {
+		t.Fatal("AppendSign did not preserve the prefix")
+	}
+	if &out[0] != &buf[0] {
+		t.Error("AppendSign reallocated despite sufficient capacity")
+	}
+	if !key.PublicKey().Verify(out[len(prefix):], message, context) {
+		t.Error("appended signature does not verify")
+	}
+
+	key44, _ := GenerateKey44(rand.Reader)
+	out, err = key44.AppendSign(prefix, rand.Reader, message, nil)
+	if err != nil || !key44.PublicKey().Verify(out[len(prefix):], message, nil) {
+		t.Errorf("ML-DSA-44 AppendSign failed: %v", err)
+	}
+	key87, _ := GenerateKey87(rand.Reader)
+	out, err = key87.AppendSign(nil, rand.Reader, message, nil)
+	if err != nil || !key87.PublicKey().Verify(out, message, nil) {
+		t.Errorf("ML-DSA-87 AppendSign failed: %v", err)
+	}
+}
+
+func TestAppendSignAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are unreliable with the race detector")
+	}
+	if auditEnabled {
+		t.Skip("allocation counts are unreliable with audit hooks")
+	}
+	message := bytes.Repeat([]byte("a long message "), 1<<12)
+	context := []byte("ctx")
+	for _, s := range Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		signer := key.(interface {
+			AppendSign(dst []byte, rand io.Reader, message, context []byte) ([]byte, error)
+		})
+		buf := make([]byte, 0, s.SignatureSize())
+		allocs := testing.AllocsPerRun(10, func() {
+			if _, err := signer.AppendSign(buf, nil, message, context); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("%s: AppendSign performed %v allocations, want 0", s.Name(), allocs)
+		}
+	}
+}
+
+func TestVerifyAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are unreliable with the race detector")
+	}
+	message := bytes.Repeat([]byte("a long message "), 1<<12)
+	context := []byte("ctx")
+	for _, s := range Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		sig, err := key.SignWithContext(rand.Reader, message, context)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk := key.Public().(PublicKey)
+		allocs := testing.AllocsPerRun(10, func() {
+			if !pk.Verify(sig, message, context) {
+				t.Fatal("signature does not verify")
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("%s: Verify performed %v allocations, want 0", s.Name(), allocs)
+		}
+	}
+}
+
+func TestPrecompute(t *testing.T) {
+	message, context := []byte("message"), []byte("ctx")
+	for _, s := range Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		want, err := key.SignWithContext(DeterministicRand, message, context)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key.(interface{ Precompute() }).Precompute()
+		for i := 0; i < 2; i++ {
+			got, err := key.SignWithContext(DeterministicRand, message, context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s: signature changed after Precompute", s.Name())
+			}
+		}
+	}
+
+	key, _ := GenerateKey65(rand.Reader)
+	key.Precompute()
+	secret := key.ntt.Load()
+	key.Destroy()
+	if key.ntt.Load() != nil || *secret != (secretNTT65{}) {
+		t.Error("Destroy did not wipe the precomputed transforms")
+	}
+	key.Precompute()
+	if key.ntt.Load() != nil {
+		t.Error("Precompute cached the transforms of a destroyed key")
+	}
+}
+
+func TestPrecomputePublicKey(t *testing.T) {
+	message, context := []byte("message"), []byte("ctx")
+	for _, s := range Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		sig, err := key.SignWithContext(rand.Reader, message, context)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk, err := s.UnmarshalPublicKey(key.Public().(PublicKey).Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk.(interface{ Precompute() }).Precompute()
+		for i := 0; i < 2; i++ {
+			if !pk.Verify(sig, message, context) {
+				t.Errorf("%s: signature does not verify after Precompute", s.Name())
+			}
+		}
+		if pk.Verify(sig, message, []byte("other")) {
+			t.Errorf("%s: signature verifies with another context after Precompute", s.Name())
+		}
+	}
+
+	key, _ := GenerateKey44(rand.Reader)
+	other, _ := GenerateKey44(rand.Reader)
+	pk := other.PublicKey()
+	pk.Precompute()
+	text, _ := key.PublicKey().MarshalText()
+	if err := pk.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	sig, _ := key.SignWithContext(rand.Reader, message, nil)
+	if !pk.Verify(sig, message, nil) {
+		t.Error("decoding into a precomputed key kept the transforms of the old key")
+	}
+}
+
+func TestDestroy(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	pk := key.PublicKey()
+	key.Destroy()
+
+	if !bytes.Equal(key.Bytes(), make([]byte, SeedSize)) {
+		t.Error("Destroy did not wipe the seed")
+	}
+	if key.key != [32]byte{} || key.s1 != [L65]RingElement{} || key.s2 != [K65]RingElement{} || key.t0 != [K65]RingElement{} {
+		t.Error("Destroy did not wipe the secret components")
+	}
+	if _, err := key.Sign(rand.Reader, []byte("m"), nil); !errors.Is(err, ErrKeyDestroyed) {
+		t.Errorf("Sign after Destroy: %v", err)
+	}
+	if !key.PrivateKey65.PublicKey().Equal(pk) {
+		t.Error("Destroy changed the public key")
+	}
+
+	seed := make([]byte, SeedSize)
+	if err := key.Regenerate(seed); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := key.Sign(rand.Reader, []byte("m"), nil); err != nil {
+		t.Errorf("Sign after Regenerate: %v", err)
+	}
+
+	sk44, _ := NewPrivateKey44FromSeed(seed)
+	sk44.Destroy()
+	if _, err := sk44.SignWithContext(rand.Reader, []byte("m"), nil); !errors.Is(err, ErrKeyDestroyed) {
+		t.Errorf("ML-DSA-44 SignWithContext after Destroy: %v", err)
+	}
+	key87, _ := NewKey87(seed)
+	key87.Destroy()
+	if _, err := key87.AppendSign(nil, rand.Reader, []byte("m"), nil); !errors.Is(err, ErrKeyDestroyed) {
+		t.Errorf("ML-DSA-87 AppendSign after Destroy: %v", err)
+	}
+}
+
+func TestWorkspace(t *testing.T) {
+	message, context := []byte("message"), []byte("ctx")
+	key, _ := GenerateKey65(rand.Reader)
+	ws := new(Workspace65)
+
+	buf := make([]byte, 3, 3+SignatureSize65)
+	sig, err := ws.AppendSign(&key.PrivateKey65, buf, rand.Reader, message, context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sig) != 3+SignatureSize65 || &sig[0] != &buf[0] {
+		t.Fatal("AppendSign did not append in place")
+	}
+	sig = sig[3:]
+	if !key.PublicKey().Verify(sig, message, context) {
+		t.Error("workspace signature does not verify")
+	}
+	if ws.sign != (signScratch65{}) {
+		t.Error("AppendSign left secret values in the workspace")
+	}
+
+	pk, err := ws.ParsePublicKey(key.PublicKey().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Equal(key.PublicKey()) {
+		t.Error("ParsePublicKey returned the wrong key")
+	}
+	if !ws.Verify(pk, sig, message, context) || ws.Verify(pk, sig, message, nil) {
+		t.Error("workspace verification is incorrect")
+	}
+
+	other, _ := GenerateKey65(rand.Reader)
+	if pk2, _ := ws.ParsePublicKey(other.PublicKey().Bytes()); pk2 != pk || ws.Verify(pk2, sig, message, context) {
+		t.Error("ParsePublicKey did not reuse the workspace")
+	}
+	if _, err := ws.ParsePublicKey(sig[:10]); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("ParsePublicKey of a short input: %v", err)
+	}
+}
+
+func TestWorkspaceAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are unreliable with the race detector")
+	}
+	if auditEnabled {
+		t.Skip("allocation counts are unreliable with audit hooks")
+	}
+	message, context := []byte("message"), []byte("ctx")
+	checks := map[string]func() func(){
+		"ML-DSA-44": func() func() {
+			key, _ := GenerateKey44(rand.Reader)
+			pub, ws := key.PublicKey().Bytes(), new(Workspace44)
+			buf := make([]byte, 0, SignatureSize44)
+			return func() {
+				sig, _ := ws.AppendSign(&key.PrivateKey44, buf, nil, message, context)
+				pk, _ := ws.ParsePublicKey(pub)
+				if !ws.Verify(pk, sig, message, context) {
+					t.Fatal("ML-DSA-44: verification failed")
+				}
+			}
+		},
+		"ML-DSA-65": func() func() {
+			key, _ := GenerateKey65(rand.Reader)
+			pub, ws := key.PublicKey().Bytes(), new(Workspace65)
+			buf := make([]byte, 0, SignatureSize65)
+			return func() {
+				sig, _ := ws.AppendSign(&key.PrivateKey65, buf, nil, message, context)
+				pk, _ := ws.ParsePublicKey(pub)
+				if !ws.Verify(pk, sig, message, context) {
+					t.Fatal("ML-DSA-65: verification failed")
+				}
+			}
+		},
+		"ML-DSA-87": func() func() {
+			key, _ := GenerateKey87(rand.Reader)
+			pub, ws := key.PublicKey().Bytes(), new(Workspace87)
+			buf := make([]byte, 0, SignatureSize87)
+			return func() {
+				sig, _ := ws.AppendSign(&key.PrivateKey87, buf, nil, message, context)
+				pk, _ := ws.ParsePublicKey(pub)
+				if !ws.Verify(pk, sig, message, context) {
+					t.Fatal("ML-DSA-87: verification failed")
+				}
+			}
+		},
+	}
+	for name, setup := range checks {
+		if allocs := testing.AllocsPerRun(10, setup()); allocs != 0 {
+			t.Errorf("%s: workspace operations performed %v allocations, want 0", name, allocs)
+		}
+	}
+}
@@ -0,0 +1,535 @@
+// Package mldsacms produces and verifies CMS SignedData (RFC 5652) signed
+// with ML-DSA, as profiled in RFC 9882.
+//
+// Signatures use pure ML-DSA with an empty context. The message digest in
+// the signed attributes is SHA-512, which RFC 9882 requires every
+// implementation to support; SHA-256 and SHA-384 digests are also accepted
+// when verifying. Content may be attached or detached.
+//
+// Verify checks signatures only: the signer certificates it returns must
+// still be validated against a trust anchor by the caller.
+package mldsacms
+
+import (
+	"bytes"
+	"crypto"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/KarpelesLab/mldsa"
+	"github.com/KarpelesLab/mldsa/mldsax509"
+)
+
+var (
+	// ErrInvalidSignature is returned when a signer's signature or message
+	// digest does not verify.
+	ErrInvalidSignature = errors.New("mldsacms: invalid signature")
+
+	// ErrSignerNotFound is returned when no certificate matches a signer.
+	ErrSignerNotFound = errors.New("mldsacms: signer certificate not found")
+
+	// ErrMissingContent is returned when verifying a detached signature
+	// without the content.
+	ErrMissingContent = errors.New("mldsacms: detached signature requires the content")
+)
+
+var (
+	oidData          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidContentType   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSigningTime   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+
+	oidSigningCertificateV2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+)
+
+// digestAlgorithms lists the accepted digest algorithms. The first one is
+// used for signing.
+var digestAlgorithms = []struct {
+	oid  asn1.ObjectIdentifier
+	hash crypto.Hash
+}{
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}, crypto.SHA512},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}, crypto.SHA384},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}, crypto.SHA256},
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapsulatedContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+type encapsulatedContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     asn1.RawValue `asn1:"optional,explicit,tag:0"`
+}
+
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+// essCertIDv2 identifies a certificate in the signing-certificate-v2
+// attribute of RFC 5035. The hash algorithm is omitted when it is the
+// default SHA-256.
+type essCertIDv2 struct {
+	HashAlgorithm pkix.AlgorithmIdentifier `asn1:"optional"`
+	CertHash      []byte
+	IssuerSerial  issuerSerial `asn1:"optional"`
+}
+
+type issuerSerial struct {
+	Issuer       []asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type signingCertificateV2 struct {
+	Certs []essCertIDv2
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+// SignOptions configures Sign.
+type SignOptions struct {
+	// Detached leaves the content out of the SignedData.
+	Detached bool
+
+	// ContentType is the type of the signed content. The default is id-data.
+	ContentType asn1.ObjectIdentifier
+
+	// SigningTime, if not zero, is included as a signed attribute.
+	SigningTime time.Time
+
+	// SigningCertificate includes the signing-certificate-v2 attribute of
+	// RFC 5035, binding the signature to the SHA-256 hash of cert. RFC 3161
+	// timestamps and CAdES signatures require it.
+	SigningCertificate bool
+
+	// SubjectKeyID identifies the signer by the SubjectKeyId of its
+	// certificate instead of its issuer and serial number.
+	SubjectKeyID bool
+
+	// NoSignedAttributes signs the content itself, without signed
+	// attributes. It requires the default content type.
+	NoSignedAttributes bool
+
+	// Certificates are additional certificates to embed, such as the
+	// intermediates of the signer's chain.
+	Certificates []*x509.Certificate
+
+	// OmitCertificates leaves all certificates out, including the
+	// signer's, for verifiers that already hold them.
+	OmitCertificates bool
+
+	// Rand is the randomness source for hedged signing. If nil,
+	// crypto/rand.Reader is used.
+	Rand io.Reader
+}
+
+// Sign signs content with key and returns a DER ContentInfo holding the
+// SignedData. cert is the signer's certificate; it must hold the public key
+// of key and is embedded in the output unless opts.OmitCertificates is set.
+func Sign(content []byte, cert *x509.Certificate, key mldsa.PrivateKey, opts *SignOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &SignOptions{}
+	}
+	rand := opts.Rand
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	params, err := mldsa.ParseParameterSet(key.Scheme().Name())
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := mldsax509.ParseCertificate(cert.Raw)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.MLDSAPublicKey == nil || !parsed.MLDSAPublicKey.Equal(key.Public()) {
+		return nil, errors.New("mldsacms: certificate does not match the signing key")
+	}
+	contentType := opts.ContentType
+	if contentType == nil {
+		contentType = oidData
+	}
+
+	si := signerInfo{
+		Version:            1,
+		DigestAlgorithm:    pkix.AlgorithmIdentifier{Algorithm: digestAlgorithms[0].oid},
+		SignatureAlgorithm: params.AlgorithmIdentifier(),
+	}
+	if opts.SubjectKeyID {
+		if len(cert.SubjectKeyId) == 0 {
+			return nil, errors.New("mldsacms: certificate has no SubjectKeyId")
+		}
+		si.Version = 3
+		si.SID = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, Bytes: cert.SubjectKeyId}
+	} else {
+		ias, err := asn1.Marshal(issuerAndSerialNumber{
+			Issuer:       asn1.RawValue{FullBytes: cert.RawIssuer},
+			SerialNumber: cert.SerialNumber,
+		})
+		if err != nil {
+			return nil, err
+		}
+		si.SID = asn1.RawValue{FullBytes: ias}
+	}
+
+	signed := content
+	if opts.NoSignedAttributes {
+		if !contentType.Equal(oidData) {
+			return nil, errors.New("mldsacms: signed attributes are required for content types other than id-data")
+		}
+	} else {
+		var signingCert *x509.Certificate
+		if opts.SigningCertificate {
+			signingCert = cert
+		}
+		attrs, err := signedAttributes(content, contentType, opts.SigningTime, signingCert)
+		if err != nil {
+			return nil, err
+		}
+		// The signature covers the DER SET OF, which is stored with an
+		// implicit [0] tag.
+		signed = attrs
+		var set asn1.RawValue
+		if _, err := asn1.Unmarshal(attrs, &set); err != nil {
+			return nil, err
+		}
+		si.SignedAttrs = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: set.Bytes}
+	}
+	si.Signature, err = key.SignWithContext(rand, signed, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []byte
+	certs = append(certs, cert.Raw...)
+	for _, c := range opts.Certificates {
+		certs = append(certs, c.Raw...)
+	}
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{si.DigestAlgorithm},
+		EncapContentInfo: encapsulatedContentInfo{EContentType: contentType},
+		SignerInfos:      []signerInfo{si},
+	}
+	if !opts.OmitCertificates {
+		sd.Certificates = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certs}
+	}
+	if si.Version == 3 || !contentType.Equal(oidData) {
+		sd.Version = 3
+	}
+	if !opts.Detached {
+		eContent, err := asn1.Marshal(content)
+		if err != nil {
+			return nil, err
+		}
+		sd.EncapContentInfo.EContent = explicit(eContent)
+	}
+	sdDER, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     explicit(sdDER),
+	})
+}
+
+// explicit wraps der in an [0] EXPLICIT tag. encoding/asn1 ignores the
+// explicit parameter when marshaling a RawValue.
+func explicit(der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der}
+}
+
+// signedAttributes returns the DER SET OF the content-type, message-digest
+// and optional signing-time and signing-certificate-v2 attributes.
+func signedAttributes(content []byte, contentType asn1.ObjectIdentifier, signingTime time.Time, signingCert *x509.Certificate) ([]byte, error) {
+	h := digestAlgorithms[0].hash.New()
+	h.Write(content)
+	values := []struct {
+		oid asn1.ObjectIdentifier
+		val any
+	}{
+		{oidContentType, contentType},
+		{oidMessageDigest, h.Sum(nil)},
+	}
+	if !signingTime.IsZero() {
+		values = append(values, struct {
+			oid asn1.ObjectIdentifier
+			val any
+		}{oidSigningTime, signingTime.UTC()})
+	}
+	if signingCert != nil {
+		certHash := sha256.Sum256(signingCert.Raw)
+		values = append(values, struct {
+			oid asn1.ObjectIdentifier
+			val any
+		}{oidSigningCertificateV2, signingCertificateV2{Certs: []essCertIDv2{{
+			CertHash: certHash[:],
+			IssuerSerial: issuerSerial{
+				Issuer:       []asn1.RawValue{{Class: asn1.ClassContextSpecific, Tag: 4, IsCompound: true, Bytes: signingCert.RawIssuer}},
+				SerialNumber: signingCert.SerialNumber,
+			},
+		}}}})
+	}
+	attrs := make([]attribute, 0, len(values))
+	for _, v := range values {
+		der, err := asn1.Marshal(v.val)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, attribute{Type: v.oid, Values: []asn1.RawValue{{FullBytes: der}}})
+	}
+	return asn1.MarshalWithParams(attrs, "set")
+}
+
+// SignedData is a parsed CMS SignedData.
+type SignedData struct {
+	// ContentType is the type of the signed content.
+	ContentType asn1.ObjectIdentifier
+
+	// Content is the attached content, or nil for a detached signature.
+	Content []byte
+
+	// Certificates are the certificates embedded in the SignedData.
+	Certificates []*mldsax509.Certificate
+
+	signerInfos []signerInfo
+}
+
+// Parse parses a DER ContentInfo holding a SignedData.
+func Parse(der []byte) (*SignedData, error) {
+	var ci contentInfo
+	if rest, err := asn1.Unmarshal(der, &ci); err != nil {
+		return nil, err
+	} else if len(rest) != 0 {
+		return nil, errors.New("mldsacms: trailing data after ContentInfo")
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		return nil, fmt.Errorf("mldsacms: content type %v is not SignedData", ci.ContentType)
+	}
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return nil, err
+	}
+	out := &SignedData{
+		ContentType: sd.EncapContentInfo.EContentType,
+		signerInfos: sd.SignerInfos,
+	}
+	if len(sd.EncapContentInfo.EContent.FullBytes) != 0 {
+		var content []byte
+		if _, err := asn1.Unmarshal(sd.EncapContentInfo.EContent.Bytes, &content); err != nil {
+			return nil, err
+		}
+		out.Content = content
+		if out.Content == nil {
+			out.Content = []byte{}
+		}
+	}
+	for rest := sd.Certificates.Bytes; len(rest) > 0; {
+		var raw asn1.RawValue
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &raw); err != nil {
+			return nil, err
+		}
+		// Skip the other certificate formats of RFC 5652.
+		if raw.Class != asn1.ClassUniversal {
+			continue
+		}
+		cert, err := mldsax509.ParseCertificate(raw.FullBytes)
+		if err != nil {
+			return nil, err
+		}
+		out.Certificates = append(out.Certificates, cert)
+	}
+	return out, nil
+}
+
+// Verify verifies every signer of the SignedData and returns their
+// certificates. content is the detached content, and must be nil if the
+// content is attached. Signers are looked up in certs if any are given, and
+// in the embedded certificates otherwise.
+func (sd *SignedData) Verify(content []byte, certs ...*mldsax509.Certificate) ([]*mldsax509.Certificate, error) {
+	if sd.Content != nil {
+		if content != nil && !bytes.Equal(content, sd.Content) {
+			return nil, errors.New("mldsacms: detached content given for attached SignedData")
+		}
+		content = sd.Content
+	} else if content == nil {
+		return nil, ErrMissingContent
+	}
+	if len(certs) == 0 {
+		certs = sd.Certificates
+	}
+	if len(sd.signerInfos) == 0 {
+		return nil, errors.New("mldsacms: SignedData has no signers")
+	}
+	var signers []*mldsax509.Certificate
+	for _, si := range sd.signerInfos {
+		cert, err := findSigner(si, certs)
+		if err != nil {
+			return nil, err
+		}
+		if err := sd.verifySigner(si, cert, content); err != nil {
+			return nil, err
+		}
+		signers = append(signers, cert)
+	}
+	return signers, nil
+}
+
+// findSigner returns the certificate identified by the signer's sid.
+func findSigner(si signerInfo, certs []*mldsax509.Certificate) (*mldsax509.Certificate, error) {
+	for _, c := range certs {
+		if si.SID.Class == asn1.ClassContextSpecific && si.SID.Tag == 0 {
+			if len(c.SubjectKeyId) != 0 && bytes.Equal(c.SubjectKeyId, si.SID.Bytes) {
+				return c, nil
+			}
+			continue
+		}
+		var ias issuerAndSerialNumber
+		if _, err := asn1.Unmarshal(si.SID.FullBytes, &ias); err != nil {
+			return nil, err
+		}
+		if bytes.Equal(c.RawIssuer, ias.Issuer.FullBytes) && c.SerialNumber.Cmp(ias.SerialNumber) == 0 {
+			return c, nil
+		}
+	}
+	return nil, ErrSignerNotFound
+}
+
+// verifySigner checks the signed attributes and signature of si.
+func (sd *SignedData) verifySigner(si signerInfo, cert *mldsax509.Certificate, content []byte) error {
+	if cert.MLDSAPublicKey == nil {
+		return errors.New("mldsacms: signer certificate does not hold an ML-DSA key")
+	}
+	params, ph, err := mldsa.ParseAlgorithmIdentifier(si.SignatureAlgorithm)
+	if err != nil {
+		return err
+	}
+	if ph != 0 || params.String() != cert.MLDSAPublicKey.Scheme().Name() {
+		return fmt.Errorf("mldsacms: signature algorithm %v does not match the %s signer key", si.SignatureAlgorithm.Algorithm, cert.MLDSAPublicKey.Scheme().Name())
+	}
+
+	signed := content
+	if len(si.SignedAttrs.FullBytes) != 0 {
+		var hash crypto.Hash
+		for _, d := range digestAlgorithms {
+			if si.DigestAlgorithm.Algorithm.Equal(d.oid) {
+				hash = d.hash
+			}
+		}
+		if hash == 0 {
+			return fmt.Errorf("mldsacms: unsupported digest algorithm %v", si.DigestAlgorithm.Algorithm)
+		}
+		set, err := asn1.Marshal(asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: si.SignedAttrs.Bytes})
+		if err != nil {
+			return err
+		}
+		var attrs []attribute
+		if _, err := asn1.UnmarshalWithParams(set, &attrs, "set"); err != nil {
+			return err
+		}
+		var contentType asn1.ObjectIdentifier
+		var digest []byte
+		var signingCert *signingCertificateV2
+		for _, a := range attrs {
+			if len(a.Values) != 1 {
+				continue
+			}
+			switch {
+			case a.Type.Equal(oidContentType):
+				_, err = asn1.Unmarshal(a.Values[0].FullBytes, &contentType)
+			case a.Type.Equal(oidMessageDigest):
+				_, err = asn1.Unmarshal(a.Values[0].FullBytes, &digest)
+			case a.Type.Equal(oidSigningCertificateV2):
+				signingCert = new(signingCertificateV2)
+				_, err = asn1.Unmarshal(a.Values[0].FullBytes, signingCert)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if !contentType.Equal(sd.ContentType) {
+			return errors.New("mldsacms: content-type attribute does not match the content")
+		}
+		h := hash.New()
+		h.Write(content)
+		if subtle.ConstantTimeCompare(digest, h.Sum(nil)) != 1 {
+			return fmt.Errorf("%w: message digest mismatch", ErrInvalidSignature)
+		}
+		if signingCert != nil {
+			if err := checkSigningCertificate(signingCert, cert); err != nil {
+				return err
+			}
+		}
+		signed = set
+	} else if !sd.ContentType.Equal(oidData) {
+		return errors.New("mldsacms: signed attributes are required for content types other than id-data")
+	}
+
+	if !cert.MLDSAPublicKey.Verify(si.Signature, signed, nil) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// checkSigningCertificate checks that the first certificate of a
+// signing-certificate-v2 attribute, which identifies the signer, is cert.
+func checkSigningCertificate(sc *signingCertificateV2, cert *mldsax509.Certificate) error {
+	if len(sc.Certs) == 0 {
+		return errors.New("mldsacms: empty signing-certificate-v2 attribute")
+	}
+	id := sc.Certs[0]
+	hash := crypto.SHA256
+	if len(id.HashAlgorithm.Algorithm) != 0 {
+		hash = 0
+		for _, d := range digestAlgorithms {
+			if id.HashAlgorithm.Algorithm.Equal(d.oid) {
+				hash = d.hash
+			}
+		}
+		if hash == 0 {
+			return fmt.Errorf("mldsacms: unsupported signing certificate hash %v", id.HashAlgorithm.Algorithm)
+		}
+	}
+	h := hash.New()
+	h.Write(cert.Raw)
+	if !bytes.Equal(id.CertHash, h.Sum(nil)) {
+		return errors.New("mldsacms: signing-certificate-v2 attribute does not match the signer certificate")
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package mldsacms
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/mldsa"
+	"github.com/KarpelesLab/mldsa/mldsax509"
+)
+
+func newSigner(t *testing.T, s mldsa.Scheme, serial int64) (mldsa.PrivateKey, *mldsax509.Certificate) {
+	t.Helper()
+	key, err := s.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "CMS signer " + s.Name()},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := mldsax509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := mldsax509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, cert
+}
+
+func TestSignVerify(t *testing.T) {
+	content := []byte("firmware image v1.2.3")
+	for _, s := range mldsa.Schemes() {
+		key, cert := newSigner(t, s, 1)
+		for _, opts := range []SignOptions{
+			{},
+			{Detached: true},
+			{SubjectKeyID: true, SigningTime: time.Now()},
+			{SigningCertificate: true},
+			{NoSignedAttributes: true},
+			{Detached: true, NoSignedAttributes: true},
+			{ContentType: asn1.ObjectIdentifier{1, 2, 3, 4}},
+		} {
+			der, err := Sign(content, cert.Certificate, key, &opts)
+			if err != nil {
+				t.Fatalf("%s %+v: Sign: %v", s.Name(), opts, err)
+			}
+			sd, err := Parse(der)
+			if err != nil {
+				t.Fatalf("%s %+v: Parse: %v", s.Name(), opts, err)
+			}
+			if len(sd.Certificates) != 1 {
+				t.Errorf("%s %+v: %d embedded certificates", s.Name(), opts, len(sd.Certificates))
+			}
+
+			var detached []byte
+			if opts.Detached {
+				if sd.Content != nil {
+					t.Errorf("%s %+v: detached SignedData carries content", s.Name(), opts)
+				}
+				if _, err := sd.Verify(nil); !errors.Is(err, ErrMissingContent) {
+					t.Errorf("%s %+v: Verify without content: %v", s.Name(), opts, err)
+				}
+				detached = content
+			} else if string(sd.Content) != string(content) {
+				t.Errorf("%s %+v: attached content not preserved", s.Name(), opts)
+			}
+
+			signers, err := sd.Verify(detached)
+			if err != nil {
+				t.Fatalf("%s %+v: Verify: %v", s.Name(), opts, err)
+			}
+			if len(signers) != 1 || signers[0] != sd.Certificates[0] {
+				t.Errorf("%s %+v: unexpected signers", s.Name(), opts)
+			}
+			if _, err := sd.Verify(detached, cert); err != nil {
+				t.Errorf("%s %+v: Verify with provided certificate: %v", s.Name(), opts, err)
+			}
+
+			if opts.Detached {
+				if _, err := sd.Verify([]byte("other content")); !errors.Is(err, ErrInvalidSignature) {
+					t.Errorf("%s %+v: Verify accepted other content: %v", s.Name(), opts, err)
+				}
+			}
+		}
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	key, cert := newSigner(t, mldsa.SchemeByName("ML-DSA-44"), 1)
+	_, other := newSigner(t, mldsa.SchemeByName("ML-DSA-44"), 2)
+	content := []byte("document")
+
+	der, err := Sign(content, cert.Certificate, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd, _ := Parse(der)
+	if _, err := sd.Verify(nil, other); !errors.Is(err, ErrSignerNotFound) {
+		t.Errorf("Verify with an unrelated certificate: %v", err)
+	}
+
+	// Flip a bit in the signature, which ends the encoding.
+	der[len(der)-1] ^= 1
+	sd, err = Parse(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sd.Verify(nil); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify accepted a corrupted signature: %v", err)
+	}
+
+	// A certificate with the same issuer and serial number is found as the
+	// signer, but the signing-certificate-v2 attribute rejects it.
+	der, _ = Sign(content, cert.Certificate, key, &SignOptions{SigningCertificate: true})
+	sd, _ = Parse(der)
+	_, twin := newSigner(t, mldsa.SchemeByName("ML-DSA-44"), 1)
+	if _, err := sd.Verify(nil, twin); err == nil || !strings.Contains(err.Error(), "signing-certificate-v2") {
+		t.Errorf("Verify with a substituted certificate: %v", err)
+	}
+
+	der, _ = Sign(content, cert.Certificate, key, &SignOptions{OmitCertificates: true})
+	sd, _ = Parse(der)
+	if len(sd.Certificates) != 0 {
+		t.Error("OmitCertificates embedded certificates")
+	}
+	if _, err := sd.Verify(nil); !errors.Is(err, ErrSignerNotFound) {
+		t.Errorf("Verify without certificates: %v", err)
+	}
+	if _, err := sd.Verify(nil, cert); err != nil {
+		t.Errorf("Verify with the signer certificate: %v", err)
+	}
+
+	if _, err := Sign(content, other.Certificate, key, nil); err == nil {
+		t.Error("Sign accepted a certificate for another key")
+	}
+	if _, err := Sign(content, cert.Certificate, key, &SignOptions{NoSignedAttributes: true, ContentType: asn1.ObjectIdentifier{1, 2, 3}}); err == nil {
+		t.Error("Sign omitted signed attributes for a non-data content type")
+	}
+}
@@ -0,0 +1,216 @@
+package mldsagrpc
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// Client calls a SigningService. It is safe for concurrent use.
+type Client struct {
+	target string
+	hc     *http.Client
+}
+
+// NewClient returns a client for the service at target, a base URL such as
+// "https://signer.example:8443", or "http://host:port" for cleartext
+// HTTP/2. If hc is nil, a client speaking HTTP/2 over TLS with the system
+// roots, and cleartext HTTP/2 for http URLs, is used.
+func NewClient(target string, hc *http.Client) *Client {
+	if hc == nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Protocols = new(http.Protocols)
+		t.Protocols.SetHTTP2(true)
+		t.Protocols.SetUnencryptedHTTP2(true)
+		hc = &http.Client{Transport: t}
+	}
+	return &Client{target: strings.TrimSuffix(target, "/"), hc: hc}
+}
+
+// call invokes method with the encoded request and returns the fields of
+// the response.
+func (c *Client) call(ctx context.Context, method string, req []byte) (map[int][]byte, error) {
+	var body bytes.Buffer
+	writeFrame(&body, req)
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.target+servicePath+method, &body)
+	if err != nil {
+		return nil, err
+	}
+	hreq.Header.Set("Content-Type", "application/grpc")
+	hreq.Header.Set("Te", "trailers")
+	resp, err := c.hc.Do(hreq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("mldsagrpc: %s: HTTP status %s", method, resp.Status)
+	}
+	if status := resp.Header.Get("Grpc-Status"); status != "" {
+		// A trailers-only response, sent for errors.
+		if err := parseStatus(status, resp.Header.Get("Grpc-Message")); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := readFrame(resp.Body)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	io.Copy(io.Discard, resp.Body) // the trailers follow the body
+	status := resp.Trailer.Get("Grpc-Status")
+	if status == "" {
+		status = resp.Header.Get("Grpc-Status")
+	}
+	if status == "" {
+		return nil, fmt.Errorf("mldsagrpc: %s: response without grpc-status", method)
+	}
+	if err := parseStatus(status, resp.Trailer.Get("Grpc-Message")); err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, fmt.Errorf("mldsagrpc: %s: empty response", method)
+	}
+	return parseFields(reply)
+}
+
+// PublicKey returns the public key of the key with the given ID, a
+// fingerprint as returned by Fingerprint.String, or of the server's only key
+// if keyID is empty.
+func (c *Client) PublicKey(ctx context.Context, keyID string) (mldsa.PublicKey, error) {
+	fields, err := c.call(ctx, "GetPublicKey", appendField(nil, fieldKeyID, []byte(keyID)))
+	if err != nil {
+		return nil, err
+	}
+	s := mldsa.SchemeByName(string(fields[fieldParameterSet]))
+	if s == nil {
+		return nil, fmt.Errorf("%w %q", mldsa.ErrUnknownParameterSet, fields[fieldParameterSet])
+	}
+	pk, err := s.UnmarshalPublicKey(fields[fieldPublicKey])
+	if err != nil {
+		return nil, err
+	}
+	if keyID != "" && !mldsa.MatchFingerprint(pk, keyID) {
+		return nil, errors.New("mldsagrpc: server returned a key with another fingerprint")
+	}
+	return pk, nil
+}
+
+// Signer returns a signer for the key with the given ID, or for the
+// server's only key if keyID is empty.
+func (c *Client) Signer(ctx context.Context, keyID string) (*Signer, error) {
+	pk, err := c.PublicKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{client: c, pub: pk}, nil
+}
+
+// Sign asks the server to sign message under context with the private key
+// of pk, sending the message itself, and checks the returned signature.
+func (c *Client) Sign(ctx context.Context, pk mldsa.PublicKey, message, context []byte) ([]byte, error) {
+	req := appendField(nil, fieldKeyID, []byte(pk.Fingerprint().String()))
+	req = appendField(req, fieldMessage, message)
+	req = appendField(req, fieldContext, context)
+	fields, err := c.call(ctx, "Sign", req)
+	if err != nil {
+		return nil, err
+	}
+	sig := fields[fieldSignature]
+	if !pk.Verify(sig, message, context) {
+		return nil, errors.New("mldsagrpc: server returned an invalid signature")
+	}
+	return sig, nil
+}
+
+// SignMu asks the server to sign the message representative µ with the
+// private key of pk, and checks the returned signature.
+func (c *Client) SignMu(ctx context.Context, pk mldsa.PublicKey, mu [mldsa.MuSize]byte) ([]byte, error) {
+	v, ok := pk.(muVerifier)
+	if !ok {
+		return nil, fmt.Errorf("mldsagrpc: %T cannot verify an external µ", pk)
+	}
+	req := appendField(nil, fieldKeyID, []byte(pk.Fingerprint().String()))
+	req = appendField(req, fieldMu, mu[:])
+	fields, err := c.call(ctx, "SignMu", req)
+	if err != nil {
+		return nil, err
+	}
+	sig := fields[fieldSignature]
+	if !v.VerifyMu(sig, mu) {
+		return nil, errors.New("mldsagrpc: server returned an invalid signature")
+	}
+	return sig, nil
+}
+
+// Signer is a private key held by a signing service. It implements
+// crypto.Signer, crypto.MessageSigner and mldsa.PrivateKey. It signs with
+// SignMu, so messages never leave the process, and the server draws the
+// hedging randomness: the rand arguments of its methods are ignored.
+type Signer struct {
+	client *Client
+	pub    mldsa.PublicKey
+}
+
+// Public returns the public key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Scheme returns the parameter set of the key.
+func (s *Signer) Scheme() mldsa.Scheme {
+	return s.pub.Scheme()
+}
+
+// Seed always returns false: the seed never leaves the server.
+func (s *Signer) Seed() ([]byte, bool) {
+	return nil, false
+}
+
+// SignWithContext signs message with an optional context string.
+func (s *Signer) SignWithContext(_ io.Reader, message, msgContext []byte) ([]byte, error) {
+	return s.SignContext(context.Background(), message, msgContext)
+}
+
+// SignContext is like SignWithContext, with ctx bounding the call to the
+// server.
+func (s *Signer) SignContext(ctx context.Context, message, msgContext []byte) ([]byte, error) {
+	v, ok := s.pub.(muVerifier)
+	if !ok {
+		return nil, fmt.Errorf("mldsagrpc: %T cannot compute µ", s.pub)
+	}
+	mu, err := v.ComputeMu(message, msgContext)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.SignMu(ctx, s.pub, mu)
+}
+
+// Sign signs message with pure ML-DSA. opts.HashFunc() must be zero; if
+// opts is *mldsa.SignerOpts, its Context field is used.
+func (s *Signer) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var context []byte
+	if opts != nil {
+		if opts.HashFunc() != 0 {
+			return nil, errors.New("mldsagrpc: HashML-DSA is not supported")
+		}
+		if o, ok := opts.(*mldsa.SignerOpts); ok && o != nil {
+			context = o.Context
+		}
+	}
+	return s.SignWithContext(rand, message, context)
+}
+
+// SignMessage is like Sign. It implements crypto.MessageSigner.
+func (s *Signer) SignMessage(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.Sign(rand, message, opts)
+}
+
+var _ mldsa.PrivateKey = (*Signer)(nil)
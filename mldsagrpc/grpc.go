@@ -0,0 +1,215 @@
+// Package mldsagrpc serves ML-DSA private keys as a gRPC signing service,
+// and provides a client whose signers implement crypto.Signer, so that keys
+// can be centralized in a signing microservice while applications keep
+// using the standard interfaces.
+//
+// The service is defined in signing.proto:
+//
+//	service SigningService {
+//	  rpc GetPublicKey(GetPublicKeyRequest) returns (GetPublicKeyResponse);
+//	  rpc Sign(SignRequest) returns (SignResponse);
+//	  rpc SignMu(SignMuRequest) returns (SignResponse);
+//	}
+//
+// It is implemented directly on net/http, without the gRPC and protobuf
+// modules. Server is an http.Handler that must be served over HTTP/2, with
+// TLS or cleartext (h2c), and interoperates with clients generated from
+// signing.proto; Client speaks the same protocol to any implementation of
+// the service. Keys are identified by the fingerprint of their public key.
+//
+// Basic usage:
+//
+//	srv, err := mldsagrpc.NewServer(key)
+//	hs := &http.Server{Addr: ":8443", Handler: srv}
+//	go hs.ListenAndServeTLS(certFile, keyFile)
+//	...
+//	client := mldsagrpc.NewClient("https://signer.example:8443", nil)
+//	signer, err := client.Signer(ctx, fingerprint)
+//	sig, err := signer.SignWithContext(nil, message, context)
+//
+// Signers send only the message representative µ, as the agent package
+// does, and check every signature they receive.
+//
+// The server does not authenticate clients itself. Serve it behind mutual
+// TLS or an equivalent, and set Server.Authorize to restrict which client
+// may use which key.
+package mldsagrpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// servicePath prefixes the HTTP paths of the methods of SigningService.
+const servicePath = "/mldsa.signing.v1.SigningService/"
+
+// maxMessageSize bounds a request or response message, as the 4 MiB default
+// of gRPC implementations does.
+const maxMessageSize = 4 << 20
+
+// ErrUnknownKey is returned when the server does not hold the requested key.
+var ErrUnknownKey = errors.New("mldsagrpc: key not held by the server")
+
+// gRPC status codes used by the service.
+const (
+	codeOK               = 0
+	codeInvalidArgument  = 3
+	codeNotFound         = 5
+	codePermissionDenied = 7
+	codeUnimplemented    = 12
+	codeInternal         = 13
+	codeUnauthenticated  = 16
+)
+
+// StatusError is a gRPC error status returned by the service.
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("mldsagrpc: rpc error: code = %d desc = %s", e.Code, e.Message)
+}
+
+// muSigner is implemented by the private keys of package mldsa.
+type muSigner interface {
+	SignMu(rand io.Reader, mu [mldsa.MuSize]byte) ([]byte, error)
+}
+
+// muVerifier is implemented by the public keys of package mldsa.
+type muVerifier interface {
+	ComputeMu(message, context []byte) ([mldsa.MuSize]byte, error)
+	VerifyMu(sig []byte, mu [mldsa.MuSize]byte) bool
+}
+
+// Message field numbers, as declared in signing.proto.
+const (
+	fieldKeyID        = 1 // all requests
+	fieldMessage      = 2 // SignRequest
+	fieldContext      = 3 // SignRequest
+	fieldMu           = 2 // SignMuRequest
+	fieldSignature    = 1 // SignResponse
+	fieldParameterSet = 1 // GetPublicKeyResponse
+	fieldPublicKey    = 2 // GetPublicKeyResponse
+)
+
+// appendField appends a length-delimited protobuf field to b. Empty values
+// are omitted, as proto3 does.
+func appendField(b []byte, num int, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = binary.AppendUvarint(b, uint64(num)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// parseFields returns the length-delimited fields of a protobuf message by
+// field number. Fields of other wire types are skipped, and a repeated field
+// keeps its last value.
+func parseFields(b []byte) (map[int][]byte, error) {
+	fields := make(map[int][]byte)
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 || tag>>3 == 0 || tag>>3 > 1<<29 {
+			return nil, errors.New("mldsagrpc: malformed protobuf message")
+		}
+		b = b[n:]
+		var skip uint64
+		switch tag & 7 {
+		case 0:
+			if _, n = binary.Uvarint(b); n <= 0 {
+				return nil, errors.New("mldsagrpc: malformed protobuf varint")
+			}
+			skip = uint64(n)
+		case 1:
+			skip = 8
+		case 2:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || l > uint64(len(b)-n) {
+				return nil, errors.New("mldsagrpc: malformed protobuf field")
+			}
+			fields[int(tag>>3)] = b[n : n+int(l)]
+			skip = uint64(n) + l
+		case 5:
+			skip = 4
+		default:
+			return nil, fmt.Errorf("mldsagrpc: unsupported protobuf wire type %d", tag&7)
+		}
+		if skip > uint64(len(b)) {
+			return nil, errors.New("mldsagrpc: truncated protobuf message")
+		}
+		b = b[skip:]
+	}
+	return fields, nil
+}
+
+// writeFrame writes a gRPC length-prefixed message.
+func writeFrame(w io.Writer, msg []byte) error {
+	hdr := binary.BigEndian.AppendUint32([]byte{0}, uint32(len(msg)))
+	if _, err := w.Write(hdr); err != nil {
+		return err
+	}
+	_, err := w.Write(msg)
+	return err
+}
+
+// readFrame reads a single uncompressed gRPC length-prefixed message.
+func readFrame(r io.Reader) ([]byte, error) {
+	var hdr [5]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	if hdr[0] != 0 {
+		return nil, errors.New("mldsagrpc: compressed messages are not supported")
+	}
+	n := binary.BigEndian.Uint32(hdr[1:])
+	if n > maxMessageSize {
+		return nil, fmt.Errorf("mldsagrpc: %d-byte message exceeds the maximum size", n)
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// encodeGRPCMessage percent-encodes a status message for the grpc-message
+// header.
+func encodeGRPCMessage(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// parseStatus returns the error for the grpc-status and grpc-message
+// values, or nil for OK.
+func parseStatus(status, message string) error {
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return fmt.Errorf("mldsagrpc: invalid grpc-status %q", status)
+	}
+	if code == codeOK {
+		return nil
+	}
+	if m, err := url.PathUnescape(message); err == nil {
+		message = m
+	}
+	if code == codeNotFound {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, message)
+	}
+	return &StatusError{Code: code, Message: message}
+}
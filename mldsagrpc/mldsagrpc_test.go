@@ -0,0 +1,232 @@
+package mldsagrpc
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// newTLSServer serves srv over HTTP/2 with TLS and returns a client for it.
+func newTLSServer(t *testing.T, h http.Handler) *Client {
+	t.Helper()
+	ts := httptest.NewUnstartedServer(h)
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+	return NewClient(ts.URL, ts.Client())
+}
+
+func TestSigningService(t *testing.T) {
+	var keys []mldsa.PrivateKey
+	for _, s := range mldsa.Schemes() {
+		key, err := s.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	srv, err := NewServer(keys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newTLSServer(t, srv)
+	ctx := context.Background()
+
+	message, msgContext := []byte("release manifest"), []byte("ctx")
+	for _, key := range keys {
+		pk := key.Public().(mldsa.PublicKey)
+		name := pk.Scheme().Name()
+		signer, err := client.Signer(ctx, pk.Fingerprint().String())
+		if err != nil {
+			t.Fatalf("%s: Signer: %v", name, err)
+		}
+		if !pk.Equal(signer.Public()) {
+			t.Fatalf("%s: server returned another key", name)
+		}
+		sig, err := signer.SignWithContext(nil, message, msgContext)
+		if err != nil || !pk.Verify(sig, message, msgContext) {
+			t.Errorf("%s: SignWithContext: %v", name, err)
+		}
+		sig, err = signer.Sign(nil, message, &mldsa.SignerOpts{Context: msgContext})
+		if err != nil || !pk.Verify(sig, message, msgContext) {
+			t.Errorf("%s: Sign: %v", name, err)
+		}
+		if _, err := signer.Sign(nil, message, crypto.SHA256); err == nil {
+			t.Errorf("%s: Sign accepted a pre-hash", name)
+		}
+		sig, err = client.Sign(ctx, pk, message, msgContext)
+		if err != nil || !pk.Verify(sig, message, msgContext) {
+			t.Errorf("%s: Client.Sign: %v", name, err)
+		}
+	}
+
+	other, _ := mldsa.GenerateKey44(rand.Reader)
+	if _, err := client.Signer(ctx, other.PublicKey().Fingerprint().String()); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Signer for a key the server does not hold: %v", err)
+	}
+	if _, err := client.Sign(ctx, other.PublicKey(), message, nil); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Sign with a key the server does not hold: %v", err)
+	}
+	var se *StatusError
+	if _, err := client.PublicKey(ctx, ""); !errors.As(err, &se) || se.Code != codeInvalidArgument {
+		t.Errorf("empty key ID with several keys: %v", err)
+	}
+	pk := keys[0].Public().(mldsa.PublicKey)
+	if _, err := client.Sign(ctx, pk, message, make([]byte, 256)); !errors.As(err, &se) || se.Code != codeInvalidArgument {
+		t.Errorf("Sign with a long context: %v", err)
+	}
+	if _, err := client.call(ctx, "SignMu", appendField(nil, fieldMu, []byte("short"))); !errors.As(err, &se) || se.Code != codeInvalidArgument {
+		t.Errorf("SignMu with a short µ: %v", err)
+	}
+	if _, err := client.call(ctx, "Verify", nil); !errors.As(err, &se) || se.Code != codeUnimplemented {
+		t.Errorf("unknown method: %v", err)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	key, _ := mldsa.GenerateKey65(rand.Reader)
+	srv, _ := NewServer(key)
+	var methods []string
+	srv.Authorize = func(r *http.Request, method string, pk mldsa.PublicKey) error {
+		methods = append(methods, method)
+		if !pk.Equal(key.PublicKey()) {
+			t.Errorf("Authorize called for another key")
+		}
+		switch r.Header.Get("Authorization") {
+		case "Bearer signer":
+			return nil
+		case "":
+			return &StatusError{Code: 16, Message: "missing credentials"} // Unauthenticated
+		}
+		if method == "GetPublicKey" {
+			return nil
+		}
+		return errors.New("read-only client")
+	}
+	ts := httptest.NewUnstartedServer(srv)
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+	withAuth := func(token string) *Client {
+		base := ts.Client().Transport
+		return NewClient(ts.URL, &http.Client{Transport: roundTripper(func(r *http.Request) (*http.Response, error) {
+			if token != "" {
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+			return base.RoundTrip(r)
+		})})
+	}
+	ctx := context.Background()
+
+	var se *StatusError
+	if _, err := withAuth("").PublicKey(ctx, ""); !errors.As(err, &se) || se.Code != 16 {
+		t.Errorf("call without credentials: %v", err)
+	}
+	signer, err := withAuth("reader").Signer(ctx, "")
+	if err != nil {
+		t.Fatalf("GetPublicKey as a reader: %v", err)
+	}
+	if _, err := signer.SignWithContext(nil, []byte("m"), nil); !errors.As(err, &se) || se.Code != codePermissionDenied {
+		t.Errorf("SignMu as a reader: %v", err)
+	}
+	if _, err := withAuth("signer").Sign(ctx, key.PublicKey(), []byte("m"), nil); err != nil {
+		t.Errorf("Sign as a signer: %v", err)
+	}
+	if want := []string{"GetPublicKey", "GetPublicKey", "SignMu", "Sign"}; strings.Join(methods, ",") != strings.Join(want, ",") {
+		t.Errorf("Authorize saw %v, want %v", methods, want)
+	}
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestCleartextHTTP2(t *testing.T) {
+	key, _ := mldsa.GenerateKey65(rand.Reader)
+	srv, _ := NewServer(key)
+	var protos http.Protocols
+	protos.SetUnencryptedHTTP2(true)
+	var proto atomic.Int32
+	hs := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proto.Store(int32(r.ProtoMajor))
+			srv.ServeHTTP(w, r)
+		}),
+		Protocols: &protos,
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	go hs.Serve(l)
+	defer hs.Close()
+
+	// An empty key ID selects the only key.
+	signer, err := NewClient("http://"+l.Addr().String(), nil).Signer(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.PublicKey().Equal(signer.Public()) {
+		t.Fatal("server returned another key")
+	}
+	sig, err := signer.SignWithContext(nil, []byte("message"), nil)
+	if err != nil || !key.PublicKey().Verify(sig, []byte("message"), nil) {
+		t.Errorf("SignWithContext: %v", err)
+	}
+	if p := proto.Load(); p != 2 {
+		t.Errorf("request used HTTP/%d, want HTTP/2", p)
+	}
+}
+
+func TestWireFormat(t *testing.T) {
+	// SignMuRequest{key_id: "k", mu: 64 bytes} as encoded by protoc.
+	mu := bytes.Repeat([]byte{0xab}, mldsa.MuSize)
+	want := append([]byte{0x0a, 0x01, 'k', 0x12, 0x40}, mu...)
+	got := appendField(appendField(nil, fieldKeyID, []byte("k")), fieldMu, mu)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("encoding = %x, want %x", got, want)
+	}
+
+	// Unknown fields of every wire type are skipped.
+	msg := append([]byte{0x18, 0x96, 0x01, 0x21, 1, 2, 3, 4, 5, 6, 7, 8, 0x2d, 1, 2, 3, 4}, want...)
+	fields, err := parseFields(msg)
+	if err != nil || string(fields[fieldKeyID]) != "k" || !bytes.Equal(fields[fieldMu], mu) {
+		t.Errorf("parseFields = %q, %v", fields, err)
+	}
+	for _, bad := range [][]byte{{0x0a}, {0x0a, 0x05, 'k'}, {0x00}, {0x0b}, {0x21, 1, 2}} {
+		if _, err := parseFields(bad); err == nil {
+			t.Errorf("parseFields(%x) succeeded", bad)
+		}
+	}
+
+	if got := encodeGRPCMessage("key 100% µ\n"); got != "key 100%25 %C2%B5%0A" {
+		t.Errorf("encodeGRPCMessage = %q", got)
+	}
+	if err := parseStatus("5", "key%20gone"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("parseStatus = %v", err)
+	}
+}
+
+func TestInvalidServerSignature(t *testing.T) {
+	key, _ := mldsa.GenerateKey44(rand.Reader)
+	// A broken server answering with a zero signature.
+	client := newTLSServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set(http.TrailerPrefix+"Grpc-Status", "0")
+		writeFrame(w, appendField(nil, fieldSignature, make([]byte, mldsa.SignatureSize44)))
+	}))
+	signer := &Signer{client: client, pub: key.PublicKey()}
+	if _, err := signer.SignWithContext(nil, []byte("m"), nil); err == nil {
+		t.Error("client accepted an invalid signature from the server")
+	}
+}
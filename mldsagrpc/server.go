@@ -0,0 +1,176 @@
+package mldsagrpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// Server holds private keys and implements SigningService as an
+// http.Handler. The http.Server serving it must enable HTTP/2, which it
+// does by default with TLS; cleartext HTTP/2 is enabled with
+// http.Protocols.SetUnencryptedHTTP2.
+//
+// Server does not authenticate its callers: without Authorize, anyone who
+// can reach it can have any of its keys sign anything. Serve it behind
+// mutual TLS (tls.Config.ClientAuth) or an equivalent authenticating proxy,
+// and use Authorize to decide which client may use which key.
+type Server struct {
+	keys map[mldsa.Fingerprint]mldsa.PrivateKey
+	only mldsa.PrivateKey // the key selected by an empty key ID, if any
+
+	// Rand is the randomness source for hedged signing. If nil,
+	// crypto/rand.Reader is used.
+	Rand io.Reader
+
+	// Authorize, if not nil, is called before every call with the HTTP
+	// request, whose TLS field carries the verified client certificates
+	// under mutual TLS, the method name, such as "Sign", and the public key
+	// the call selects. If it returns an error, the call fails without
+	// using the key: with the code of a *StatusError, or PermissionDenied
+	// for other errors.
+	Authorize func(r *http.Request, method string, key mldsa.PublicKey) error
+}
+
+// NewServer returns a server holding keys. Keys must be the key types of
+// package mldsa, which can sign an external µ.
+func NewServer(keys ...mldsa.PrivateKey) (*Server, error) {
+	s := &Server{keys: make(map[mldsa.Fingerprint]mldsa.PrivateKey)}
+	for _, k := range keys {
+		pk, ok := k.Public().(mldsa.PublicKey)
+		if _, signsMu := k.(muSigner); !ok || !signsMu {
+			return nil, fmt.Errorf("mldsagrpc: %T cannot sign an external µ", k)
+		}
+		s.keys[pk.Fingerprint()] = k
+	}
+	if len(s.keys) == 1 {
+		s.only = keys[0]
+	}
+	return s, nil
+}
+
+// ServeHTTP answers a gRPC call of one of the methods of SigningService.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method, ok := strings.CutPrefix(r.URL.Path, servicePath)
+	if r.Method != http.MethodPost || !ok {
+		http.Error(w, "not a SigningService call", http.StatusNotFound)
+		return
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "application/grpc" && !strings.HasPrefix(ct, "application/grpc+") {
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/grpc")
+	req, err := readFrame(r.Body)
+	if err != nil {
+		writeStatus(w, codeInvalidArgument, err.Error())
+		return
+	}
+	fields, err := parseFields(req)
+	if err != nil {
+		writeStatus(w, codeInvalidArgument, err.Error())
+		return
+	}
+	var handle func(mldsa.PrivateKey, map[int][]byte) ([]byte, error)
+	switch method {
+	case "GetPublicKey":
+		handle = s.getPublicKey
+	case "Sign":
+		handle = s.sign
+	case "SignMu":
+		handle = s.signMu
+	default:
+		writeStatus(w, codeUnimplemented, "unknown method "+method)
+		return
+	}
+	reply, err := s.call(r, method, fields, handle)
+	if err != nil {
+		var se *StatusError
+		if !errors.As(err, &se) {
+			se = &StatusError{Code: codeInternal, Message: err.Error()}
+		}
+		writeStatus(w, se.Code, se.Message)
+		return
+	}
+
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(codeOK))
+	writeFrame(w, reply)
+}
+
+// writeStatus answers with a trailers-only response carrying an error.
+func writeStatus(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Grpc-Status", strconv.Itoa(code))
+	w.Header().Set("Grpc-Message", encodeGRPCMessage(message))
+	w.WriteHeader(http.StatusOK)
+}
+
+// call selects the key of a request, checks that the caller may use it, and
+// passes it to handle.
+func (s *Server) call(r *http.Request, method string, fields map[int][]byte, handle func(mldsa.PrivateKey, map[int][]byte) ([]byte, error)) ([]byte, error) {
+	key, err := s.key(fields)
+	if err != nil {
+		return nil, err
+	}
+	if s.Authorize != nil {
+		if err := s.Authorize(r, method, key.Public().(mldsa.PublicKey)); err != nil {
+			var se *StatusError
+			if errors.As(err, &se) {
+				return nil, se
+			}
+			return nil, &StatusError{Code: codePermissionDenied, Message: err.Error()}
+		}
+	}
+	return handle(key, fields)
+}
+
+// key returns the key named by the key_id field of a request.
+func (s *Server) key(fields map[int][]byte) (mldsa.PrivateKey, error) {
+	id := string(fields[fieldKeyID])
+	if id == "" && s.only != nil {
+		return s.only, nil
+	}
+	fp, ok := mldsa.ParseFingerprint(id)
+	if !ok {
+		return nil, &StatusError{Code: codeInvalidArgument, Message: fmt.Sprintf("invalid key ID %q", id)}
+	}
+	key, ok := s.keys[fp]
+	if !ok {
+		return nil, &StatusError{Code: codeNotFound, Message: fmt.Sprintf("key %s not held by the server", id)}
+	}
+	return key, nil
+}
+
+func (s *Server) getPublicKey(key mldsa.PrivateKey, fields map[int][]byte) ([]byte, error) {
+	pk := key.Public().(mldsa.PublicKey)
+	b := appendField(nil, fieldParameterSet, []byte(pk.Scheme().Name()))
+	return appendField(b, fieldPublicKey, pk.Bytes()), nil
+}
+
+func (s *Server) sign(key mldsa.PrivateKey, fields map[int][]byte) ([]byte, error) {
+	sig, err := key.SignWithContext(s.Rand, fields[fieldMessage], fields[fieldContext])
+	if errors.Is(err, mldsa.ErrContextTooLong) {
+		return nil, &StatusError{Code: codeInvalidArgument, Message: err.Error()}
+	} else if err != nil {
+		return nil, err
+	}
+	return appendField(nil, fieldSignature, sig), nil
+}
+
+func (s *Server) signMu(key mldsa.PrivateKey, fields map[int][]byte) ([]byte, error) {
+	var mu [mldsa.MuSize]byte
+	if len(fields[fieldMu]) != len(mu) {
+		return nil, &StatusError{Code: codeInvalidArgument, Message: fmt.Sprintf("µ must be %d bytes", len(mu))}
+	}
+	copy(mu[:], fields[fieldMu])
+	sig, err := key.(muSigner).SignMu(s.Rand, mu)
+	if err != nil {
+		return nil, err
+	}
+	return appendField(nil, fieldSignature, sig), nil
+}
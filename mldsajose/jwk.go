@@ -0,0 +1,151 @@
+package mldsajose
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// KeyTypeAKP is the "kty" of ML-DSA JWKs: Algorithm Key Pair.
+const KeyTypeAKP = "AKP"
+
+// JWK is an ML-DSA JSON Web Key. "pub" holds the encoded public key and
+// "priv", if present, the 32-byte seed of the private key, both
+// base64url-encoded.
+type JWK struct {
+	KeyType   string   `json:"kty"`
+	Algorithm string   `json:"alg"`
+	KeyID     string   `json:"kid,omitempty"`
+	Use       string   `json:"use,omitempty"`
+	KeyOps    []string `json:"key_ops,omitempty"`
+	Public    string   `json:"pub"`
+	Private   string   `json:"priv,omitempty"`
+}
+
+// JWKSet is a JWK Set, as served by JWKS endpoints.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Lookup returns the key with the given "kid", or nil.
+func (s *JWKSet) Lookup(kid string) *JWK {
+	for i := range s.Keys {
+		if s.Keys[i].KeyID == kid {
+			return &s.Keys[i]
+		}
+	}
+	return nil
+}
+
+// NewJWK returns the JWK of key, which is an mldsa.PublicKey or an
+// mldsa.PrivateKey. Private keys must keep their seed.
+func NewJWK(key any) (*JWK, error) {
+	switch k := key.(type) {
+	case mldsa.PrivateKey:
+		seed, ok := k.Seed()
+		if !ok {
+			return nil, errors.New("mldsajose: private key has no seed")
+		}
+		jwk, err := NewJWK(k.Public())
+		if err != nil {
+			return nil, err
+		}
+		jwk.Private = b64.EncodeToString(seed)
+		return jwk, nil
+	case mldsa.PublicKey:
+		return &JWK{
+			KeyType:   KeyTypeAKP,
+			Algorithm: k.Scheme().Name(),
+			Public:    b64.EncodeToString(k.Bytes()),
+		}, nil
+	}
+	return nil, fmt.Errorf("mldsajose: unsupported key type %T", key)
+}
+
+// scheme returns the parameter set of the JWK.
+func (k *JWK) scheme() (mldsa.Scheme, error) {
+	if k.KeyType != KeyTypeAKP {
+		return nil, fmt.Errorf("mldsajose: unsupported key type %q", k.KeyType)
+	}
+	switch k.Algorithm {
+	case AlgMLDSA44, AlgMLDSA65, AlgMLDSA87:
+		return mldsa.SchemeByName(k.Algorithm), nil
+	}
+	return nil, fmt.Errorf("mldsajose: unsupported algorithm %q", k.Algorithm)
+}
+
+// PublicKey decodes the public key of the JWK.
+func (k *JWK) PublicKey() (mldsa.PublicKey, error) {
+	s, err := k.scheme()
+	if err != nil {
+		return nil, err
+	}
+	b, err := b64.DecodeString(k.Public)
+	if err != nil {
+		return nil, fmt.Errorf("mldsajose: pub: %w", err)
+	}
+	return s.UnmarshalPublicKey(b)
+}
+
+// PrivateKey derives the private key from the seed of the JWK and checks
+// that it matches the public key.
+func (k *JWK) PrivateKey() (mldsa.PrivateKey, error) {
+	s, err := k.scheme()
+	if err != nil {
+		return nil, err
+	}
+	if k.Private == "" {
+		return nil, errors.New("mldsajose: JWK has no private key")
+	}
+	seed, err := b64.DecodeString(k.Private)
+	if err != nil {
+		return nil, fmt.Errorf("mldsajose: priv: %w", err)
+	}
+	sk, err := s.NewKeyFromSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := b64.DecodeString(k.Public)
+	if err != nil {
+		return nil, fmt.Errorf("mldsajose: pub: %w", err)
+	}
+	if subtle.ConstantTimeCompare(pub, sk.Public().(mldsa.PublicKey).Bytes()) != 1 {
+		return nil, errors.New("mldsajose: priv does not match pub")
+	}
+	return sk, nil
+}
+
+// PublicJWK returns a copy of k without the private key, for publication.
+func (k *JWK) PublicJWK() *JWK {
+	pub := *k
+	pub.Private = ""
+	return &pub
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of the JWK computed with h,
+// over its required members "alg", "kty" and "pub".
+func (k *JWK) Thumbprint(h crypto.Hash) ([]byte, error) {
+	if _, err := k.PublicKey(); err != nil {
+		return nil, err
+	}
+	if !h.Available() {
+		return nil, fmt.Errorf("mldsajose: hash function %v is not available", h)
+	}
+	// Members in lexicographic order, without whitespace. The values are
+	// validated above and need no escaping.
+	var b bytes.Buffer
+	b.WriteString(`{"alg":`)
+	alg, _ := json.Marshal(k.Algorithm)
+	b.Write(alg)
+	b.WriteString(`,"kty":"AKP","pub":"`)
+	b.WriteString(k.Public)
+	b.WriteString(`"}`)
+	d := h.New()
+	d.Write(b.Bytes())
+	return d.Sum(nil), nil
+}
@@ -0,0 +1,105 @@
+package mldsajose
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestJWK(t *testing.T) {
+	for _, s := range mldsa.Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		jwk, err := NewJWK(key)
+		if err != nil {
+			t.Fatalf("%s: NewJWK: %v", s.Name(), err)
+		}
+		jwk.KeyID = "k1"
+		data, err := json.Marshal(jwk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded JWK
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded.KeyType != "AKP" || decoded.Algorithm != s.Name() {
+			t.Errorf("%s: kty/alg = %q/%q", s.Name(), decoded.KeyType, decoded.Algorithm)
+		}
+		sk, err := decoded.PrivateKey()
+		if err != nil {
+			t.Fatalf("%s: PrivateKey: %v", s.Name(), err)
+		}
+		if seed, _ := sk.Seed(); len(seed) != mldsa.SeedSize {
+			t.Errorf("%s: private key lost its seed", s.Name())
+		}
+		pk, err := decoded.PublicKey()
+		if err != nil || !pk.Equal(key.Public()) {
+			t.Errorf("%s: PublicKey: %v", s.Name(), err)
+		}
+
+		pub := decoded.PublicJWK()
+		if pub.Private != "" || decoded.Private == "" {
+			t.Errorf("%s: PublicJWK did not strip only the copy", s.Name())
+		}
+		if _, err := pub.PrivateKey(); err == nil {
+			t.Errorf("%s: PrivateKey succeeded without priv", s.Name())
+		}
+		fromPub, _ := NewJWK(key.Public())
+		t1, _ := fromPub.Thumbprint(crypto.SHA256)
+		t2, _ := decoded.Thumbprint(crypto.SHA256)
+		if len(t1) != 32 || string(t1) != string(t2) {
+			t.Errorf("%s: thumbprints differ between public and private JWKs", s.Name())
+		}
+	}
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	seed := make([]byte, mldsa.SeedSize)
+	key, _ := mldsa.NewKey44(seed)
+	jwk, _ := NewJWK(key.PublicKey())
+	got, err := jwk.Thumbprint(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sha256.Sum256([]byte(`{"alg":"ML-DSA-44","kty":"AKP","pub":"` + jwk.Public + `"}`))
+	if hex.EncodeToString(got) != hex.EncodeToString(want[:]) {
+		t.Errorf("Thumbprint = %x, want %x", got, want)
+	}
+}
+
+func TestJWKRejects(t *testing.T) {
+	key, _ := mldsa.GenerateKey65(rand.Reader)
+	other, _ := mldsa.GenerateKey65(rand.Reader)
+	jwk, _ := NewJWK(key)
+	otherJWK, _ := NewJWK(other)
+
+	mismatched := *jwk
+	mismatched.Private = otherJWK.Private
+	if _, err := mismatched.PrivateKey(); err == nil {
+		t.Error("PrivateKey accepted a seed that does not match pub")
+	}
+	for _, mutate := range []func(*JWK){
+		func(k *JWK) { k.KeyType = "OKP" },
+		func(k *JWK) { k.Algorithm = "ML-DSA-87" },
+		func(k *JWK) { k.Algorithm = "EdDSA" },
+		func(k *JWK) { k.Public = strings.TrimSuffix(k.Public, k.Public[len(k.Public)-4:]) },
+	} {
+		bad := *jwk
+		mutate(&bad)
+		if _, err := bad.PublicKey(); err == nil {
+			t.Errorf("PublicKey accepted kty %q, alg %q, pub of %d chars", bad.KeyType, bad.Algorithm, len(bad.Public))
+		}
+	}
+
+	set := JWKSet{Keys: []JWK{*jwk.PublicJWK()}}
+	set.Keys[0].KeyID = "a"
+	if set.Lookup("a") == nil || set.Lookup("b") != nil {
+		t.Error("Lookup returned the wrong key")
+	}
+}
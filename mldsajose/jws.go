@@ -0,0 +1,203 @@
+// Package mldsajose implements the ML-DSA algorithms for JOSE, as specified
+// in draft-ietf-cose-dilithium: JWS compact serialization signed with
+// "ML-DSA-44", "ML-DSA-65" or "ML-DSA-87", and JWKs of key type "AKP".
+//
+// The JWS signing input is signed with pure ML-DSA and an empty context.
+package mldsajose
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// JWS algorithm names, which are also the names of the parameter sets.
+const (
+	AlgMLDSA44 = "ML-DSA-44"
+	AlgMLDSA65 = "ML-DSA-65"
+	AlgMLDSA87 = "ML-DSA-87"
+)
+
+var (
+	// ErrMalformed is returned for JWS input that cannot be parsed.
+	ErrMalformed = errors.New("mldsajose: malformed JWS")
+
+	// ErrInvalidSignature is returned when a JWS signature does not verify.
+	ErrInvalidSignature = errors.New("mldsajose: invalid signature")
+)
+
+var b64 = base64.RawURLEncoding.Strict()
+
+// Header is the JOSE header of a JWS.
+type Header struct {
+	Algorithm   string   `json:"alg"`
+	KeyID       string   `json:"kid,omitempty"`
+	Type        string   `json:"typ,omitempty"`
+	ContentType string   `json:"cty,omitempty"`
+	Critical    []string `json:"crit,omitempty"`
+
+	// Extra holds the header parameters not listed above. It is filled in
+	// by Parse and merged into the header by Sign.
+	Extra map[string]any `json:"-"`
+}
+
+// MarshalJSON encodes the header with its extra parameters.
+func (h Header) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(h.Extra)+5)
+	for k, v := range h.Extra {
+		m[k] = v
+	}
+	type header Header
+	b, err := json.Marshal(header(h))
+	if err != nil {
+		return nil, err
+	}
+	var known map[string]any
+	if err := json.Unmarshal(b, &known); err != nil {
+		return nil, err
+	}
+	for k, v := range known {
+		if _, dup := h.Extra[k]; dup {
+			return nil, fmt.Errorf("mldsajose: extra header parameter %q duplicates a header field", k)
+		}
+		m[k] = v
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON decodes the header, keeping unknown parameters in Extra.
+func (h *Header) UnmarshalJSON(data []byte) error {
+	type header Header
+	var known header
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+	var all map[string]any
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	for _, k := range []string{"alg", "kid", "typ", "cty", "crit"} {
+		delete(all, k)
+	}
+	*h = Header(known)
+	if len(all) > 0 {
+		h.Extra = all
+	}
+	return nil
+}
+
+// SignOptions configures Sign.
+type SignOptions struct {
+	// Header holds the protected header parameters. Its Algorithm is set
+	// from the key and must otherwise be empty or match it.
+	Header Header
+
+	// Rand is the randomness source for hedged signing. If nil,
+	// crypto/rand.Reader is used.
+	Rand io.Reader
+}
+
+// Sign signs payload with key and returns the JWS compact serialization.
+func Sign(payload []byte, key mldsa.PrivateKey, opts *SignOptions) (string, error) {
+	if opts == nil {
+		opts = &SignOptions{}
+	}
+	h := opts.Header
+	alg := key.Scheme().Name()
+	if h.Algorithm != "" && h.Algorithm != alg {
+		return "", fmt.Errorf("mldsajose: header algorithm %q does not match the %s key", h.Algorithm, alg)
+	}
+	h.Algorithm = alg
+	if len(h.Critical) != 0 {
+		return "", errors.New("mldsajose: critical header parameters are not supported")
+	}
+	header, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	input := b64.EncodeToString(header) + "." + b64.EncodeToString(payload)
+	sig, err := key.SignWithContext(opts.Rand, []byte(input), nil)
+	if err != nil {
+		return "", err
+	}
+	return input + "." + b64.EncodeToString(sig), nil
+}
+
+// JWS is a parsed JWS in compact serialization whose signature has not been
+// verified yet.
+type JWS struct {
+	Header  Header
+	Payload []byte
+
+	signingInput string
+	signature    []byte
+}
+
+// Parse parses a JWS compact serialization. The header can be inspected,
+// for example to select a key by its "kid", before calling Verify.
+func Parse(token string) (*JWS, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: want 3 parts, got %d", ErrMalformed, len(parts))
+	}
+	header, err := b64.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: header: %w", ErrMalformed, err)
+	}
+	payload, err := b64.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: payload: %w", ErrMalformed, err)
+	}
+	sig, err := b64.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %w", ErrMalformed, err)
+	}
+	j := &JWS{
+		Payload:      payload,
+		signingInput: parts[0] + "." + parts[1],
+		signature:    sig,
+	}
+	if err := json.Unmarshal(header, &j.Header); err != nil {
+		return nil, fmt.Errorf("%w: header: %w", ErrMalformed, err)
+	}
+	switch j.Header.Algorithm {
+	case AlgMLDSA44, AlgMLDSA65, AlgMLDSA87:
+	default:
+		return nil, fmt.Errorf("mldsajose: unsupported algorithm %q", j.Header.Algorithm)
+	}
+	// No extensions are understood, so any critical one must be rejected
+	// (RFC 7515, Section 4.1.11).
+	if j.Header.Critical != nil {
+		return nil, fmt.Errorf("%w: unsupported critical header parameters %q", ErrMalformed, j.Header.Critical)
+	}
+	return j, nil
+}
+
+// Verify verifies the signature of j with key, whose parameter set must
+// match the "alg" header parameter.
+func (j *JWS) Verify(key mldsa.PublicKey) error {
+	if alg := key.Scheme().Name(); j.Header.Algorithm != alg {
+		return fmt.Errorf("mldsajose: JWS algorithm %q does not match the %s key", j.Header.Algorithm, alg)
+	}
+	if !key.Verify(j.signature, []byte(j.signingInput), nil) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Verify parses token, verifies it with key and returns the payload.
+func Verify(token string, key mldsa.PublicKey) ([]byte, error) {
+	j, err := Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := j.Verify(key); err != nil {
+		return nil, err
+	}
+	return j.Payload, nil
+}
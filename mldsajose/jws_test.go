@@ -0,0 +1,79 @@
+package mldsajose
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestSignVerify(t *testing.T) {
+	payload := []byte(`{"sub":"1234567890","name":"PQ"}`)
+	for _, s := range mldsa.Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		pub := key.Public().(mldsa.PublicKey)
+
+		token, err := Sign(payload, key, &SignOptions{Header: Header{
+			KeyID: "key-1",
+			Type:  "JWT",
+			Extra: map[string]any{"x-custom": "value"},
+		}})
+		if err != nil {
+			t.Fatalf("%s: Sign: %v", s.Name(), err)
+		}
+		got, err := Verify(token, pub)
+		if err != nil {
+			t.Fatalf("%s: Verify: %v", s.Name(), err)
+		}
+		if string(got) != string(payload) {
+			t.Errorf("%s: payload changed", s.Name())
+		}
+
+		j, err := Parse(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if j.Header.Algorithm != s.Name() || j.Header.KeyID != "key-1" || j.Header.Type != "JWT" || j.Header.Extra["x-custom"] != "value" {
+			t.Errorf("%s: unexpected header %+v", s.Name(), j.Header)
+		}
+
+		// Tampering with the payload invalidates the signature.
+		parts := strings.Split(token, ".")
+		parts[1] = b64.EncodeToString([]byte(`{"sub":"0"}`))
+		if _, err := Verify(strings.Join(parts, "."), pub); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: tampered payload: %v", s.Name(), err)
+		}
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	key, _ := mldsa.GenerateKey65(rand.Reader)
+	token, _ := Sign([]byte("payload"), key, nil)
+
+	other, _ := mldsa.GenerateKey44(rand.Reader)
+	if _, err := Verify(token, other.PublicKey()); err == nil {
+		t.Error("Verify accepted a key of another parameter set")
+	}
+	if _, err := Sign([]byte("payload"), key, &SignOptions{Header: Header{Algorithm: AlgMLDSA87}}); err == nil {
+		t.Error("Sign accepted a mismatched algorithm")
+	}
+
+	for name, header := range map[string]string{
+		"alg none":      `{"alg":"none"}`,
+		"lowercase alg": `{"alg":"ml-dsa-65"}`,
+		"crit":          `{"alg":"ML-DSA-65","crit":["exp"],"exp":1}`,
+	} {
+		parts := strings.Split(token, ".")
+		parts[0] = b64.EncodeToString([]byte(header))
+		if _, err := Verify(strings.Join(parts, "."), key.PublicKey()); err == nil {
+			t.Errorf("%s: Verify accepted the token", name)
+		}
+	}
+	for _, bad := range []string{"", "a.b", token + ".x", token + "="} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%.20q) succeeded", bad)
+		}
+	}
+}
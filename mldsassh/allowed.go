@@ -0,0 +1,284 @@
+package mldsassh
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// ErrNotAllowed is returned by AllowedSigners.Verify when a valid signature
+// was not made by a key allowed for the identity and namespace.
+var ErrNotAllowed = errors.New("mldsassh: signer not allowed")
+
+// AllowedSigner is an entry of an allowed signers file, as read by
+// "ssh-keygen -Y verify" and git's gpg.ssh.allowedSignersFile.
+type AllowedSigner struct {
+	// Principals is the comma-separated list of principal patterns, which
+	// may use the "*" and "?" wildcards and "!" negation.
+	Principals string
+
+	// CertAuthority marks Key as a certificate authority: signatures are
+	// accepted from keys certified by it for the identity.
+	CertAuthority bool
+
+	// Namespaces lists the namespace patterns the key may sign for; nil
+	// means any namespace.
+	Namespaces []string
+
+	// ValidAfter and ValidBefore bound when the key is trusted; zero
+	// values mean no bound.
+	ValidAfter  time.Time
+	ValidBefore time.Time
+
+	Key mldsa.PublicKey
+}
+
+// AllowedSigners is the content of an allowed signers file.
+type AllowedSigners []AllowedSigner
+
+// ParseAllowedSigners reads an allowed signers file. Each line holds
+// principals, optional options (cert-authority, namespaces="...",
+// valid-after="..." and valid-before="...") and a public key; empty lines
+// and comments are skipped. Lines for key types other than ML-DSA are
+// skipped too, so files shared with other tools can be read.
+func ParseAllowedSigners(r io.Reader) (AllowedSigners, error) {
+	var signers AllowedSigners
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		signer, err := parseAllowedSigner(line)
+		if err != nil {
+			return nil, fmt.Errorf("mldsassh: allowed signers line %d: %w", n, err)
+		}
+		if signer != nil {
+			signers = append(signers, *signer)
+		}
+	}
+	return signers, s.Err()
+}
+
+// parseAllowedSigner parses one line, returning nil for keys of other types.
+func parseAllowedSigner(line string) (*AllowedSigner, error) {
+	fields, err := splitQuoted(line, func(c byte) bool { return c == ' ' || c == '\t' })
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) < 3 {
+		return nil, errors.New("missing fields")
+	}
+	signer := &AllowedSigner{Principals: unquote(fields[0])}
+	rest := fields[1:]
+	// The options field is present unless the next two fields are a key
+	// type and a key blob of that type.
+	if !isKey(rest[0], rest[1]) {
+		if err := signer.parseOptions(rest[0]); err != nil {
+			return nil, err
+		}
+		rest = rest[1:]
+		if len(rest) < 2 || !isKey(rest[0], rest[1]) {
+			return nil, errors.New("malformed public key")
+		}
+	}
+	if schemeForKeyType(rest[0]) == nil {
+		return nil, nil
+	}
+	blob, _ := base64.StdEncoding.DecodeString(rest[1])
+	if signer.Key, err = ParsePublicKey(blob); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// isKey reports whether keyType and b64 form an authorized_keys key.
+func isKey(keyType, b64 string) bool {
+	blob, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return false
+	}
+	r := &reader{b: blob}
+	return string(r.string()) == keyType && r.err == nil
+}
+
+func (a *AllowedSigner) parseOptions(field string) error {
+	options, err := splitQuoted(field, func(c byte) bool { return c == ',' })
+	if err != nil {
+		return err
+	}
+	for _, opt := range options {
+		name, value, hasValue := strings.Cut(opt, "=")
+		value = unquote(value)
+		switch name = strings.ToLower(name); {
+		case name == "cert-authority" && !hasValue:
+			a.CertAuthority = true
+		case name == "namespaces" && hasValue:
+			a.Namespaces = strings.Split(value, ",")
+		case name == "valid-after" && hasValue:
+			if a.ValidAfter, err = parseSignerTime(value); err != nil {
+				return err
+			}
+		case name == "valid-before" && hasValue:
+			if a.ValidBefore, err = parseSignerTime(value); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported option %q", opt)
+		}
+	}
+	return nil
+}
+
+// parseSignerTime parses the YYYYMMDD[HHMM[SS]][Z] times of allowed signers
+// files, in local time unless suffixed with Z.
+func parseSignerTime(s string) (time.Time, error) {
+	loc := time.Local
+	if t, ok := strings.CutSuffix(s, "Z"); ok {
+		s, loc = t, time.UTC
+	}
+	layouts := map[int]string{8: "20060102", 12: "200601021504", 14: "20060102150405"}
+	layout, ok := layouts[len(s)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("malformed time %q", s)
+	}
+	return time.ParseInLocation(layout, s, loc)
+}
+
+// splitQuoted splits s at separators outside double quotes, dropping empty
+// fields.
+func splitQuoted(s string, sep func(byte) bool) ([]string, error) {
+	var fields []string
+	start, quoted := 0, false
+	for i := 0; i <= len(s); i++ {
+		switch {
+		case i < len(s) && s[i] == '"':
+			quoted = !quoted
+		case i == len(s) || !quoted && sep(s[i]):
+			if i > start {
+				fields = append(fields, s[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	return fields, nil
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// matchPattern matches s against an OpenSSH pattern with "*" and "?"
+// wildcards.
+func matchPattern(s, pattern string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(s); i >= 0; i-- {
+				if matchPattern(s[i:], pattern[1:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+		default:
+			if len(s) == 0 || s[0] != pattern[0] {
+				return false
+			}
+		}
+		s, pattern = s[1:], pattern[1:]
+	}
+	return len(s) == 0
+}
+
+// matchPatternList reports whether s matches a comma-separated pattern
+// list: at least one pattern must match and no negated one.
+func matchPatternList(s string, patterns []string) bool {
+	matched := false
+	for _, p := range patterns {
+		if neg, ok := strings.CutPrefix(p, "!"); ok {
+			if matchPattern(s, neg) {
+				return false
+			}
+		} else if matchPattern(s, p) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// allows reports whether the entry accepts sig from identity for namespace
+// at time now. The signature itself must already be verified.
+func (a *AllowedSigner) allows(sig *Signature, identity, namespace string, now time.Time) bool {
+	if !matchPatternList(identity, strings.Split(a.Principals, ",")) {
+		return false
+	}
+	if a.Namespaces != nil && !matchPatternList(namespace, a.Namespaces) {
+		return false
+	}
+	if !a.ValidAfter.IsZero() && now.Before(a.ValidAfter) ||
+		!a.ValidBefore.IsZero() && !now.Before(a.ValidBefore) {
+		return false
+	}
+	if !a.CertAuthority {
+		return sig.Certificate == nil && sig.PublicKey.Equal(a.Key)
+	}
+	if sig.Certificate == nil {
+		return false
+	}
+	err := sig.Certificate.Check(&CheckOptions{
+		Authorities: []mldsa.PublicKey{a.Key},
+		CertType:    UserCert,
+		Principal:   identity,
+		Time:        now,
+	})
+	return err == nil
+}
+
+// Verify verifies sig over the message read from message, as
+// "ssh-keygen -Y verify" does: the signature must be valid for namespace
+// and made by a key that an entry allows for identity at the current time.
+func (a AllowedSigners) Verify(sig *Signature, identity, namespace string, message io.Reader) error {
+	if err := sig.Verify(namespace, message); err != nil {
+		return err
+	}
+	now := time.Now()
+	for i := range a {
+		if a[i].allows(sig, identity, namespace, now) {
+			return nil
+		}
+	}
+	return ErrNotAllowed
+}
+
+// FindPrincipals returns the principal lists of the entries for the key
+// that made sig, as "ssh-keygen -Y find-principals" does.
+func (a AllowedSigners) FindPrincipals(sig *Signature) []string {
+	var principals []string
+	for _, s := range a {
+		if s.CertAuthority {
+			if sig.Certificate != nil && sig.Certificate.Verify(s.Key) == nil {
+				principals = append(principals, s.Principals)
+			}
+		} else if sig.Certificate == nil && sig.PublicKey.Equal(s.Key) {
+			principals = append(principals, s.Principals)
+		}
+	}
+	return principals
+}
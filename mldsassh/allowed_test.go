@@ -0,0 +1,139 @@
+package mldsassh
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func authorizedField(pk mldsa.PublicKey) string {
+	return strings.TrimSpace(string(MarshalAuthorizedKey(pk, "")))
+}
+
+func TestAllowedSigners(t *testing.T) {
+	alice, _ := mldsa.GenerateKey65(rand.Reader)
+	bob, _ := mldsa.GenerateKey44(rand.Reader)
+	ca, _ := mldsa.GenerateKey87(rand.Reader)
+	carol, _ := mldsa.GenerateKey65(rand.Reader)
+
+	file := strings.Join([]string{
+		"# allowed signers",
+		"",
+		"alice@example.com " + authorizedField(alice.PublicKey()) + " alice's key",
+		`"bob@example.com,robert@example.com" namespaces="git",valid-before="20991231Z" ` + authorizedField(bob.PublicKey()),
+		`*@example.com,!mallory@example.com cert-authority ` + authorizedField(ca.PublicKey()),
+		"dave@example.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGPFa6L+yScEs+6pCHLpot6iyilZbHOt2ybLqWwFJYai",
+		`erin@example.com valid-after="20000101" ` + authorizedField(carol.PublicKey()),
+	}, "\n")
+	signers, err := ParseAllowedSigners(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("ParseAllowedSigners: %v", err)
+	}
+	if len(signers) != 4 {
+		t.Fatalf("got %d signers, want 4 (the Ed25519 line is skipped)", len(signers))
+	}
+	if signers[1].Principals != "bob@example.com,robert@example.com" {
+		t.Errorf("principals = %q", signers[1].Principals)
+	}
+	if !signers[2].CertAuthority || signers[1].Namespaces[0] != "git" || signers[1].ValidBefore.Year() != 2099 {
+		t.Errorf("options not parsed: %+v", signers[1:3])
+	}
+
+	message := "signed commit"
+	sign := func(key mldsa.PrivateKey, namespace string) *Signature {
+		sig, err := SignMessage(rand.Reader, key, namespace, strings.NewReader(message))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+
+	if err := signers.Verify(sign(alice, "file"), "alice@example.com", "file", strings.NewReader(message)); err != nil {
+		t.Errorf("alice: %v", err)
+	}
+	if err := signers.Verify(sign(alice, "git"), "bob@example.com", "git", strings.NewReader(message)); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("alice's key for bob: %v", err)
+	}
+	if err := signers.Verify(sign(bob, "git"), "robert@example.com", "git", strings.NewReader(message)); err != nil {
+		t.Errorf("bob: %v", err)
+	}
+	if err := signers.Verify(sign(bob, "file"), "bob@example.com", "file", strings.NewReader(message)); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("bob outside his namespaces: %v", err)
+	}
+	if got := signers.FindPrincipals(sign(bob, "git")); len(got) != 1 || !strings.Contains(got[0], "bob@example.com") {
+		t.Errorf("FindPrincipals = %q", got)
+	}
+
+	// carol signs with a certificate issued by the CA.
+	certify := func(principal string) *Signature {
+		cert := &Certificate{
+			Key:             carol.PublicKey(),
+			CertType:        UserCert,
+			ValidPrincipals: []string{principal},
+			ValidBefore:     uint64(time.Now().Add(time.Hour).Unix()),
+		}
+		if err := cert.SignCert(rand.Reader, ca); err != nil {
+			t.Fatal(err)
+		}
+		sig := sign(carol, "git")
+		sig.Certificate = cert
+		armored, err := sig.Armor()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig, err = ParseSignature(armored); err != nil || sig.Certificate == nil {
+			t.Fatalf("ParseSignature with certificate: %v", err)
+		}
+		return sig
+	}
+	if err := signers.Verify(certify("carol@example.com"), "carol@example.com", "git", strings.NewReader(message)); err != nil {
+		t.Errorf("carol with certificate: %v", err)
+	}
+	if err := signers.Verify(certify("carol@example.com"), "frank@example.com", "git", strings.NewReader(message)); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("certificate for another principal: %v", err)
+	}
+	if err := signers.Verify(certify("mallory@example.com"), "mallory@example.com", "git", strings.NewReader(message)); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("negated principal: %v", err)
+	}
+	// erin's plain-key entry ignores certificates, but the CA entry
+	// covers her too.
+	if err := signers.Verify(certify("erin@example.com"), "erin@example.com", "git", strings.NewReader(message)); err != nil {
+		t.Errorf("erin through the CA: %v", err)
+	}
+
+	for _, bad := range []string{
+		"alice@example.com",
+		"alice@example.com unknown-option " + authorizedField(alice.PublicKey()),
+		`alice@example.com namespaces="git ` + authorizedField(alice.PublicKey()),
+		`alice@example.com valid-after="2024" ` + authorizedField(alice.PublicKey()),
+	} {
+		if _, err := ParseAllowedSigners(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseAllowedSigners accepted %.50q", bad)
+		}
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	for _, tt := range []struct {
+		s, pattern string
+		want       bool
+	}{
+		{"alice@example.com", "*@example.com", true},
+		{"alice@example.org", "*@example.com", false},
+		{"bob", "b?b", true},
+		{"bob", "b?", false},
+		{"", "*", true},
+		{"abc", "a*c*", true},
+	} {
+		if got := matchPattern(tt.s, tt.pattern); got != tt.want {
+			t.Errorf("matchPattern(%q, %q) = %v", tt.s, tt.pattern, got)
+		}
+	}
+	if matchPatternList("mallory@example.com", []string{"*@example.com", "!mallory@*"}) {
+		t.Error("negated pattern did not exclude the name")
+	}
+}
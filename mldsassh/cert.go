@@ -0,0 +1,346 @@
+package mldsassh
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// Certificate types.
+const (
+	UserCert = 1
+	HostCert = 2
+)
+
+// CertTimeInfinity is the ValidBefore of a certificate that never expires.
+const CertTimeInfinity = math.MaxUint64
+
+const certSuffix = "-cert-v01@openssh.com"
+
+// Certificate is an OpenSSH certificate (PROTOCOL.certkeys) signed by an
+// ML-DSA certificate authority.
+type Certificate struct {
+	Nonce []byte
+
+	// Key is the certified key: an mldsa.PublicKey or an
+	// ed25519.PublicKey.
+	Key any
+
+	Serial          uint64
+	CertType        uint32
+	KeyID           string
+	ValidPrincipals []string
+
+	// ValidAfter and ValidBefore are Unix times bounding the validity
+	// period; ValidBefore is CertTimeInfinity for no expiry.
+	ValidAfter  uint64
+	ValidBefore uint64
+
+	// CriticalOptions and Extensions map option names to their values,
+	// which are empty for flags such as "permit-pty".
+	CriticalOptions map[string]string
+	Extensions      map[string]string
+
+	Reserved []byte
+
+	// SignatureKey and Signature are set by SignCert.
+	SignatureKey mldsa.PublicKey
+	Signature    []byte
+}
+
+// keyType returns the SSH key type and key-specific fields of c.Key.
+func (c *Certificate) keyType() (string, []byte, error) {
+	switch k := c.Key.(type) {
+	case mldsa.PublicKey:
+		return KeyType(k), appendString(nil, k.Bytes()), nil
+	case ed25519.PublicKey:
+		return "ssh-ed25519", appendString(nil, k), nil
+	}
+	return "", nil, fmt.Errorf("mldsassh: unsupported certificate key type %T", c.Key)
+}
+
+// Type returns the certificate key type, such as
+// "ssh-mldsa-65-cert-v01@openssh.com".
+func (c *Certificate) Type() string {
+	t, _, _ := c.keyType()
+	return t + certSuffix
+}
+
+// appendOptions encodes options sorted by name, each value being wrapped
+// in a string unless empty.
+func appendOptions(b []byte, options map[string]string) []byte {
+	var list []byte
+	for _, name := range slices.Sorted(maps.Keys(options)) {
+		list = appendString(list, []byte(name))
+		var data []byte
+		if v := options[name]; v != "" {
+			data = appendString(nil, []byte(v))
+		}
+		list = appendString(list, data)
+	}
+	return appendString(b, list)
+}
+
+// parseOptions decodes a list encoded by appendOptions, which must be
+// sorted without duplicates.
+func parseOptions(data []byte) (map[string]string, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	options := make(map[string]string)
+	r := &reader{b: data}
+	prev := ""
+	for len(r.b) > 0 && r.err == nil {
+		name := string(r.string())
+		value := r.string()
+		if r.err != nil {
+			break
+		}
+		if name <= prev && prev != "" {
+			return nil, errors.New("mldsassh: certificate options are not sorted or repeat")
+		}
+		prev = name
+		if len(value) > 0 {
+			vr := &reader{b: value}
+			value = vr.string()
+			if err := vr.done(); err != nil {
+				return nil, err
+			}
+		}
+		options[name] = string(value)
+	}
+	return options, r.done()
+}
+
+// signedBytes returns the encoding of c up to and including the signature
+// key, which is what the signature covers.
+func (c *Certificate) signedBytes() ([]byte, error) {
+	keyType, keyFields, err := c.keyType()
+	if err != nil {
+		return nil, err
+	}
+	if c.SignatureKey == nil {
+		return nil, errors.New("mldsassh: certificate has no signature key")
+	}
+	b := appendString(nil, []byte(keyType+certSuffix))
+	b = appendString(b, c.Nonce)
+	b = append(b, keyFields...)
+	b = binary.BigEndian.AppendUint64(b, c.Serial)
+	b = binary.BigEndian.AppendUint32(b, c.CertType)
+	b = appendString(b, []byte(c.KeyID))
+	var principals []byte
+	for _, p := range c.ValidPrincipals {
+		principals = appendString(principals, []byte(p))
+	}
+	b = appendString(b, principals)
+	b = binary.BigEndian.AppendUint64(b, c.ValidAfter)
+	b = binary.BigEndian.AppendUint64(b, c.ValidBefore)
+	b = appendOptions(b, c.CriticalOptions)
+	b = appendOptions(b, c.Extensions)
+	b = appendString(b, c.Reserved)
+	return appendString(b, MarshalPublicKey(c.SignatureKey)), nil
+}
+
+// SignCert sets the signature key of c to the public key of authority, a
+// random nonce if c has none, and signs the certificate. If rand is nil,
+// crypto/rand.Reader is used.
+func (c *Certificate) SignCert(rand io.Reader, authority mldsa.PrivateKey) error {
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	pk, ok := authority.Public().(mldsa.PublicKey)
+	if !ok {
+		return errors.New("mldsassh: authority has no ML-DSA public key")
+	}
+	if len(c.Nonce) == 0 {
+		c.Nonce = make([]byte, 32)
+		if _, err := io.ReadFull(rand, c.Nonce); err != nil {
+			return err
+		}
+	}
+	c.SignatureKey = pk
+	data, err := c.signedBytes()
+	if err != nil {
+		return err
+	}
+	c.Signature, err = Sign(rand, authority, data)
+	return err
+}
+
+// Marshal returns the SSH wire encoding of the signed certificate.
+func (c *Certificate) Marshal() ([]byte, error) {
+	if c.Signature == nil {
+		return nil, errors.New("mldsassh: certificate is not signed")
+	}
+	b, err := c.signedBytes()
+	if err != nil {
+		return nil, err
+	}
+	return appendString(b, c.Signature), nil
+}
+
+// MarshalAuthorizedKey returns the certificate in the authorized_keys
+// format used for "-cert.pub" files, with a trailing newline.
+func (c *Certificate) MarshalAuthorizedKey(comment string) ([]byte, error) {
+	blob, err := c.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return marshalAuthorized(c.Type(), blob, comment), nil
+}
+
+// ParseCertificate parses the SSH wire encoding of a certificate. The
+// signature is not verified; use Verify or Check.
+func ParseCertificate(blob []byte) (*Certificate, error) {
+	r := &reader{b: blob}
+	certType := string(r.string())
+	keyType, ok := strings.CutSuffix(certType, certSuffix)
+	if r.err == nil && !ok {
+		return nil, fmt.Errorf("mldsassh: %q is not a certificate type", certType)
+	}
+	c := &Certificate{Nonce: r.string()}
+	keyBytes := r.string()
+	if r.err == nil {
+		if s := schemeForKeyType(keyType); s != nil {
+			pk, err := s.UnmarshalPublicKey(keyBytes)
+			if err != nil {
+				return nil, err
+			}
+			c.Key = pk
+		} else if keyType == "ssh-ed25519" && len(keyBytes) == ed25519.PublicKeySize {
+			c.Key = ed25519.PublicKey(keyBytes)
+		} else {
+			return nil, fmt.Errorf("mldsassh: unsupported certificate key type %q", keyType)
+		}
+	}
+	c.Serial = r.uint64()
+	c.CertType = r.uint32()
+	c.KeyID = string(r.string())
+	principals := &reader{b: r.string()}
+	for len(principals.b) > 0 && principals.err == nil {
+		c.ValidPrincipals = append(c.ValidPrincipals, string(principals.string()))
+	}
+	c.ValidAfter = r.uint64()
+	c.ValidBefore = r.uint64()
+	critical := r.string()
+	extensions := r.string()
+	c.Reserved = r.string()
+	sigKey := r.string()
+	c.Signature = r.string()
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	if err := principals.done(); err != nil {
+		return nil, err
+	}
+	var err error
+	if c.CriticalOptions, err = parseOptions(critical); err != nil {
+		return nil, err
+	}
+	if c.Extensions, err = parseOptions(extensions); err != nil {
+		return nil, err
+	}
+	if c.SignatureKey, err = ParsePublicKey(sigKey); err != nil {
+		return nil, fmt.Errorf("mldsassh: certificate signature key: %w", err)
+	}
+	// Re-encoding must reproduce the signed data exactly.
+	signed, err := c.signedBytes()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(blob, signed) {
+		return nil, errors.New("mldsassh: non-canonical certificate encoding")
+	}
+	return c, nil
+}
+
+// ParseAuthorizedCertificate parses a certificate in the authorized_keys
+// format and returns it with its comment.
+func ParseAuthorizedCertificate(line []byte) (*Certificate, string, error) {
+	blob, comment, err := parseAuthorized(line)
+	if err != nil {
+		return nil, "", err
+	}
+	c, err := ParseCertificate(blob)
+	return c, comment, err
+}
+
+// Verify checks that c is signed by authority.
+func (c *Certificate) Verify(authority mldsa.PublicKey) error {
+	if c.SignatureKey == nil || !c.SignatureKey.Equal(authority) {
+		return errors.New("mldsassh: certificate is not signed by this authority")
+	}
+	data, err := c.signedBytes()
+	if err != nil {
+		return err
+	}
+	return Verify(authority, data, c.Signature)
+}
+
+// CheckOptions configures Certificate.Check.
+type CheckOptions struct {
+	// Authorities are the trusted certificate authority keys.
+	Authorities []mldsa.PublicKey
+
+	// CertType is UserCert or HostCert.
+	CertType uint32
+
+	// Principal is the user or host name the certificate must be valid
+	// for. A certificate without principals is valid for any principal.
+	Principal string
+
+	// Time is the time at which the certificate must be valid. If zero,
+	// the current time is used.
+	Time time.Time
+
+	// SupportedCriticalOptions lists the critical options the caller
+	// enforces. Certificates with any other critical option are rejected.
+	SupportedCriticalOptions []string
+}
+
+// Check verifies that c is signed by one of the trusted authorities and is
+// valid for the principal, certificate type and time in opts, as an SSH
+// server or client would before accepting it.
+func (c *Certificate) Check(opts *CheckOptions) error {
+	trusted := false
+	for _, a := range opts.Authorities {
+		if c.SignatureKey != nil && c.SignatureKey.Equal(a) {
+			trusted = true
+		}
+	}
+	if !trusted {
+		return errors.New("mldsassh: certificate signed by an untrusted authority")
+	}
+	if c.CertType != opts.CertType {
+		return fmt.Errorf("mldsassh: certificate type %d, want %d", c.CertType, opts.CertType)
+	}
+	if len(c.ValidPrincipals) > 0 && !slices.Contains(c.ValidPrincipals, opts.Principal) {
+		return fmt.Errorf("mldsassh: principal %q not in the certificate", opts.Principal)
+	}
+	now := opts.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if t := now.Unix(); t < 0 || uint64(t) < c.ValidAfter {
+		return errors.New("mldsassh: certificate is not yet valid")
+	} else if c.ValidBefore != CertTimeInfinity && uint64(t) >= c.ValidBefore {
+		return errors.New("mldsassh: certificate has expired")
+	}
+	for name := range c.CriticalOptions {
+		if !slices.Contains(opts.SupportedCriticalOptions, name) {
+			return fmt.Errorf("mldsassh: unsupported critical option %q", name)
+		}
+	}
+	return c.Verify(c.SignatureKey)
+}
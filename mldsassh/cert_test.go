@@ -0,0 +1,128 @@
+package mldsassh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestCertificate(t *testing.T) {
+	ca, _ := mldsa.GenerateKey65(rand.Reader)
+	userKey, _ := mldsa.GenerateKey44(rand.Reader)
+	now := time.Now()
+
+	cert := &Certificate{
+		Key:             userKey.PublicKey(),
+		Serial:          42,
+		CertType:        UserCert,
+		KeyID:           "alice",
+		ValidPrincipals: []string{"alice", "admin"},
+		ValidAfter:      uint64(now.Add(-time.Minute).Unix()),
+		ValidBefore:     uint64(now.Add(time.Hour).Unix()),
+		CriticalOptions: map[string]string{"force-command": "/usr/bin/true", "source-address": "10.0.0.0/8"},
+		Extensions:      map[string]string{"permit-pty": "", "permit-agent-forwarding": ""},
+	}
+	if err := cert.SignCert(rand.Reader, ca); err != nil {
+		t.Fatal(err)
+	}
+	if cert.Type() != "ssh-mldsa-44-cert-v01@openssh.com" || len(cert.Nonce) != 32 {
+		t.Errorf("unexpected type %q or nonce length %d", cert.Type(), len(cert.Nonce))
+	}
+
+	line, err := cert.MarshalAuthorizedKey("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, comment, err := ParseAuthorizedCertificate(line)
+	if err != nil {
+		t.Fatalf("ParseAuthorizedCertificate: %v", err)
+	}
+	if comment != "alice" || !parsed.Key.(mldsa.PublicKey).Equal(userKey.PublicKey()) ||
+		parsed.Serial != 42 || parsed.KeyID != "alice" ||
+		!reflect.DeepEqual(parsed.ValidPrincipals, cert.ValidPrincipals) ||
+		!reflect.DeepEqual(parsed.CriticalOptions, cert.CriticalOptions) ||
+		!reflect.DeepEqual(parsed.Extensions, cert.Extensions) {
+		t.Errorf("certificate fields changed in the round trip: %+v", parsed)
+	}
+	if err := parsed.Verify(ca.PublicKey()); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+
+	opts := &CheckOptions{
+		Authorities:              []mldsa.PublicKey{ca.PublicKey()},
+		CertType:                 UserCert,
+		Principal:                "admin",
+		SupportedCriticalOptions: []string{"force-command", "source-address"},
+	}
+	if err := parsed.Check(opts); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+
+	otherCA, _ := mldsa.GenerateKey65(rand.Reader)
+	for name, mutate := range map[string]func(o *CheckOptions){
+		"untrusted authority":  func(o *CheckOptions) { o.Authorities = []mldsa.PublicKey{otherCA.PublicKey()} },
+		"host certificate":     func(o *CheckOptions) { o.CertType = HostCert },
+		"unlisted principal":   func(o *CheckOptions) { o.Principal = "mallory" },
+		"expired":              func(o *CheckOptions) { o.Time = now.Add(2 * time.Hour) },
+		"not yet valid":        func(o *CheckOptions) { o.Time = now.Add(-time.Hour) },
+		"unsupported critical": func(o *CheckOptions) { o.SupportedCriticalOptions = []string{"force-command"} },
+	} {
+		o := *opts
+		mutate(&o)
+		if err := parsed.Check(&o); err == nil {
+			t.Errorf("%s: Check succeeded", name)
+		}
+	}
+
+	// Changing a signed field invalidates the signature.
+	parsed.ValidPrincipals = append(parsed.ValidPrincipals, "mallory")
+	if err := parsed.Verify(ca.PublicKey()); err == nil {
+		t.Error("Verify accepted a modified certificate")
+	}
+}
+
+func TestHostCertificate(t *testing.T) {
+	ca, _ := mldsa.GenerateKey87(rand.Reader)
+	hostKey, _, _ := ed25519.GenerateKey(rand.Reader)
+	cert := &Certificate{
+		Key:         hostKey,
+		CertType:    HostCert,
+		KeyID:       "host",
+		ValidBefore: CertTimeInfinity,
+	}
+	if err := cert.SignCert(nil, ca); err != nil {
+		t.Fatal(err)
+	}
+	blob, err := cert.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseCertificate(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hostKey.Equal(parsed.Key) || parsed.Type() != "ssh-ed25519-cert-v01@openssh.com" {
+		t.Errorf("host key not preserved")
+	}
+	// No principals: valid for any host name.
+	err = parsed.Check(&CheckOptions{
+		Authorities: []mldsa.PublicKey{ca.PublicKey()},
+		CertType:    HostCert,
+		Principal:   "server.example.com",
+		Time:        time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Errorf("Check: %v", err)
+	}
+
+	if _, err := ParseCertificate(blob[:len(blob)-1]); err == nil {
+		t.Error("ParseCertificate accepted a truncated certificate")
+	}
+	if _, err := ParseCertificate(MarshalPublicKey(ca.PublicKey())); err == nil {
+		t.Error("ParseCertificate accepted a plain public key")
+	}
+}
@@ -0,0 +1,147 @@
+// Package mldsassh implements the SSH encodings of ML-DSA keys and
+// signatures, OpenSSH certificates issued by ML-DSA certificate
+// authorities, and SSHSIG detached signatures with allowed signers files.
+//
+// Keys use the "ssh-mldsa-44", "ssh-mldsa-65" and "ssh-mldsa-87" key types
+// of draft-sfluhrer-ssh-mldsa: the public key blob is the key type followed
+// by the encoded public key, and signatures are pure ML-DSA with an empty
+// context. The package depends only on the standard library; it does not
+// implement the SSH transport.
+package mldsassh
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// SSH key type names.
+const (
+	KeyTypeMLDSA44 = "ssh-mldsa-44"
+	KeyTypeMLDSA65 = "ssh-mldsa-65"
+	KeyTypeMLDSA87 = "ssh-mldsa-87"
+)
+
+// ErrInvalidSignature is returned when an SSH signature does not verify.
+var ErrInvalidSignature = errors.New("mldsassh: invalid signature")
+
+// KeyType returns the SSH key type of pk, such as "ssh-mldsa-65".
+func KeyType(pk mldsa.PublicKey) string {
+	return "ssh-mldsa-" + strings.TrimPrefix(pk.Scheme().Name(), "ML-DSA-")
+}
+
+// schemeForKeyType returns the scheme of an SSH key type, or nil.
+func schemeForKeyType(keyType string) mldsa.Scheme {
+	switch keyType {
+	case KeyTypeMLDSA44, KeyTypeMLDSA65, KeyTypeMLDSA87:
+		return mldsa.SchemeByName("ML-DSA-" + strings.TrimPrefix(keyType, "ssh-mldsa-"))
+	}
+	return nil
+}
+
+// MarshalPublicKey returns the SSH wire encoding of pk.
+func MarshalPublicKey(pk mldsa.PublicKey) []byte {
+	b := appendString(nil, []byte(KeyType(pk)))
+	return appendString(b, pk.Bytes())
+}
+
+// ParsePublicKey parses the SSH wire encoding of an ML-DSA public key.
+func ParsePublicKey(blob []byte) (mldsa.PublicKey, error) {
+	r := &reader{b: blob}
+	keyType := string(r.string())
+	key := r.string()
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	s := schemeForKeyType(keyType)
+	if s == nil {
+		return nil, fmt.Errorf("mldsassh: unsupported key type %q", keyType)
+	}
+	return s.UnmarshalPublicKey(key)
+}
+
+// MarshalAuthorizedKey returns pk in the authorized_keys format,
+// "ssh-mldsa-65 AAAA... comment", with a trailing newline.
+func MarshalAuthorizedKey(pk mldsa.PublicKey, comment string) []byte {
+	return marshalAuthorized(KeyType(pk), MarshalPublicKey(pk), comment)
+}
+
+func marshalAuthorized(keyType string, blob []byte, comment string) []byte {
+	var b bytes.Buffer
+	b.WriteString(keyType)
+	b.WriteByte(' ')
+	b.WriteString(base64.StdEncoding.EncodeToString(blob))
+	if comment != "" {
+		b.WriteByte(' ')
+		b.WriteString(comment)
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+// ParseAuthorizedKey parses a public key in the authorized_keys format,
+// without options, and returns it with its comment.
+func ParseAuthorizedKey(line []byte) (mldsa.PublicKey, string, error) {
+	blob, comment, err := parseAuthorized(line)
+	if err != nil {
+		return nil, "", err
+	}
+	pk, err := ParsePublicKey(blob)
+	return pk, comment, err
+}
+
+// parseAuthorized splits "keytype base64 [comment]" and decodes the blob,
+// checking that its key type matches the first field.
+func parseAuthorized(line []byte) ([]byte, string, error) {
+	fields := strings.Fields(string(line))
+	if len(fields) < 2 {
+		return nil, "", errors.New("mldsassh: malformed authorized key")
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, "", fmt.Errorf("mldsassh: malformed authorized key: %w", err)
+	}
+	r := &reader{b: blob}
+	if keyType := string(r.string()); r.err != nil || keyType != fields[0] {
+		return nil, "", fmt.Errorf("mldsassh: key type %q does not match the encoded key", fields[0])
+	}
+	return blob, strings.Join(fields[2:], " "), nil
+}
+
+// Sign signs data with key and returns the SSH signature blob: the key type
+// followed by the ML-DSA signature. If rand is nil, crypto/rand.Reader is
+// used.
+func Sign(rand io.Reader, key mldsa.PrivateKey, data []byte) ([]byte, error) {
+	pk, ok := key.Public().(mldsa.PublicKey)
+	if !ok {
+		return nil, errors.New("mldsassh: private key has no ML-DSA public key")
+	}
+	sig, err := key.SignWithContext(rand, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	b := appendString(nil, []byte(KeyType(pk)))
+	return appendString(b, sig), nil
+}
+
+// Verify verifies the SSH signature blob sig of data with pk.
+func Verify(pk mldsa.PublicKey, data, sig []byte) error {
+	r := &reader{b: sig}
+	format := string(r.string())
+	s := r.string()
+	if err := r.done(); err != nil {
+		return err
+	}
+	if format != KeyType(pk) {
+		return fmt.Errorf("mldsassh: %q signature cannot be verified with an %s key", format, KeyType(pk))
+	}
+	if !pk.Verify(s, data, nil) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package mldsassh
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestPublicKey(t *testing.T) {
+	for _, s := range mldsa.Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		pk := key.Public().(mldsa.PublicKey)
+		want := "ssh-mldsa-" + strings.TrimPrefix(s.Name(), "ML-DSA-")
+		if KeyType(pk) != want {
+			t.Errorf("%s: KeyType = %q, want %q", s.Name(), KeyType(pk), want)
+		}
+
+		parsed, err := ParsePublicKey(MarshalPublicKey(pk))
+		if err != nil || !parsed.Equal(pk) {
+			t.Errorf("%s: wire round trip: %v", s.Name(), err)
+		}
+
+		line := MarshalAuthorizedKey(pk, "alice@example.com")
+		if !strings.HasPrefix(string(line), want+" AAAA") || !strings.HasSuffix(string(line), " alice@example.com\n") {
+			t.Errorf("%s: unexpected authorized key %.40q", s.Name(), line)
+		}
+		parsed, comment, err := ParseAuthorizedKey(line)
+		if err != nil || !parsed.Equal(pk) || comment != "alice@example.com" {
+			t.Errorf("%s: authorized key round trip: %v, %q", s.Name(), err, comment)
+		}
+	}
+
+	key, _ := mldsa.GenerateKey65(rand.Reader)
+	line := MarshalAuthorizedKey(key.PublicKey(), "")
+	if _, _, err := ParseAuthorizedKey([]byte(strings.Replace(string(line), "ssh-mldsa-65", "ssh-mldsa-44", 1))); err == nil {
+		t.Error("ParseAuthorizedKey accepted a mismatched key type")
+	}
+	blob := MarshalPublicKey(key.PublicKey())
+	if _, err := ParsePublicKey(blob[:len(blob)-1]); err == nil {
+		t.Error("ParsePublicKey accepted a truncated key")
+	}
+	if _, err := ParsePublicKey(append(blob, 0)); err == nil {
+		t.Error("ParsePublicKey accepted trailing data")
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	key, _ := mldsa.GenerateKey87(rand.Reader)
+	data := []byte("session identifier")
+	sig, err := Sign(rand.Reader, key, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(key.PublicKey(), data, sig); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if err := Verify(key.PublicKey(), []byte("other"), sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify with other data: %v", err)
+	}
+	other, _ := mldsa.GenerateKey65(rand.Reader)
+	if err := Verify(other.PublicKey(), data, sig); err == nil {
+		t.Error("Verify accepted an ssh-mldsa-87 signature with an ssh-mldsa-65 key")
+	}
+}
@@ -0,0 +1,205 @@
+package mldsassh
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/sha256" // for crypto.SHA256
+	_ "crypto/sha512" // for crypto.SHA512
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// SSHSIG detached signatures (OpenSSH PROTOCOL.sshsig), as produced by
+// "ssh-keygen -Y sign" and used for git commit signing. The message is
+// hashed, and the signature covers the hash together with a namespace
+// ("git", "file", ...) so that a signature made for one purpose cannot be
+// reused for another.
+
+const (
+	sshsigMagic   = "SSHSIG"
+	sshsigVersion = 1
+
+	armorBegin = "-----BEGIN SSH SIGNATURE-----"
+	armorEnd   = "-----END SSH SIGNATURE-----"
+)
+
+// hashAlgorithms maps the SSHSIG hash algorithm names to hash functions.
+var hashAlgorithms = map[string]crypto.Hash{
+	"sha256": crypto.SHA256,
+	"sha512": crypto.SHA512,
+}
+
+// Signature is an SSHSIG detached signature.
+type Signature struct {
+	// PublicKey is the key that made the signature. If the signer used a
+	// certificate, it is the certified key and Certificate is set.
+	PublicKey   mldsa.PublicKey
+	Certificate *Certificate
+
+	Namespace     string
+	HashAlgorithm string // "sha512" or "sha256"
+
+	// Blob is the SSH signature blob over the signed data.
+	Blob []byte
+}
+
+// signedData returns the data an SSHSIG signature covers.
+func signedData(namespace, hashAlgorithm string, message io.Reader) ([]byte, error) {
+	h, ok := hashAlgorithms[hashAlgorithm]
+	if !ok {
+		return nil, fmt.Errorf("mldsassh: unsupported SSHSIG hash algorithm %q", hashAlgorithm)
+	}
+	if namespace == "" {
+		return nil, errors.New("mldsassh: SSHSIG namespace must not be empty")
+	}
+	d := h.New()
+	if _, err := io.Copy(d, message); err != nil {
+		return nil, err
+	}
+	b := []byte(sshsigMagic)
+	b = appendString(b, []byte(namespace))
+	b = appendString(b, nil)
+	b = appendString(b, []byte(hashAlgorithm))
+	return appendString(b, d.Sum(nil)), nil
+}
+
+// SignMessage signs the message read from message with key for the given
+// namespace, hashing it with SHA-512 as ssh-keygen does. If rand is nil,
+// crypto/rand.Reader is used. To sign with a certificate, set Certificate
+// on the returned signature to a certificate for key before marshaling.
+func SignMessage(rand io.Reader, key mldsa.PrivateKey, namespace string, message io.Reader) (*Signature, error) {
+	pk, ok := key.Public().(mldsa.PublicKey)
+	if !ok {
+		return nil, errors.New("mldsassh: private key has no ML-DSA public key")
+	}
+	data, err := signedData(namespace, "sha512", message)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := Sign(rand, key, data)
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{
+		PublicKey:     pk,
+		Namespace:     namespace,
+		HashAlgorithm: "sha512",
+		Blob:          blob,
+	}, nil
+}
+
+// Verify verifies that s is a signature of the message read from message
+// for namespace, made by s.PublicKey. It does not decide whether that key
+// is trusted; see AllowedSigners.Verify.
+func (s *Signature) Verify(namespace string, message io.Reader) error {
+	if s.Namespace != namespace {
+		return fmt.Errorf("mldsassh: signature namespace %q, want %q", s.Namespace, namespace)
+	}
+	data, err := signedData(s.Namespace, s.HashAlgorithm, message)
+	if err != nil {
+		return err
+	}
+	return Verify(s.PublicKey, data, s.Blob)
+}
+
+// Marshal returns the binary SSHSIG encoding of s.
+func (s *Signature) Marshal() ([]byte, error) {
+	var key []byte
+	if s.Certificate != nil {
+		if !s.PublicKey.Equal(s.Certificate.Key) {
+			return nil, errors.New("mldsassh: certificate does not certify the signing key")
+		}
+		var err error
+		if key, err = s.Certificate.Marshal(); err != nil {
+			return nil, err
+		}
+	} else {
+		key = MarshalPublicKey(s.PublicKey)
+	}
+	b := []byte(sshsigMagic)
+	b = append(b, 0, 0, 0, sshsigVersion)
+	b = appendString(b, key)
+	b = appendString(b, []byte(s.Namespace))
+	b = appendString(b, nil)
+	b = appendString(b, []byte(s.HashAlgorithm))
+	return appendString(b, s.Blob), nil
+}
+
+// Armor returns s in the armored form written by ssh-keygen, between
+// "-----BEGIN SSH SIGNATURE-----" and "-----END SSH SIGNATURE-----" lines.
+func (s *Signature) Armor() ([]byte, error) {
+	raw, err := s.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	enc := base64.StdEncoding.EncodeToString(raw)
+	var b bytes.Buffer
+	b.WriteString(armorBegin + "\n")
+	for len(enc) > 70 {
+		b.WriteString(enc[:70] + "\n")
+		enc = enc[70:]
+	}
+	b.WriteString(enc + "\n")
+	b.WriteString(armorEnd + "\n")
+	return b.Bytes(), nil
+}
+
+// ParseSignature parses an SSHSIG signature in armored or binary form.
+func ParseSignature(data []byte) (*Signature, error) {
+	if text := strings.TrimSpace(string(data)); strings.HasPrefix(text, armorBegin) {
+		body, ok := strings.CutPrefix(text, armorBegin)
+		if body, ok = strings.CutSuffix(body, armorEnd); !ok {
+			return nil, errors.New("mldsassh: malformed armored signature")
+		}
+		raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+		if err != nil {
+			return nil, fmt.Errorf("mldsassh: malformed armored signature: %w", err)
+		}
+		data = raw
+	}
+
+	if !bytes.HasPrefix(data, []byte(sshsigMagic)) {
+		return nil, errors.New("mldsassh: not an SSHSIG signature")
+	}
+	r := &reader{b: data[len(sshsigMagic):]}
+	version := r.uint32()
+	key := r.string()
+	s := &Signature{Namespace: string(r.string())}
+	r.string() // reserved
+	s.HashAlgorithm = string(r.string())
+	s.Blob = r.string()
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	if version != sshsigVersion {
+		return nil, fmt.Errorf("mldsassh: unsupported SSHSIG version %d", version)
+	}
+	if _, ok := hashAlgorithms[s.HashAlgorithm]; !ok {
+		return nil, fmt.Errorf("mldsassh: unsupported SSHSIG hash algorithm %q", s.HashAlgorithm)
+	}
+
+	kr := &reader{b: key}
+	if keyType := string(kr.string()); strings.HasSuffix(keyType, certSuffix) {
+		cert, err := ParseCertificate(key)
+		if err != nil {
+			return nil, err
+		}
+		pk, ok := cert.Key.(mldsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("mldsassh: unsupported signing key type %q", keyType)
+		}
+		s.PublicKey, s.Certificate = pk, cert
+	} else {
+		pk, err := ParsePublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+		s.PublicKey = pk
+	}
+	return s, nil
+}
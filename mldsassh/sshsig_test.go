@@ -0,0 +1,62 @@
+package mldsassh
+
+import (
+	"bytes"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestSSHSIG(t *testing.T) {
+	message := []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n")
+	for _, s := range mldsa.Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		sig, err := SignMessage(rand.Reader, key, "git", bytes.NewReader(message))
+		if err != nil {
+			t.Fatalf("%s: SignMessage: %v", s.Name(), err)
+		}
+		armored, err := sig.Armor()
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(armored)), "\n")
+		if lines[0] != "-----BEGIN SSH SIGNATURE-----" || lines[len(lines)-1] != "-----END SSH SIGNATURE-----" || len(lines[1]) != 70 {
+			t.Errorf("%s: unexpected armor", s.Name())
+		}
+
+		parsed, err := ParseSignature(armored)
+		if err != nil {
+			t.Fatalf("%s: ParseSignature: %v", s.Name(), err)
+		}
+		if !parsed.PublicKey.Equal(key.Public()) || parsed.Namespace != "git" || parsed.HashAlgorithm != "sha512" {
+			t.Errorf("%s: unexpected signature fields", s.Name())
+		}
+		if err := parsed.Verify("git", bytes.NewReader(message)); err != nil {
+			t.Errorf("%s: Verify: %v", s.Name(), err)
+		}
+		if err := parsed.Verify("file", bytes.NewReader(message)); err == nil {
+			t.Errorf("%s: Verify accepted another namespace", s.Name())
+		}
+		if err := parsed.Verify("git", strings.NewReader("other")); err == nil {
+			t.Errorf("%s: Verify accepted another message", s.Name())
+		}
+
+		raw, _ := sig.Marshal()
+		if !bytes.HasPrefix(raw, []byte("SSHSIG\x00\x00\x00\x01")) {
+			t.Errorf("%s: missing SSHSIG preamble", s.Name())
+		}
+		if _, err := ParseSignature(raw); err != nil {
+			t.Errorf("%s: ParseSignature(binary): %v", s.Name(), err)
+		}
+	}
+
+	key, _ := mldsa.GenerateKey44(rand.Reader)
+	if _, err := SignMessage(rand.Reader, key, "", strings.NewReader("m")); err == nil {
+		t.Error("SignMessage accepted an empty namespace")
+	}
+	if _, err := ParseSignature([]byte("SSHSIG")); err == nil {
+		t.Error("ParseSignature accepted a truncated signature")
+	}
+}
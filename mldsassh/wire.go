@@ -0,0 +1,67 @@
+package mldsassh
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// errShortData is returned when SSH wire data ends prematurely.
+var errShortData = errors.New("mldsassh: truncated data")
+
+// appendString appends s in the SSH "string" encoding: a uint32 length
+// followed by the bytes (RFC 4251, Section 5).
+func appendString(b, s []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// reader decodes SSH wire data. The first error sticks, so a sequence of
+// reads can be checked once at the end.
+type reader struct {
+	b   []byte
+	err error
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.b) < n {
+		r.err = errShortData
+		return nil
+	}
+	v := r.b[:n:n]
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *reader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) uint64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *reader) string() []byte {
+	n := r.uint32()
+	if r.err == nil && uint64(n) > uint64(len(r.b)) {
+		r.err = errShortData
+		return nil
+	}
+	return r.bytes(int(n))
+}
+
+// done returns the first error, or an error if data remains.
+func (r *reader) done() error {
+	if r.err == nil && len(r.b) != 0 {
+		return errors.New("mldsassh: trailing data")
+	}
+	return r.err
+}
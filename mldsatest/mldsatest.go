@@ -0,0 +1,111 @@
+// Package mldsatest provides well-known ML-DSA key pairs and deterministic
+// signatures for tests and examples, in the spirit of RFC 9500.
+//
+// THESE KEYS ARE PUBLIC. They exist so that downstream projects can write
+// integration tests and documentation without generating keys at test time
+// or accidentally committing real ones. Never use them to protect anything.
+//
+// Each test key is derived from a fixed seed:
+//
+//	seed = SHAKE256("mldsatest: <parameter set> test key, DO NOT USE")[:32]
+//
+// and signatures are produced with the deterministic variant of ML-DSA
+// (all-zero rnd), so every fixture is reproducible byte for byte.
+package mldsatest
+
+import (
+	"crypto/sha3"
+	"encoding/json"
+	"io"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// Message and Context are the inputs used for the signatures in Fixtures.
+var (
+	Message = []byte("The quick brown fox jumps over the lazy dog")
+	Context = []byte("mldsatest")
+)
+
+// Seed returns the documented seed of the test key for params, which must be
+// "ML-DSA-44", "ML-DSA-65" or "ML-DSA-87".
+func Seed(params string) []byte {
+	seed := make([]byte, mldsa.SeedSize)
+	h := sha3.NewSHAKE256()
+	h.Write([]byte("mldsatest: " + params + " test key, DO NOT USE"))
+	h.Read(seed)
+	return seed
+}
+
+// newKey44, newKey65 and newKey87 create the test keys. Tests replace them
+// to simulate failures.
+var (
+	newKey44 = mldsa.NewKey44
+	newKey65 = mldsa.NewKey65
+	newKey87 = mldsa.NewKey87
+)
+
+// must returns v, or panics with err.
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic("mldsatest: cannot create test fixture: " + err.Error())
+	}
+	return v
+}
+
+// Key44 returns a fresh copy of the ML-DSA-44 test key pair. It panics if
+// the key cannot be created, as when locked memory is enabled with
+// mldsa.SetLockedMemory but cannot be allocated.
+func Key44() *mldsa.Key44 {
+	return must(newKey44(Seed("ML-DSA-44")))
+}
+
+// Key65 returns a fresh copy of the ML-DSA-65 test key pair. It panics if
+// the key cannot be created, as when locked memory is enabled with
+// mldsa.SetLockedMemory but cannot be allocated.
+func Key65() *mldsa.Key65 {
+	return must(newKey65(Seed("ML-DSA-65")))
+}
+
+// Key87 returns a fresh copy of the ML-DSA-87 test key pair. It panics if
+// the key cannot be created, as when locked memory is enabled with
+// mldsa.SetLockedMemory but cannot be allocated.
+func Key87() *mldsa.Key87 {
+	return must(newKey87(Seed("ML-DSA-87")))
+}
+
+// Fixture is a complete, reproducible test vector for one parameter set.
+type Fixture struct {
+	ParameterSet string `json:"parameterSet"`
+	Seed         []byte `json:"seed"`
+	PublicKey    []byte `json:"pk"`
+	PrivateKey   []byte `json:"sk"`
+	Message      []byte `json:"message"`
+	Context      []byte `json:"context"`
+	Signature    []byte `json:"signature"`
+}
+
+// Fixtures returns one fixture per parameter set: the test key in every
+// encoding and a deterministic signature of Message under Context. It panics
+// if a key cannot be created or cannot sign, so that no incomplete fixture
+// is ever returned. The mldsa command regenerates them with "mldsa
+// fixtures".
+func Fixtures() []Fixture {
+	k44, k65, k87 := Key44(), Key65(), Key87()
+	sig44 := must(k44.SignWithContext(mldsa.DeterministicRand, Message, Context))
+	sig65 := must(k65.SignWithContext(mldsa.DeterministicRand, Message, Context))
+	sig87 := must(k87.SignWithContext(mldsa.DeterministicRand, Message, Context))
+	return []Fixture{
+		{"ML-DSA-44", k44.Bytes(), k44.PublicKey().Bytes(), k44.PrivateKeyBytes(), Message, Context, sig44},
+		{"ML-DSA-65", k65.Bytes(), k65.PublicKey().Bytes(), k65.PrivateKeyBytes(), Message, Context, sig65},
+		{"ML-DSA-87", k87.Bytes(), k87.PublicKey().Bytes(), k87.PrivateKeyBytes(), Message, Context, sig87},
+	}
+}
+
+// WriteFixtures writes Fixtures to w as indented JSON, with byte fields in
+// standard base64.
+func WriteFixtures(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Fixtures())
+}
@@ -0,0 +1,88 @@
+package mldsatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestFixturesDeterministic(t *testing.T) {
+	a, b := Fixtures(), Fixtures()
+	for i := range a {
+		if !bytes.Equal(a[i].Signature, b[i].Signature) || !bytes.Equal(a[i].PrivateKey, b[i].PrivateKey) {
+			t.Errorf("%s: fixtures are not reproducible", a[i].ParameterSet)
+		}
+	}
+}
+
+func TestFixturesVerify(t *testing.T) {
+	for _, f := range Fixtures() {
+		var ok bool
+		switch f.ParameterSet {
+		case "ML-DSA-44":
+			pk, err := mldsa.NewPublicKey44(f.PublicKey)
+			ok = err == nil && pk.Verify(f.Signature, f.Message, f.Context)
+		case "ML-DSA-65":
+			pk, err := mldsa.NewPublicKey65(f.PublicKey)
+			ok = err == nil && pk.Verify(f.Signature, f.Message, f.Context)
+		case "ML-DSA-87":
+			pk, err := mldsa.NewPublicKey87(f.PublicKey)
+			ok = err == nil && pk.Verify(f.Signature, f.Message, f.Context)
+		}
+		if !ok {
+			t.Errorf("%s: fixture signature does not verify", f.ParameterSet)
+		}
+		if !bytes.Equal(f.Seed, Seed(f.ParameterSet)) {
+			t.Errorf("%s: fixture seed differs from Seed()", f.ParameterSet)
+		}
+	}
+}
+
+func TestKeysAreIndependentCopies(t *testing.T) {
+	k := Key65()
+	k.Regenerate(make([]byte, mldsa.SeedSize))
+	if bytes.Equal(Key65().Bytes(), k.Bytes()) {
+		t.Error("Key65 returned a shared key")
+	}
+}
+
+func TestWriteFixtures(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteFixtures(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got []Fixture
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[2].ParameterSet != "ML-DSA-87" {
+		t.Errorf("unexpected fixtures: %d entries", len(got))
+	}
+}
+
+// wantPanic checks that f panics with an error mentioning ErrLockedMemory.
+func wantPanic(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if msg, _ := recover().(string); !strings.Contains(msg, mldsa.ErrLockedMemory.Error()) {
+			t.Errorf("%s: panic %q, want one reporting ErrLockedMemory", name, msg)
+		}
+	}()
+	f()
+}
+
+func TestKeyFailure(t *testing.T) {
+	k44, k65, k87 := newKey44, newKey65, newKey87
+	defer func() { newKey44, newKey65, newKey87 = k44, k65, k87 }()
+	newKey44 = func([]byte) (*mldsa.Key44, error) { return nil, mldsa.ErrLockedMemory }
+	newKey65 = func([]byte) (*mldsa.Key65, error) { return nil, mldsa.ErrLockedMemory }
+	newKey87 = func([]byte) (*mldsa.Key87, error) { return nil, mldsa.ErrLockedMemory }
+
+	wantPanic(t, "Key44", func() { Key44() })
+	wantPanic(t, "Key65", func() { Key65() })
+	wantPanic(t, "Key87", func() { Key87() })
+	wantPanic(t, "Fixtures", func() { Fixtures() })
+}
@@ -0,0 +1,14 @@
+//go:build !go1.27
+
+package mldsatls
+
+import (
+	"crypto"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// tlsPublicKey returns pk: crypto/tls before Go 1.27 has no ML-DSA key type.
+func tlsPublicKey(pk mldsa.PublicKey) crypto.PublicKey {
+	return pk
+}
@@ -0,0 +1,32 @@
+//go:build go1.27
+
+package mldsatls
+
+import (
+	"crypto"
+	stdmldsa "crypto/mldsa"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// tlsPublicKey converts pk to the *crypto/mldsa.PublicKey that crypto/tls
+// supports. It returns pk unchanged if the standard library rejects it, as
+// with the FIPS 140-3 module v1.0.0.
+func tlsPublicKey(pk mldsa.PublicKey) crypto.PublicKey {
+	var params stdmldsa.Parameters
+	switch pk.Scheme().Name() {
+	case "ML-DSA-44":
+		params = stdmldsa.MLDSA44()
+	case "ML-DSA-65":
+		params = stdmldsa.MLDSA65()
+	case "ML-DSA-87":
+		params = stdmldsa.MLDSA87()
+	default:
+		return pk
+	}
+	std, err := stdmldsa.NewPublicKey(params, pk.Bytes())
+	if err != nil {
+		return pk
+	}
+	return std
+}
@@ -0,0 +1,155 @@
+// Package mldsatls provides the TLS 1.3 integration of ML-DSA keys: the
+// SignatureScheme codepoints of draft-ietf-tls-mldsa, a crypto.Signer that
+// signs CertificateVerify messages, and helpers to build tls.Certificate
+// values and verify handshake signatures.
+//
+// With Go 1.27 or later, whose crypto/tls negotiates ML-DSA, certificates
+// from NewCertificate are used as is:
+//
+//	certDER, err := mldsax509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+//	cert, err := mldsatls.NewCertificate([][]byte{certDER}, key)
+//	config := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS13}
+//
+// With older versions, experimental TLS stacks can use Signer, SignedMessage
+// and VerifySignature directly.
+package mldsatls
+
+import (
+	"crypto"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/KarpelesLab/mldsa"
+	"github.com/KarpelesLab/mldsa/mldsax509"
+)
+
+// TLS SignatureScheme codepoints of draft-ietf-tls-mldsa, matching
+// tls.MLDSA44, tls.MLDSA65 and tls.MLDSA87 in Go 1.27 and later.
+const (
+	MLDSA44 tls.SignatureScheme = 0x0904
+	MLDSA65 tls.SignatureScheme = 0x0905
+	MLDSA87 tls.SignatureScheme = 0x0906
+)
+
+// CertificateVerify context strings of RFC 8446, Section 4.4.3.
+const (
+	ServerSignatureContext = "TLS 1.3, server CertificateVerify"
+	ClientSignatureContext = "TLS 1.3, client CertificateVerify"
+)
+
+// ErrInvalidSignature is returned when a handshake signature does not
+// verify.
+var ErrInvalidSignature = errors.New("mldsatls: invalid signature")
+
+// SignatureScheme returns the TLS signature scheme of p, or 0 if p is not a
+// valid parameter set.
+func SignatureScheme(p mldsa.ParameterSet) tls.SignatureScheme {
+	switch p {
+	case mldsa.MLDSA44:
+		return MLDSA44
+	case mldsa.MLDSA65:
+		return MLDSA65
+	case mldsa.MLDSA87:
+		return MLDSA87
+	}
+	return 0
+}
+
+// ParameterSet returns the parameter set of an ML-DSA TLS signature scheme.
+func ParameterSet(s tls.SignatureScheme) (mldsa.ParameterSet, bool) {
+	switch s {
+	case MLDSA44:
+		return mldsa.MLDSA44, true
+	case MLDSA65:
+		return mldsa.MLDSA65, true
+	case MLDSA87:
+		return mldsa.MLDSA87, true
+	}
+	return 0, false
+}
+
+// schemeOf returns the TLS signature scheme of an ML-DSA scheme.
+func schemeOf(s mldsa.Scheme) tls.SignatureScheme {
+	p, _ := mldsa.ParseParameterSet(s.Name())
+	return SignatureScheme(p)
+}
+
+// SignedMessage returns the content covered by a TLS 1.3 CertificateVerify
+// signature: 64 spaces, the context string, a zero byte and the transcript
+// hash. ML-DSA signs it directly, with an empty ML-DSA context.
+func SignedMessage(context string, transcriptHash []byte) []byte {
+	b := make([]byte, 0, 64+len(context)+1+len(transcriptHash))
+	for range 64 {
+		b = append(b, ' ')
+	}
+	b = append(b, context...)
+	b = append(b, 0)
+	return append(b, transcriptHash...)
+}
+
+// Signer is a crypto.Signer and crypto.MessageSigner producing TLS 1.3
+// handshake signatures with an ML-DSA key.
+//
+// TLS uses pure ML-DSA with an empty context, so Sign requires
+// opts.HashFunc() to be zero and signs its input as the message. With Go
+// 1.27 or later, Public returns a *crypto/mldsa.PublicKey, as crypto/tls
+// expects of ML-DSA certificate keys.
+type Signer struct {
+	Key mldsa.PrivateKey
+}
+
+// Public returns the public key of s.Key, as a *crypto/mldsa.PublicKey with
+// Go 1.27 or later.
+func (s *Signer) Public() crypto.PublicKey {
+	return tlsPublicKey(s.Key.Public().(mldsa.PublicKey))
+}
+
+// Sign signs message, the unhashed handshake content.
+func (s *Signer) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != 0 {
+		return nil, fmt.Errorf("mldsatls: ML-DSA signs the handshake directly, not a %v digest", opts.HashFunc())
+	}
+	return s.Key.SignWithContext(rand, message, nil)
+}
+
+// SignMessage signs message, like Sign.
+func (s *Signer) SignMessage(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.Sign(rand, message, opts)
+}
+
+// NewCertificate returns a tls.Certificate for the DER certificate chain
+// and key. The leaf certificate must certify the public key of key. The
+// certificate only offers the signature scheme of key, so crypto/tls only
+// uses it for TLS 1.3 peers that support it.
+func NewCertificate(chain [][]byte, key mldsa.PrivateKey) (tls.Certificate, error) {
+	if len(chain) == 0 {
+		return tls.Certificate{}, errors.New("mldsatls: empty certificate chain")
+	}
+	leaf, err := mldsax509.ParseCertificate(chain[0])
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if leaf.MLDSAPublicKey == nil || !leaf.MLDSAPublicKey.Equal(key.Public()) {
+		return tls.Certificate{}, errors.New("mldsatls: leaf certificate does not match the private key")
+	}
+	return tls.Certificate{
+		Certificate:                  chain,
+		PrivateKey:                   &Signer{Key: key},
+		SupportedSignatureAlgorithms: []tls.SignatureScheme{schemeOf(key.Scheme())},
+		Leaf:                         leaf.Certificate,
+	}, nil
+}
+
+// VerifySignature verifies a TLS 1.3 CertificateVerify signature made with
+// scheme over signed, as returned by SignedMessage.
+func VerifySignature(pub mldsa.PublicKey, scheme tls.SignatureScheme, signed, sig []byte) error {
+	if want := schemeOf(pub.Scheme()); scheme != want {
+		return fmt.Errorf("mldsatls: signature scheme %v cannot be verified with an %s key", scheme, pub.Scheme().Name())
+	}
+	if !pub.Verify(sig, signed, nil) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
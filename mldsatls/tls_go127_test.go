@@ -0,0 +1,50 @@
+//go:build go1.27
+
+package mldsatls
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestHandshake(t *testing.T) {
+	if tls.MLDSA44 != MLDSA44 || tls.MLDSA65 != MLDSA65 || tls.MLDSA87 != MLDSA87 {
+		t.Fatal("codepoints differ from crypto/tls")
+	}
+	for _, s := range mldsa.Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		der := selfSigned(t, key)
+		cert, err := NewCertificate([][]byte{der}, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		roots := x509.NewCertPool()
+		roots.AddCert(cert.Leaf)
+
+		c1, c2 := net.Pipe()
+		server := tls.Server(c1, &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS13})
+		client := tls.Client(c2, &tls.Config{RootCAs: roots, ServerName: "localhost", MinVersion: tls.VersionTLS13})
+		go func() {
+			defer server.Close()
+			if err := server.Handshake(); err == nil {
+				server.Write([]byte("hello"))
+			}
+		}()
+		if err := client.Handshake(); err != nil {
+			t.Fatalf("%s: Handshake: %v", s.Name(), err)
+		}
+		if got := client.ConnectionState().PeerCertificates[0].Raw; string(got) != string(der) {
+			t.Errorf("%s: unexpected peer certificate", s.Name())
+		}
+		if b, err := io.ReadAll(client); err != nil || string(b) != "hello" {
+			t.Errorf("%s: read %q, %v", s.Name(), b, err)
+		}
+		client.Close()
+	}
+}
@@ -0,0 +1,108 @@
+package mldsatls
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/mldsa"
+	"github.com/KarpelesLab/mldsa/mldsax509"
+)
+
+// selfSigned returns a self-signed certificate for key, valid for
+// "localhost".
+func selfSigned(t *testing.T, key mldsa.PrivateKey) []byte {
+	t.Helper()
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := mldsax509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestSignatureScheme(t *testing.T) {
+	for _, p := range mldsa.ParameterSets() {
+		s := SignatureScheme(p)
+		if got, ok := ParameterSet(s); !ok || got != p {
+			t.Errorf("ParameterSet(SignatureScheme(%v)) = %v, %v", p, got, ok)
+		}
+	}
+	if SignatureScheme(mldsa.MLDSA65) != 0x0905 {
+		t.Error("unexpected ML-DSA-65 codepoint")
+	}
+	if _, ok := ParameterSet(0x0807); ok {
+		t.Error("ParameterSet accepted Ed25519")
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	signed := SignedMessage(ServerSignatureContext, make([]byte, 32))
+	if len(signed) != 64+len(ServerSignatureContext)+1+32 || !strings.HasPrefix(string(signed), strings.Repeat(" ", 64)+"TLS 1.3") {
+		t.Fatalf("unexpected signed message %q", signed)
+	}
+	for _, s := range mldsa.Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		pub := key.Public().(mldsa.PublicKey)
+		scheme := schemeOf(s)
+		signer := &Signer{Key: key}
+
+		sig, err := signer.Sign(rand.Reader, signed, crypto.Hash(0))
+		if err != nil {
+			t.Fatalf("%s: Sign: %v", s.Name(), err)
+		}
+		if err := VerifySignature(pub, scheme, signed, sig); err != nil {
+			t.Errorf("%s: VerifySignature: %v", s.Name(), err)
+		}
+		client := SignedMessage(ClientSignatureContext, make([]byte, 32))
+		if err := VerifySignature(pub, scheme, client, sig); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: VerifySignature of the client context: %v", s.Name(), err)
+		}
+		if err := VerifySignature(pub, 0x0807, signed, sig); err == nil {
+			t.Errorf("%s: VerifySignature accepted another scheme", s.Name())
+		}
+		if _, err := signer.Sign(rand.Reader, signed, crypto.SHA256); err == nil {
+			t.Errorf("%s: Sign accepted a hash", s.Name())
+		}
+	}
+}
+
+func TestNewCertificate(t *testing.T) {
+	key, _ := mldsa.GenerateKey65(rand.Reader)
+	der := selfSigned(t, key)
+	cert, err := NewCertificate([][]byte{der}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cert.SupportedSignatureAlgorithms) != 1 || cert.SupportedSignatureAlgorithms[0] != MLDSA65 {
+		t.Errorf("SupportedSignatureAlgorithms = %v", cert.SupportedSignatureAlgorithms)
+	}
+	if cert.Leaf == nil || cert.Leaf.Subject.CommonName != "localhost" {
+		t.Error("missing leaf")
+	}
+
+	other, _ := mldsa.GenerateKey65(rand.Reader)
+	if _, err := NewCertificate([][]byte{der}, other); err == nil {
+		t.Error("NewCertificate accepted a mismatched key")
+	}
+	if _, err := NewCertificate(nil, key); err == nil {
+		t.Error("NewCertificate accepted an empty chain")
+	}
+}
@@ -0,0 +1,545 @@
+// Package mldsatsp issues and verifies RFC 3161 time-stamp tokens signed
+// with ML-DSA, so that archives can start collecting quantum-safe
+// timestamps.
+//
+// A time-stamp token is a CMS SignedData, produced by package mldsacms, that
+// encapsulates a TSTInfo: the hash of the time-stamped data, the time, a
+// serial number and the TSA policy. The TSA certificate must carry the
+// id-kp-timeStamping extended key usage.
+//
+// Basic usage:
+//
+//	req, err := mldsatsp.NewRequest(data, crypto.SHA256)
+//	der, err := req.Marshal()
+//	...
+//	tsa := &mldsatsp.Authority{Certificate: cert, Key: key, Policy: policy}
+//	resp, err := tsa.Respond(req, serial)
+//	...
+//	ts, err := mldsatsp.ParseResponse(resp, tsaCert)
+//	err = ts.Match(req)
+//
+// Like mldsacms, verification checks signatures only: the TSA certificate
+// must still be validated against a trust anchor by the caller.
+package mldsatsp
+
+import (
+	"bytes"
+	"crypto"
+	cryptorand "crypto/rand"
+	_ "crypto/sha256" // for crypto.SHA256
+	_ "crypto/sha512" // for crypto.SHA384 and crypto.SHA512
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/KarpelesLab/mldsa"
+	"github.com/KarpelesLab/mldsa/mldsacms"
+	"github.com/KarpelesLab/mldsa/mldsax509"
+)
+
+var (
+	// ErrMismatch is returned when a timestamp does not cover the given
+	// data or request.
+	ErrMismatch = errors.New("mldsatsp: timestamp does not match")
+
+	// ErrNotTimestampingCertificate is returned when the TSA certificate
+	// lacks the id-kp-timeStamping extended key usage.
+	ErrNotTimestampingCertificate = errors.New("mldsatsp: certificate is not valid for time stamping")
+)
+
+// oidTSTInfo is id-ct-TSTInfo, the content type of time-stamp tokens.
+var oidTSTInfo = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+
+// hashAlgorithms lists the message imprint hashes accepted on both sides.
+var hashAlgorithms = []struct {
+	hash crypto.Hash
+	oid  asn1.ObjectIdentifier
+}{
+	{crypto.SHA256, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}},
+	{crypto.SHA384, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}},
+	{crypto.SHA512, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}},
+}
+
+// supportedHash reports whether h is listed in hashAlgorithms. It is checked
+// before any crypto.Hash method is called on h, as those panic for unknown
+// values such as mldsa.SHAKE128.
+func supportedHash(h crypto.Hash) bool {
+	for _, a := range hashAlgorithms {
+		if a.hash == h {
+			return true
+		}
+	}
+	return false
+}
+
+type messageImprint struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	HashedMessage []byte
+}
+
+type timeStampReq struct {
+	Version        int
+	MessageImprint messageImprint
+	ReqPolicy      asn1.ObjectIdentifier `asn1:"optional"`
+	Nonce          *big.Int              `asn1:"optional"`
+	CertReq        bool                  `asn1:"optional"`
+	Extensions     []pkix.Extension      `asn1:"optional,tag:0"`
+}
+
+type pkiStatusInfo struct {
+	Status       int
+	StatusString []asn1.RawValue `asn1:"optional"`
+	FailInfo     asn1.BitString  `asn1:"optional"`
+}
+
+type timeStampResp struct {
+	Status         pkiStatusInfo
+	TimeStampToken asn1.RawValue `asn1:"optional"`
+}
+
+type accuracy struct {
+	Seconds int `asn1:"optional"`
+	Millis  int `asn1:"optional,tag:0"`
+	Micros  int `asn1:"optional,tag:1"`
+}
+
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint messageImprint
+	SerialNumber   *big.Int
+	GenTime        asn1.RawValue
+	Accuracy       accuracy         `asn1:"optional"`
+	Ordering       bool             `asn1:"optional"`
+	Nonce          *big.Int         `asn1:"optional"`
+	TSA            asn1.RawValue    `asn1:"optional,tag:0"`
+	Extensions     []pkix.Extension `asn1:"optional,tag:1"`
+}
+
+func imprint(h crypto.Hash, digest []byte) (messageImprint, error) {
+	for _, a := range hashAlgorithms {
+		if a.hash == h {
+			if len(digest) != h.Size() {
+				return messageImprint{}, fmt.Errorf("mldsatsp: %d-byte digest for %v", len(digest), h)
+			}
+			return messageImprint{
+				HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: a.oid},
+				HashedMessage: digest,
+			}, nil
+		}
+	}
+	return messageImprint{}, fmt.Errorf("mldsatsp: unsupported hash algorithm %v", h)
+}
+
+func (m messageImprint) hash() (crypto.Hash, error) {
+	for _, a := range hashAlgorithms {
+		if m.HashAlgorithm.Algorithm.Equal(a.oid) {
+			if len(m.HashedMessage) != a.hash.Size() {
+				return 0, fmt.Errorf("mldsatsp: %d-byte digest for %v", len(m.HashedMessage), a.hash)
+			}
+			return a.hash, nil
+		}
+	}
+	return 0, fmt.Errorf("mldsatsp: unsupported hash algorithm %v", m.HashAlgorithm.Algorithm)
+}
+
+// Request is a TimeStampReq.
+type Request struct {
+	HashAlgorithm crypto.Hash
+	HashedMessage []byte
+
+	// Policy is the TSA policy requested, or nil for the TSA's default.
+	Policy asn1.ObjectIdentifier
+
+	// Nonce, if set, is echoed in the timestamp to bind it to the request.
+	Nonce *big.Int
+
+	// CertReq asks the TSA to embed its certificate in the token.
+	CertReq bool
+}
+
+// NewRequest returns a request for a timestamp of data hashed with h
+// (SHA-256, SHA-384 or SHA-512), with a random 64-bit nonce and CertReq
+// set.
+func NewRequest(data []byte, h crypto.Hash) (*Request, error) {
+	if !supportedHash(h) {
+		return nil, fmt.Errorf("mldsatsp: unsupported hash algorithm %v", h)
+	}
+	d := h.New()
+	d.Write(data)
+	nonce, err := cryptorand.Int(cryptorand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, err
+	}
+	return &Request{HashAlgorithm: h, HashedMessage: d.Sum(nil), Nonce: nonce, CertReq: true}, nil
+}
+
+// Marshal returns the DER encoding of the request.
+func (r *Request) Marshal() ([]byte, error) {
+	mi, err := imprint(r.HashAlgorithm, r.HashedMessage)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(timeStampReq{
+		Version:        1,
+		MessageImprint: mi,
+		ReqPolicy:      r.Policy,
+		Nonce:          r.Nonce,
+		CertReq:        r.CertReq,
+	})
+}
+
+// ParseRequest parses a DER TimeStampReq. Requests with extensions are
+// rejected, since none are supported.
+func ParseRequest(der []byte) (*Request, error) {
+	var req timeStampReq
+	if rest, err := asn1.Unmarshal(der, &req); err != nil {
+		return nil, fmt.Errorf("mldsatsp: malformed request: %w", err)
+	} else if len(rest) != 0 {
+		return nil, errors.New("mldsatsp: trailing data after request")
+	}
+	if req.Version != 1 {
+		return nil, fmt.Errorf("mldsatsp: unsupported request version %d", req.Version)
+	}
+	if len(req.Extensions) != 0 {
+		return nil, errors.New("mldsatsp: request extensions are not supported")
+	}
+	h, err := req.MessageImprint.hash()
+	if err != nil {
+		return nil, err
+	}
+	return &Request{
+		HashAlgorithm: h,
+		HashedMessage: req.MessageImprint.HashedMessage,
+		Policy:        req.ReqPolicy,
+		Nonce:         req.Nonce,
+		CertReq:       req.CertReq,
+	}, nil
+}
+
+// Status is the PKIStatus of a TimeStampResp.
+type Status int
+
+const (
+	Granted Status = iota
+	GrantedWithMods
+	Rejection
+	Waiting
+	RevocationWarning
+	RevocationNotification
+)
+
+// FailureInfo is a PKIFailureInfo bit of a rejected request.
+type FailureInfo int
+
+const (
+	BadAlgorithm        FailureInfo = 0
+	BadRequest          FailureInfo = 2
+	BadDataFormat       FailureInfo = 5
+	TimeNotAvailable    FailureInfo = 14
+	UnacceptedPolicy    FailureInfo = 15
+	UnacceptedExtension FailureInfo = 16
+	AddInfoNotAvailable FailureInfo = 17
+	SystemFailure       FailureInfo = 25
+)
+
+// StatusError is returned by ParseResponse for responses that do not grant
+// a timestamp.
+type StatusError struct {
+	Status      Status
+	FailureInfo []FailureInfo
+	Text        string
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("mldsatsp: timestamp not granted (status %d", e.Status)
+	if len(e.FailureInfo) != 0 {
+		msg += fmt.Sprintf(", failure info %v", e.FailureInfo)
+	}
+	msg += ")"
+	if e.Text != "" {
+		msg += ": " + e.Text
+	}
+	return msg
+}
+
+// Authority is a time-stamping authority.
+type Authority struct {
+	// Certificate is the TSA certificate, with the id-kp-timeStamping
+	// extended key usage, and Key its ML-DSA private key.
+	Certificate *x509.Certificate
+	Key         mldsa.PrivateKey
+
+	// Chain holds intermediate certificates embedded with Certificate
+	// when a request sets CertReq.
+	Chain []*x509.Certificate
+
+	// Policy is the TSA policy stamped in every token. Requests for another
+	// policy are rejected.
+	Policy asn1.ObjectIdentifier
+
+	// Accuracy is the accuracy of the TSA clock, or zero if unspecified.
+	Accuracy time.Duration
+
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+
+	// Rand is the randomness source for hedged signing. If nil,
+	// crypto/rand.Reader is used.
+	Rand io.Reader
+}
+
+// Respond returns the DER TimeStampResp granting req a timestamp with the
+// given serial number, which must be unique for the TSA. Requests the TSA
+// cannot honor get a rejection response and a nil error; errors are only
+// returned when the TSA itself is misconfigured or cannot sign.
+func (a *Authority) Respond(req *Request, serial *big.Int) ([]byte, error) {
+	if !slices.Contains(a.Certificate.ExtKeyUsage, x509.ExtKeyUsageTimeStamping) {
+		return nil, ErrNotTimestampingCertificate
+	}
+	if len(a.Policy) == 0 {
+		return nil, errors.New("mldsatsp: authority has no policy")
+	}
+	if serial == nil || serial.Sign() < 0 {
+		return nil, errors.New("mldsatsp: serial number must be non-negative")
+	}
+	mi, err := imprint(req.HashAlgorithm, req.HashedMessage)
+	if err != nil {
+		return Reject(BadAlgorithm, err.Error())
+	}
+	if len(req.Policy) != 0 && !req.Policy.Equal(a.Policy) {
+		return Reject(UnacceptedPolicy, "policy "+req.Policy.String()+" is not supported")
+	}
+
+	now := time.Now
+	if a.Now != nil {
+		now = a.Now
+	}
+	info := tstInfo{
+		Version:        1,
+		Policy:         a.Policy,
+		MessageImprint: mi,
+		SerialNumber:   serial,
+		GenTime:        marshalGeneralizedTime(now()),
+		Accuracy:       marshalAccuracy(a.Accuracy),
+		Nonce:          req.Nonce,
+	}
+	content, err := asn1.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	token, err := mldsacms.Sign(content, a.Certificate, a.Key, &mldsacms.SignOptions{
+		ContentType:        oidTSTInfo,
+		SigningCertificate: true,
+		Certificates:       a.Chain,
+		OmitCertificates:   !req.CertReq,
+		Rand:               a.Rand,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(timeStampResp{
+		Status:         pkiStatusInfo{Status: int(Granted)},
+		TimeStampToken: asn1.RawValue{FullBytes: token},
+	})
+}
+
+// Reject returns a DER TimeStampResp rejecting a request for the given
+// reason, with an optional free-text explanation.
+func Reject(reason FailureInfo, text string) ([]byte, error) {
+	if reason < 0 || reason > SystemFailure {
+		return nil, fmt.Errorf("mldsatsp: invalid failure info %d", reason)
+	}
+	// A named bit list is encoded without trailing zero bits.
+	n := int(reason) + 1
+	info := pkiStatusInfo{
+		Status:   int(Rejection),
+		FailInfo: asn1.BitString{Bytes: make([]byte, (n+7)/8), BitLength: n},
+	}
+	info.FailInfo.Bytes[reason/8] |= 0x80 >> (reason % 8)
+	if text != "" {
+		s, err := asn1.MarshalWithParams(text, "utf8")
+		if err != nil {
+			return nil, err
+		}
+		info.StatusString = []asn1.RawValue{{FullBytes: s}}
+	}
+	return asn1.Marshal(timeStampResp{Status: info})
+}
+
+// marshalGeneralizedTime encodes t as a DER GeneralizedTime in UTC, with
+// fractional seconds down to the microsecond and trailing zeros removed.
+func marshalGeneralizedTime(t time.Time) asn1.RawValue {
+	s := t.UTC().Truncate(time.Microsecond).Format("20060102150405.999999Z")
+	return asn1.RawValue{Tag: asn1.TagGeneralizedTime, Bytes: []byte(s)}
+}
+
+// parseGeneralizedTime parses a GeneralizedTime in UTC with optional
+// fractional seconds, as encoding/asn1 rejects the latter.
+func parseGeneralizedTime(v asn1.RawValue) (time.Time, error) {
+	s := string(v.Bytes)
+	if v.Class != asn1.ClassUniversal || v.Tag != asn1.TagGeneralizedTime {
+		return time.Time{}, errors.New("mldsatsp: genTime is not a GeneralizedTime")
+	}
+	t, err := time.Parse("20060102150405Z", s)
+	if err != nil || !strings.HasSuffix(s, "Z") {
+		return time.Time{}, fmt.Errorf("mldsatsp: malformed genTime %q", s)
+	}
+	return t, nil
+}
+
+func marshalAccuracy(d time.Duration) accuracy {
+	d = d.Round(time.Microsecond)
+	return accuracy{
+		Seconds: int(d / time.Second),
+		Millis:  int(d % time.Second / time.Millisecond),
+		Micros:  int(d % time.Millisecond / time.Microsecond),
+	}
+}
+
+func (a accuracy) duration() (time.Duration, error) {
+	if a.Seconds < 0 || a.Millis < 0 || a.Millis > 999 || a.Micros < 0 || a.Micros > 999 {
+		return 0, errors.New("mldsatsp: malformed accuracy")
+	}
+	return time.Duration(a.Seconds)*time.Second + time.Duration(a.Millis)*time.Millisecond +
+		time.Duration(a.Micros)*time.Microsecond, nil
+}
+
+// Timestamp is a verified time-stamp token.
+type Timestamp struct {
+	HashAlgorithm crypto.Hash
+	HashedMessage []byte
+
+	// Time is the time the TSA stamped, and Accuracy its claimed accuracy,
+	// or zero if unspecified.
+	Time     time.Time
+	Accuracy time.Duration
+
+	SerialNumber *big.Int
+	Policy       asn1.ObjectIdentifier
+	Nonce        *big.Int
+	Ordering     bool
+
+	// Certificate is the TSA certificate that signed the token.
+	Certificate *mldsax509.Certificate
+
+	// Token is the DER time-stamp token, a CMS ContentInfo, for storing
+	// alongside the time-stamped data.
+	Token []byte
+}
+
+// ParseResponse parses a DER TimeStampResp and verifies its token as
+// ParseToken does. Responses that do not grant a timestamp return a
+// *StatusError.
+func ParseResponse(der []byte, certs ...*mldsax509.Certificate) (*Timestamp, error) {
+	var resp timeStampResp
+	if rest, err := asn1.Unmarshal(der, &resp); err != nil {
+		return nil, fmt.Errorf("mldsatsp: malformed response: %w", err)
+	} else if len(rest) != 0 {
+		return nil, errors.New("mldsatsp: trailing data after response")
+	}
+	status := Status(resp.Status.Status)
+	if status != Granted && status != GrantedWithMods {
+		e := &StatusError{Status: status}
+		for i := range resp.Status.FailInfo.BitLength {
+			if resp.Status.FailInfo.At(i) == 1 {
+				e.FailureInfo = append(e.FailureInfo, FailureInfo(i))
+			}
+		}
+		var text []string
+		for _, v := range resp.Status.StatusString {
+			text = append(text, string(v.Bytes))
+		}
+		e.Text = strings.Join(text, "; ")
+		return nil, e
+	}
+	if len(resp.TimeStampToken.FullBytes) == 0 {
+		return nil, errors.New("mldsatsp: granted response has no token")
+	}
+	return ParseToken(resp.TimeStampToken.FullBytes, certs...)
+}
+
+// ParseToken parses a DER time-stamp token and verifies its signature. The
+// TSA certificate is looked up in certs, or among the certificates embedded
+// in the token if certs is empty, and must carry the id-kp-timeStamping
+// extended key usage.
+func ParseToken(der []byte, certs ...*mldsax509.Certificate) (*Timestamp, error) {
+	sd, err := mldsacms.Parse(der)
+	if err != nil {
+		return nil, err
+	}
+	if !sd.ContentType.Equal(oidTSTInfo) {
+		return nil, fmt.Errorf("mldsatsp: token content type %v is not TSTInfo", sd.ContentType)
+	}
+	signers, err := sd.Verify(nil, certs...)
+	if err != nil {
+		return nil, err
+	}
+	if len(signers) != 1 {
+		return nil, fmt.Errorf("mldsatsp: token has %d signers, want 1", len(signers))
+	}
+	if !slices.Contains(signers[0].ExtKeyUsage, x509.ExtKeyUsageTimeStamping) {
+		return nil, ErrNotTimestampingCertificate
+	}
+
+	var info tstInfo
+	if rest, err := asn1.Unmarshal(sd.Content, &info); err != nil {
+		return nil, fmt.Errorf("mldsatsp: malformed TSTInfo: %w", err)
+	} else if len(rest) != 0 {
+		return nil, errors.New("mldsatsp: trailing data after TSTInfo")
+	}
+	if info.Version != 1 {
+		return nil, fmt.Errorf("mldsatsp: unsupported TSTInfo version %d", info.Version)
+	}
+	ts := &Timestamp{
+		HashedMessage: info.MessageImprint.HashedMessage,
+		SerialNumber:  info.SerialNumber,
+		Policy:        info.Policy,
+		Nonce:         info.Nonce,
+		Ordering:      info.Ordering,
+		Certificate:   signers[0],
+		Token:         der,
+	}
+	if ts.HashAlgorithm, err = info.MessageImprint.hash(); err != nil {
+		return nil, err
+	}
+	if ts.Time, err = parseGeneralizedTime(info.GenTime); err != nil {
+		return nil, err
+	}
+	if ts.Accuracy, err = info.Accuracy.duration(); err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+// Verify checks that the timestamp covers data.
+func (ts *Timestamp) Verify(data []byte) error {
+	d := ts.HashAlgorithm.New()
+	d.Write(data)
+	if !bytes.Equal(d.Sum(nil), ts.HashedMessage) {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// Match checks that the timestamp answers req: same message imprint, same
+// nonce, and the requested policy if any.
+func (ts *Timestamp) Match(req *Request) error {
+	if ts.HashAlgorithm != req.HashAlgorithm || !bytes.Equal(ts.HashedMessage, req.HashedMessage) {
+		return fmt.Errorf("%w: message imprint", ErrMismatch)
+	}
+	if (ts.Nonce == nil) != (req.Nonce == nil) || ts.Nonce != nil && ts.Nonce.Cmp(req.Nonce) != 0 {
+		return fmt.Errorf("%w: nonce", ErrMismatch)
+	}
+	if len(req.Policy) != 0 && !ts.Policy.Equal(req.Policy) {
+		return fmt.Errorf("%w: policy", ErrMismatch)
+	}
+	return nil
+}
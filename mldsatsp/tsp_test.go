@@ -0,0 +1,225 @@
+package mldsatsp
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/mldsa"
+	"github.com/KarpelesLab/mldsa/mldsacms"
+	"github.com/KarpelesLab/mldsa/mldsax509"
+)
+
+var testPolicy = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1}
+
+func newAuthority(t *testing.T, s mldsa.Scheme, eku ...x509.ExtKeyUsage) (*Authority, *mldsax509.Certificate) {
+	t.Helper()
+	key, err := s.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "TSA " + s.Name()},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  eku,
+	}
+	der, err := mldsax509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := mldsax509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Authority{Certificate: cert.Certificate, Key: key, Policy: testPolicy}, cert
+}
+
+func TestTimestamp(t *testing.T) {
+	data := []byte("archived document")
+	genTime := time.Date(2025, 6, 1, 12, 30, 45, 123400000, time.UTC)
+	for _, s := range mldsa.Schemes() {
+		tsa, cert := newAuthority(t, s, x509.ExtKeyUsageTimeStamping)
+		tsa.Accuracy = 1500 * time.Millisecond
+		tsa.Now = func() time.Time { return genTime }
+
+		for _, h := range []crypto.Hash{crypto.SHA256, crypto.SHA384, crypto.SHA512} {
+			req, err := NewRequest(data, h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			der, err := req.Marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := ParseRequest(der)
+			if err != nil {
+				t.Fatalf("%s %v: ParseRequest: %v", s.Name(), h, err)
+			}
+			resp, err := tsa.Respond(parsed, big.NewInt(42))
+			if err != nil {
+				t.Fatalf("%s %v: Respond: %v", s.Name(), h, err)
+			}
+			ts, err := ParseResponse(resp)
+			if err != nil {
+				t.Fatalf("%s %v: ParseResponse: %v", s.Name(), h, err)
+			}
+			if err := ts.Match(req); err != nil {
+				t.Errorf("%s %v: Match: %v", s.Name(), h, err)
+			}
+			if err := ts.Verify(data); err != nil {
+				t.Errorf("%s %v: Verify: %v", s.Name(), h, err)
+			}
+			if err := ts.Verify([]byte("other document")); !errors.Is(err, ErrMismatch) {
+				t.Errorf("%s %v: Verify of other data: %v", s.Name(), h, err)
+			}
+			if !ts.Time.Equal(genTime) || ts.Accuracy != tsa.Accuracy || ts.SerialNumber.Int64() != 42 ||
+				!ts.Policy.Equal(testPolicy) || !ts.Certificate.Equal(cert.Certificate) {
+				t.Errorf("%s %v: got %+v", s.Name(), h, ts)
+			}
+			if again, err := ParseToken(ts.Token, cert); err != nil || !again.Time.Equal(genTime) {
+				t.Errorf("%s %v: ParseToken: %v", s.Name(), h, err)
+			}
+		}
+	}
+}
+
+func TestCertReq(t *testing.T) {
+	tsa, cert := newAuthority(t, mldsa.SchemeByName("ML-DSA-44"), x509.ExtKeyUsageTimeStamping)
+	req, err := NewRequest([]byte("data"), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.CertReq = false
+	resp, err := tsa.Respond(req, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseResponse(resp); !errors.Is(err, mldsacms.ErrSignerNotFound) {
+		t.Errorf("ParseResponse without certificates: %v", err)
+	}
+	if _, err := ParseResponse(resp, cert); err != nil {
+		t.Errorf("ParseResponse with the TSA certificate: %v", err)
+	}
+}
+
+func TestRejections(t *testing.T) {
+	tsa, _ := newAuthority(t, mldsa.SchemeByName("ML-DSA-44"), x509.ExtKeyUsageTimeStamping)
+	req, err := NewRequest([]byte("data"), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Policy = asn1.ObjectIdentifier{1, 2, 3}
+	resp, err := tsa.Respond(req, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var se *StatusError
+	if _, err := ParseResponse(resp); !errors.As(err, &se) || se.Status != Rejection ||
+		!slices.Equal(se.FailureInfo, []FailureInfo{UnacceptedPolicy}) || se.Text == "" {
+		t.Errorf("unaccepted policy: %v", err)
+	}
+
+	req.Policy = nil
+	req.HashAlgorithm = crypto.SHA1
+	resp, err = tsa.Respond(req, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseResponse(resp); !errors.As(err, &se) || !slices.Equal(se.FailureInfo, []FailureInfo{BadAlgorithm}) {
+		t.Errorf("bad algorithm: %v", err)
+	}
+	for _, h := range []crypto.Hash{crypto.SHA1, mldsa.SHAKE128} {
+		if _, err := NewRequest([]byte("data"), h); err == nil {
+			t.Errorf("NewRequest accepted %v", h)
+		}
+	}
+}
+
+func TestMatchRejects(t *testing.T) {
+	tsa, _ := newAuthority(t, mldsa.SchemeByName("ML-DSA-44"), x509.ExtKeyUsageTimeStamping)
+	req, err := NewRequest([]byte("data"), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := tsa.Respond(req, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := ParseResponse(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := NewRequest([]byte("data"), crypto.SHA256)
+	if err := ts.Match(other); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Match with another nonce: %v", err)
+	}
+	other, _ = NewRequest([]byte("other"), crypto.SHA256)
+	other.Nonce = req.Nonce
+	if err := ts.Match(other); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Match with another message: %v", err)
+	}
+}
+
+func TestRequiresTimestampingCertificate(t *testing.T) {
+	tsa, _ := newAuthority(t, mldsa.SchemeByName("ML-DSA-44"), x509.ExtKeyUsageCodeSigning)
+	req, err := NewRequest([]byte("data"), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tsa.Respond(req, big.NewInt(1)); !errors.Is(err, ErrNotTimestampingCertificate) {
+		t.Errorf("Respond: %v", err)
+	}
+
+	// A token signed directly through mldsacms must still be refused.
+	mi, _ := imprint(req.HashAlgorithm, req.HashedMessage)
+	content, err := asn1.Marshal(tstInfo{
+		Version:        1,
+		Policy:         testPolicy,
+		MessageImprint: mi,
+		SerialNumber:   big.NewInt(1),
+		GenTime:        marshalGeneralizedTime(time.Now()),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := mldsacms.Sign(content, tsa.Certificate, tsa.Key, &mldsacms.SignOptions{ContentType: oidTSTInfo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseToken(token); !errors.Is(err, ErrNotTimestampingCertificate) {
+		t.Errorf("ParseToken: %v", err)
+	}
+}
+
+func TestGeneralizedTime(t *testing.T) {
+	for in, want := range map[time.Time]string{
+		time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC):         "20250102030405Z",
+		time.Date(2025, 1, 2, 3, 4, 5, 500000000, time.UTC): "20250102030405.5Z",
+		time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC): "20250102030405.123456Z",
+	} {
+		v := marshalGeneralizedTime(in)
+		if string(v.Bytes) != want {
+			t.Errorf("marshalGeneralizedTime(%v) = %s, want %s", in, v.Bytes, want)
+		}
+		got, err := parseGeneralizedTime(v)
+		if err != nil || !got.Equal(in.Truncate(time.Microsecond)) {
+			t.Errorf("parseGeneralizedTime(%s) = %v, %v", want, got, err)
+		}
+	}
+	for _, bad := range []string{"20250102030405", "20250102030405+0100", "2025010203Z"} {
+		if _, err := parseGeneralizedTime(asn1.RawValue{Tag: asn1.TagGeneralizedTime, Bytes: []byte(bad)}); err == nil {
+			t.Errorf("parseGeneralizedTime(%s) succeeded", bad)
+		}
+	}
+}
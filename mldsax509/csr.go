@@ -0,0 +1,183 @@
+package mldsax509
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	cryptorand "crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// RequestOptions configures CreateCertificateRequestWithOptions.
+type RequestOptions struct {
+	// PreHash signs the request with HashML-DSA over SHA-512
+	// (id-hash-ml-dsa-*-with-sha512) instead of pure ML-DSA, for CAs that
+	// require pre-hashed signatures.
+	PreHash bool
+}
+
+// certificateRequest is the PKCS #10 CertificationRequest structure.
+type certificateRequest struct {
+	CertificationRequestInfo asn1.RawValue
+	SignatureAlgorithm       pkix.AlgorithmIdentifier
+	Signature                asn1.BitString
+}
+
+// certificationRequestInfo is the PKCS #10 CertificationRequestInfo
+// structure, with its fields kept in their original encoding.
+type certificationRequestInfo struct {
+	Version       int
+	Subject       asn1.RawValue
+	PublicKey     asn1.RawValue
+	RawAttributes []asn1.RawValue `asn1:"tag:0"`
+}
+
+// CreateCertificateRequest creates a PKCS #10 certificate signing request
+// based on template for the ML-DSA key priv, signed with pure ML-DSA. It is
+// shorthand for CreateCertificateRequestWithOptions with nil options.
+func CreateCertificateRequest(rand io.Reader, template *x509.CertificateRequest, priv mldsa.PrivateKey) ([]byte, error) {
+	return CreateCertificateRequestWithOptions(rand, template, priv, nil)
+}
+
+// CreateCertificateRequestWithOptions creates a PKCS #10 certificate signing
+// request based on template for the ML-DSA key priv, and returns it in DER
+// form. The subject, SANs, extensions and attributes of template are
+// encoded as by x509.CreateCertificateRequest; its SignatureAlgorithm is
+// ignored. If rand is nil, crypto/rand.Reader is used.
+func CreateCertificateRequestWithOptions(rand io.Reader, template *x509.CertificateRequest, priv mldsa.PrivateKey, opts *RequestOptions) ([]byte, error) {
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	if opts == nil {
+		opts = &RequestOptions{}
+	}
+	params, err := mldsa.ParseParameterSet(priv.Scheme().Name())
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := priv.Public().(mldsa.PublicKey)
+	if !ok {
+		return nil, errors.New("mldsax509: private key has no ML-DSA public key")
+	}
+	spki, err := mldsa.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	_, placeholder, err := ed25519.GenerateKey(cryptorand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := *template
+	tmpl.SignatureAlgorithm = x509.UnknownSignatureAlgorithm
+	der, err := x509.CreateCertificateRequest(rand, &tmpl, placeholder)
+	if err != nil {
+		return nil, err
+	}
+
+	var csr certificateRequest
+	if _, err := asn1.Unmarshal(der, &csr); err != nil {
+		return nil, err
+	}
+	var info certificationRequestInfo
+	if _, err := asn1.Unmarshal(csr.CertificationRequestInfo.FullBytes, &info); err != nil {
+		return nil, err
+	}
+	info.PublicKey = asn1.RawValue{FullBytes: spki}
+	infoDER, err := asn1.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+
+	var alg pkix.AlgorithmIdentifier
+	var sig []byte
+	if opts.PreHash {
+		alg, _ = params.HashAlgorithmIdentifier(crypto.SHA512)
+		digest := sha512.Sum512(infoDER)
+		sig, err = priv.Sign(rand, digest[:], &mldsa.SignerOpts{Hash: crypto.SHA512})
+	} else {
+		alg = params.AlgorithmIdentifier()
+		sig, err = priv.SignWithContext(rand, infoDER, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(certificateRequest{
+		CertificationRequestInfo: asn1.RawValue{FullBytes: infoDER},
+		SignatureAlgorithm:       alg,
+		Signature:                asn1.BitString{Bytes: sig, BitLength: 8 * len(sig)},
+	})
+}
+
+// CertificateRequest is a parsed PKCS #10 certificate signing request with
+// its ML-DSA details.
+type CertificateRequest struct {
+	*x509.CertificateRequest
+
+	// MLDSAPublicKey is the requested ML-DSA public key, or nil if the
+	// request holds another kind of key.
+	MLDSAPublicKey mldsa.PublicKey
+
+	// SignatureParameterSet is the ML-DSA parameter set of the request
+	// signature, or 0 if it is not signed with ML-DSA.
+	SignatureParameterSet mldsa.ParameterSet
+
+	// PreHash is crypto.SHA512 if the request is signed with HashML-DSA,
+	// and 0 for pure ML-DSA.
+	PreHash crypto.Hash
+}
+
+// ParseCertificateRequest parses a single DER certificate signing request.
+func ParseCertificateRequest(der []byte) (*CertificateRequest, error) {
+	r, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return nil, err
+	}
+	req := &CertificateRequest{CertificateRequest: r}
+
+	var outer certificateRequest
+	if _, err := asn1.Unmarshal(r.Raw, &outer); err != nil {
+		return nil, err
+	}
+	req.SignatureParameterSet, req.PreHash, err = signatureAlgorithm(outer.SignatureAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	req.MLDSAPublicKey, err = parsePublicKey(r.RawSubjectPublicKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// CheckSignature verifies that the request is signed by the ML-DSA key it
+// contains, proving possession of the private key.
+func (r *CertificateRequest) CheckSignature() error {
+	if r.SignatureParameterSet == 0 || r.MLDSAPublicKey == nil {
+		return errors.New("mldsax509: certificate request is not signed with ML-DSA")
+	}
+	pub := r.MLDSAPublicKey
+	if pub.Scheme().Name() != r.SignatureParameterSet.String() {
+		return fmt.Errorf("mldsax509: %s signature cannot be verified with an %s key", r.SignatureParameterSet, pub.Scheme().Name())
+	}
+	var ok bool
+	if r.PreHash != 0 {
+		digest := sha512.Sum512(r.RawTBSCertificateRequest)
+		ok = pub.(interface {
+			VerifyPreHash(sig, digest []byte, ph crypto.Hash, context []byte) bool
+		}).VerifyPreHash(r.Signature, digest[:], crypto.SHA512, nil)
+	} else {
+		ok = pub.Verify(r.Signature, r.RawTBSCertificateRequest, nil)
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
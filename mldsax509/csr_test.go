@@ -0,0 +1,71 @@
+package mldsax509
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"testing"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestCertificateRequest(t *testing.T) {
+	template := &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "pq.example.com", Organization: []string{"Example"}},
+		DNSNames: []string{"pq.example.com", "www.pq.example.com"},
+	}
+	for _, s := range mldsa.Schemes() {
+		key, _ := s.GenerateKey(rand.Reader)
+		for _, preHash := range []bool{false, true} {
+			der, err := CreateCertificateRequestWithOptions(rand.Reader, template, key, &RequestOptions{PreHash: preHash})
+			if err != nil {
+				t.Fatalf("%s (pre-hash %v): %v", s.Name(), preHash, err)
+			}
+			req, err := ParseCertificateRequest(der)
+			if err != nil {
+				t.Fatalf("%s (pre-hash %v): ParseCertificateRequest: %v", s.Name(), preHash, err)
+			}
+			if req.SignatureParameterSet.String() != s.Name() {
+				t.Errorf("%s: signature parameter set is %v", s.Name(), req.SignatureParameterSet)
+			}
+			if want := map[bool]crypto.Hash{false: 0, true: crypto.SHA512}[preHash]; req.PreHash != want {
+				t.Errorf("%s: PreHash = %v, want %v", s.Name(), req.PreHash, want)
+			}
+			if req.MLDSAPublicKey == nil || !req.MLDSAPublicKey.Equal(key.Public()) {
+				t.Errorf("%s: request key does not match", s.Name())
+			}
+			if req.Subject.CommonName != "pq.example.com" || len(req.DNSNames) != 2 {
+				t.Errorf("%s: template fields not preserved", s.Name())
+			}
+			if err := req.CheckSignature(); err != nil {
+				t.Errorf("%s (pre-hash %v): CheckSignature: %v", s.Name(), preHash, err)
+			}
+
+			der[len(der)-1] ^= 1
+			tampered, err := ParseCertificateRequest(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tampered.CheckSignature(); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("%s (pre-hash %v): tampered request: %v", s.Name(), preHash, err)
+			}
+		}
+	}
+}
+
+func TestCertificateRequestPreHashDiffers(t *testing.T) {
+	key, _ := mldsa.GenerateKey65(rand.Reader)
+	template := &x509.CertificateRequest{Subject: pkix.Name{CommonName: "test"}}
+	der, err := CreateCertificateRequestWithOptions(nil, template, key, &RequestOptions{PreHash: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := ParseCertificateRequest(der)
+	// A HashML-DSA signature must not verify as pure ML-DSA.
+	req.PreHash = 0
+	if err := req.CheckSignature(); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("HashML-DSA signature verified as pure ML-DSA: %v", err)
+	}
+}
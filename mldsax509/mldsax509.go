@@ -0,0 +1,246 @@
+// Package mldsax509 creates and parses X.509 certificates signed with
+// ML-DSA, as profiled in RFC 9881, for Go versions whose crypto/x509 cannot
+// sign with ML-DSA keys.
+//
+// Certificates are built from an *x509.Certificate template exactly as
+// x509.CreateCertificate would build them, so all of its template fields
+// are honored. The subject public key may be an ML-DSA key or any key type
+// supported by crypto/x509; the issuer key must be an ML-DSA private key.
+// Signatures use pure ML-DSA with an empty context.
+package mldsax509
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// ErrInvalidSignature is returned when a certificate signature does not
+// verify.
+var ErrInvalidSignature = errors.New("mldsax509: invalid certificate signature")
+
+// certificate is the outer X.509 Certificate structure.
+type certificate struct {
+	TBSCertificate     asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SignatureValue     asn1.BitString
+}
+
+// tbsCertificate is the X.509 TBSCertificate structure, with every field
+// kept in its original encoding so it can be re-encoded unchanged.
+type tbsCertificate struct {
+	Version            int `asn1:"optional,explicit,default:0,tag:0"`
+	SerialNumber       asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Issuer             asn1.RawValue
+	Validity           asn1.RawValue
+	Subject            asn1.RawValue
+	PublicKey          asn1.RawValue
+	UniqueID           asn1.BitString `asn1:"optional,tag:1"`
+	SubjectUniqueID    asn1.BitString `asn1:"optional,tag:2"`
+	Extensions         asn1.RawValue  `asn1:"optional,explicit,tag:3"`
+}
+
+// CreateCertificate creates a new X.509 v3 certificate based on template and
+// signed by priv, the ML-DSA private key of the issuer described by parent.
+// For a self-signed certificate, parent is template and priv matches pub.
+// pub is the subject's public key: an mldsa.PublicKey or any public key
+// accepted by x509.MarshalPKIXPublicKey. It returns the DER certificate.
+//
+// As with x509.CreateCertificate, the AuthorityKeyId is taken from
+// parent.SubjectKeyId, and a SubjectKeyId is generated for CA certificates
+// that do not set one. template.SignatureAlgorithm is ignored: the signature
+// algorithm is the parameter set of priv. If rand is nil, crypto/rand.Reader
+// is used.
+func CreateCertificate(rand io.Reader, template, parent *x509.Certificate, pub any, priv mldsa.PrivateKey) ([]byte, error) {
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	params, err := mldsa.ParseParameterSet(priv.Scheme().Name())
+	if err != nil {
+		return nil, err
+	}
+	spki, err := marshalPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	// Let crypto/x509 encode the TBSCertificate around a placeholder key,
+	// then swap in the real key and signature algorithm.
+	placeholderPub, placeholder, err := ed25519.GenerateKey(cryptorand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := *template
+	tmpl.SignatureAlgorithm = x509.UnknownSignatureAlgorithm
+	tmpl.PublicKey = nil
+	if len(tmpl.SubjectKeyId) == 0 && tmpl.IsCA {
+		tmpl.SubjectKeyId = subjectKeyID(spki)
+	}
+	par := &tmpl
+	if parent != template {
+		p := *parent
+		p.PublicKey = nil
+		par = &p
+	}
+	der, err := x509.CreateCertificate(rand, &tmpl, par, placeholderPub, placeholder)
+	if err != nil {
+		return nil, err
+	}
+
+	var cert certificate
+	if _, err := asn1.Unmarshal(der, &cert); err != nil {
+		return nil, err
+	}
+	var tbs tbsCertificate
+	if _, err := asn1.Unmarshal(cert.TBSCertificate.FullBytes, &tbs); err != nil {
+		return nil, err
+	}
+	alg := params.AlgorithmIdentifier()
+	tbs.SignatureAlgorithm = alg
+	tbs.PublicKey = asn1.RawValue{FullBytes: spki}
+	tbsDER, err := asn1.Marshal(tbs)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := priv.SignWithContext(rand, tbsDER, nil)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(certificate{
+		TBSCertificate:     asn1.RawValue{FullBytes: tbsDER},
+		SignatureAlgorithm: alg,
+		SignatureValue:     asn1.BitString{Bytes: sig, BitLength: 8 * len(sig)},
+	})
+}
+
+// marshalPublicKey returns the DER SubjectPublicKeyInfo of pub.
+func marshalPublicKey(pub any) ([]byte, error) {
+	if pk, ok := pub.(mldsa.PublicKey); ok {
+		return mldsa.MarshalPKIXPublicKey(pk)
+	}
+	return x509.MarshalPKIXPublicKey(pub)
+}
+
+// subjectKeyID computes a key identifier with method 1 of RFC 7093: the
+// leftmost 160 bits of the SHA-256 hash of the subjectPublicKey BIT STRING.
+func subjectKeyID(spki []byte) []byte {
+	var info struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(spki, &info); err != nil {
+		return nil
+	}
+	h := sha256.Sum256(info.PublicKey.Bytes)
+	return h[:20]
+}
+
+// Certificate is a parsed X.509 certificate with its ML-DSA details.
+type Certificate struct {
+	*x509.Certificate
+
+	// MLDSAPublicKey is the subject's ML-DSA public key, or nil if the
+	// certificate holds another kind of key.
+	MLDSAPublicKey mldsa.PublicKey
+
+	// SignatureParameterSet is the ML-DSA parameter set of the certificate
+	// signature, or 0 if it is not signed with ML-DSA.
+	SignatureParameterSet mldsa.ParameterSet
+}
+
+// ParseCertificate parses a single DER certificate. Certificates that are
+// not signed with ML-DSA or do not hold an ML-DSA key are accepted, with
+// the corresponding fields of Certificate left empty.
+func ParseCertificate(der []byte) (*Certificate, error) {
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	cert := &Certificate{Certificate: c}
+
+	var outer certificate
+	if _, err := asn1.Unmarshal(c.Raw, &outer); err != nil {
+		return nil, err
+	}
+	// RFC 9881 uses pure ML-DSA only: HashML-DSA certificates are reported
+	// as not signed with ML-DSA.
+	if ps, ph, err := signatureAlgorithm(outer.SignatureAlgorithm); err != nil {
+		return nil, err
+	} else if ph == 0 {
+		cert.SignatureParameterSet = ps
+	}
+	cert.MLDSAPublicKey, err = parsePublicKey(c.RawSubjectPublicKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// signatureAlgorithm returns the ML-DSA parameter set and pre-hash function
+// identified by alg, or 0 and 0 if alg is not an ML-DSA algorithm.
+func signatureAlgorithm(alg pkix.AlgorithmIdentifier) (mldsa.ParameterSet, crypto.Hash, error) {
+	p, ph, err := mldsa.ParseAlgorithmIdentifier(alg)
+	if errors.Is(err, mldsa.ErrUnknownParameterSet) {
+		return 0, 0, nil
+	}
+	return p, ph, err
+}
+
+// parsePublicKey parses an ML-DSA SubjectPublicKeyInfo, or returns nil if
+// spki holds another kind of key.
+func parsePublicKey(spki []byte) (mldsa.PublicKey, error) {
+	var info struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(spki, &info); err != nil {
+		return nil, err
+	}
+	if p, ph, _ := signatureAlgorithm(info.Algorithm); p == 0 || ph != 0 {
+		return nil, nil
+	}
+	return mldsa.ParsePKIXPublicKey(spki)
+}
+
+// CheckSignature verifies that the signature of c was made by pub.
+func (c *Certificate) CheckSignature(pub mldsa.PublicKey) error {
+	if c.SignatureParameterSet == 0 {
+		return errors.New("mldsax509: certificate is not signed with ML-DSA")
+	}
+	if pub.Scheme().Name() != c.SignatureParameterSet.String() {
+		return fmt.Errorf("mldsax509: %s signature cannot be verified with an %s key", c.SignatureParameterSet, pub.Scheme().Name())
+	}
+	if !pub.Verify(c.Signature, c.RawTBSCertificate, nil) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// CheckSignatureFrom verifies that the signature of c was made by the
+// issuer certificate parent, which must hold an ML-DSA key and be allowed
+// to sign certificates, with the same checks as
+// x509.Certificate.CheckSignatureFrom.
+func (c *Certificate) CheckSignatureFrom(parent *Certificate) error {
+	if parent.Version == 3 && !parent.BasicConstraintsValid ||
+		parent.BasicConstraintsValid && !parent.IsCA {
+		return x509.ConstraintViolationError{}
+	}
+	if parent.KeyUsage != 0 && parent.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return x509.ConstraintViolationError{}
+	}
+	if parent.MLDSAPublicKey == nil {
+		return errors.New("mldsax509: issuer certificate does not hold an ML-DSA key")
+	}
+	return c.CheckSignature(parent.MLDSAPublicKey)
+}
@@ -0,0 +1,142 @@
+package mldsax509
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func TestCertificateChain(t *testing.T) {
+	now := time.Now()
+	caKey, _ := mldsa.GenerateKey65(rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.PublicKey(), caKey)
+	if err != nil {
+		t.Fatalf("CA certificate: %v", err)
+	}
+	ca, err := ParseCertificate(caDER)
+	if err != nil {
+		t.Fatalf("ParseCertificate(CA): %v", err)
+	}
+	if ca.SignatureParameterSet != mldsa.MLDSA65 || ca.MLDSAPublicKey == nil || !ca.MLDSAPublicKey.Equal(caKey.PublicKey()) {
+		t.Fatalf("CA certificate: unexpected key or signature algorithm")
+	}
+	if ca.Subject.CommonName != "Test CA" || !ca.IsCA || len(ca.SubjectKeyId) != 20 {
+		t.Errorf("CA certificate: template fields not preserved")
+	}
+	if err := ca.CheckSignatureFrom(ca); err != nil {
+		t.Errorf("self-signed CA: %v", err)
+	}
+
+	leafKey, _ := mldsa.GenerateKey44(rand.Reader)
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "leaf.example.com"},
+		DNSNames:     []string{"leaf.example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	leafDER, err := CreateCertificate(rand.Reader, leafTemplate, ca.Certificate, leafKey.PublicKey(), caKey)
+	if err != nil {
+		t.Fatalf("leaf certificate: %v", err)
+	}
+	leaf, err := ParseCertificate(leafDER)
+	if err != nil {
+		t.Fatalf("ParseCertificate(leaf): %v", err)
+	}
+	if leaf.SignatureParameterSet != mldsa.MLDSA65 || !leaf.MLDSAPublicKey.Equal(leafKey.PublicKey()) {
+		t.Errorf("leaf certificate: unexpected key or signature algorithm")
+	}
+	if leaf.Issuer.CommonName != "Test CA" || leaf.DNSNames[0] != "leaf.example.com" {
+		t.Errorf("leaf certificate: template fields not preserved")
+	}
+	if string(leaf.AuthorityKeyId) != string(ca.SubjectKeyId) {
+		t.Errorf("leaf AuthorityKeyId does not match the CA SubjectKeyId")
+	}
+	if err := leaf.CheckSignatureFrom(ca); err != nil {
+		t.Errorf("leaf: %v", err)
+	}
+	if err := ca.CheckSignatureFrom(leaf); err == nil {
+		t.Error("a non-CA certificate was accepted as issuer")
+	}
+
+	// A leaf with a classical key issued by the ML-DSA CA.
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecDER, err := CreateCertificate(rand.Reader, leafTemplate, ca.Certificate, &ecKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("ECDSA leaf certificate: %v", err)
+	}
+	ecLeaf, err := ParseCertificate(ecDER)
+	if err != nil {
+		t.Fatalf("ParseCertificate(ECDSA leaf): %v", err)
+	}
+	if ecLeaf.MLDSAPublicKey != nil {
+		t.Error("ECDSA leaf reported an ML-DSA key")
+	}
+	if pub, ok := ecLeaf.PublicKey.(*ecdsa.PublicKey); !ok || !pub.Equal(&ecKey.PublicKey) {
+		t.Error("ECDSA leaf key not preserved")
+	}
+	if err := ecLeaf.CheckSignatureFrom(ca); err != nil {
+		t.Errorf("ECDSA leaf: %v", err)
+	}
+}
+
+func TestCheckSignatureRejects(t *testing.T) {
+	key, _ := mldsa.GenerateKey87(rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Self-signed"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := CreateCertificate(nil, template, template, key.PublicKey(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cert.CheckSignature(key.PublicKey()); err != nil {
+		t.Fatalf("CheckSignature: %v", err)
+	}
+
+	other, _ := mldsa.GenerateKey87(rand.Reader)
+	if err := cert.CheckSignature(other.PublicKey()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("wrong key: %v", err)
+	}
+	wrongSet, _ := mldsa.GenerateKey44(rand.Reader)
+	if err := cert.CheckSignature(wrongSet.PublicKey()); err == nil {
+		t.Error("a key of another parameter set was accepted")
+	}
+
+	// Flip a bit inside the signature.
+	der[len(der)-10] ^= 1
+	tampered, err := ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tampered.CheckSignatureFrom(cert); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("tampered certificate: %v", err)
+	}
+}
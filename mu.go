@@ -0,0 +1,42 @@
+package mldsa
+
+import (
+	"crypto/sha3"
+	"io"
+)
+
+// MuSize is the size in bytes of the message representative µ.
+const MuSize = 64
+
+// computeMu derives µ = H(tr || M') (FIPS 204 Algorithm 7, line 6) for the
+// pure ML-DSA message representative M' = 0 || len(ctx) || ctx || M, reading
+// M from r. The message is streamed into the hash and never buffered.
+func computeMu(tr []byte, r io.Reader, context []byte) ([MuSize]byte, error) {
+	var mu [MuSize]byte
+	if len(context) > 255 {
+		return mu, ErrContextTooLong
+	}
+	h := sha3.NewSHAKE256()
+	h.Write(tr)
+	h.Write([]byte{0, byte(len(context))})
+	h.Write(context)
+	if _, err := io.Copy(h, r); err != nil {
+		return mu, err
+	}
+	h.Read(mu[:])
+	return mu, nil
+}
+
+// pureMu is like computeMu for a message held in memory. It hashes the
+// message in place rather than copying it into M', so signing does not
+// allocate. The context length must already have been checked.
+func pureMu(tr, message, context []byte) [MuSize]byte {
+	h := sha3.NewSHAKE256()
+	h.Write(tr)
+	h.Write([]byte{0, byte(len(context))})
+	h.Write(context)
+	h.Write(message)
+	var mu [MuSize]byte
+	h.Read(mu[:])
+	return mu
+}
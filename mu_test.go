@@ -0,0 +1,160 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha3"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestComputeMu(t *testing.T) {
+	message := []byte("relayed message")
+	context := []byte("ctx")
+
+	type muComputer interface {
+		ComputeMu(message, context []byte) ([MuSize]byte, error)
+	}
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		pk := sk.Public().(PublicKey)
+
+		// µ = SHAKE256(SHAKE256(pk, 64) || 0 || len(ctx) || ctx || M, 64)
+		tr := sha3.SumSHAKE256(pk.Bytes(), 64)
+		in := append(append(append(tr, 0, byte(len(context))), context...), message...)
+		want := sha3.SumSHAKE256(in, MuSize)
+
+		for _, c := range []muComputer{sk.(muComputer), pk.(muComputer)} {
+			mu, err := c.ComputeMu(message, context)
+			if err != nil {
+				t.Fatalf("%s %T: ComputeMu failed: %v", s.Name(), c, err)
+			}
+			if !bytes.Equal(mu[:], want) {
+				t.Errorf("%s %T: ComputeMu mismatch", s.Name(), c)
+			}
+		}
+	}
+
+	key, _ := GenerateKey87(rand.Reader)
+	mu, _ := key.ComputeMu(message, nil)
+	streamed, err := key.PublicKey().ComputeMuReader(iotest.OneByteReader(bytes.NewReader(message)), nil)
+	if err != nil || streamed != mu {
+		t.Errorf("ComputeMuReader = %x, %v; want %x", streamed, err, mu)
+	}
+	if _, err := key.ComputeMu(message, make([]byte, 256)); !errors.Is(err, ErrContextTooLong) {
+		t.Errorf("ComputeMu with a long context: %v", err)
+	}
+	if _, err := key.ComputeMuReader(iotest.ErrReader(errors.New("boom")), nil); err == nil {
+		t.Error("ComputeMuReader ignored a read error")
+	}
+}
+
+func TestSignMu(t *testing.T) {
+	message := []byte("hashed elsewhere")
+	context := []byte("ctx")
+
+	type muSigner interface {
+		SignMu(rand io.Reader, mu [MuSize]byte) ([]byte, error)
+		Destroy()
+	}
+	type muVerifier interface {
+		ComputeMu(message, context []byte) ([MuSize]byte, error)
+		VerifyMu(sig []byte, mu [MuSize]byte) bool
+	}
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		pk := sk.Public().(PublicKey)
+		mu, err := pk.(muVerifier).ComputeMu(message, context)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := sk.(muSigner).SignMu(rand.Reader, mu)
+		if err != nil {
+			t.Fatalf("%s: SignMu: %v", s.Name(), err)
+		}
+		if !pk.Verify(sig, message, context) {
+			t.Errorf("%s: external-µ signature does not verify", s.Name())
+		}
+		if !pk.(muVerifier).VerifyMu(sig, mu) {
+			t.Errorf("%s: VerifyMu rejected a valid signature", s.Name())
+		}
+		mu[0] ^= 1
+		if pk.(muVerifier).VerifyMu(sig, mu) {
+			t.Errorf("%s: VerifyMu accepted another µ", s.Name())
+		}
+		if pk.(muVerifier).VerifyMu(sig[:len(sig)-1], mu) {
+			t.Errorf("%s: VerifyMu accepted a truncated signature", s.Name())
+		}
+		sk.(muSigner).Destroy()
+		if _, err := sk.(muSigner).SignMu(rand.Reader, mu); !errors.Is(err, ErrKeyDestroyed) {
+			t.Errorf("%s: SignMu with a destroyed key: %v", s.Name(), err)
+		}
+	}
+}
+
+func TestSignReader(t *testing.T) {
+	chunk := bytes.Repeat([]byte("streamed "), 1000)
+	message := bytes.Repeat(chunk, 100)
+	context := []byte("ctx")
+
+	type readerSigner interface {
+		SignReader(rand io.Reader, msg io.Reader, context []byte) ([]byte, error)
+	}
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		pk := sk.Public().(PublicKey)
+		sig, err := sk.(readerSigner).SignReader(rand.Reader, iotest.OneByteReader(bytes.NewReader(message[:10000])), context)
+		if err != nil {
+			t.Fatalf("%s: SignReader: %v", s.Name(), err)
+		}
+		if !pk.Verify(sig, message[:10000], context) {
+			t.Errorf("%s: streamed signature does not verify", s.Name())
+		}
+		sig, err = sk.(readerSigner).SignReader(rand.Reader, bytes.NewReader(message), nil)
+		if err != nil || !pk.Verify(sig, message, nil) {
+			t.Errorf("%s: streamed signature of a large message: %v", s.Name(), err)
+		}
+		errRead := errors.New("read failed")
+		if _, err := sk.(readerSigner).SignReader(rand.Reader, iotest.ErrReader(errRead), nil); !errors.Is(err, errRead) {
+			t.Errorf("%s: SignReader with a failing reader: %v", s.Name(), err)
+		}
+		if _, err := sk.(readerSigner).SignReader(rand.Reader, bytes.NewReader(nil), make([]byte, 256)); !errors.Is(err, ErrContextTooLong) {
+			t.Errorf("%s: SignReader with a long context: %v", s.Name(), err)
+		}
+	}
+}
+
+func TestVerifyReader(t *testing.T) {
+	message := bytes.Repeat([]byte("disk image "), 10000)
+	context := []byte("ctx")
+
+	type readerVerifier interface {
+		VerifyReader(sig []byte, msg io.Reader, context []byte) bool
+	}
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		pk := sk.Public().(readerVerifier)
+		sig, err := sk.SignWithContext(rand.Reader, message, context)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pk.VerifyReader(sig, iotest.HalfReader(bytes.NewReader(message)), context) {
+			t.Errorf("%s: VerifyReader rejected a valid signature", s.Name())
+		}
+		if pk.VerifyReader(sig, bytes.NewReader(message[1:]), context) {
+			t.Errorf("%s: VerifyReader accepted another message", s.Name())
+		}
+		if pk.VerifyReader(sig, bytes.NewReader(message), nil) {
+			t.Errorf("%s: VerifyReader accepted another context", s.Name())
+		}
+		if pk.VerifyReader(sig[1:], bytes.NewReader(message), context) {
+			t.Errorf("%s: VerifyReader accepted a truncated signature", s.Name())
+		}
+		failing := io.MultiReader(bytes.NewReader(message), iotest.ErrReader(errors.New("read failed")))
+		if pk.VerifyReader(sig, failing, context) {
+			t.Errorf("%s: VerifyReader accepted a failing reader", s.Name())
+		}
+	}
+}
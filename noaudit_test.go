@@ -0,0 +1,5 @@
+//go:build !mldsa_ctaudit
+
+package mldsa
+
+const auditEnabled = false
@@ -0,0 +1,5 @@
+//go:build !purego
+
+package mldsa
+
+const puregoEnabled = false
@@ -42,6 +42,14 @@ var zetas = [N]FieldElement{
 // The input is in standard form, output is in NTT form (bit-reversed order).
 // Implements FIPS 204 Algorithm 41.
 func NTT(f RingElement) NttElement {
+	nttInPlace((*[N]FieldElement)(&f))
+	return NttElement(f)
+}
+
+// nttInPlace is NTT operating on f in place. The signing and verification
+// loops use the in-place forms, which do not pass polynomials by value, to
+// keep their stack frames small.
+func nttInPlace(f *[N]FieldElement) {
 	k := 1
 	for length := 128; length >= 1; length /= 2 {
 		for start := 0; start < N; start += 2 * length {
@@ -57,13 +65,24 @@ func NTT(f RingElement) NttElement {
 			}
 		}
 	}
-	return NttElement(f)
+}
+
+// nttTo stores NTT(*f) in dst.
+func nttTo(dst *NttElement, f *RingElement) {
+	*dst = NttElement(*f)
+	nttInPlace((*[N]FieldElement)(dst))
 }
 
 // InvNTT performs the inverse Number Theoretic Transform.
 // Input is in NTT form, output is in standard polynomial form.
 // Implements FIPS 204 Algorithm 42.
 func InvNTT(f NttElement) RingElement {
+	invNTTInPlace((*[N]FieldElement)(&f))
+	return RingElement(f)
+}
+
+// invNTTInPlace is InvNTT operating on f in place.
+func invNTTInPlace(f *[N]FieldElement) {
 	k := 255
 	for length := 1; length < N; length *= 2 {
 		for start := 0; start < N; start += 2 * length {
@@ -82,7 +101,12 @@ func InvNTT(f NttElement) RingElement {
 	for i := range f {
 		f[i] = fieldMul(f[i], invN)
 	}
-	return RingElement(f)
+}
+
+// invNTTTo stores InvNTT(*f) in dst.
+func invNTTTo(dst *RingElement, f *NttElement) {
+	*dst = RingElement(*f)
+	invNTTInPlace((*[N]FieldElement)(dst))
 }
 
 // NttMul performs component-wise multiplication of two NTT-domain polynomials.
@@ -93,3 +117,24 @@ func NttMul(a, b NttElement) NttElement {
 	}
 	return c
 }
+
+// nttMulTo stores NttMul(*a, *b) in dst.
+func nttMulTo(dst, a, b *NttElement) {
+	for i := range dst {
+		dst[i] = fieldMul(a[i], b[i])
+	}
+}
+
+// nttMulAdd adds NttMul(*a, *b) to dst.
+func nttMulAdd(dst, a, b *NttElement) {
+	for i := range dst {
+		dst[i] = fieldAdd(dst[i], fieldMul(a[i], b[i]))
+	}
+}
+
+// nttMulSub subtracts NttMul(*a, *b) from dst.
+func nttMulSub(dst, a, b *NttElement) {
+	for i := range dst {
+		dst[i] = fieldSub(dst[i], fieldMul(a[i], b[i]))
+	}
+}
@@ -0,0 +1,52 @@
+package mldsa
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// SignStats describes one run of the signing rejection loop (FIPS 204
+// Algorithm 7), as reported to a SignObserver. An attempt is rejected by
+// the first of four checks it fails; the counts of rejections by each check
+// add up to Attempts-1 for a successful signature.
+type SignStats struct {
+	ParameterSet ParameterSet
+	Attempts     int // iterations of the loop, including the final one
+
+	ZRejections    int // ||z||∞ ≥ γ1 − β
+	R0Rejections   int // ||r0||∞ ≥ γ2 − β
+	CT0Rejections  int // ||ct0||∞ ≥ γ2
+	HintRejections int // more than ω hints
+
+	// Duration is the time spent signing µ, which excludes hashing the
+	// message.
+	Duration time.Duration
+	// Err is nil on success, ErrSigningFailed if the loop hit the attempt
+	// cap, ErrSignatureFault or ErrParanoidCheckFailed.
+	Err error
+}
+
+// SignObserver receives the statistics of every signature.
+type SignObserver func(SignStats)
+
+var signObserver atomic.Pointer[SignObserver]
+
+// SetSignObserver installs f to be called, synchronously, at the end of
+// every signature computation, or removes the observer if f is nil. It
+// lets operators monitor the long tail of the rejection loop and
+// researchers gather its distribution. f must be safe for concurrent use
+// and should be fast, since it runs on the signing path.
+func SetSignObserver(f SignObserver) {
+	if f == nil {
+		signObserver.Store(nil)
+		return
+	}
+	signObserver.Store(&f)
+}
+
+// observeSign reports stats to the observer installed when signing
+// started at start.
+func observeSign(obs *SignObserver, stats *SignStats, start time.Time) {
+	stats.Duration = time.Since(start)
+	(*obs)(*stats)
+}
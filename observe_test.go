@@ -0,0 +1,59 @@
+package mldsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSignObserver(t *testing.T) {
+	var mu sync.Mutex
+	var seen []SignStats
+	SetSignObserver(func(s SignStats) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, s)
+	})
+	defer SetSignObserver(nil)
+
+	for _, p := range ParameterSets() {
+		key, _ := p.Scheme().GenerateKey(rand.Reader)
+		seen = nil
+		for i := 0; i < 10; i++ {
+			if _, err := key.SignWithContext(rand.Reader, []byte("message"), nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(seen) != 10 {
+			t.Fatalf("%v: observer saw %d signatures, want 10", p, len(seen))
+		}
+		for _, s := range seen {
+			rejected := s.ZRejections + s.R0Rejections + s.CT0Rejections + s.HintRejections
+			if s.ParameterSet != p || s.Attempts < 1 || rejected != s.Attempts-1 || s.Err != nil || s.Duration <= 0 {
+				t.Errorf("%v: inconsistent stats %+v", p, s)
+			}
+		}
+	}
+
+	SetMaxSignAttempts(1)
+	defer SetMaxSignAttempts(0)
+	key, _ := GenerateKey65(rand.Reader)
+	seen = nil
+	for i := 0; i < 100; i++ {
+		if _, err := key.SignWithContext(rand.Reader, []byte("message"), nil); err != nil {
+			break
+		}
+	}
+	last := seen[len(seen)-1]
+	if !errors.Is(last.Err, ErrSigningFailed) || last.Attempts != 1 {
+		t.Errorf("capped signing reported %+v", last)
+	}
+
+	SetSignObserver(nil)
+	seen = nil
+	key.SignWithContext(rand.Reader, []byte("message"), nil)
+	if len(seen) != 0 {
+		t.Error("removed observer was called")
+	}
+}
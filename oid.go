@@ -0,0 +1,76 @@
+package mldsa
+
+import (
+	"crypto"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"slices"
+)
+
+// Object identifiers assigned by NIST in the signature algorithm arc
+// 2.16.840.1.101.3.4.3. The id-ml-dsa-* OIDs identify keys and pure ML-DSA
+// signatures (RFC 9881); the id-hash-ml-dsa-*-with-sha512 OIDs identify
+// HashML-DSA signatures with SHA-512 pre-hashing. These values must not be
+// modified.
+var (
+	OIDMLDSA44 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 17}
+	OIDMLDSA65 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 18}
+	OIDMLDSA87 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 19}
+
+	OIDHashMLDSA44WithSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 32}
+	OIDHashMLDSA65WithSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 33}
+	OIDHashMLDSA87WithSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 34}
+)
+
+// HashOID returns the OID of HashML-DSA signatures with p and the pre-hash
+// function h, or false if none is assigned. NIST only assigns OIDs for
+// SHA-512.
+func (p ParameterSet) HashOID(h crypto.Hash) (asn1.ObjectIdentifier, bool) {
+	if h != crypto.SHA512 {
+		return nil, false
+	}
+	switch p {
+	case MLDSA44:
+		return slices.Clone(OIDHashMLDSA44WithSHA512), true
+	case MLDSA65:
+		return slices.Clone(OIDHashMLDSA65WithSHA512), true
+	case MLDSA87:
+		return slices.Clone(OIDHashMLDSA87WithSHA512), true
+	}
+	return nil, false
+}
+
+// AlgorithmIdentifier returns the AlgorithmIdentifier of p, with absent
+// parameters, for SubjectPublicKeyInfo, PKCS #8 and pure ML-DSA signatures.
+func (p ParameterSet) AlgorithmIdentifier() pkix.AlgorithmIdentifier {
+	return pkix.AlgorithmIdentifier{Algorithm: p.OID()}
+}
+
+// HashAlgorithmIdentifier returns the AlgorithmIdentifier of HashML-DSA
+// signatures with p and the pre-hash function h, or false if no OID is
+// assigned to the combination.
+func (p ParameterSet) HashAlgorithmIdentifier(h crypto.Hash) (pkix.AlgorithmIdentifier, bool) {
+	oid, ok := p.HashOID(h)
+	return pkix.AlgorithmIdentifier{Algorithm: oid}, ok
+}
+
+// ParseAlgorithmIdentifier returns the parameter set identified by ai and,
+// for HashML-DSA, its pre-hash function; the hash is 0 for ML-DSA keys and
+// pure signatures. ML-DSA AlgorithmIdentifiers must have absent parameters.
+func ParseAlgorithmIdentifier(ai pkix.AlgorithmIdentifier) (ParameterSet, crypto.Hash, error) {
+	for _, p := range ParameterSets() {
+		var h crypto.Hash
+		if !ai.Algorithm.Equal(p.OID()) {
+			if oid, _ := p.HashOID(crypto.SHA512); !ai.Algorithm.Equal(oid) {
+				continue
+			}
+			h = crypto.SHA512
+		}
+		if par := ai.Parameters; len(par.FullBytes) != 0 || par.Class != 0 || par.Tag != 0 || len(par.Bytes) != 0 {
+			return 0, 0, fmt.Errorf("mldsa: %s AlgorithmIdentifier parameters must be absent", p)
+		}
+		return p, h, nil
+	}
+	return 0, 0, fmt.Errorf("%w %v", ErrUnknownParameterSet, ai.Algorithm)
+}
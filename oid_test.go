@@ -0,0 +1,59 @@
+package mldsa
+
+import (
+	"crypto"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"testing"
+)
+
+func TestAlgorithmIdentifiers(t *testing.T) {
+	tests := []struct {
+		p       ParameterSet
+		pure    string
+		withSHA string
+	}{
+		{MLDSA44, "2.16.840.1.101.3.4.3.17", "2.16.840.1.101.3.4.3.32"},
+		{MLDSA65, "2.16.840.1.101.3.4.3.18", "2.16.840.1.101.3.4.3.33"},
+		{MLDSA87, "2.16.840.1.101.3.4.3.19", "2.16.840.1.101.3.4.3.34"},
+	}
+	for _, tt := range tests {
+		ai := tt.p.AlgorithmIdentifier()
+		if ai.Algorithm.String() != tt.pure || len(ai.Parameters.FullBytes) != 0 {
+			t.Errorf("%v: AlgorithmIdentifier = %v", tt.p, ai)
+		}
+		if p, h, err := ParseAlgorithmIdentifier(ai); p != tt.p || h != 0 || err != nil {
+			t.Errorf("%v: ParseAlgorithmIdentifier(pure) = %v, %v, %v", tt.p, p, h, err)
+		}
+
+		hai, ok := tt.p.HashAlgorithmIdentifier(crypto.SHA512)
+		if !ok || hai.Algorithm.String() != tt.withSHA {
+			t.Errorf("%v: HashAlgorithmIdentifier = %v, %v", tt.p, hai, ok)
+		}
+		if p, h, err := ParseAlgorithmIdentifier(hai); p != tt.p || h != crypto.SHA512 || err != nil {
+			t.Errorf("%v: ParseAlgorithmIdentifier(hash) = %v, %v, %v", tt.p, p, h, err)
+		}
+		if _, ok := tt.p.HashOID(crypto.SHA256); ok {
+			t.Errorf("%v: HashOID reported an OID for SHA-256", tt.p)
+		}
+
+		ai.Parameters = asn1.NullRawValue
+		if _, _, err := ParseAlgorithmIdentifier(ai); err == nil {
+			t.Errorf("%v: ParseAlgorithmIdentifier accepted NULL parameters", tt.p)
+		}
+	}
+
+	if !OIDMLDSA65.Equal(MLDSA65.OID()) || !OIDHashMLDSA87WithSHA512.Equal(asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 34}) {
+		t.Error("exported OIDs do not match the parameter sets")
+	}
+	MLDSA44.OID()[0] = 9
+	if OIDMLDSA44[0] != 2 {
+		t.Error("OID returned the exported value instead of a copy")
+	}
+
+	ed25519 := pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 3, 101, 112}}
+	if _, _, err := ParseAlgorithmIdentifier(ed25519); !errors.Is(err, ErrUnknownParameterSet) {
+		t.Errorf("ParseAlgorithmIdentifier(Ed25519) = %v", err)
+	}
+}
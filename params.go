@@ -0,0 +1,131 @@
+package mldsa
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ParameterSet identifies one of the ML-DSA parameter sets defined in
+// FIPS 204. The zero value is not a valid parameter set.
+type ParameterSet int
+
+// Parameter sets defined in FIPS 204.
+const (
+	MLDSA44 ParameterSet = iota + 1 // ML-DSA-44, NIST security level 2
+	MLDSA65                         // ML-DSA-65, NIST security level 3
+	MLDSA87                         // ML-DSA-87, NIST security level 5
+)
+
+// ParameterSets returns all parameter sets, in increasing order of security.
+func ParameterSets() []ParameterSet {
+	return []ParameterSet{MLDSA44, MLDSA65, MLDSA87}
+}
+
+// ParseParameterSet returns the parameter set with the given name, such as
+// "ML-DSA-65". The comparison is case-insensitive.
+func ParseParameterSet(name string) (ParameterSet, error) {
+	for _, p := range ParameterSets() {
+		if strings.EqualFold(name, p.String()) {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q", ErrUnknownParameterSet, name)
+}
+
+// String returns the FIPS 204 name of the parameter set, e.g. "ML-DSA-65".
+func (p ParameterSet) String() string {
+	switch p {
+	case MLDSA44:
+		return "ML-DSA-44"
+	case MLDSA65:
+		return "ML-DSA-65"
+	case MLDSA87:
+		return "ML-DSA-87"
+	}
+	return fmt.Sprintf("ParameterSet(%d)", int(p))
+}
+
+// Valid reports whether p is a known parameter set.
+func (p ParameterSet) Valid() bool {
+	return p >= MLDSA44 && p <= MLDSA87
+}
+
+// PublicKeySize returns the size of an encoded public key, or 0 if p is not
+// valid.
+func (p ParameterSet) PublicKeySize() int {
+	switch p {
+	case MLDSA44:
+		return PublicKeySize44
+	case MLDSA65:
+		return PublicKeySize65
+	case MLDSA87:
+		return PublicKeySize87
+	}
+	return 0
+}
+
+// PrivateKeySize returns the size of an encoded (expanded) private key, or 0
+// if p is not valid.
+func (p ParameterSet) PrivateKeySize() int {
+	switch p {
+	case MLDSA44:
+		return PrivateKeySize44
+	case MLDSA65:
+		return PrivateKeySize65
+	case MLDSA87:
+		return PrivateKeySize87
+	}
+	return 0
+}
+
+// SignatureSize returns the size of a signature, or 0 if p is not valid.
+func (p ParameterSet) SignatureSize() int {
+	switch p {
+	case MLDSA44:
+		return SignatureSize44
+	case MLDSA65:
+		return SignatureSize65
+	case MLDSA87:
+		return SignatureSize87
+	}
+	return 0
+}
+
+// Scheme returns the Scheme implementing p, or nil if p is not valid.
+func (p ParameterSet) Scheme() Scheme {
+	switch p {
+	case MLDSA44:
+		return scheme44{}
+	case MLDSA65:
+		return scheme65{}
+	case MLDSA87:
+		return scheme87{}
+	}
+	return nil
+}
+
+// OID returns the object identifier of p used in X.509 and PKCS #8
+// AlgorithmIdentifiers (RFC 9881), or nil if p is not valid.
+func (p ParameterSet) OID() asn1.ObjectIdentifier {
+	switch p {
+	case MLDSA44:
+		return slices.Clone(OIDMLDSA44)
+	case MLDSA65:
+		return slices.Clone(OIDMLDSA65)
+	case MLDSA87:
+		return slices.Clone(OIDMLDSA87)
+	}
+	return nil
+}
+
+// parameterSetForOID returns the parameter set identified by oid, or 0.
+func parameterSetForOID(oid asn1.ObjectIdentifier) ParameterSet {
+	for _, p := range ParameterSets() {
+		if oid.Equal(p.OID()) {
+			return p
+		}
+	}
+	return 0
+}
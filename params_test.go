@@ -0,0 +1,35 @@
+package mldsa
+
+import "testing"
+
+func TestParameterSet(t *testing.T) {
+	for _, p := range ParameterSets() {
+		if !p.Valid() {
+			t.Errorf("%v: not valid", p)
+		}
+		s := p.Scheme()
+		if s.Name() != p.String() {
+			t.Errorf("%v: scheme name %s", p, s.Name())
+		}
+		if p.PublicKeySize() != s.PublicKeySize() || p.PrivateKeySize() != s.PrivateKeySize() || p.SignatureSize() != s.SignatureSize() {
+			t.Errorf("%v: sizes do not match the scheme", p)
+		}
+		for _, name := range []string{p.String(), "ml-dsa-" + p.String()[7:]} {
+			got, err := ParseParameterSet(name)
+			if err != nil || got != p {
+				t.Errorf("ParseParameterSet(%q) = %v, %v", name, got, err)
+			}
+		}
+	}
+
+	var zero ParameterSet
+	if zero.Valid() || zero.Scheme() != nil || zero.PublicKeySize() != 0 {
+		t.Error("zero ParameterSet is treated as valid")
+	}
+	if zero.String() != "ParameterSet(0)" {
+		t.Errorf("zero ParameterSet String() = %q", zero.String())
+	}
+	if _, err := ParseParameterSet("ML-DSA-1"); err == nil {
+		t.Error("ParseParameterSet accepted an unknown name")
+	}
+}
@@ -0,0 +1,100 @@
+package mldsa
+
+// Paranoid mode.
+//
+// When enabled, every signature, whichever method produced it, is verified
+// before it is returned against a public key recomputed from the secret
+// components, which must also hash to tr. Every successful verification,
+// whichever method performed it, is repeated against a public key re-parsed
+// from its encoding, so that A and tr are re-derived independently of the
+// cached copies. Only the internal functions of the conformance package are
+// exempt.
+//
+// An optional Backend provides a second, independent ML-DSA implementation
+// that must agree with this one. Its Verify method takes the message, so it
+// checks pure ML-DSA signing and verification of a message held in memory:
+// Sign, SignMessage, SignWithContext and AppendSign without a pre-hash, and
+// Verify, VerifyError, VerifyWithOpts and VerifyMany. A backend that also
+// implements MuBackend checks every signature over µ as well, which covers
+// the remaining operations: SignMu, SignReader, SignPreHash, VerifyMu,
+// VerifyReader and VerifyPreHash, and HashML-DSA through Sign, SignMessage
+// and VerifyWithOpts. Expect roughly three times the cost per signature and
+// twice the cost per verification, plus the cost of the backend.
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Backend is an independent ML-DSA implementation used to cross-check
+// results in paranoid mode, for example a cgo binding to liboqs.
+type Backend interface {
+	// Verify reports whether sig is a valid signature of message under
+	// context for the encoded publicKey. params is the parameter set name:
+	// "ML-DSA-44", "ML-DSA-65" or "ML-DSA-87".
+	Verify(params string, publicKey, sig, message, context []byte) bool
+}
+
+// MuBackend is a Backend that can also verify a signature of the message
+// representative µ (FIPS 204 Section 6.2), so that paranoid mode can
+// cross-check the operations that do not hand it the message.
+type MuBackend interface {
+	Backend
+	// VerifyMu reports whether sig is a valid signature of µ for the
+	// encoded publicKey of parameter set params.
+	VerifyMu(params string, publicKey, sig []byte, mu [MuSize]byte) bool
+}
+
+// ErrParanoidCheckFailed is returned when paranoid mode detects that a
+// freshly produced signature does not verify, or that the cross-check
+// backend disagrees with this implementation.
+var ErrParanoidCheckFailed = errors.New("mldsa: paranoid self-check failed")
+
+var (
+	paranoid        atomic.Bool
+	paranoidBackend atomic.Pointer[backendHolder]
+)
+
+// backendHolder wraps a Backend so it can be stored in an atomic.Pointer.
+type backendHolder struct {
+	b Backend
+}
+
+// SetParanoid enables or disables paranoid mode for the whole package.
+func SetParanoid(enabled bool) {
+	paranoid.Store(enabled)
+}
+
+// Paranoid reports whether paranoid mode is enabled.
+func Paranoid() bool {
+	return paranoid.Load()
+}
+
+// SetBackend installs b as the cross-check backend used in paranoid mode.
+// Passing nil removes any previously installed backend.
+func SetBackend(b Backend) {
+	if b == nil {
+		paranoidBackend.Store(nil)
+		return
+	}
+	paranoidBackend.Store(&backendHolder{b: b})
+}
+
+// crossCheckBackend returns the backend to consult, or nil if paranoid mode
+// is disabled or no backend is installed.
+func crossCheckBackend() Backend {
+	if !paranoid.Load() {
+		return nil
+	}
+	if h := paranoidBackend.Load(); h != nil {
+		return h.b
+	}
+	return nil
+}
+
+// crossCheckMu reports whether the backend accepts sig over µ, or true if
+// paranoid mode is disabled or the backend is not a MuBackend.
+func crossCheckMu(params string, publicKey, sig []byte, mu *[MuSize]byte) bool {
+	b, ok := crossCheckBackend().(MuBackend)
+	return !ok || b.VerifyMu(params, publicKey, sig, *mu)
+}
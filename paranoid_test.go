@@ -0,0 +1,168 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+// recordingBackend is a Backend that records calls and returns a fixed result.
+type recordingBackend struct {
+	result bool
+	calls  int
+	params string
+}
+
+func (b *recordingBackend) Verify(params string, publicKey, sig, message, context []byte) bool {
+	b.calls++
+	b.params = params
+	return b.result
+}
+
+func enableParanoid(t *testing.T, b Backend) {
+	t.Helper()
+	SetParanoid(true)
+	SetBackend(b)
+	t.Cleanup(func() {
+		SetParanoid(false)
+		SetBackend(nil)
+	})
+}
+
+func TestParanoidSignVerify(t *testing.T) {
+	enableParanoid(t, nil)
+
+	message := []byte("paranoid message")
+	context := []byte("ctx")
+
+	key44, _ := GenerateKey44(rand.Reader)
+	sig44, err := key44.SignWithContext(rand.Reader, message, context)
+	if err != nil {
+		t.Fatalf("ML-DSA-44 paranoid sign failed: %v", err)
+	}
+	if !key44.PublicKey().Verify(sig44, message, context) {
+		t.Error("ML-DSA-44 paranoid verify failed")
+	}
+
+	key65, _ := GenerateKey65(rand.Reader)
+	sig65, err := key65.SignWithContext(rand.Reader, message, context)
+	if err != nil {
+		t.Fatalf("ML-DSA-65 paranoid sign failed: %v", err)
+	}
+	if !key65.PublicKey().Verify(sig65, message, context) {
+		t.Error("ML-DSA-65 paranoid verify failed")
+	}
+
+	key87, _ := GenerateKey87(rand.Reader)
+	sig87, err := key87.SignWithContext(rand.Reader, message, context)
+	if err != nil {
+		t.Fatalf("ML-DSA-87 paranoid sign failed: %v", err)
+	}
+	if !key87.PublicKey().Verify(sig87, message, context) {
+		t.Error("ML-DSA-87 paranoid verify failed")
+	}
+	if key87.PublicKey().Verify(sig87, []byte("other"), context) {
+		t.Error("ML-DSA-87 paranoid verify accepted wrong message")
+	}
+	if !key87.PublicKey().VerifyReader(sig87, bytes.NewReader(message), context) {
+		t.Error("ML-DSA-87 paranoid VerifyReader failed")
+	}
+}
+
+func TestParanoidBackendDisagreement(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	message := []byte("message")
+	sig, err := key.Sign(rand.Reader, message, nil)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	b := &recordingBackend{result: false}
+	enableParanoid(t, b)
+
+	if _, err := key.Sign(rand.Reader, message, nil); !errors.Is(err, ErrParanoidCheckFailed) {
+		t.Errorf("Sign with disagreeing backend: got %v, want ErrParanoidCheckFailed", err)
+	}
+	if key.PublicKey().Verify(sig, message, nil) {
+		t.Error("Verify succeeded despite disagreeing backend")
+	}
+	if b.calls != 2 || b.params != "ML-DSA-65" {
+		t.Errorf("backend calls = %d (params %q), want 2 (ML-DSA-65)", b.calls, b.params)
+	}
+
+	b.result = true
+	if _, err := key.Sign(rand.Reader, message, nil); err != nil {
+		t.Errorf("Sign with agreeing backend failed: %v", err)
+	}
+	if !key.PublicKey().Verify(sig, message, nil) {
+		t.Error("Verify failed with agreeing backend")
+	}
+}
+
+// recordingMuBackend is a MuBackend that records calls to VerifyMu.
+type recordingMuBackend struct {
+	recordingBackend
+	muCalls int
+}
+
+func (b *recordingMuBackend) VerifyMu(params string, publicKey, sig []byte, mu [MuSize]byte) bool {
+	b.muCalls++
+	return b.result
+}
+
+func TestParanoidMuBackend(t *testing.T) {
+	key, _ := GenerateKey44(rand.Reader)
+	pk := key.PublicKey()
+	message, context := []byte("message"), []byte("ctx")
+	mu, _ := pk.ComputeMu(message, context)
+	digest, _ := PreHash(crypto.SHA256, message)
+	sigMu, _ := key.SignMu(rand.Reader, mu)
+	sigPH, _ := key.SignPreHash(rand.Reader, digest, crypto.SHA256, context)
+
+	b := &recordingMuBackend{recordingBackend: recordingBackend{result: true}}
+	enableParanoid(t, b)
+	sign := map[string]func() ([]byte, error){
+		"SignMu":      func() ([]byte, error) { return key.SignMu(rand.Reader, mu) },
+		"SignReader":  func() ([]byte, error) { return key.SignReader(rand.Reader, bytes.NewReader(message), context) },
+		"SignPreHash": func() ([]byte, error) { return key.SignPreHash(rand.Reader, digest, crypto.SHA256, context) },
+	}
+	verify := map[string]func() bool{
+		"VerifyMu":      func() bool { return pk.VerifyMu(sigMu, mu) },
+		"VerifyReader":  func() bool { return pk.VerifyReader(sigMu, bytes.NewReader(message), context) },
+		"VerifyPreHash": func() bool { return pk.VerifyPreHash(sigPH, digest, crypto.SHA256, context) },
+	}
+	for _, result := range []bool{true, false} {
+		b.result = result
+		for name, f := range sign {
+			b.muCalls = 0
+			_, err := f()
+			if b.muCalls == 0 {
+				t.Errorf("%s did not consult the backend", name)
+			}
+			if result && err != nil || !result && !errors.Is(err, ErrParanoidCheckFailed) {
+				t.Errorf("%s with backend result %v: %v", name, result, err)
+			}
+		}
+		for name, f := range verify {
+			b.muCalls = 0
+			if f() != result || b.muCalls == 0 {
+				t.Errorf("%s with backend result %v: backend called %d times", name, result, b.muCalls)
+			}
+		}
+	}
+}
+
+func TestParanoidRederivesPublicKey(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	key.PrivateKey65.PublicKey() // cache the public key of the intact key
+	key.tr[0] ^= 1               // tr no longer matches the public key
+	if _, err := key.SignMu(rand.Reader, [MuSize]byte{}); err != nil {
+		t.Fatalf("SignMu outside paranoid mode: %v", err)
+	}
+	enableParanoid(t, nil)
+	if _, err := key.SignMu(rand.Reader, [MuSize]byte{}); !errors.Is(err, ErrParanoidCheckFailed) {
+		t.Errorf("SignMu with a corrupted tr: got %v, want ErrParanoidCheckFailed", err)
+	}
+}
@@ -0,0 +1,197 @@
+package mldsa
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PEM block types for raw ML-DSA encodings. The parameter set name is part
+// of the block type, e.g. "ML-DSA-65 PUBLIC KEY".
+//
+// A private key block holds either the 32-byte seed or the full expanded
+// private key encoding; the two are told apart by length.
+const (
+	pemPublicKeySuffix  = " PUBLIC KEY"
+	pemPrivateKeySuffix = " PRIVATE KEY"
+	pemSignatureSuffix  = " SIGNATURE"
+
+	// PEMTypeCertificate is the block type of DER-encoded X.509 certificates.
+	PEMTypeCertificate = "CERTIFICATE"
+)
+
+// PEMSignature is a detached signature read from or written to a PEM bundle.
+type PEMSignature struct {
+	// ParameterSet is "ML-DSA-44", "ML-DSA-65" or "ML-DSA-87".
+	ParameterSet string
+	Signature    []byte
+}
+
+// PEMBundle is a typed collection of the blocks found in a multi-entry PEM
+// file. Entries keep the order in which they appear within their category.
+type PEMBundle struct {
+	PublicKeys   []PublicKey
+	PrivateKeys  []PrivateKey // *Key44/65/87 (seed) or *PrivateKey44/65/87 (expanded)
+	Signatures   []PEMSignature
+	Certificates [][]byte     // DER-encoded certificates
+	Other        []*pem.Block // blocks of unrecognized types, untouched
+}
+
+// ParsePEMBundle decodes every PEM block in data. ML-DSA keys and signatures
+// are parsed into their concrete types, certificates are kept as DER and any
+// other block is returned in Other. Non-PEM data between blocks is ignored.
+func ParsePEMBundle(data []byte) (*PEMBundle, error) {
+	bundle := &PEMBundle{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return bundle, nil
+		}
+		if err := bundle.add(block); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// add parses a single block into the bundle.
+func (b *PEMBundle) add(block *pem.Block) error {
+	if block.Type == PEMTypeCertificate {
+		b.Certificates = append(b.Certificates, block.Bytes)
+		return nil
+	}
+
+	params, kind, ok := splitPEMType(block.Type)
+	if !ok {
+		b.Other = append(b.Other, block)
+		return nil
+	}
+
+	switch kind {
+	case pemPublicKeySuffix:
+		pk, err := parsePublicKeyFor(params, block.Bytes)
+		if err != nil {
+			return fmt.Errorf("mldsa: invalid %s PEM block: %w", block.Type, err)
+		}
+		b.PublicKeys = append(b.PublicKeys, pk)
+	case pemPrivateKeySuffix:
+		sk, err := parsePrivateKeyFor(params, block.Bytes)
+		if err != nil {
+			return fmt.Errorf("mldsa: invalid %s PEM block: %w", block.Type, err)
+		}
+		b.PrivateKeys = append(b.PrivateKeys, sk)
+	case pemSignatureSuffix:
+		if len(block.Bytes) != signatureSizeFor(params) {
+			return fmt.Errorf("mldsa: invalid %s PEM block: %w", block.Type, &LengthError{Err: ErrBadSignatureLength, Got: len(block.Bytes), Want: signatureSizeFor(params)})
+		}
+		b.Signatures = append(b.Signatures, PEMSignature{ParameterSet: params, Signature: block.Bytes})
+	}
+	return nil
+}
+
+// Marshal encodes the bundle as a sequence of PEM blocks: private keys,
+// public keys, certificates, signatures and finally the other blocks.
+func (b *PEMBundle) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := b.Encode(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Encode writes the bundle to w in the same order as Marshal.
+func (b *PEMBundle) Encode(w io.Writer) error {
+	for _, sk := range b.PrivateKeys {
+		block, err := privateKeyPEMBlock(sk)
+		if err != nil {
+			return err
+		}
+		if err := pem.Encode(w, block); err != nil {
+			return err
+		}
+	}
+	for _, pk := range b.PublicKeys {
+		if err := pem.Encode(w, publicKeyPEMBlock(pk)); err != nil {
+			return err
+		}
+	}
+	for _, der := range b.Certificates {
+		if err := pem.Encode(w, &pem.Block{Type: PEMTypeCertificate, Bytes: der}); err != nil {
+			return err
+		}
+	}
+	for _, sig := range b.Signatures {
+		if len(sig.Signature) != signatureSizeFor(sig.ParameterSet) {
+			return fmt.Errorf("mldsa: %s signature: %w", sig.ParameterSet, &LengthError{Err: ErrBadSignatureLength, Got: len(sig.Signature), Want: signatureSizeFor(sig.ParameterSet)})
+		}
+		block := &pem.Block{Type: sig.ParameterSet + pemSignatureSuffix, Bytes: sig.Signature}
+		if err := pem.Encode(w, block); err != nil {
+			return err
+		}
+	}
+	for _, block := range b.Other {
+		if err := pem.Encode(w, block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitPEMType splits an ML-DSA block type such as "ML-DSA-65 PUBLIC KEY"
+// into its parameter set and kind suffix.
+func splitPEMType(typ string) (params, kind string, ok bool) {
+	for _, suffix := range []string{pemPublicKeySuffix, pemPrivateKeySuffix, pemSignatureSuffix} {
+		if p, found := strings.CutSuffix(typ, suffix); found && signatureSizeFor(p) != 0 {
+			return p, suffix, true
+		}
+	}
+	return "", "", false
+}
+
+// publicKeyPEMBlock returns the PEM block for an ML-DSA public key.
+func publicKeyPEMBlock(pk PublicKey) *pem.Block {
+	return &pem.Block{Type: pk.Scheme().Name() + pemPublicKeySuffix, Bytes: pk.Bytes()}
+}
+
+// privateKeyPEMBlock returns the PEM block for an ML-DSA private key. Key
+// pairs are written as their seed, standalone private keys in expanded form.
+func privateKeyPEMBlock(sk PrivateKey) (*pem.Block, error) {
+	b, err := privateKeyEncoding(sk)
+	if err != nil {
+		return nil, err
+	}
+	return &pem.Block{Type: sk.Scheme().Name() + pemPrivateKeySuffix, Bytes: b}, nil
+}
+
+// signatureSizeFor returns the signature size of the named parameter set,
+// or 0 if the name is unknown.
+func signatureSizeFor(params string) int {
+	if s := SchemeByName(params); s != nil {
+		return s.SignatureSize()
+	}
+	return 0
+}
+
+// parsePublicKeyFor parses an encoded public key of the named parameter set.
+func parsePublicKeyFor(params string, b []byte) (PublicKey, error) {
+	s := SchemeByName(params)
+	if s == nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownParameterSet, params)
+	}
+	return s.UnmarshalPublicKey(b)
+}
+
+// parsePrivateKeyFor parses a seed or an expanded private key of the named
+// parameter set.
+func parsePrivateKeyFor(params string, b []byte) (PrivateKey, error) {
+	s := SchemeByName(params)
+	if s == nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownParameterSet, params)
+	}
+	if len(b) == s.SeedSize() {
+		return s.NewKeyFromSeed(b)
+	}
+	return s.UnmarshalPrivateKey(b)
+}
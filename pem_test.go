@@ -0,0 +1,82 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/pem"
+	"testing"
+)
+
+func TestPEMBundleRoundtrip(t *testing.T) {
+	key44, _ := GenerateKey44(rand.Reader)
+	key65, _ := GenerateKey65(rand.Reader)
+	sk87, _ := GenerateKey87(rand.Reader)
+	expanded87, err := NewPrivateKey87(sk87.PrivateKeyBytes())
+	if err != nil {
+		t.Fatalf("NewPrivateKey87 failed: %v", err)
+	}
+	seedKey65, err := GenerateSeedKey(MLDSA65, rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateSeedKey failed: %v", err)
+	}
+
+	message := []byte("bundle message")
+	sig, err := key65.Sign(rand.Reader, message, nil)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	in := &PEMBundle{
+		PrivateKeys:  []PrivateKey{key44, expanded87, seedKey65},
+		PublicKeys:   []PublicKey{key65.PublicKey()},
+		Signatures:   []PEMSignature{{ParameterSet: "ML-DSA-65", Signature: sig}},
+		Certificates: [][]byte{{0x30, 0x00}},
+		Other:        []*pem.Block{{Type: "COMMENT", Bytes: []byte("hello")}},
+	}
+	data, err := in.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	out, err := ParsePEMBundle(append([]byte("leading text\n"), data...))
+	if err != nil {
+		t.Fatalf("ParsePEMBundle failed: %v", err)
+	}
+	if len(out.PrivateKeys) != 3 || len(out.PublicKeys) != 1 || len(out.Signatures) != 1 ||
+		len(out.Certificates) != 1 || len(out.Other) != 1 {
+		t.Fatalf("unexpected bundle shape: %+v", out)
+	}
+
+	seedKey, ok := out.PrivateKeys[0].(*Key44)
+	if !ok || !bytes.Equal(seedKey.Bytes(), key44.Bytes()) {
+		t.Error("ML-DSA-44 seed key did not roundtrip")
+	}
+	sk, ok := out.PrivateKeys[1].(*PrivateKey87)
+	if !ok || !bytes.Equal(sk.Bytes(), expanded87.Bytes()) {
+		t.Error("ML-DSA-87 expanded key did not roundtrip")
+	}
+	if k, ok := out.PrivateKeys[2].(*Key65); !ok || !bytes.Equal(k.Bytes(), seedKey65.Bytes()) {
+		t.Error("ML-DSA-65 seed key did not roundtrip")
+	}
+	pk, ok := out.PublicKeys[0].(*PublicKey65)
+	if !ok || !pk.Equal(key65.PublicKey()) {
+		t.Fatal("ML-DSA-65 public key did not roundtrip")
+	}
+	if out.Signatures[0].ParameterSet != "ML-DSA-65" || !pk.Verify(out.Signatures[0].Signature, message, nil) {
+		t.Error("signature did not roundtrip")
+	}
+	if out.Other[0].Type != "COMMENT" {
+		t.Errorf("other block type: got %q", out.Other[0].Type)
+	}
+}
+
+func TestPEMBundleRejectsMalformed(t *testing.T) {
+	data := pem.EncodeToMemory(&pem.Block{Type: "ML-DSA-65 PUBLIC KEY", Bytes: []byte{1, 2, 3}})
+	if _, err := ParsePEMBundle(data); err == nil {
+		t.Error("ParsePEMBundle accepted a truncated public key")
+	}
+	data = pem.EncodeToMemory(&pem.Block{Type: "ML-DSA-44 SIGNATURE", Bytes: make([]byte, SignatureSize65)})
+	if _, err := ParsePEMBundle(data); err == nil {
+		t.Error("ParsePEMBundle accepted a signature of the wrong length")
+	}
+}
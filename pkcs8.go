@@ -0,0 +1,195 @@
+package mldsa
+
+import (
+	"crypto/subtle"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+)
+
+// PKCS #8 encoding of ML-DSA private keys (RFC 5958 OneAsymmetricKey), as
+// specified in RFC 9881 (formerly draft-ietf-lamps-dilithium-certificates).
+// The AlgorithmIdentifier carries the parameter set OID with absent
+// parameters, and privateKey holds an ML-DSA-PrivateKey:
+//
+//	ML-DSA-PrivateKey ::= CHOICE {
+//	  seed [0] OCTET STRING (SIZE (32)),
+//	  expandedKey OCTET STRING,
+//	  both SEQUENCE {
+//	    seed OCTET STRING (SIZE (32)),
+//	    expandedKey OCTET STRING } }
+//
+// All three forms are parsed. The seed form is written by default, as it is
+// the one recommended for interoperability with OpenSSL 3.5 and other
+// PQC-enabled stacks; PKCS8Options selects another.
+
+// errInvalidPKCS8 is wrapped by ParsePKCS8PrivateKey for malformed input.
+var errInvalidPKCS8 = errors.New("mldsa: invalid PKCS #8 private key")
+
+// oneAsymmetricKey is the RFC 5958 structure. Attributes and the public key
+// of version 2 are accepted on input and never written.
+type oneAsymmetricKey struct {
+	Version    int
+	Algorithm  pkix.AlgorithmIdentifier
+	PrivateKey []byte
+	Attributes asn1.RawValue `asn1:"optional,tag:0"`
+	PublicKey  asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// PKCS8Form selects the ML-DSA-PrivateKey CHOICE alternative written by
+// MarshalPKCS8PrivateKeyWithOptions.
+type PKCS8Form int
+
+const (
+	// PKCS8Seed writes only the 32-byte seed.
+	PKCS8Seed PKCS8Form = iota
+	// PKCS8ExpandedKey writes only the expanded private key encoding.
+	PKCS8ExpandedKey
+	// PKCS8Both writes the seed together with the expanded private key.
+	PKCS8Both
+)
+
+// PKCS8Options controls MarshalPKCS8PrivateKeyWithOptions.
+type PKCS8Options struct {
+	// Form is the CHOICE alternative to write. The seed and both forms
+	// require a key that retains its seed.
+	Form PKCS8Form
+}
+
+// MarshalPKCS8PrivateKey encodes sk as a DER PKCS #8 private key holding its
+// 32-byte seed. sk must retain its seed: key pairs (*Key44/65/87) do, but
+// standalone private keys do not and are rejected.
+func MarshalPKCS8PrivateKey(sk PrivateKey) ([]byte, error) {
+	return MarshalPKCS8PrivateKeyWithOptions(sk, nil)
+}
+
+// MarshalPKCS8PrivateKeyWithOptions is like MarshalPKCS8PrivateKey, but
+// writes the private key form selected by opts. A nil opts writes the seed.
+// It returns ErrKeyDestroyed for a destroyed key.
+func MarshalPKCS8PrivateKeyWithOptions(sk PrivateKey, opts *PKCS8Options) ([]byte, error) {
+	if keyDestroyed(sk) {
+		return nil, ErrKeyDestroyed
+	}
+	form := PKCS8Seed
+	if opts != nil {
+		form = opts.Form
+	}
+	p, err := ParseParameterSet(sk.Scheme().Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var expanded []byte
+	if form == PKCS8ExpandedKey || form == PKCS8Both {
+		if expanded, err = expandedPrivateKey(sk); err != nil {
+			return nil, err
+		}
+	}
+	var seed []byte
+	if form == PKCS8Seed || form == PKCS8Both {
+		var ok bool
+		if seed, ok = sk.Seed(); !ok {
+			return nil, fmt.Errorf("mldsa: cannot marshal %T to PKCS #8: the seed is not available", sk)
+		}
+	}
+
+	var inner []byte
+	switch form {
+	case PKCS8Seed:
+		// seed [0] IMPLICIT OCTET STRING
+		inner, err = asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, Bytes: seed})
+	case PKCS8ExpandedKey:
+		inner, err = asn1.Marshal(expanded)
+	case PKCS8Both:
+		inner, err = asn1.Marshal(pkcs8Both{Seed: seed, ExpandedKey: expanded})
+	default:
+		return nil, fmt.Errorf("mldsa: unknown PKCS #8 form %d", form)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(oneAsymmetricKey{
+		Algorithm:  p.AlgorithmIdentifier(),
+		PrivateKey: inner,
+	})
+}
+
+// pkcs8Both is the "both" alternative of ML-DSA-PrivateKey.
+type pkcs8Both struct {
+	Seed        []byte
+	ExpandedKey []byte
+}
+
+// ParsePKCS8PrivateKey parses a DER PKCS #8 ML-DSA private key in any of the
+// three forms. The seed and both forms return the key pair (*Key44, *Key65
+// or *Key87) derived from the seed; for the both form, the expanded key must
+// match the one derived from the seed. The expanded form returns a
+// standalone private key (*PrivateKey44, *PrivateKey65 or *PrivateKey87).
+func ParsePKCS8PrivateKey(der []byte) (PrivateKey, error) {
+	var k oneAsymmetricKey
+	rest, err := asn1.Unmarshal(der, &k)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidPKCS8, err)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing data", errInvalidPKCS8)
+	}
+	if k.Version != 0 && k.Version != 1 {
+		return nil, fmt.Errorf("%w: unsupported version %d", errInvalidPKCS8, k.Version)
+	}
+	p := parameterSetForOID(k.Algorithm.Algorithm)
+	if p == 0 {
+		return nil, fmt.Errorf("%w %v", ErrUnknownParameterSet, k.Algorithm.Algorithm)
+	}
+	if len(k.Algorithm.Parameters.FullBytes) != 0 {
+		return nil, fmt.Errorf("%w: AlgorithmIdentifier parameters must be absent", errInvalidPKCS8)
+	}
+
+	var inner asn1.RawValue
+	rest, err = asn1.Unmarshal(k.PrivateKey, &inner)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidPKCS8, err)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing data in privateKey", errInvalidPKCS8)
+	}
+
+	switch {
+	case inner.Class == asn1.ClassContextSpecific && inner.Tag == 0 && !inner.IsCompound:
+		return newKeyFromPKCS8Seed(p, inner.Bytes)
+
+	case inner.Class == asn1.ClassUniversal && inner.Tag == asn1.TagOctetString && !inner.IsCompound:
+		if len(inner.Bytes) != p.PrivateKeySize() {
+			return nil, &LengthError{Err: ErrInvalidPrivateKey, Got: len(inner.Bytes), Want: p.PrivateKeySize()}
+		}
+		return p.Scheme().UnmarshalPrivateKey(inner.Bytes)
+
+	case inner.Class == asn1.ClassUniversal && inner.Tag == asn1.TagSequence && inner.IsCompound:
+		var both pkcs8Both
+		if rest, err := asn1.Unmarshal(inner.FullBytes, &both); err != nil || len(rest) != 0 {
+			return nil, fmt.Errorf("%w: malformed seed and expanded key", errInvalidPKCS8)
+		}
+		sk, err := newKeyFromPKCS8Seed(p, both.Seed)
+		if err != nil {
+			return nil, err
+		}
+		expanded, err := expandedPrivateKey(sk)
+		if err != nil {
+			return nil, err
+		}
+		if subtle.ConstantTimeCompare(expanded, both.ExpandedKey) != 1 {
+			return nil, fmt.Errorf("%w: the expanded key does not match the seed", ErrInvalidPrivateKey)
+		}
+		return sk, nil
+	}
+	return nil, fmt.Errorf("%w: unknown private key form", errInvalidPKCS8)
+}
+
+// newKeyFromPKCS8Seed derives the key pair of parameter set p from seed.
+func newKeyFromPKCS8Seed(p ParameterSet, seed []byte) (PrivateKey, error) {
+	if len(seed) != SeedSize {
+		return nil, &LengthError{Err: ErrInvalidSeedLength, Got: len(seed), Want: SeedSize}
+	}
+	return p.Scheme().NewKeyFromSeed(seed)
+}
@@ -0,0 +1,128 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPKCS8Seed(t *testing.T) {
+	seed := make([]byte, SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	key, _ := NewKey44(seed)
+	der, err := MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// OneAsymmetricKey { 0, { id-ml-dsa-44 }, OCTET STRING { [0] seed } }
+	want, _ := hex.DecodeString("3034020100300b060960864801650304031104228020" + hex.EncodeToString(seed))
+	if !bytes.Equal(der, want) {
+		t.Errorf("MarshalPKCS8PrivateKey = %x, want %x", der, want)
+	}
+
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		der, err := MarshalPKCS8PrivateKey(sk)
+		if err != nil {
+			t.Fatalf("%s: %v", s.Name(), err)
+		}
+		parsed, err := ParsePKCS8PrivateKey(der)
+		if err != nil {
+			t.Fatalf("%s: ParsePKCS8PrivateKey failed: %v", s.Name(), err)
+		}
+		if parsed.Scheme() != s || !parsed.(interface {
+			Equal(other crypto.PrivateKey) bool
+		}).Equal(sk) {
+			t.Errorf("%s: PKCS #8 round trip changed the key", s.Name())
+		}
+	}
+}
+
+func TestPKCS8Errors(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	if _, err := MarshalPKCS8PrivateKey(&key.PrivateKey65); err == nil {
+		t.Error("MarshalPKCS8PrivateKey accepted a key without its seed")
+	}
+
+	der, _ := MarshalPKCS8PrivateKey(key)
+	for name, bad := range map[string][]byte{
+		"empty":      nil,
+		"trailing":   append(bytes.Clone(der), 0),
+		"unknown":    bytes.Replace(der, []byte{0x03, 0x04, 0x03, 0x12}, []byte{0x03, 0x04, 0x03, 0x20}, 1),
+		"short seed": mustHex("3033020100300b0609608648016503040312" + "0421801f" + strings.Repeat("00", 31)),
+	} {
+		if _, err := ParsePKCS8PrivateKey(bad); err == nil {
+			t.Errorf("%s: ParsePKCS8PrivateKey accepted invalid input", name)
+		}
+	}
+	unknown := bytes.Replace(der, []byte{0x03, 0x04, 0x03, 0x12}, []byte{0x03, 0x04, 0x03, 0x20}, 1)
+	if _, err := ParsePKCS8PrivateKey(unknown); !errors.Is(err, ErrUnknownParameterSet) {
+		t.Errorf("unknown OID: %v", err)
+	}
+}
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestPKCS8Forms(t *testing.T) {
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		expanded, _ := expandedPrivateKey(sk)
+		p, _ := ParseParameterSet(s.Name())
+		seed, _ := sk.Seed()
+		seedKey, _ := NewSeedKey(p, seed)
+		for _, key := range []PrivateKey{sk, seedKey} {
+			for _, form := range []PKCS8Form{PKCS8Seed, PKCS8ExpandedKey, PKCS8Both} {
+				der, err := MarshalPKCS8PrivateKeyWithOptions(key, &PKCS8Options{Form: form})
+				if err != nil {
+					t.Fatalf("%s %T form %d: %v", s.Name(), key, form, err)
+				}
+				parsed, err := ParsePKCS8PrivateKey(der)
+				if err != nil {
+					t.Fatalf("%s %T form %d: ParsePKCS8PrivateKey failed: %v", s.Name(), key, form, err)
+				}
+				got, _ := expandedPrivateKey(parsed)
+				if !bytes.Equal(got, expanded) {
+					t.Errorf("%s %T form %d: round trip changed the key", s.Name(), key, form)
+				}
+				_, hasSeed := parsed.Seed()
+				if hasSeed != (form != PKCS8ExpandedKey) {
+					t.Errorf("%s %T form %d: parsed key has seed = %v", s.Name(), key, form, hasSeed)
+				}
+			}
+		}
+	}
+
+	// A standalone private key can only be written in expanded form.
+	key, _ := GenerateKey87(rand.Reader)
+	if _, err := MarshalPKCS8PrivateKeyWithOptions(&key.PrivateKey87, &PKCS8Options{Form: PKCS8ExpandedKey}); err != nil {
+		t.Errorf("expanded form of a standalone key: %v", err)
+	}
+	if _, err := MarshalPKCS8PrivateKeyWithOptions(&key.PrivateKey87, &PKCS8Options{Form: PKCS8Both}); err == nil {
+		t.Error("both form accepted a key without its seed")
+	}
+
+	// The seed and the expanded key must agree.
+	other, _ := GenerateKey87(rand.Reader)
+	both, _ := asn1.Marshal(pkcs8Both{Seed: key.Bytes(), ExpandedKey: other.PrivateKeyBytes()})
+	der, _ := asn1.Marshal(oneAsymmetricKey{
+		Algorithm:  pkix.AlgorithmIdentifier{Algorithm: MLDSA87.OID()},
+		PrivateKey: both,
+	})
+	if _, err := ParsePKCS8PrivateKey(der); !errors.Is(err, ErrInvalidPrivateKey) {
+		t.Errorf("mismatched seed and expanded key: %v", err)
+	}
+}
@@ -0,0 +1,417 @@
+package mldsa
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/KarpelesLab/mldsa/internal/scrypt"
+)
+
+// Encrypted PKCS #8 private keys (RFC 5958 EncryptedPrivateKeyInfo) with
+// PBES2 (RFC 8018). The key is derived from the password with PBKDF2 or
+// scrypt (RFC 7914) and encrypts the DER PrivateKeyInfo with AES-CBC
+// (RFC 8018) or AES-GCM (RFC 5084). AES-256-CBC with PBKDF2-HMAC-SHA256 is
+// the default, as it is the combination read by the widest range of tools;
+// OpenSSL, in particular, does not accept AES-GCM in PBES2.
+
+// ErrIncorrectPassword is returned by ParseEncryptedPKCS8PrivateKey when the
+// private key cannot be decrypted, which usually means the password is wrong.
+var ErrIncorrectPassword = errors.New("mldsa: incorrect password or corrupted private key")
+
+var (
+	oidPBES2      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidScrypt     = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11591, 4, 11}
+	oidHMACSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+)
+
+// PKCS8Cipher is the content encryption algorithm of an encrypted PKCS #8
+// private key.
+type PKCS8Cipher int
+
+const (
+	AES256CBC PKCS8Cipher = iota // the default
+	AES128CBC
+	AES192CBC
+	AES128GCM
+	AES192GCM
+	AES256GCM
+)
+
+// pkcs8CipherSpec describes a content encryption algorithm.
+type pkcs8CipherSpec struct {
+	c       PKCS8Cipher
+	oid     asn1.ObjectIdentifier
+	keySize int
+	gcm     bool
+}
+
+// pkcs8Ciphers maps each cipher to its OID, key size and mode.
+var pkcs8Ciphers = []pkcs8CipherSpec{
+	{AES256CBC, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}, 32, false},
+	{AES128CBC, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}, 16, false},
+	{AES192CBC, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}, 24, false},
+	{AES128GCM, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 6}, 16, true},
+	{AES192GCM, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 26}, 24, true},
+	{AES256GCM, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 46}, 32, true},
+}
+
+// PKCS8KDF is the password-based key derivation function of an encrypted
+// PKCS #8 private key.
+type PKCS8KDF int
+
+const (
+	PBKDF2 PKCS8KDF = iota // PBKDF2 with HMAC-SHA256, the default
+	Scrypt
+)
+
+// Default cost parameters for EncryptedPKCS8Options.
+const (
+	DefaultPBKDF2Iterations = 600000
+	DefaultScryptN          = 1 << 17
+	DefaultScryptR          = 8
+	DefaultScryptP          = 1
+)
+
+// EncryptedPKCS8Options controls MarshalEncryptedPKCS8PrivateKey. The zero
+// value selects AES-256-CBC with PBKDF2-HMAC-SHA256 and the default costs.
+type EncryptedPKCS8Options struct {
+	// PKCS8Options selects the private key form, as for
+	// MarshalPKCS8PrivateKeyWithOptions.
+	PKCS8Options
+
+	Cipher PKCS8Cipher
+	KDF    PKCS8KDF
+
+	// Iterations is the PBKDF2 iteration count.
+	Iterations int
+
+	// ScryptN, ScryptR and ScryptP are the scrypt cost parameters. Their
+	// product must not exceed 1<<24, the limit enforced when parsing.
+	ScryptN, ScryptR, ScryptP int
+
+	// Rand is the source of the salt and IV. If nil, crypto/rand.Reader is
+	// used.
+	Rand io.Reader
+}
+
+// encryptedPrivateKeyInfo is the RFC 5958 EncryptedPrivateKeyInfo.
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+type scryptParams struct {
+	Salt                     []byte
+	CostParameter            int
+	BlockSize                int
+	ParallelizationParameter int
+	KeyLength                int `asn1:"optional"`
+}
+
+type gcmParams struct {
+	Nonce  []byte
+	ICVLen int `asn1:"optional,default:12"`
+}
+
+// MarshalEncryptedPKCS8PrivateKey encodes sk as a DER PKCS #8 private key
+// encrypted under password with PBES2. A nil opts uses the defaults.
+func MarshalEncryptedPKCS8PrivateKey(sk PrivateKey, password []byte, opts *EncryptedPKCS8Options) ([]byte, error) {
+	if opts == nil {
+		opts = &EncryptedPKCS8Options{}
+	}
+	plain, err := MarshalPKCS8PrivateKeyWithOptions(sk, &opts.PKCS8Options)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(plain)
+
+	var c *pkcs8CipherSpec
+	for i := range pkcs8Ciphers {
+		if pkcs8Ciphers[i].c == opts.Cipher {
+			c = &pkcs8Ciphers[i]
+		}
+	}
+	if c == nil {
+		return nil, fmt.Errorf("mldsa: unknown PKCS #8 cipher %d", opts.Cipher)
+	}
+
+	rand := randReader(opts.Rand)
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand, salt); err != nil {
+		return nil, err
+	}
+
+	var kdf pkix.AlgorithmIdentifier
+	var key []byte
+	switch opts.KDF {
+	case PBKDF2:
+		iter := opts.Iterations
+		if iter == 0 {
+			iter = DefaultPBKDF2Iterations
+		}
+		params, err := asn1.Marshal(pbkdf2Params{
+			Salt:           salt,
+			IterationCount: iter,
+			PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACSHA256, Parameters: asn1.NullRawValue},
+		})
+		if err != nil {
+			return nil, err
+		}
+		kdf = pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: params}}
+		if key, err = pbkdf2.Key(sha256.New, string(password), salt, iter, c.keySize); err != nil {
+			return nil, err
+		}
+	case Scrypt:
+		n, r, p := opts.ScryptN, opts.ScryptR, opts.ScryptP
+		if n == 0 {
+			n = DefaultScryptN
+		}
+		if r == 0 {
+			r = DefaultScryptR
+		}
+		if p == 0 {
+			p = DefaultScryptP
+		}
+		if scryptWorkExceeded(n, r, p) {
+			return nil, fmt.Errorf("mldsa: scrypt cost N=%d r=%d p=%d is above the limit accepted when parsing", n, r, p)
+		}
+		params, err := asn1.Marshal(scryptParams{Salt: salt, CostParameter: n, BlockSize: r, ParallelizationParameter: p})
+		if err != nil {
+			return nil, err
+		}
+		kdf = pkix.AlgorithmIdentifier{Algorithm: oidScrypt, Parameters: asn1.RawValue{FullBytes: params}}
+		if key, err = scrypt.Key(password, salt, n, r, p, c.keySize); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("mldsa: unknown PKCS #8 key derivation function %d", opts.KDF)
+	}
+	defer clear(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	var encParams, ciphertext []byte
+	if c.gcm {
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(rand, nonce); err != nil {
+			return nil, err
+		}
+		if encParams, err = asn1.Marshal(gcmParams{Nonce: nonce, ICVLen: aead.Overhead()}); err != nil {
+			return nil, err
+		}
+		ciphertext = aead.Seal(nil, nonce, plain, nil)
+	} else {
+		iv := make([]byte, aes.BlockSize)
+		if _, err := io.ReadFull(rand, iv); err != nil {
+			return nil, err
+		}
+		if encParams, err = asn1.Marshal(iv); err != nil {
+			return nil, err
+		}
+		// PKCS #7 padding
+		pad := aes.BlockSize - len(plain)%aes.BlockSize
+		ciphertext = make([]byte, len(plain)+pad)
+		copy(ciphertext, plain)
+		for i := len(plain); i < len(ciphertext); i++ {
+			ciphertext[i] = byte(pad)
+		}
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+	}
+
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: kdf,
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: c.oid, Parameters: asn1.RawValue{FullBytes: encParams}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData: ciphertext,
+	})
+}
+
+// ParseEncryptedPKCS8PrivateKey decrypts a DER PBES2-encrypted PKCS #8
+// private key with password and parses it like ParsePKCS8PrivateKey. PBKDF2
+// with HMAC-SHA1, SHA-256, SHA-384 or SHA-512 and scrypt are accepted, with
+// AES-CBC or AES-GCM. A wrong password yields ErrIncorrectPassword.
+func ParseEncryptedPKCS8PrivateKey(der, password []byte) (PrivateKey, error) {
+	var info encryptedPrivateKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil || len(rest) != 0 {
+		return nil, fmt.Errorf("%w: malformed EncryptedPrivateKeyInfo", errInvalidPKCS8)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("%w: unsupported encryption algorithm %v", errInvalidPKCS8, info.Algorithm.Algorithm)
+	}
+	var params pbes2Params
+	if rest, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil || len(rest) != 0 {
+		return nil, fmt.Errorf("%w: malformed PBES2 parameters", errInvalidPKCS8)
+	}
+
+	keySize, gcm := 0, false
+	for _, c := range pkcs8Ciphers {
+		if params.EncryptionScheme.Algorithm.Equal(c.oid) {
+			keySize, gcm = c.keySize, c.gcm
+		}
+	}
+	if keySize == 0 {
+		return nil, fmt.Errorf("%w: unsupported cipher %v", errInvalidPKCS8, params.EncryptionScheme.Algorithm)
+	}
+
+	key, err := deriveKey(params.KeyDerivationFunc, password, keySize)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var plain []byte
+	if gcm {
+		var gp gcmParams
+		if rest, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &gp); err != nil || len(rest) != 0 {
+			return nil, fmt.Errorf("%w: malformed GCM parameters", errInvalidPKCS8)
+		}
+		aead, err := cipher.NewGCMWithTagSize(block, gp.ICVLen)
+		if err != nil || len(gp.Nonce) != aead.NonceSize() {
+			return nil, fmt.Errorf("%w: unsupported GCM parameters", errInvalidPKCS8)
+		}
+		if plain, err = aead.Open(nil, gp.Nonce, info.EncryptedData, nil); err != nil {
+			return nil, ErrIncorrectPassword
+		}
+	} else {
+		var iv []byte
+		if rest, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil || len(rest) != 0 || len(iv) != aes.BlockSize {
+			return nil, fmt.Errorf("%w: malformed CBC parameters", errInvalidPKCS8)
+		}
+		n := len(info.EncryptedData)
+		if n == 0 || n%aes.BlockSize != 0 {
+			return nil, ErrIncorrectPassword
+		}
+		plain = make([]byte, n)
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, info.EncryptedData)
+		pad := int(plain[n-1])
+		if pad == 0 || pad > aes.BlockSize || subtle.ConstantTimeCompare(plain[n-pad:], makePadding(pad)) != 1 {
+			return nil, ErrIncorrectPassword
+		}
+		plain = plain[:n-pad]
+	}
+	defer clear(plain)
+
+	sk, err := ParsePKCS8PrivateKey(plain)
+	if err != nil {
+		// With CBC, a wrong password passes the padding check about once
+		// in 256 tries and then fails here.
+		return nil, fmt.Errorf("%w: %w", ErrIncorrectPassword, err)
+	}
+	return sk, nil
+}
+
+// makePadding returns n bytes of PKCS #7 padding.
+func makePadding(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(n)
+	}
+	return b
+}
+
+// maxPBKDF2Iterations bounds the work done for untrusted input.
+const maxPBKDF2Iterations = 1 << 24
+
+// maxScryptWork bounds the work done by scrypt for untrusted input, which
+// is proportional to N*r*p. The default parameters need 1 << 20.
+const maxScryptWork = 1 << 24
+
+// scryptWorkExceeded reports whether N*r*p is above maxScryptWork.
+// Non-positive values are left for scrypt.Key to reject.
+func scryptWorkExceeded(n, r, p int) bool {
+	if n <= 0 || r <= 0 || p <= 0 {
+		return false
+	}
+	return n > maxScryptWork/r || n*r > maxScryptWork/p
+}
+
+// deriveKey runs the PBES2 key derivation function kdf.
+func deriveKey(kdf pkix.AlgorithmIdentifier, password []byte, keySize int) ([]byte, error) {
+	switch {
+	case kdf.Algorithm.Equal(oidPBKDF2):
+		var p pbkdf2Params
+		if rest, err := asn1.Unmarshal(kdf.Parameters.FullBytes, &p); err != nil || len(rest) != 0 {
+			return nil, fmt.Errorf("%w: malformed PBKDF2 parameters", errInvalidPKCS8)
+		}
+		if p.IterationCount <= 0 || p.IterationCount > maxPBKDF2Iterations {
+			return nil, fmt.Errorf("%w: unsupported PBKDF2 iteration count %d", errInvalidPKCS8, p.IterationCount)
+		}
+		if p.KeyLength != 0 && p.KeyLength != keySize {
+			return nil, fmt.Errorf("%w: PBKDF2 key length does not match the cipher", errInvalidPKCS8)
+		}
+		var h func() hash.Hash
+		switch prf := p.PRF.Algorithm; {
+		case len(prf) == 0 || prf.Equal(oidHMACSHA1):
+			h = sha1.New
+		case prf.Equal(oidHMACSHA256):
+			h = sha256.New
+		case prf.Equal(oidHMACSHA384):
+			h = sha512.New384
+		case prf.Equal(oidHMACSHA512):
+			h = sha512.New
+		default:
+			return nil, fmt.Errorf("%w: unsupported PBKDF2 PRF %v", errInvalidPKCS8, prf)
+		}
+		return pbkdf2.Key(h, string(password), p.Salt, p.IterationCount, keySize)
+
+	case kdf.Algorithm.Equal(oidScrypt):
+		var p scryptParams
+		if rest, err := asn1.Unmarshal(kdf.Parameters.FullBytes, &p); err != nil || len(rest) != 0 {
+			return nil, fmt.Errorf("%w: malformed scrypt parameters", errInvalidPKCS8)
+		}
+		if p.KeyLength != 0 && p.KeyLength != keySize {
+			return nil, fmt.Errorf("%w: scrypt key length does not match the cipher", errInvalidPKCS8)
+		}
+		if scryptWorkExceeded(p.CostParameter, p.BlockSize, p.ParallelizationParameter) {
+			return nil, fmt.Errorf("%w: unsupported scrypt cost N=%d r=%d p=%d", errInvalidPKCS8, p.CostParameter, p.BlockSize, p.ParallelizationParameter)
+		}
+		key, err := scrypt.Key(password, p.Salt, p.CostParameter, p.BlockSize, p.ParallelizationParameter, keySize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidPKCS8, err)
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported key derivation function %v", errInvalidPKCS8, kdf.Algorithm)
+}
@@ -0,0 +1,66 @@
+package mldsa
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"testing"
+)
+
+func TestEncryptedPKCS8(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	password := []byte("correct horse battery staple")
+
+	for _, opts := range []*EncryptedPKCS8Options{
+		{Iterations: 1000},
+		{Iterations: 1000, Cipher: AES128CBC},
+		{Iterations: 1000, Cipher: AES192CBC},
+		{Iterations: 1000, Cipher: AES128GCM},
+		{Iterations: 1000, Cipher: AES256GCM},
+		{KDF: Scrypt, ScryptN: 1024, Cipher: AES256GCM},
+		{KDF: Scrypt, ScryptN: 1024, PKCS8Options: PKCS8Options{Form: PKCS8Both}},
+	} {
+		der, err := MarshalEncryptedPKCS8PrivateKey(key, password, opts)
+		if err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		}
+		sk, err := ParseEncryptedPKCS8PrivateKey(der, password)
+		if err != nil {
+			t.Fatalf("%+v: ParseEncryptedPKCS8PrivateKey failed: %v", opts, err)
+		}
+		if !key.Equal(sk) {
+			t.Errorf("%+v: round trip changed the key", opts)
+		}
+		if _, err := ParseEncryptedPKCS8PrivateKey(der, []byte("wrong")); !errors.Is(err, ErrIncorrectPassword) {
+			t.Errorf("%+v: wrong password: %v", opts, err)
+		}
+	}
+
+	if _, err := MarshalEncryptedPKCS8PrivateKey(key, password, &EncryptedPKCS8Options{Cipher: 99}); err == nil {
+		t.Error("MarshalEncryptedPKCS8PrivateKey accepted an unknown cipher")
+	}
+	plain, _ := MarshalPKCS8PrivateKey(key)
+	if _, err := ParseEncryptedPKCS8PrivateKey(plain, password); err == nil {
+		t.Error("ParseEncryptedPKCS8PrivateKey accepted an unencrypted key")
+	}
+}
+
+func TestEncryptedPKCS8ScryptWork(t *testing.T) {
+	// Each of these fits the scrypt memory limit, but together the
+	// parameters ask for 1 << 23 passes over 1 GiB.
+	hostile, err := asn1.Marshal(scryptParams{Salt: make([]byte, 16), CostParameter: 1 << 23, BlockSize: 1, ParallelizationParameter: 1 << 23})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kdf := pkix.AlgorithmIdentifier{Algorithm: oidScrypt, Parameters: asn1.RawValue{FullBytes: hostile}}
+	if _, err := deriveKey(kdf, []byte("password"), 32); !errors.Is(err, errInvalidPKCS8) {
+		t.Errorf("hostile scrypt parameters: %v", err)
+	}
+
+	key, _ := GenerateKey44(rand.Reader)
+	opts := &EncryptedPKCS8Options{KDF: Scrypt, ScryptN: 1 << 20, ScryptR: 8, ScryptP: 4}
+	if _, err := MarshalEncryptedPKCS8PrivateKey(key, []byte("password"), opts); err == nil {
+		t.Error("MarshalEncryptedPKCS8PrivateKey accepted scrypt parameters it would refuse to parse")
+	}
+}
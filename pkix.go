@@ -0,0 +1,57 @@
+package mldsa
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+)
+
+// errInvalidPKIX is wrapped by ParsePKIXPublicKey for malformed input.
+var errInvalidPKIX = errors.New("mldsa: invalid PKIX public key")
+
+// subjectPublicKeyInfo is the X.509 SubjectPublicKeyInfo structure. For
+// ML-DSA, the AlgorithmIdentifier has absent parameters and the BIT STRING
+// holds the raw public key encoding (RFC 9881).
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// MarshalPKIXPublicKey encodes pk as a DER SubjectPublicKeyInfo, as used in
+// certificates and "PUBLIC KEY" PEM blocks.
+func MarshalPKIXPublicKey(pk PublicKey) ([]byte, error) {
+	p, err := ParseParameterSet(pk.Scheme().Name())
+	if err != nil {
+		return nil, err
+	}
+	b := pk.Bytes()
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: p.AlgorithmIdentifier(),
+		PublicKey: asn1.BitString{Bytes: b, BitLength: 8 * len(b)},
+	})
+}
+
+// ParsePKIXPublicKey parses a DER SubjectPublicKeyInfo holding an ML-DSA
+// public key and returns a *PublicKey44, *PublicKey65 or *PublicKey87.
+func ParsePKIXPublicKey(der []byte) (PublicKey, error) {
+	var spki subjectPublicKeyInfo
+	rest, err := asn1.Unmarshal(der, &spki)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidPKIX, err)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing data", errInvalidPKIX)
+	}
+	p := parameterSetForOID(spki.Algorithm.Algorithm)
+	if p == 0 {
+		return nil, fmt.Errorf("%w %v", ErrUnknownParameterSet, spki.Algorithm.Algorithm)
+	}
+	if len(spki.Algorithm.Parameters.FullBytes) != 0 {
+		return nil, fmt.Errorf("%w: AlgorithmIdentifier parameters must be absent", errInvalidPKIX)
+	}
+	if spki.PublicKey.BitLength%8 != 0 {
+		return nil, fmt.Errorf("%w: public key is not a whole number of bytes", errInvalidPKIX)
+	}
+	return p.Scheme().UnmarshalPublicKey(spki.PublicKey.Bytes)
+}
@@ -0,0 +1,45 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestPKIXPublicKey(t *testing.T) {
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		pk := sk.Public().(PublicKey)
+		der, err := MarshalPKIXPublicKey(pk)
+		if err != nil {
+			t.Fatalf("%s: %v", s.Name(), err)
+		}
+		// SEQUENCE { SEQUENCE { OID }, BIT STRING { 0 unused bits, pk } }
+		if !bytes.HasSuffix(der, append([]byte{0x00}, pk.Bytes()...)) {
+			t.Errorf("%s: the BIT STRING does not end with the raw key", s.Name())
+		}
+		parsed, err := ParsePKIXPublicKey(der)
+		if err != nil {
+			t.Fatalf("%s: ParsePKIXPublicKey failed: %v", s.Name(), err)
+		}
+		if !parsed.Equal(pk) {
+			t.Errorf("%s: PKIX round trip changed the key", s.Name())
+		}
+	}
+
+	key, _ := GenerateKey44(rand.Reader)
+	der, _ := MarshalPKIXPublicKey(key.PublicKey())
+	if _, err := ParsePKIXPublicKey(append(bytes.Clone(der), 0)); err == nil {
+		t.Error("ParsePKIXPublicKey accepted trailing data")
+	}
+	// Relabel the ML-DSA-44 key as ML-DSA-65: the length no longer matches.
+	relabeled := bytes.Replace(der, []byte{0x03, 0x04, 0x03, 0x11}, []byte{0x03, 0x04, 0x03, 0x12}, 1)
+	if _, err := ParsePKIXPublicKey(relabeled); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("mislabeled key: %v", err)
+	}
+	unknown := bytes.Replace(der, []byte{0x03, 0x04, 0x03, 0x11}, []byte{0x03, 0x04, 0x03, 0x20}, 1)
+	if _, err := ParsePKIXPublicKey(unknown); !errors.Is(err, ErrUnknownParameterSet) {
+		t.Errorf("unknown OID: %v", err)
+	}
+}
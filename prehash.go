@@ -0,0 +1,144 @@
+package mldsa
+
+import (
+	"crypto"
+	"crypto/sha3"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// HashML-DSA (FIPS 204, Section 5.4) signs a digest of the message rather
+// than the message itself. The signed message M' binds the digest to the
+// hash function through the function's DER-encoded OID:
+//
+//	M' = 1 || len(ctx) || ctx || OID(PH) || PH(M)
+//
+// Signatures produced this way are not interchangeable with pure ML-DSA
+// signatures and must be checked with VerifyPreHash.
+
+// ErrUnsupportedPreHash is returned for pre-hash functions that are not
+// approved for HashML-DSA.
+var ErrUnsupportedPreHash = errors.New("mldsa: unsupported pre-hash function")
+
+// SHAKE128 and SHAKE256 identify the SHAKE pre-hash functions, which have no
+// crypto.Hash value of their own. Their outputs are 256 and 512 bits long,
+// as required by FIPS 204.
+//
+// They are not registered with package crypto, and are only meaningful to
+// the HashML-DSA functions of this package and to PreHash, PreHashOID and
+// PreHashSize. They must never be passed to the crypto.Hash methods: Size
+// and New panic, Available reports false and String does not name them.
+// Code that handles arbitrary pre-hash values should use PreHash and
+// PreHashSize instead of h.New and h.Size.
+const (
+	SHAKE128 crypto.Hash = 256 + iota
+	SHAKE256
+)
+
+// preHash describes an approved pre-hash function.
+type preHash struct {
+	h    crypto.Hash
+	size int
+	oid  asn1.ObjectIdentifier
+}
+
+// preHashes lists the approved pre-hash functions and their OIDs, all in the
+// NIST hash algorithm arc 2.16.840.1.101.3.4.2.
+var preHashes = []preHash{
+	{crypto.SHA224, 28, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 4}},
+	{crypto.SHA256, 32, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}},
+	{crypto.SHA384, 48, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}},
+	{crypto.SHA512, 64, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}},
+	{crypto.SHA512_224, 28, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 5}},
+	{crypto.SHA512_256, 32, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 6}},
+	{crypto.SHA3_224, 28, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 7}},
+	{crypto.SHA3_256, 32, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 8}},
+	{crypto.SHA3_384, 48, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 9}},
+	{crypto.SHA3_512, 64, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 10}},
+	{SHAKE128, 32, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 11}},
+	{SHAKE256, 64, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 12}},
+}
+
+// lookupPreHash returns the description of h, or false if h is not an
+// approved pre-hash function.
+func lookupPreHash(h crypto.Hash) (preHash, bool) {
+	for _, p := range preHashes {
+		if p.h == h {
+			return p, true
+		}
+	}
+	return preHash{}, false
+}
+
+// PreHashFunctions returns the pre-hash functions supported for HashML-DSA.
+func PreHashFunctions() []crypto.Hash {
+	list := make([]crypto.Hash, len(preHashes))
+	for i, p := range preHashes {
+		list[i] = p.h
+	}
+	return list
+}
+
+// PreHashOID returns the OID identifying h in HashML-DSA signatures, or false
+// if h is not supported.
+func PreHashOID(h crypto.Hash) (asn1.ObjectIdentifier, bool) {
+	p, ok := lookupPreHash(h)
+	if !ok {
+		return nil, false
+	}
+	return slices.Clone(p.oid), true
+}
+
+// PreHashSize returns the length in bytes of the digests of h, or false if h
+// is not supported. Unlike h.Size, it also supports SHAKE128 and SHAKE256.
+func PreHashSize(h crypto.Hash) (int, bool) {
+	p, ok := lookupPreHash(h)
+	return p.size, ok
+}
+
+// PreHash computes the digest of message with h, for use with SignPreHash
+// and VerifyPreHash. Unlike h.New, it also supports SHAKE128 and SHAKE256.
+func PreHash(h crypto.Hash, message []byte) ([]byte, error) {
+	p, ok := lookupPreHash(h)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedPreHash, h)
+	}
+	switch h {
+	case SHAKE128:
+		return sha3.SumSHAKE128(message, p.size), nil
+	case SHAKE256:
+		return sha3.SumSHAKE256(message, p.size), nil
+	}
+	if !h.Available() {
+		return nil, fmt.Errorf("%w: %v is not linked into the binary", ErrUnsupportedPreHash, h)
+	}
+	hh := h.New()
+	hh.Write(message)
+	return hh.Sum(nil), nil
+}
+
+// preHashMPrime builds M' for HashML-DSA.
+func preHashMPrime(digest []byte, ph crypto.Hash, context []byte) ([]byte, error) {
+	if len(context) > 255 {
+		return nil, ErrContextTooLong
+	}
+	p, ok := lookupPreHash(ph)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedPreHash, ph)
+	}
+	if len(digest) != p.size {
+		return nil, fmt.Errorf("mldsa: digest length %d does not match %v", len(digest), ph)
+	}
+	oid, err := asn1.Marshal(p.oid)
+	if err != nil {
+		return nil, err
+	}
+
+	mPrime := make([]byte, 0, 2+len(context)+len(oid)+len(digest))
+	mPrime = append(mPrime, 1, byte(len(context)))
+	mPrime = append(mPrime, context...)
+	mPrime = append(mPrime, oid...)
+	return append(mPrime, digest...), nil
+}
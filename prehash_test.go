@@ -0,0 +1,106 @@
+package mldsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestPreHash(t *testing.T) {
+	message := []byte("pre-hashed message")
+	context := []byte("ctx")
+	digest := sha512.Sum512(message)
+
+	for _, s := range Schemes() {
+		sk, _ := s.GenerateKey(rand.Reader)
+		signer := sk.(interface {
+			SignPreHash(io.Reader, []byte, crypto.Hash, []byte) ([]byte, error)
+		})
+		pk := sk.Public().(interface {
+			PublicKey
+			VerifyPreHash(sig, digest []byte, ph crypto.Hash, context []byte) bool
+		})
+
+		sig, err := signer.SignPreHash(rand.Reader, digest[:], crypto.SHA512, context)
+		if err != nil {
+			t.Fatalf("%s: SignPreHash failed: %v", s.Name(), err)
+		}
+		if !pk.VerifyPreHash(sig, digest[:], crypto.SHA512, context) {
+			t.Errorf("%s: VerifyPreHash failed", s.Name())
+		}
+		if pk.Verify(sig, digest[:], context) || pk.Verify(sig, message, context) {
+			t.Errorf("%s: HashML-DSA signature verified as pure ML-DSA", s.Name())
+		}
+		if pk.VerifyPreHash(sig, digest[:], crypto.SHA512, nil) {
+			t.Errorf("%s: VerifyPreHash accepted the wrong context", s.Name())
+		}
+
+		// The same bytes under another hash function must not verify.
+		short := sha256.Sum256(message)
+		sig, _ = signer.SignPreHash(rand.Reader, short[:], crypto.SHA256, nil)
+		if !pk.VerifyPreHash(sig, short[:], crypto.SHA256, nil) {
+			t.Errorf("%s: VerifyPreHash failed for SHA-256", s.Name())
+		}
+		if pk.VerifyPreHash(sig, short[:], crypto.SHA3_256, nil) {
+			t.Errorf("%s: VerifyPreHash accepted the wrong hash function", s.Name())
+		}
+	}
+
+	key, _ := GenerateKey65(rand.Reader)
+	if _, err := key.SignPreHash(rand.Reader, make([]byte, 16), crypto.MD5, nil); !errors.Is(err, ErrUnsupportedPreHash) {
+		t.Errorf("SignPreHash with MD5: %v", err)
+	}
+	if _, err := key.SignPreHash(rand.Reader, digest[:32], crypto.SHA512, nil); err == nil {
+		t.Error("SignPreHash accepted a digest of the wrong length")
+	}
+}
+
+func TestPreHashFunctions(t *testing.T) {
+	key, _ := GenerateKey44(rand.Reader)
+	pk := key.PublicKey()
+	message := []byte("message")
+
+	for _, h := range PreHashFunctions() {
+		oid, ok := PreHashOID(h)
+		if !ok || len(oid) != 9 || oid[8] < 1 || oid[8] > 12 {
+			t.Errorf("%v: unexpected OID %v", h, oid)
+		}
+		digest, err := PreHash(h, message)
+		if err != nil {
+			t.Fatalf("%v: PreHash failed: %v", h, err)
+		}
+		if size, ok := PreHashSize(h); !ok || size != len(digest) {
+			t.Errorf("%v: PreHashSize = %d, want %d", h, size, len(digest))
+		}
+		sig, err := key.SignPreHash(rand.Reader, digest, h, nil)
+		if err != nil {
+			t.Fatalf("%v: SignPreHash failed: %v", h, err)
+		}
+		if !pk.VerifyPreHash(sig, digest, h, nil) {
+			t.Errorf("%v: VerifyPreHash failed", h)
+		}
+	}
+
+	if len(PreHashFunctions()) != 12 {
+		t.Errorf("got %d pre-hash functions, want 12", len(PreHashFunctions()))
+	}
+	if oid, _ := PreHashOID(SHAKE256); oid.String() != "2.16.840.1.101.3.4.2.12" {
+		t.Errorf("SHAKE256 OID = %v", oid)
+	}
+	if d, _ := PreHash(SHAKE128, message); len(d) != 32 {
+		t.Errorf("SHAKE128 digest is %d bytes, want 32", len(d))
+	}
+	if _, ok := PreHashOID(crypto.MD5); ok {
+		t.Error("PreHashOID reported MD5 as supported")
+	}
+	if _, ok := PreHashSize(crypto.MD5); ok {
+		t.Error("PreHashSize reported MD5 as supported")
+	}
+	if _, err := PreHash(crypto.SHA1, message); !errors.Is(err, ErrUnsupportedPreHash) {
+		t.Errorf("PreHash with SHA-1: %v", err)
+	}
+}
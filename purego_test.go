@@ -0,0 +1,7 @@
+//go:build purego
+
+package mldsa
+
+// puregoEnabled reports whether the purego build tag is set. The standard
+// library then refuses to start in FIPS 140-3 mode.
+const puregoEnabled = true
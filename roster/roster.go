@@ -0,0 +1,236 @@
+// Package roster implements a small signed trust bundle for ML-DSA keys.
+//
+// A Roster lists trusted public keys together with a key ID, fingerprint,
+// role and validity window. It is serialized as JSON and signed by a root
+// ML-DSA key, giving fleets a PKI-lite way to distribute trust without
+// X.509. The signed form is a JSON object holding the exact payload bytes
+// that were signed, so verification never depends on re-encoding.
+//
+// Basic usage:
+//
+//	data, err := roster.Sign(rand.Reader, rootKey, r)
+//	...
+//	r, err := roster.Verify(data, rootKey.PublicKey())
+//	entry := r.Lookup("build-1", time.Now())
+package roster
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+// Version is the roster format version produced by this package.
+const Version = 1
+
+// signingContext is the ML-DSA context string used for roster signatures.
+var signingContext = []byte("mldsa-roster-v1")
+
+var (
+	// ErrInvalidSignature is returned when the roster signature does not verify.
+	ErrInvalidSignature = errors.New("roster: invalid signature")
+	// ErrUnsupportedVersion is returned for rosters of an unknown format version.
+	ErrUnsupportedVersion = errors.New("roster: unsupported version")
+)
+
+// Entry describes one trusted public key.
+type Entry struct {
+	KeyID       string    `json:"kid"`
+	Algorithm   string    `json:"alg"` // "ML-DSA-44", "ML-DSA-65" or "ML-DSA-87"
+	PublicKey   []byte    `json:"pub"`
+	Fingerprint string    `json:"fingerprint"` // hex SHA-256 of PublicKey
+	Role        string    `json:"role,omitempty"`
+	NotBefore   time.Time `json:"nbf"`
+	NotAfter    time.Time `json:"exp"`
+}
+
+// NewEntry returns an entry for pub, filling in the algorithm and fingerprint.
+func NewEntry(keyID, role string, pub mldsa.PublicKey, notBefore, notAfter time.Time) Entry {
+	b := pub.Bytes()
+	return Entry{
+		KeyID:       keyID,
+		Algorithm:   pub.Scheme().Name(),
+		PublicKey:   b,
+		Fingerprint: Fingerprint(b),
+		Role:        role,
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+	}
+}
+
+// Key parses the entry's public key.
+func (e *Entry) Key() (mldsa.PublicKey, error) {
+	s := mldsa.SchemeByName(e.Algorithm)
+	if s == nil {
+		return nil, fmt.Errorf("roster: unsupported algorithm %q", e.Algorithm)
+	}
+	return s.UnmarshalPublicKey(e.PublicKey)
+}
+
+// ValidAt reports whether t falls within the entry's validity window.
+func (e *Entry) ValidAt(t time.Time) bool {
+	return !t.Before(e.NotBefore) && !t.After(e.NotAfter)
+}
+
+// check validates the internal consistency of the entry.
+func (e *Entry) check() error {
+	if e.KeyID == "" {
+		return errors.New("roster: entry without key ID")
+	}
+	if _, err := e.Key(); err != nil {
+		return fmt.Errorf("roster: entry %q: %w", e.KeyID, err)
+	}
+	if e.Fingerprint != Fingerprint(e.PublicKey) {
+		return fmt.Errorf("roster: entry %q: fingerprint mismatch", e.KeyID)
+	}
+	if e.NotAfter.Before(e.NotBefore) {
+		return fmt.Errorf("roster: entry %q: invalid validity window", e.KeyID)
+	}
+	return nil
+}
+
+// Roster is a versioned list of trusted keys.
+type Roster struct {
+	Version  int       `json:"version"`
+	Serial   uint64    `json:"serial"`
+	IssuedAt time.Time `json:"iat"`
+	Entries  []Entry   `json:"entries"`
+}
+
+// Lookup returns the entry with the given key ID that is valid at t, or nil.
+func (r *Roster) Lookup(keyID string, t time.Time) *Entry {
+	for i := range r.Entries {
+		if e := &r.Entries[i]; e.KeyID == keyID && e.ValidAt(t) {
+			return e
+		}
+	}
+	return nil
+}
+
+// Check validates every entry and rejects duplicate key IDs.
+func (r *Roster) Check() error {
+	if r.Version != Version {
+		return ErrUnsupportedVersion
+	}
+	seen := make(map[string]bool, len(r.Entries))
+	for i := range r.Entries {
+		e := &r.Entries[i]
+		if err := e.check(); err != nil {
+			return err
+		}
+		if seen[e.KeyID] {
+			return fmt.Errorf("roster: duplicate key ID %q", e.KeyID)
+		}
+		seen[e.KeyID] = true
+	}
+	return nil
+}
+
+// signed is the wire form of a signed roster.
+type signed struct {
+	Payload   []byte `json:"payload"`
+	Algorithm string `json:"alg"`
+	Signature []byte `json:"sig"`
+}
+
+// Sign encodes r and signs it with root, returning the signed roster. If
+// r.Version is zero it is set to Version.
+func Sign(rand io.Reader, root crypto.Signer, r *Roster) ([]byte, error) {
+	if r.Version == 0 {
+		r.Version = Version
+	}
+	if err := r.Check(); err != nil {
+		return nil, err
+	}
+	pub, ok := root.Public().(mldsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("roster: unsupported root key type %T", root.Public())
+	}
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := root.Sign(rand, payload, &mldsa.SignerOpts{Context: signingContext})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&signed{Payload: payload, Algorithm: pub.Scheme().Name(), Signature: sig})
+}
+
+// Verify checks the signature on a signed roster against the root public key
+// and returns the decoded roster.
+func Verify(data []byte, root mldsa.PublicKey) (*Roster, error) {
+	var s signed
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if s.Algorithm != root.Scheme().Name() || !root.Verify(s.Signature, s.Payload, signingContext) {
+		return nil, ErrInvalidSignature
+	}
+
+	r := &Roster{}
+	if err := json.Unmarshal(s.Payload, r); err != nil {
+		return nil, err
+	}
+	if err := r.Check(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Diff describes the changes between two rosters, keyed by key ID.
+type Diff struct {
+	Added   []Entry
+	Removed []Entry
+	Changed []Entry // entries of the new roster whose content differs
+}
+
+// Empty reports whether the diff contains no changes.
+func (d *Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Compare returns the changes needed to go from old to new.
+func Compare(old, new *Roster) *Diff {
+	d := &Diff{}
+	oldByID := make(map[string]*Entry, len(old.Entries))
+	for i := range old.Entries {
+		oldByID[old.Entries[i].KeyID] = &old.Entries[i]
+	}
+	newIDs := make(map[string]bool, len(new.Entries))
+	for _, e := range new.Entries {
+		newIDs[e.KeyID] = true
+		o, ok := oldByID[e.KeyID]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, e)
+		case !sameEntry(o, &e):
+			d.Changed = append(d.Changed, e)
+		}
+	}
+	for _, e := range old.Entries {
+		if !newIDs[e.KeyID] {
+			d.Removed = append(d.Removed, e)
+		}
+	}
+	return d
+}
+
+// sameEntry reports whether two entries carry the same content.
+func sameEntry(a, b *Entry) bool {
+	return a.Algorithm == b.Algorithm && a.Fingerprint == b.Fingerprint && a.Role == b.Role &&
+		a.NotBefore.Equal(b.NotBefore) && a.NotAfter.Equal(b.NotAfter)
+}
+
+// Fingerprint returns the hex-encoded SHA-256 of an encoded public key.
+func Fingerprint(publicKey []byte) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:])
+}
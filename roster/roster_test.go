@@ -0,0 +1,113 @@
+package roster
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/mldsa"
+)
+
+func testRoster(t *testing.T) (*mldsa.Key65, *Roster) {
+	t.Helper()
+	root, err := mldsa.GenerateKey65(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	build, _ := mldsa.GenerateKey44(rand.Reader)
+	deploy, _ := mldsa.GenerateKey87(rand.Reader)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	e1 := NewEntry("build-1", "build", build.PublicKey(), now.Add(-time.Hour), now.Add(time.Hour))
+	e2 := NewEntry("deploy-1", "deploy", deploy.PublicKey(), now.Add(-time.Hour), now.Add(time.Hour))
+	return root, &Roster{Serial: 1, IssuedAt: now, Entries: []Entry{e1, e2}}
+}
+
+func TestSignVerify(t *testing.T) {
+	root, r := testRoster(t)
+	data, err := Sign(rand.Reader, root, r)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	got, err := Verify(data, root.PublicKey())
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if len(got.Entries) != 2 || got.Serial != 1 {
+		t.Fatalf("unexpected roster: %+v", got)
+	}
+
+	e := got.Lookup("deploy-1", time.Now())
+	if e == nil {
+		t.Fatal("Lookup did not find deploy-1")
+	}
+	if !bytes.Equal(e.PublicKey, r.Entries[1].PublicKey) || e.Role != "deploy" {
+		t.Error("entry did not roundtrip")
+	}
+	if _, err := e.Key(); err != nil {
+		t.Errorf("Key failed: %v", err)
+	}
+	if got.Lookup("deploy-1", time.Now().Add(2*time.Hour)) != nil {
+		t.Error("Lookup returned an expired entry")
+	}
+
+	other, _ := mldsa.GenerateKey65(rand.Reader)
+	if _, err := Verify(data, other.PublicKey()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify with wrong root: got %v, want ErrInvalidSignature", err)
+	}
+
+	var s signed
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	s.Payload = bytes.Replace(s.Payload, []byte(`"serial":1`), []byte(`"serial":2`), 1)
+	tampered, _ := json.Marshal(&s)
+	if _, err := Verify(tampered, root.PublicKey()); !errors.Is(err, ErrInvalidSignature) {
+		t.Error("Verify accepted a tampered roster")
+	}
+}
+
+func TestCheckRejectsBadEntries(t *testing.T) {
+	root, r := testRoster(t)
+
+	r.Entries[0].Fingerprint = Fingerprint([]byte("other"))
+	if _, err := Sign(rand.Reader, root, r); err == nil {
+		t.Error("Sign accepted an entry with a wrong fingerprint")
+	}
+
+	_, r = testRoster(t)
+	r.Entries[1].KeyID = r.Entries[0].KeyID
+	if _, err := Sign(rand.Reader, root, r); err == nil {
+		t.Error("Sign accepted duplicate key IDs")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	_, old := testRoster(t)
+	_, extra := testRoster(t)
+
+	next := &Roster{Serial: 2, IssuedAt: old.IssuedAt}
+	changed := old.Entries[0]
+	changed.Role = "release"
+	added := extra.Entries[0]
+	added.KeyID = "build-2"
+	next.Entries = []Entry{changed, added}
+
+	d := Compare(old, next)
+	if len(d.Added) != 1 || d.Added[0].KeyID != "build-2" {
+		t.Errorf("Added = %+v", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].KeyID != "deploy-1" {
+		t.Errorf("Removed = %+v", d.Removed)
+	}
+	if len(d.Changed) != 1 || d.Changed[0].Role != "release" {
+		t.Errorf("Changed = %+v", d.Changed)
+	}
+	if !Compare(old, old).Empty() {
+		t.Error("Compare of a roster with itself is not empty")
+	}
+}
@@ -14,22 +14,25 @@ func SampleNTTPoly(rho []byte, s, r byte) NttElement {
 
 	var buf [168]byte // SHAKE128 rate
 	var a NttElement
-	j := 0
-
-	for {
+	for j := 0; j < N; {
 		h.Read(buf[:])
-		for i := 0; i < len(buf) && j < N; i += 3 {
-			// Extract 24 bits, mask to 23 bits
-			v := uint32(buf[i]) | uint32(buf[i+1])<<8 | (uint32(buf[i+2])&0x7f)<<16
-			if v < Q {
-				a[j] = FieldElement(v)
-				j++
-			}
-		}
-		if j >= N {
-			return a
+		j = rejNTTPoly(&a, j, buf[:])
+	}
+	return a
+}
+
+// rejNTTPoly fills a from coefficient j on with the 23-bit values of buf
+// below Q, and returns the next coefficient to fill.
+func rejNTTPoly(a *NttElement, j int, buf []byte) int {
+	for i := 0; i+3 <= len(buf) && j < N; i += 3 {
+		// Extract 24 bits, mask to 23 bits
+		v := uint32(buf[i]) | uint32(buf[i+1])<<8 | (uint32(buf[i+2])&0x7f)<<16
+		if v < Q {
+			a[j] = FieldElement(v)
+			j++
 		}
 	}
+	return j
 }
 
 // SampleBoundedPoly generates a polynomial with coefficients in [-eta, eta]
@@ -81,13 +84,20 @@ func SampleBoundedPoly(seed []byte, eta int, nonce uint16) RingElement {
 			}
 		}
 	}
+	// The output stream determines the secret vectors; wipe it.
+	clear(buf[:])
+	h.Reset()
 	return a
 }
 
 // SampleChallenge generates the challenge polynomial c with tau non-zero
-// coefficients in {-1, 1}. Uses Fisher-Yates shuffle.
+// coefficients in {-1, 1}. Uses Fisher-Yates shuffle. It returns the zero
+// polynomial if tau is not in [0, N].
 // Implements FIPS 204 Algorithm 29 (SampleInBall).
 func SampleChallenge(seed []byte, tau int) RingElement {
+	if tau < 0 || tau > N {
+		return RingElement{}
+	}
 	h := sha3.NewSHAKE256()
 	h.Write(seed)
 
@@ -132,22 +142,28 @@ func SampleChallenge(seed []byte, tau int) RingElement {
 // ExpandMask generates a polynomial with coefficients in [-gamma1+1, gamma1].
 // Implements FIPS 204 Algorithm 34 (ExpandMask).
 func ExpandMask(seed []byte, gamma1Bits int) RingElement {
+	var f RingElement
+	expandMaskTo(&f, seed, gamma1Bits)
+	return f
+}
+
+// expandMaskTo is ExpandMask writing into f. The mask is as secret as the
+// signing key, so it wipes its buffer and sponge state before returning.
+func expandMaskTo(f *RingElement, seed []byte, gamma1Bits int) {
 	h := sha3.NewSHAKE256()
 	h.Write(seed)
 
-	var f RingElement
+	// 18 or 20 bits per coefficient, 256 coefficients = 576 or 640 bytes
+	var buf [640]byte
 	if gamma1Bits == 17 {
-		// 18 bits per coefficient, 256 coefficients = 576 bytes
-		var buf [576]byte
-		h.Read(buf[:])
-		unpackZ17Into(buf[:], &f)
+		h.Read(buf[:576])
+		unpackZ17Into(buf[:576], f)
 	} else { // gamma1Bits == 19
-		// 20 bits per coefficient, 256 coefficients = 640 bytes
-		var buf [640]byte
 		h.Read(buf[:])
-		unpackZ19Into(buf[:], &f)
+		unpackZ19Into(buf[:], f)
 	}
-	return f
+	clear(buf[:])
+	h.Reset()
 }
 
 // unpackZ17Into unpacks 256 coefficients encoded as 18-bit signed values.
@@ -0,0 +1,266 @@
+package mldsa
+
+import (
+	"crypto"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Scheme is a parameter-set-agnostic view of one ML-DSA parameter set. It
+// lets applications select a parameter set at runtime, by name, instead of
+// hardcoding one of the *44, *65 or *87 APIs.
+type Scheme interface {
+	// Name returns the parameter set name, e.g. "ML-DSA-65".
+	Name() string
+
+	// PublicKeySize returns the size of an encoded public key.
+	PublicKeySize() int
+	// PrivateKeySize returns the size of an encoded (expanded) private key.
+	PrivateKeySize() int
+	// SignatureSize returns the size of a signature.
+	SignatureSize() int
+	// SeedSize returns the size of the key generation seed.
+	SeedSize() int
+
+	// GenerateKey generates a new key pair.
+	GenerateKey(rand io.Reader) (PrivateKey, error)
+	// NewKeyFromSeed derives a key pair from a seed.
+	NewKeyFromSeed(seed []byte) (PrivateKey, error)
+	// UnmarshalPublicKey parses an encoded public key.
+	UnmarshalPublicKey(b []byte) (PublicKey, error)
+	// UnmarshalPrivateKey parses an encoded (expanded) private key.
+	UnmarshalPrivateKey(b []byte) (PrivateKey, error)
+
+	// Sign signs message with an optional context string. sk must be a
+	// private key of this parameter set.
+	Sign(rand io.Reader, sk crypto.Signer, message, context []byte) ([]byte, error)
+	// Verify reports whether sig is a valid signature of message under
+	// context. It returns false if pk is not a public key of this parameter set.
+	Verify(pk crypto.PublicKey, sig, message, context []byte) bool
+}
+
+// errWrongScheme is returned when a key of another parameter set is passed
+// to a Scheme.
+var errWrongScheme = errors.New("mldsa: key does not belong to this parameter set")
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = make(map[string]Scheme)
+)
+
+func init() {
+	RegisterScheme(scheme44{})
+	RegisterScheme(scheme65{})
+	RegisterScheme(scheme87{})
+}
+
+// RegisterScheme makes s available through SchemeByName and Schemes. It
+// panics if a scheme with the same name is already registered.
+func RegisterScheme(s Scheme) {
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	if _, dup := schemes[s.Name()]; dup {
+		panic("mldsa: RegisterScheme called twice for " + s.Name())
+	}
+	schemes[s.Name()] = s
+}
+
+// SchemeByName returns the registered scheme with the given name, or nil if
+// there is none.
+func SchemeByName(name string) Scheme {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	return schemes[name]
+}
+
+// Schemes returns all registered schemes, sorted by name.
+func Schemes() []Scheme {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	list := make([]Scheme, 0, len(schemes))
+	for _, s := range schemes {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// signWithScheme signs through a crypto.Signer that is not one of this
+// package's concrete types, such as a wrapper around an external device.
+// The signer's public key must be of type P.
+func signWithScheme[P crypto.PublicKey](rand io.Reader, sk crypto.Signer, message, context []byte) ([]byte, error) {
+	if _, ok := sk.Public().(P); !ok {
+		return nil, errWrongScheme
+	}
+	return sk.Sign(rand, message, &SignerOpts{Context: context})
+}
+
+// scheme44 implements Scheme for ML-DSA-44.
+type scheme44 struct{}
+
+func (scheme44) Name() string        { return "ML-DSA-44" }
+func (scheme44) PublicKeySize() int  { return PublicKeySize44 }
+func (scheme44) PrivateKeySize() int { return PrivateKeySize44 }
+func (scheme44) SignatureSize() int  { return SignatureSize44 }
+func (scheme44) SeedSize() int       { return SeedSize }
+
+func (scheme44) GenerateKey(rand io.Reader) (PrivateKey, error) {
+	k, err := GenerateKey44(rand)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (scheme44) NewKeyFromSeed(seed []byte) (PrivateKey, error) {
+	k, err := NewKey44(seed)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (scheme44) UnmarshalPublicKey(b []byte) (PublicKey, error) {
+	k, err := NewPublicKey44(b)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (scheme44) UnmarshalPrivateKey(b []byte) (PrivateKey, error) {
+	k, err := NewPrivateKey44(b)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (scheme44) Sign(rand io.Reader, sk crypto.Signer, message, context []byte) ([]byte, error) {
+	switch k := sk.(type) {
+	case *Key44:
+		return k.SignWithContext(rand, message, context)
+	case *PrivateKey44:
+		return k.SignWithContext(rand, message, context)
+	}
+	return signWithScheme[*PublicKey44](rand, sk, message, context)
+}
+
+func (scheme44) Verify(pk crypto.PublicKey, sig, message, context []byte) bool {
+	k, ok := pk.(*PublicKey44)
+	return ok && k.Verify(sig, message, context)
+}
+
+// scheme65 implements Scheme for ML-DSA-65.
+type scheme65 struct{}
+
+func (scheme65) Name() string        { return "ML-DSA-65" }
+func (scheme65) PublicKeySize() int  { return PublicKeySize65 }
+func (scheme65) PrivateKeySize() int { return PrivateKeySize65 }
+func (scheme65) SignatureSize() int  { return SignatureSize65 }
+func (scheme65) SeedSize() int       { return SeedSize }
+
+func (scheme65) GenerateKey(rand io.Reader) (PrivateKey, error) {
+	k, err := GenerateKey65(rand)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (scheme65) NewKeyFromSeed(seed []byte) (PrivateKey, error) {
+	k, err := NewKey65(seed)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (scheme65) UnmarshalPublicKey(b []byte) (PublicKey, error) {
+	k, err := NewPublicKey65(b)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (scheme65) UnmarshalPrivateKey(b []byte) (PrivateKey, error) {
+	k, err := NewPrivateKey65(b)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (scheme65) Sign(rand io.Reader, sk crypto.Signer, message, context []byte) ([]byte, error) {
+	switch k := sk.(type) {
+	case *Key65:
+		return k.SignWithContext(rand, message, context)
+	case *PrivateKey65:
+		return k.SignWithContext(rand, message, context)
+	}
+	return signWithScheme[*PublicKey65](rand, sk, message, context)
+}
+
+func (scheme65) Verify(pk crypto.PublicKey, sig, message, context []byte) bool {
+	k, ok := pk.(*PublicKey65)
+	return ok && k.Verify(sig, message, context)
+}
+
+// scheme87 implements Scheme for ML-DSA-87.
+type scheme87 struct{}
+
+func (scheme87) Name() string        { return "ML-DSA-87" }
+func (scheme87) PublicKeySize() int  { return PublicKeySize87 }
+func (scheme87) PrivateKeySize() int { return PrivateKeySize87 }
+func (scheme87) SignatureSize() int  { return SignatureSize87 }
+func (scheme87) SeedSize() int       { return SeedSize }
+
+func (scheme87) GenerateKey(rand io.Reader) (PrivateKey, error) {
+	k, err := GenerateKey87(rand)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (scheme87) NewKeyFromSeed(seed []byte) (PrivateKey, error) {
+	k, err := NewKey87(seed)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (scheme87) UnmarshalPublicKey(b []byte) (PublicKey, error) {
+	k, err := NewPublicKey87(b)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (scheme87) UnmarshalPrivateKey(b []byte) (PrivateKey, error) {
+	k, err := NewPrivateKey87(b)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (scheme87) Sign(rand io.Reader, sk crypto.Signer, message, context []byte) ([]byte, error) {
+	switch k := sk.(type) {
+	case *Key87:
+		return k.SignWithContext(rand, message, context)
+	case *PrivateKey87:
+		return k.SignWithContext(rand, message, context)
+	}
+	return signWithScheme[*PublicKey87](rand, sk, message, context)
+}
+
+func (scheme87) Verify(pk crypto.PublicKey, sig, message, context []byte) bool {
+	k, ok := pk.(*PublicKey87)
+	return ok && k.Verify(sig, message, context)
+}
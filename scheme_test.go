@@ -0,0 +1,112 @@
+package mldsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestSchemeRegistry(t *testing.T) {
+	names := []string{"ML-DSA-44", "ML-DSA-65", "ML-DSA-87"}
+	list := Schemes()
+	if len(list) != len(names) {
+		t.Fatalf("Schemes() returned %d schemes, want %d", len(list), len(names))
+	}
+	for i, name := range names {
+		if list[i].Name() != name {
+			t.Errorf("Schemes()[%d] = %s, want %s", i, list[i].Name(), name)
+		}
+		if SchemeByName(name) == nil {
+			t.Errorf("SchemeByName(%q) returned nil", name)
+		}
+	}
+	if SchemeByName("ML-DSA-1") != nil {
+		t.Error("SchemeByName returned a scheme for an unknown name")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterScheme did not panic on a duplicate name")
+		}
+	}()
+	RegisterScheme(scheme65{})
+}
+
+func TestSchemeRoundtrip(t *testing.T) {
+	message := []byte("scheme message")
+	context := []byte("scheme context")
+
+	for _, s := range Schemes() {
+		sk, err := s.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("%s: GenerateKey failed: %v", s.Name(), err)
+		}
+		sig, err := s.Sign(rand.Reader, sk, message, context)
+		if err != nil {
+			t.Fatalf("%s: Sign failed: %v", s.Name(), err)
+		}
+		if len(sig) != s.SignatureSize() {
+			t.Errorf("%s: signature size %d, want %d", s.Name(), len(sig), s.SignatureSize())
+		}
+		if !s.Verify(sk.Public(), sig, message, context) {
+			t.Errorf("%s: Verify failed", s.Name())
+		}
+
+		seedKey, err := s.NewKeyFromSeed(make([]byte, s.SeedSize()))
+		if err != nil {
+			t.Fatalf("%s: NewKeyFromSeed failed: %v", s.Name(), err)
+		}
+		pkBytes := seedKey.Public().(interface{ Bytes() []byte }).Bytes()
+		if len(pkBytes) != s.PublicKeySize() {
+			t.Errorf("%s: public key size %d, want %d", s.Name(), len(pkBytes), s.PublicKeySize())
+		}
+		pk, err := s.UnmarshalPublicKey(pkBytes)
+		if err != nil {
+			t.Fatalf("%s: UnmarshalPublicKey failed: %v", s.Name(), err)
+		}
+		if !pk.(interface{ Equal(crypto.PublicKey) bool }).Equal(seedKey.Public()) {
+			t.Errorf("%s: public key did not roundtrip", s.Name())
+		}
+
+		if _, err := s.UnmarshalPublicKey(pkBytes[1:]); err == nil {
+			t.Errorf("%s: UnmarshalPublicKey accepted a short key", s.Name())
+		}
+		if k, err := s.UnmarshalPrivateKey(nil); err == nil || k != nil {
+			t.Errorf("%s: UnmarshalPrivateKey(nil) = %v, %v", s.Name(), k, err)
+		}
+	}
+}
+
+func TestSchemeRejectsOtherLevel(t *testing.T) {
+	key44, _ := GenerateKey44(rand.Reader)
+	s := SchemeByName("ML-DSA-65")
+	if _, err := s.Sign(rand.Reader, key44, []byte("m"), nil); err == nil {
+		t.Error("ML-DSA-65 scheme signed with an ML-DSA-44 key")
+	}
+	sig, _ := key44.Sign(rand.Reader, []byte("m"), nil)
+	if s.Verify(key44.PublicKey(), sig, []byte("m"), nil) {
+		t.Error("ML-DSA-65 scheme verified with an ML-DSA-44 key")
+	}
+}
+
+// wrappedSigner hides the concrete key type, like an external signer would.
+type wrappedSigner struct {
+	crypto.Signer
+}
+
+func TestSchemeSignWithForeignSigner(t *testing.T) {
+	key, _ := GenerateKey87(rand.Reader)
+	s := SchemeByName("ML-DSA-87")
+	context := []byte("ctx")
+	sig, err := s.Sign(rand.Reader, wrappedSigner{key}, []byte("m"), context)
+	if err != nil {
+		t.Fatalf("Sign through wrapper failed: %v", err)
+	}
+	if !s.Verify(key.PublicKey(), sig, []byte("m"), context) {
+		t.Error("signature through wrapper does not verify")
+	}
+	if _, err := SchemeByName("ML-DSA-44").Sign(rand.Reader, wrappedSigner{key}, []byte("m"), nil); !errors.Is(err, errWrongScheme) {
+		t.Errorf("Sign with mismatched wrapper: got %v", err)
+	}
+}
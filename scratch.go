@@ -0,0 +1,42 @@
+package mldsa
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var scratchPoolingOff atomic.Bool
+
+// SetScratchPooling controls whether signing, verification and key checks
+// take the vectors they work on from per-parameter-set pools. Pooling is
+// enabled by default: it keeps these tens of kilobytes off goroutine stacks
+// and lets repeated operations run without allocating. Disabling it releases
+// the memory after each operation, for memory-constrained programs that sign
+// or verify once.
+func SetScratchPooling(enabled bool) {
+	scratchPoolingOff.Store(!enabled)
+}
+
+// scratchPool is a pool of scratch space of type T.
+type scratchPool[T any] struct {
+	pool sync.Pool
+}
+
+// get returns zeroed scratch space.
+func (p *scratchPool[T]) get() *T {
+	if !scratchPoolingOff.Load() {
+		if s, ok := p.pool.Get().(*T); ok {
+			return s
+		}
+	}
+	return new(T)
+}
+
+// put wipes s, which may hold secret values, and returns it to the pool.
+func (p *scratchPool[T]) put(s *T) {
+	var zero T
+	*s = zero
+	if !scratchPoolingOff.Load() {
+		p.pool.Put(s)
+	}
+}
@@ -0,0 +1,46 @@
+package mldsa
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestScratchPooling(t *testing.T) {
+	defer SetScratchPooling(true)
+	message, context := []byte("message"), []byte("ctx")
+	for _, enabled := range []bool{false, true} {
+		SetScratchPooling(enabled)
+		for _, s := range Schemes() {
+			key, err := s.GenerateKey(rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 3; i++ {
+				sig, err := key.SignWithContext(rand.Reader, message, context)
+				if err != nil {
+					t.Fatalf("%s: %v", s.Name(), err)
+				}
+				pk := key.Public().(PublicKey)
+				if !pk.Verify(sig, message, context) {
+					t.Errorf("%s (pooling %v): signature does not verify", s.Name(), enabled)
+				}
+				sig[len(sig)-1] ^= 1
+				if pk.Verify(sig, message, context) {
+					t.Errorf("%s (pooling %v): corrupted signature verifies", s.Name(), enabled)
+				}
+			}
+		}
+	}
+}
+
+func TestScratchWiped(t *testing.T) {
+	key, _ := GenerateKey44(rand.Reader)
+	if _, err := key.SignWithContext(rand.Reader, []byte("message"), nil); err != nil {
+		t.Fatal(err)
+	}
+	sc := signScratchPool44.get()
+	defer signScratchPool44.put(sc)
+	if *sc != (signScratch44{}) {
+		t.Error("pooled signing scratch space holds values of a previous signature")
+	}
+}
@@ -0,0 +1,211 @@
+package mldsa
+
+import (
+	"crypto"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// SeedKey is a private key held as its 32-byte seed, which FIPS 204 key
+// generation expands into the full key. The seed is expanded for each
+// signature and the expansion is wiped afterwards, so only the seed stays
+// resident between operations. It suits embedded devices, and callers that
+// treat the seed as the canonical secret.
+//
+// SeedKey implements crypto.Signer, crypto.MessageSigner and PrivateKey.
+// It is safe for concurrent use. AnyPrivateKey, PEMBundle and PKCS #8 write
+// it as its seed, like a key pair, and expand it for the PKCS #8 expanded
+// form.
+type SeedKey struct {
+	// Cache keeps the expanded key between signatures, trading its memory
+	// (up to about 100 KB for ML-DSA-87) for the cost of an expansion per
+	// signature. It must be set before the key is first used.
+	Cache bool
+
+	params ParameterSet
+
+	mu        sync.Mutex
+	seed      [SeedSize]byte
+	key       PrivateKey // the expanded key, when Cache is set
+	pub       PublicKey  // compact, see Public
+	destroyed bool
+}
+
+// NewSeedKey returns a key of parameter set p held as seed. The seed is
+// not expanded until the key is used.
+func NewSeedKey(p ParameterSet, seed []byte) (*SeedKey, error) {
+	if !p.Valid() {
+		return nil, fmt.Errorf("%w %v", ErrUnknownParameterSet, p)
+	}
+	if len(seed) != SeedSize {
+		return nil, &LengthError{Err: ErrInvalidSeedLength, Got: len(seed), Want: SeedSize}
+	}
+	k := &SeedKey{params: p}
+	copy(k.seed[:], seed)
+	return k, nil
+}
+
+// GenerateSeedKey generates a new key of parameter set p, as the key pair
+// generation of its Scheme does, and returns it held as its seed. If rand
+// is nil, crypto/rand.Reader is used.
+func GenerateSeedKey(p ParameterSet, rand io.Reader) (*SeedKey, error) {
+	if !p.Valid() {
+		return nil, fmt.Errorf("%w %v", ErrUnknownParameterSet, p)
+	}
+	key, err := p.Scheme().GenerateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	defer key.(wiper).wipe()
+	seed, _ := key.Seed()
+	k := &SeedKey{params: p, pub: compactPublicKey(key)}
+	copy(k.seed[:], seed)
+	clear(seed)
+	return k, nil
+}
+
+// wiper is implemented by the key pair types, whose wipe method clears
+// the secret material without deriving the public key first.
+type wiper interface {
+	wipe()
+}
+
+// compactPublicKey returns the public key of the key pair key in the form
+// returned by ParsePublicKeyCompact.
+func compactPublicKey(key PrivateKey) PublicKey {
+	b := key.(interface{ publicKeyBytes() []byte }).publicKeyBytes()
+	pk, err := ParsePublicKeyCompact(b)
+	if err != nil {
+		panic("mldsa: internal error: " + err.Error())
+	}
+	return pk
+}
+
+// expand returns the expanded key, and whether the caller must wipe it
+// after use because it is not cached.
+func (k *SeedKey) expand() (PrivateKey, bool, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.destroyed {
+		return nil, false, ErrKeyDestroyed
+	}
+	if k.key != nil {
+		return k.key, false, nil
+	}
+	key, err := k.params.Scheme().NewKeyFromSeed(k.seed[:])
+	if err != nil {
+		return nil, false, err
+	}
+	if k.pub == nil {
+		k.pub = compactPublicKey(key)
+	}
+	if k.Cache {
+		k.key = key
+		return key, false, nil
+	}
+	return key, true, nil
+}
+
+// sign calls f with the expanded key.
+func (k *SeedKey) sign(f func(PrivateKey) ([]byte, error)) ([]byte, error) {
+	key, wipe, err := k.expand()
+	if err != nil {
+		return nil, err
+	}
+	if wipe {
+		defer key.(wiper).wipe()
+	}
+	return f(key)
+}
+
+// Public returns the public key. It is computed on first use, by expanding
+// the seed unless the key has already signed, and kept in the compact form
+// of ParsePublicKeyCompact. It returns nil if the key was destroyed before
+// its public key was ever computed, which Destroy prevents.
+func (k *SeedKey) Public() crypto.PublicKey {
+	k.mu.Lock()
+	pub := k.pub
+	k.mu.Unlock()
+	if pub != nil {
+		return pub
+	}
+	key, wipe, err := k.expand()
+	if err != nil {
+		return nil
+	}
+	if wipe {
+		key.(wiper).wipe()
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.pub
+}
+
+// Scheme returns the parameter set of the key.
+func (k *SeedKey) Scheme() Scheme {
+	return k.params.Scheme()
+}
+
+// Seed returns a copy of the seed, or false once the key is destroyed.
+func (k *SeedKey) Seed() ([]byte, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.destroyed {
+		return nil, false
+	}
+	return append([]byte(nil), k.seed[:]...), true
+}
+
+// isDestroyed reports whether the key was destroyed.
+func (k *SeedKey) isDestroyed() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.destroyed
+}
+
+// Bytes returns a copy of the seed, or nil once the key is destroyed.
+func (k *SeedKey) Bytes() []byte {
+	seed, _ := k.Seed()
+	return seed
+}
+
+// Sign signs digest as PrivateKey44.Sign does.
+func (k *SeedKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return k.sign(func(key PrivateKey) ([]byte, error) {
+		return key.Sign(rand, digest, opts)
+	})
+}
+
+// SignMessage signs msg as PrivateKey44.SignMessage does. It implements
+// crypto.MessageSigner.
+func (k *SeedKey) SignMessage(rand io.Reader, msg []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return k.sign(func(key PrivateKey) ([]byte, error) {
+		return key.(interface {
+			SignMessage(io.Reader, []byte, crypto.SignerOpts) ([]byte, error)
+		}).SignMessage(rand, msg, opts)
+	})
+}
+
+// SignWithContext signs message with an optional context string.
+func (k *SeedKey) SignWithContext(rand io.Reader, message, context []byte) ([]byte, error) {
+	return k.sign(func(key PrivateKey) ([]byte, error) {
+		return key.SignWithContext(rand, message, context)
+	})
+}
+
+// Destroy wipes the seed and any cached expansion, and makes the key
+// unusable: subsequent signing operations return ErrKeyDestroyed. The
+// public key is computed first if needed, so Public still works. Destroy
+// must not be called concurrently with signing.
+func (k *SeedKey) Destroy() {
+	k.Public()
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	clear(k.seed[:])
+	if k.key != nil {
+		k.key.(wiper).wipe()
+		k.key = nil
+	}
+	k.destroyed = true
+}
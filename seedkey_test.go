@@ -0,0 +1,81 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestSeedKey(t *testing.T) {
+	message, context := []byte("message"), []byte("ctx")
+	for _, p := range ParameterSets() {
+		for _, cache := range []bool{false, true} {
+			k, err := GenerateSeedKey(p, rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			k.Cache = cache
+			pk := k.Public().(PublicKey)
+			for i := 0; i < 2; i++ {
+				sig, err := k.SignWithContext(rand.Reader, message, context)
+				if err != nil {
+					t.Fatalf("%v: %v", p, err)
+				}
+				if !pk.Verify(sig, message, context) {
+					t.Errorf("%v (cache %v): signature does not verify", p, cache)
+				}
+			}
+			sig, err := k.Sign(rand.Reader, message, &SignerOpts{Context: context})
+			if err != nil || !pk.Verify(sig, message, context) {
+				t.Errorf("%v: Sign: %v", p, err)
+			}
+			if _, err := k.SignMessage(rand.Reader, message, crypto.SHA256); err != nil {
+				t.Errorf("%v: SignMessage with HashML-DSA: %v", p, err)
+			}
+
+			seed, ok := k.Seed()
+			if !ok || !bytes.Equal(seed, k.Bytes()) {
+				t.Fatalf("%v: Seed failed", p)
+			}
+			full, _ := p.Scheme().NewKeyFromSeed(seed)
+			if !pk.Equal(full.Public()) {
+				t.Errorf("%v: public key differs from the expanded key's", p)
+			}
+			again, err := NewSeedKey(p, seed)
+			if err != nil || !pk.Equal(again.Public()) {
+				t.Errorf("%v: NewSeedKey: %v", p, err)
+			}
+
+			k.Destroy()
+			if _, err := k.SignWithContext(rand.Reader, message, nil); !errors.Is(err, ErrKeyDestroyed) {
+				t.Errorf("%v: signing after Destroy: %v", p, err)
+			}
+			if _, ok := k.Seed(); ok || k.key != nil || k.seed != [SeedSize]byte{} {
+				t.Errorf("%v: Destroy did not wipe the key", p)
+			}
+			if !pk.Equal(k.Public()) {
+				t.Errorf("%v: Destroy changed the public key", p)
+			}
+			if _, err := (AnyPrivateKey{k}).MarshalCBOR(); !errors.Is(err, ErrKeyDestroyed) {
+				t.Errorf("%v: encoding after Destroy: %v", p, err)
+			}
+			if _, err := MarshalPKCS8PrivateKeyWithOptions(k, &PKCS8Options{Form: PKCS8ExpandedKey}); !errors.Is(err, ErrKeyDestroyed) {
+				t.Errorf("%v: PKCS #8 after Destroy: %v", p, err)
+			}
+		}
+	}
+
+	if _, err := NewSeedKey(0, make([]byte, SeedSize)); !errors.Is(err, ErrUnknownParameterSet) {
+		t.Errorf("NewSeedKey with an invalid parameter set: %v", err)
+	}
+	if _, err := NewSeedKey(MLDSA65, make([]byte, 31)); !errors.Is(err, ErrInvalidSeedLength) {
+		t.Errorf("NewSeedKey with a short seed: %v", err)
+	}
+	k, _ := NewSeedKey(MLDSA44, make([]byte, SeedSize))
+	k.Destroy()
+	if k.Public() == nil {
+		t.Error("Destroy did not compute the public key")
+	}
+}
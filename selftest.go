@@ -0,0 +1,112 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/sha3"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrSelfTestFailed is returned when a power-up self-test fails.
+var ErrSelfTestFailed = errors.New("mldsa: self-test failed")
+
+// selfTestVector holds the expected SHA3-256 hashes of the outputs of the
+// known answer tests for one parameter set. The key is derived from the seed
+// 00 01 ... 1f, and both signatures are deterministic (all-zero rnd) over
+// selfTestMessage with an empty context: a pure ML-DSA signature and a
+// HashML-DSA signature of its SHA-256 digest.
+type selfTestVector struct {
+	publicKey string
+	signature string
+	preHash   string
+}
+
+// selfTestVectors are the known answers, one per parameter set. They were
+// checked against an independent implementation.
+var selfTestVectors = map[string]selfTestVector{
+	"ML-DSA-44": {
+		publicKey: "373c7bf2cac5bd2a6c35933bab0fa1c951f22247e1333383fcb618822080373f",
+		signature: "240578adb829152aac9de7c0649a159625d419ec9e95e7a449cde337a909f79a",
+		preHash:   "e8a1edf9c79ebd2285aa437e5f7b57b0f486ab46bd652c2a56c876fdf61e09ae",
+	},
+	"ML-DSA-65": {
+		publicKey: "1800725067e388d837d911fe4f66101cc1961b1bb755030dc574272cfb00013f",
+		signature: "beb88505caafc8d0dd9dd96402c3b42901d2e7f6ca9a83578a56c277cca99d05",
+		preHash:   "23a3d5791d5630ae04bdad525f58edffec4a966a58335a4f198c2abfac7db8b4",
+	},
+	"ML-DSA-87": {
+		publicKey: "e6cf50a9c2fa5234f59949ff61f8161db4d629532127f4aefa8bb10811ecfb1e",
+		signature: "65d499c4e4cbff209c702f92243716aad6816341651d5b158f739c94a07a5220",
+		preHash:   "c64ad9edb3dbede65abc1f19bb750fb37ed0e3d1279f62eb0b7a13114813f608",
+	},
+}
+
+var selfTestMessage = []byte("mldsa power-up self-test")
+
+// SelfTest runs the cryptographic algorithm self-tests (CAST) suitable for
+// module power-up. For every parameter set it checks key generation, pure
+// and pre-hash deterministic signing against known answers, and that
+// verification accepts the known signatures and rejects a corrupted one.
+// It does not depend on strict FIPS mode and may be called at any time. It
+// returns an error wrapping ErrSelfTestFailed on the first failure.
+func SelfTest() error {
+	seed := make([]byte, SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	digest := sha256.Sum256(selfTestMessage)
+	pureMPrime := append([]byte{0, 0}, selfTestMessage...)
+	preHashMPrime, err := preHashMPrime(digest[:], crypto.SHA256, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSelfTestFailed, err)
+	}
+
+	for _, s := range Schemes() {
+		want := selfTestVectors[s.Name()]
+		sk, err := s.NewKeyFromSeed(seed)
+		if err != nil {
+			return fmt.Errorf("%w: %s key generation: %w", ErrSelfTestFailed, s.Name(), err)
+		}
+		pk := sk.Public().(interface {
+			PublicKey
+			verifyInternal(sig, mPrime []byte) error
+		})
+		if !matchesHash(pk.Bytes(), want.publicKey) {
+			return fmt.Errorf("%w: %s key generation known answer mismatch", ErrSelfTestFailed, s.Name())
+		}
+
+		signer := sk.(interface {
+			signInternal(dst, rnd, mPrime []byte) ([]byte, error)
+		})
+		for _, kat := range []struct {
+			name, want string
+			mPrime     []byte
+		}{
+			{"ML-DSA", want.signature, pureMPrime},
+			{"HashML-DSA", want.preHash, preHashMPrime},
+		} {
+			sig, err := signer.signInternal(nil, make([]byte, 32), kat.mPrime)
+			if err != nil || !matchesHash(sig, kat.want) {
+				return fmt.Errorf("%w: %s %s signing known answer mismatch", ErrSelfTestFailed, s.Name(), kat.name)
+			}
+			if pk.verifyInternal(sig, kat.mPrime) != nil {
+				return fmt.Errorf("%w: %s %s verification rejected the known signature", ErrSelfTestFailed, s.Name(), kat.name)
+			}
+			sig[len(sig)/2] ^= 1
+			if pk.verifyInternal(sig, kat.mPrime) == nil {
+				return fmt.Errorf("%w: %s %s verification accepted a corrupted signature", ErrSelfTestFailed, s.Name(), kat.name)
+			}
+		}
+	}
+	return nil
+}
+
+// matchesHash reports whether the SHA3-256 hash of b is the hex string want.
+func matchesHash(b []byte, want string) bool {
+	h := sha3.Sum256(b)
+	w, err := hex.DecodeString(want)
+	return err == nil && bytes.Equal(h[:], w)
+}
@@ -0,0 +1,23 @@
+package mldsa
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		t.Fatal(err)
+	}
+	saved := selfTestVectors["ML-DSA-65"]
+	defer func() { selfTestVectors["ML-DSA-65"] = saved }()
+	broken := saved
+	broken.preHash = saved.signature
+	selfTestVectors["ML-DSA-65"] = broken
+	if err := SetStrictFIPS(true); !errors.Is(err, ErrSelfTestFailed) {
+		t.Errorf("SetStrictFIPS with a broken known answer: %v", err)
+	}
+	if StrictFIPS() {
+		t.Error("strict mode was enabled despite the self-test failure")
+	}
+}
@@ -0,0 +1,49 @@
+package mldsa
+
+import (
+	"crypto"
+	"crypto/rand"
+)
+
+// Sign signs message with an optional context string using priv, drawing
+// randomness from crypto/rand. priv may be a key of any parameter set, or any
+// crypto.Signer that accepts *SignerOpts, such as a wrapper around a hardware
+// token.
+func Sign(priv crypto.Signer, message, context []byte) ([]byte, error) {
+	if sk, ok := priv.(PrivateKey); ok {
+		return sk.SignWithContext(rand.Reader, message, context)
+	}
+	return priv.Sign(rand.Reader, message, &SignerOpts{Context: context})
+}
+
+// SignBytes is like Sign, but takes an expanded private key as returned by
+// the Bytes method of PrivateKey44, PrivateKey65 or PrivateKey87. The
+// parameter set is inferred from the length of privateKey. Seeds are not
+// accepted since they do not identify a parameter set; use NewKeyFromSeed
+// on the appropriate Scheme instead.
+func SignBytes(privateKey, message, context []byte) ([]byte, error) {
+	var sk PrivateKey
+	var err error
+	switch len(privateKey) {
+	case PrivateKeySize44:
+		sk, err = NewPrivateKey44(privateKey)
+	case PrivateKeySize65:
+		sk, err = NewPrivateKey65(privateKey)
+	case PrivateKeySize87:
+		sk, err = NewPrivateKey87(privateKey)
+	default:
+		return nil, &LengthError{Err: ErrInvalidPrivateKey, Got: len(privateKey)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sk.SignWithContext(rand.Reader, message, context)
+}
+
+// Verify reports whether sig is a valid signature of message under context
+// by the encoded public key publicKey. The parameter set is inferred from
+// the length of publicKey; Verify returns false for malformed keys.
+func Verify(publicKey, sig, message, context []byte) bool {
+	pk, err := ParsePublicKey(publicKey)
+	return err == nil && pk.Verify(sig, message, context)
+}
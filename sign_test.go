@@ -0,0 +1,54 @@
+package mldsa
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestPackageSignVerify(t *testing.T) {
+	message := []byte("one-shot message")
+	context := []byte("one-shot context")
+
+	for _, s := range Schemes() {
+		sk, err := s.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk := sk.Public().(PublicKey).Bytes()
+
+		sig, err := Sign(sk, message, context)
+		if err != nil {
+			t.Fatalf("%s: Sign failed: %v", s.Name(), err)
+		}
+		if !Verify(pk, sig, message, context) {
+			t.Errorf("%s: Verify failed", s.Name())
+		}
+		if Verify(pk, sig, message, nil) {
+			t.Errorf("%s: Verify accepted the wrong context", s.Name())
+		}
+
+		sig, err = Sign(wrappedSigner{sk}, message, context)
+		if err != nil {
+			t.Fatalf("%s: Sign through wrapper failed: %v", s.Name(), err)
+		}
+		if !Verify(pk, sig, message, context) {
+			t.Errorf("%s: Verify failed for wrapped signer", s.Name())
+		}
+
+		expanded := sk.(interface{ PrivateKeyBytes() []byte }).PrivateKeyBytes()
+		sig, err = SignBytes(expanded, message, context)
+		if err != nil {
+			t.Fatalf("%s: SignBytes failed: %v", s.Name(), err)
+		}
+		if !Verify(pk, sig, message, context) {
+			t.Errorf("%s: Verify failed for SignBytes", s.Name())
+		}
+	}
+
+	if _, err := SignBytes(make([]byte, SeedSize), message, nil); err == nil {
+		t.Error("SignBytes accepted a seed")
+	}
+	if Verify(make([]byte, 10), make([]byte, SignatureSize44), message, nil) {
+		t.Error("Verify accepted a malformed public key")
+	}
+}
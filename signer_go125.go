@@ -9,4 +9,9 @@ var (
 	_ crypto.MessageSigner = (*PrivateKey44)(nil)
 	_ crypto.MessageSigner = (*PrivateKey65)(nil)
 	_ crypto.MessageSigner = (*PrivateKey87)(nil)
+	_ crypto.MessageSigner = (*Key44)(nil)
+	_ crypto.MessageSigner = (*Key65)(nil)
+	_ crypto.MessageSigner = (*Key87)(nil)
+	_ crypto.MessageSigner = (*SeedKey)(nil)
+	_ crypto.MessageSigner = (*ExternalSigner)(nil)
 )
@@ -0,0 +1,140 @@
+package mldsa
+
+import (
+	"io"
+	"runtime"
+	"sync"
+)
+
+// SignRequest is a request to a SignerPool. Done is called from a worker
+// goroutine with the signature or the error.
+type SignRequest struct {
+	Key     Fingerprint // zero selects the first key of the pool
+	Message []byte
+	Context []byte
+	Done    func(sig []byte, err error)
+}
+
+// SignerPool signs with a set of keys on a fixed number of worker
+// goroutines, for services under sustained signing load. The secret NTT
+// transforms of its keys are precomputed (see PrivateKey65.Precompute),
+// and each worker reuses pooled scratch space, so a signature costs only
+// its own computation and the allocation of its result.
+//
+// Requests are submitted with Submit, which takes a callback, or through
+// the channel returned by Requests; Sign wraps Submit for synchronous use.
+// A SignerPool is safe for concurrent use.
+type SignerPool struct {
+	// Rand is the randomness source for hedged signing. If nil,
+	// crypto/rand.Reader is used. It must be set before the first request.
+	Rand io.Reader
+
+	keys  map[Fingerprint]PrivateKey
+	first Fingerprint
+
+	requests chan *SignRequest
+	wg       sync.WaitGroup
+
+	mu     sync.RWMutex // guards closed and sends on requests
+	closed bool
+}
+
+// NewSignerPool returns a pool signing with keys on workers goroutines, or
+// on GOMAXPROCS goroutines if workers is not positive. Close stops them.
+func NewSignerPool(workers int, keys ...PrivateKey) (*SignerPool, error) {
+	if len(keys) == 0 {
+		return nil, ErrUnknownKey
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	p := &SignerPool{
+		keys:     make(map[Fingerprint]PrivateKey, len(keys)),
+		requests: make(chan *SignRequest, 2*workers),
+	}
+	for i, key := range keys {
+		fp := key.Public().(PublicKey).Fingerprint()
+		if i == 0 {
+			p.first = fp
+		}
+		if pre, ok := key.(interface{ Precompute() }); ok {
+			pre.Precompute()
+		}
+		p.keys[fp] = key
+	}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p, nil
+}
+
+func (p *SignerPool) work() {
+	defer p.wg.Done()
+	for req := range p.requests {
+		req.Done(p.sign(req))
+	}
+}
+
+func (p *SignerPool) sign(req *SignRequest) ([]byte, error) {
+	fp := req.Key
+	if fp == (Fingerprint{}) {
+		fp = p.first
+	}
+	key, ok := p.keys[fp]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key.SignWithContext(p.Rand, req.Message, req.Context)
+}
+
+// Submit queues req, blocking while the queue is full. req.Done must not
+// be nil, and req must not be modified until Done is called.
+func (p *SignerPool) Submit(req *SignRequest) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	p.requests <- req
+	return nil
+}
+
+// Sign signs message with the key whose fingerprint is key, or with the
+// first key of the pool if key is zero, and waits for the signature.
+func (p *SignerPool) Sign(key Fingerprint, message, context []byte) ([]byte, error) {
+	type result struct {
+		sig []byte
+		err error
+	}
+	done := make(chan result, 1)
+	err := p.Submit(&SignRequest{
+		Key:     key,
+		Message: message,
+		Context: context,
+		Done:    func(sig []byte, err error) { done <- result{sig, err} },
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := <-done
+	return r.sig, r.err
+}
+
+// Requests returns a channel feeding requests to the pool, for callers
+// that prefer sending to calling Submit. Sending on it after Close panics.
+func (p *SignerPool) Requests() chan<- *SignRequest {
+	return p.requests
+}
+
+// Close stops accepting requests, waits until the queued ones are signed,
+// and stops the workers. The keys are not destroyed.
+func (p *SignerPool) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.requests)
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
@@ -0,0 +1,69 @@
+package mldsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSignerPool(t *testing.T) {
+	var keys []PrivateKey
+	for _, s := range Schemes() {
+		key, err := s.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	p, err := NewSignerPool(3, keys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message, context := []byte("message"), []byte("ctx")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 12; i++ {
+		key := keys[i%len(keys)]
+		pk := key.Public().(PublicKey)
+		wg.Add(1)
+		err := p.Submit(&SignRequest{
+			Key:     pk.Fingerprint(),
+			Message: message,
+			Context: context,
+			Done: func(sig []byte, err error) {
+				defer wg.Done()
+				if err != nil || !pk.Verify(sig, message, context) {
+					t.Errorf("%s: pool signature invalid: %v", pk.Scheme().Name(), err)
+				}
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+
+	sig, err := p.Sign(Fingerprint{}, message, nil)
+	if err != nil || !keys[0].Public().(PublicKey).Verify(sig, message, nil) {
+		t.Errorf("signing with the default key: %v", err)
+	}
+	done := make(chan error, 1)
+	p.Requests() <- &SignRequest{Message: message, Done: func(_ []byte, err error) { done <- err }}
+	if err := <-done; err != nil {
+		t.Errorf("request sent on the channel: %v", err)
+	}
+	other, _ := GenerateKey44(rand.Reader)
+	if _, err := p.Sign(other.PublicKey().Fingerprint(), message, nil); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("signing with a key outside the pool: %v", err)
+	}
+
+	p.Close()
+	p.Close()
+	if _, err := p.Sign(Fingerprint{}, message, nil); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("signing after Close: %v", err)
+	}
+	if _, err := NewSignerPool(1); err == nil {
+		t.Error("NewSignerPool accepted no keys")
+	}
+}
@@ -0,0 +1,266 @@
+package mldsa
+
+import (
+	"encoding"
+	"encoding/base64"
+)
+
+// Keys and signatures implement encoding.TextMarshaler and
+// encoding.TextUnmarshaler using padded base64url, so they can be stored
+// directly in YAML configuration files and environment variables. Public
+// keys encode their standard encoding, private keys their expanded encoding,
+// and key pairs their 32-byte seed. JSON uses the tagged form of MarshalJSON
+// instead, see json.go.
+
+// Signature is an encoded ML-DSA signature of any parameter set. It behaves
+// like a []byte but marshals to text as padded base64url.
+type Signature []byte
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Signature) MarshalText() ([]byte, error) {
+	return marshalText(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Signature) UnmarshalText(text []byte) error {
+	b, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+	*s = b
+	return nil
+}
+
+// marshalText encodes b as padded base64url.
+func marshalText(b []byte) []byte {
+	text := make([]byte, base64.URLEncoding.EncodedLen(len(b)))
+	base64.URLEncoding.Encode(text, b)
+	return text
+}
+
+// unmarshalText decodes padded base64url text.
+func unmarshalText(text []byte) ([]byte, error) {
+	b := make([]byte, base64.URLEncoding.DecodedLen(len(text)))
+	n, err := base64.URLEncoding.Decode(b, text)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (pk *PublicKey44) MarshalText() ([]byte, error) {
+	return marshalText(pk.Bytes()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (pk *PublicKey44) UnmarshalText(text []byte) error {
+	b, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+	parsed, err := NewPublicKey44(b)
+	if err != nil {
+		return err
+	}
+	pk.set(parsed)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns ErrKeyDestroyed
+// for a destroyed key.
+func (sk *PrivateKey44) MarshalText() ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	b := sk.Bytes()
+	defer clear(b)
+	return marshalText(b), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (sk *PrivateKey44) UnmarshalText(text []byte) error {
+	b, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+	defer clear(b)
+	parsed, err := NewPrivateKey44(b)
+	if err != nil {
+		return err
+	}
+	defer parsed.wipe()
+	return sk.set(parsed)
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns ErrKeyDestroyed
+// for a destroyed key.
+func (key *Key44) MarshalText() ([]byte, error) {
+	if key.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return marshalText(key.seed[:]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (key *Key44) UnmarshalText(text []byte) error {
+	b, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+	defer clear(b)
+	return key.Regenerate(b)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (pk *PublicKey65) MarshalText() ([]byte, error) {
+	return marshalText(pk.Bytes()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (pk *PublicKey65) UnmarshalText(text []byte) error {
+	b, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+	parsed, err := NewPublicKey65(b)
+	if err != nil {
+		return err
+	}
+	pk.set(parsed)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns ErrKeyDestroyed
+// for a destroyed key.
+func (sk *PrivateKey65) MarshalText() ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	b := sk.Bytes()
+	defer clear(b)
+	return marshalText(b), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (sk *PrivateKey65) UnmarshalText(text []byte) error {
+	b, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+	defer clear(b)
+	parsed, err := NewPrivateKey65(b)
+	if err != nil {
+		return err
+	}
+	defer parsed.wipe()
+	return sk.set(parsed)
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns ErrKeyDestroyed
+// for a destroyed key.
+func (key *Key65) MarshalText() ([]byte, error) {
+	if key.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return marshalText(key.seed[:]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (key *Key65) UnmarshalText(text []byte) error {
+	b, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+	defer clear(b)
+	return key.Regenerate(b)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (pk *PublicKey87) MarshalText() ([]byte, error) {
+	return marshalText(pk.Bytes()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (pk *PublicKey87) UnmarshalText(text []byte) error {
+	b, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+	parsed, err := NewPublicKey87(b)
+	if err != nil {
+		return err
+	}
+	pk.set(parsed)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns ErrKeyDestroyed
+// for a destroyed key.
+func (sk *PrivateKey87) MarshalText() ([]byte, error) {
+	if sk.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	b := sk.Bytes()
+	defer clear(b)
+	return marshalText(b), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (sk *PrivateKey87) UnmarshalText(text []byte) error {
+	b, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+	defer clear(b)
+	parsed, err := NewPrivateKey87(b)
+	if err != nil {
+		return err
+	}
+	defer parsed.wipe()
+	return sk.set(parsed)
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns ErrKeyDestroyed
+// for a destroyed key.
+func (key *Key87) MarshalText() ([]byte, error) {
+	if key.destroyed {
+		return nil, ErrKeyDestroyed
+	}
+	return marshalText(key.seed[:]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (key *Key87) UnmarshalText(text []byte) error {
+	b, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+	defer clear(b)
+	return key.Regenerate(b)
+}
+
+// Compile-time interface assertions for encoding.TextMarshaler and
+// encoding.TextUnmarshaler.
+var (
+	_ encoding.TextMarshaler   = Signature(nil)
+	_ encoding.TextUnmarshaler = (*Signature)(nil)
+	_ encoding.TextMarshaler   = (*PublicKey44)(nil)
+	_ encoding.TextUnmarshaler = (*PublicKey44)(nil)
+	_ encoding.TextMarshaler   = (*PrivateKey44)(nil)
+	_ encoding.TextUnmarshaler = (*PrivateKey44)(nil)
+	_ encoding.TextMarshaler   = (*Key44)(nil)
+	_ encoding.TextUnmarshaler = (*Key44)(nil)
+	_ encoding.TextMarshaler   = (*PublicKey65)(nil)
+	_ encoding.TextUnmarshaler = (*PublicKey65)(nil)
+	_ encoding.TextMarshaler   = (*PrivateKey65)(nil)
+	_ encoding.TextUnmarshaler = (*PrivateKey65)(nil)
+	_ encoding.TextMarshaler   = (*Key65)(nil)
+	_ encoding.TextUnmarshaler = (*Key65)(nil)
+	_ encoding.TextMarshaler   = (*PublicKey87)(nil)
+	_ encoding.TextUnmarshaler = (*PublicKey87)(nil)
+	_ encoding.TextMarshaler   = (*PrivateKey87)(nil)
+	_ encoding.TextUnmarshaler = (*PrivateKey87)(nil)
+	_ encoding.TextMarshaler   = (*Key87)(nil)
+	_ encoding.TextUnmarshaler = (*Key87)(nil)
+)
@@ -0,0 +1,69 @@
+package mldsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTextMarshaling(t *testing.T) {
+	key, _ := GenerateKey65(rand.Reader)
+	sig, _ := key.SignWithContext(rand.Reader, []byte("m"), nil)
+
+	type config struct {
+		Key       *Key65
+		Private   *PrivateKey65
+		Public    *PublicKey65
+		Signature Signature
+	}
+	in := config{Key: key, Private: &key.PrivateKey65, Public: key.PublicKey(), Signature: sig}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	if strings.ContainsAny(string(data), "+/") {
+		t.Errorf("encoding is not base64url: %s", data)
+	}
+
+	var out config
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	if !out.Key.Equal(key) || !out.Private.Equal(key) || !out.Public.Equal(key.PublicKey()) {
+		t.Error("keys did not roundtrip")
+	}
+	if !bytes.Equal(out.Signature, sig) || !out.Public.Verify(out.Signature, []byte("m"), nil) {
+		t.Error("signature did not roundtrip")
+	}
+
+	text, _ := key.MarshalText()
+	if !strings.HasSuffix(string(text), "=") {
+		t.Errorf("seed encoding is not padded: %s", text)
+	}
+	var pk44 PublicKey44
+	if err := pk44.UnmarshalText([]byte("AAAA")); err == nil {
+		t.Error("UnmarshalText accepted a short public key")
+	}
+	var sk87 PrivateKey87
+	if err := sk87.UnmarshalText([]byte("not base64!")); err == nil {
+		t.Error("UnmarshalText accepted invalid base64")
+	}
+}
+
+func TestTextMarshalDestroyed(t *testing.T) {
+	for _, p := range ParameterSets() {
+		key, _ := p.Scheme().GenerateKey(rand.Reader)
+		b, _ := expandedPrivateKey(key)
+		sk, _ := p.Scheme().UnmarshalPrivateKey(b)
+		for _, k := range []PrivateKey{key, sk} {
+			k.(interface{ Destroy() }).Destroy()
+			if _, err := k.(encoding.TextMarshaler).MarshalText(); !errors.Is(err, ErrKeyDestroyed) {
+				t.Errorf("%v: MarshalText of a destroyed %T: %v", p, k, err)
+			}
+		}
+	}
+}
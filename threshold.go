@@ -93,7 +93,17 @@ func PackPolyQ(f RingElement, b []byte) {
 }
 
 // UnpackPolyQ unpacks a polynomial packed with PackPolyQ.
-func UnpackPolyQ(b []byte) RingElement {
+// It returns a *LengthError wrapping ErrShortEncoding if b is shorter than
+// PackPolyQSize bytes.
+func UnpackPolyQ(b []byte) (RingElement, error) {
+	if len(b) < PackPolyQSize {
+		return RingElement{}, &LengthError{Err: ErrShortEncoding, Got: len(b), Want: PackPolyQSize}
+	}
+	return unpackPolyQ(b), nil
+}
+
+// unpackPolyQ is UnpackPolyQ for input known to be long enough.
+func unpackPolyQ(b []byte) RingElement {
 	var f RingElement
 	var bitBuf uint64
 	var bitLen uint
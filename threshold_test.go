@@ -13,8 +13,8 @@ func TestPackPolyQRoundtrip(t *testing.T) {
 	}
 	buf := make([]byte, PackPolyQSize)
 	PackPolyQ(f, buf)
-	g := UnpackPolyQ(buf)
-	if g != f {
+	g, err := UnpackPolyQ(buf)
+	if err != nil || g != f {
 		t.Fatalf("PackPolyQ/UnpackPolyQ roundtrip mismatch")
 	}
 
@@ -24,7 +24,7 @@ func TestPackPolyQRoundtrip(t *testing.T) {
 	if !bytes.Equal(make([]byte, PackPolyQSize), buf2) {
 		t.Fatalf("PackPolyQ(0) should produce all-zero bytes")
 	}
-	if UnpackPolyQ(buf2) != zero {
+	if g, _ := UnpackPolyQ(buf2); g != zero {
 		t.Fatalf("UnpackPolyQ(0) should produce zero polynomial")
 	}
 }